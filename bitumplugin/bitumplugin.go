@@ -1,6 +1,13 @@
 package bitumplugin
 
-import "encoding/json"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
 
 // Plugin settings, kinda doesn;t go here but for now it is fine
 const (
@@ -8,6 +15,7 @@ const (
 	ID                       = "bitum"
 	CmdAuthorizeVote         = "authorizevote"
 	CmdStartVote             = "startvote"
+	CmdStartVoteRunoff       = "startvoterunoff"
 	CmdVoteDetails           = "votedetails"
 	CmdVoteSummary           = "votesummary"
 	CmdLoadVoteResults       = "loadvoteresults"
@@ -16,16 +24,36 @@ const (
 	CmdNewComment            = "newcomment"
 	CmdLikeComment           = "likecomment"
 	CmdCensorComment         = "censorcomment"
+	CmdEditComment           = "editcomment"
+	CmdDeleteComment         = "deletecomment"
+	CmdCommentRevisions      = "commentrevisions"
+	CmdFlagComment           = "flagcomment"
+	CmdDismissCommentFlags   = "dismisscommentflags"
+	CmdCommentFlags          = "commentflags"
+	CmdFlaggedComments       = "flaggedcomments"
+	CmdAppealCensorship      = "appealcensorship"
+	CmdResolveAppeal         = "resolveappeal"
+	CmdCensorshipAppeals     = "censorshipappeals"
 	CmdGetComment            = "getcomment"
 	CmdGetComments           = "getcomments"
+	CmdUserComments          = "usercomments"
+	CmdUnreadComments        = "unreadcomments"
 	CmdProposalVotes         = "proposalvotes"
 	CmdCommentLikes          = "commentlikes"
 	CmdProposalCommentsLikes = "proposalcommentslikes"
 	CmdInventory             = "inventory"
 	CmdTokenInventory        = "tokeninventory"
+	CmdVoteResultsBundle     = "voteresultsbundle"
+	CmdCancelVote            = "cancelvote"
+	CmdStartScheduledVotes   = "startscheduledvotes"
+	CmdVoteReceipt           = "votereceipt"
+	CmdVoteTimeSeries        = "votetimeseries"
+	CmdJournalProof          = "journalproof"
 	MDStreamAuthorizeVote    = 13 // Vote authorization by proposal author
 	MDStreamVoteBits         = 14 // Vote bits and mask
 	MDStreamVoteSnapshot     = 15 // Vote tickets and start/end parameters
+	MDStreamCancelVote       = 16 // Vote cancellation by admin
+	MDStreamScheduledVote    = 17 // Vote scheduled to start at a future height
 
 	VoteDurationMin = 2016 // Minimum vote duration (in blocks)
 	VoteDurationMax = 4032 // Maximum vote duration (in blocks)
@@ -33,6 +61,52 @@ const (
 	// Authorize vote actions
 	AuthVoteActionAuthorize = "authorize" // Authorize a proposal vote
 	AuthVoteActionRevoke    = "revoke"    // Revoke a proposal vote authorization
+
+	// VoteOptionIDApprove is the vote option ID that approves a
+	// standard vote.
+	VoteOptionIDApprove = "yes"
+
+	// Comment sort orders
+	CommentSortTop = "top" // Highest vote score first
+	CommentSortNew = "new" // Newest first
+	CommentSortOld = "old" // Oldest first
+
+	// Censorship appeal resolutions
+	AppealActionUphold  = "uphold"  // Comment remains censored
+	AppealActionRestore = "restore" // Comment is restored
+)
+
+// VoteT represents the type of a proposal vote.
+type VoteT int
+
+const (
+	// VoteTypeStandard is a yes/no vote.  Each ticket selects a single
+	// option and the vote is approved when the quorum is met and the
+	// approve option received at least the pass percentage of the cast
+	// votes.  This is the zero value so that votes that were started
+	// before vote types existed are treated as standard votes.
+	VoteTypeStandard VoteT = 0
+
+	// VoteTypeMultipleChoice is a vote where each ticket selects a
+	// single option out of several.  The option with the most votes
+	// wins provided that the quorum is met and the option received at
+	// least the pass percentage of the cast votes.
+	VoteTypeMultipleChoice VoteT = 1
+
+	// VoteTypeApproval is a vote where each ticket may approve several
+	// options by setting the bits of each option in its vote bits.  The
+	// option with the most approvals wins provided that the quorum is
+	// met and the option was approved by at least the pass percentage
+	// of the tickets that voted.
+	VoteTypeApproval VoteT = 2
+
+	// VoteTypeRunoff is a yes/no vote on one of several competing
+	// proposals that share a single voting period and ticket snapshot.
+	// Each ticket may vote once across all proposals in the runoff.
+	// The winner is the proposal with the most approve votes out of the
+	// proposals that met the pass percentage, provided that the quorum
+	// is met by the votes cast across the entire runoff.
+	VoteTypeRunoff VoteT = 3
 )
 
 // CastVote is a signed vote.
@@ -67,9 +141,10 @@ func DecodeBallot(payload []byte) (*Ballot, error) {
 
 // CastVoteReply contains the signature or error to a cast vote command.
 type CastVoteReply struct {
-	ClientSignature string `json:"clientsignature"` // Signature that was sent in
-	Signature       string `json:"signature"`       // Signature of the ClientSignature
-	Error           string `json:"error"`           // Error if something wen't wrong during casting a vote
+	ClientSignature string `json:"clientsignature"`       // Signature that was sent in
+	Signature       string `json:"signature"`             // Signature of the ClientSignature
+	Error           string `json:"error"`                 // Error if something wen't wrong during casting a vote
+	BlockHeight     uint32 `json:"blockheight,omitempty"` // Best block height when the vote was cast
 }
 
 // EncodeCastVoteReply encodes CastVoteReply into a JSON byte slice.
@@ -121,11 +196,23 @@ type VoteOption struct {
 // Vote represents the vote options for vote that is identified by its token.
 type Vote struct {
 	Token            string       `json:"token"`            // Token that identifies vote
+	Type             VoteT        `json:"type"`             // Vote type
 	Mask             uint64       `json:"mask"`             // Valid votebits
 	Duration         uint32       `json:"duration"`         // Duration in blocks
 	QuorumPercentage uint32       `json:"quorumpercentage"` // Percent of eligible votes required for quorum
 	PassPercentage   uint32       `json:"passpercentage"`   // Percent of total votes required to pass
 	Options          []VoteOption `json:"options"`          // Vote option
+	RunoffTokens     []string     `json:"runofftokens"`     // Tokens of all proposals in a runoff vote
+
+	// AllowRevote allows a ticket to vote again until the voting
+	// period ends.  Only the latest vote of a ticket is counted.
+	AllowRevote bool `json:"allowrevote,omitempty"`
+
+	// StartHeight schedules the vote to start once the chain reaches
+	// the provided block height.  The ticket snapshot is taken and
+	// voting opens at that height.  The vote starts immediately when
+	// it is not set.
+	StartHeight uint32 `json:"startheight,omitempty"`
 }
 
 // EncodeVote encodes Vote into a JSON byte slice.
@@ -204,6 +291,65 @@ func DecodeAuthorizeVoteReply(payload []byte) (*AuthorizeVoteReply, error) {
 	return &avr, nil
 }
 
+// CancelVote is an MDStream that is used to indicate that an admin has
+// cancelled an active proposal vote.  The signature and public key are from
+// the admin.  A cancelled vote no longer accepts ballots and does not produce
+// a winner.  Cancelling a vote that is part of a runoff cancels the vote of
+// every proposal in the runoff.
+const VersionCancelVote = 1
+
+type CancelVote struct {
+	// Generated by bitumplugin
+	Version   uint   `json:"version"`   // Version of this structure
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+
+	// Generated by client
+	Token     string `json:"token"`     // Proposal censorship token
+	Reason    string `json:"reason"`    // Reason for cancelling the vote
+	Signature string `json:"signature"` // Signature of token+reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// EncodeCancelVote encodes CancelVote into a JSON byte slice.
+func EncodeCancelVote(cv CancelVote) ([]byte, error) {
+	return json.Marshal(cv)
+}
+
+// DecodeCancelVote decodes a JSON byte slice into a CancelVote.
+func DecodeCancelVote(payload []byte) (*CancelVote, error) {
+	var cv CancelVote
+	err := json.Unmarshal(payload, &cv)
+	if err != nil {
+		return nil, err
+	}
+	return &cv, nil
+}
+
+// CancelVoteReply returns the receipt for the vote cancellation along with
+// the tokens of all proposals whose vote was cancelled.  The receipt is the
+// server side signature of CancelVote.Signature.
+type CancelVoteReply struct {
+	Tokens    []string `json:"tokens"`    // Tokens of cancelled votes
+	Receipt   string   `json:"receipt"`   // Server signature of client signature
+	Timestamp int64    `json:"timestamp"` // Received UNIX timestamp
+}
+
+// EncodeCancelVoteReply encodes CancelVoteReply into a JSON byte slice.
+func EncodeCancelVoteReply(cvr CancelVoteReply) ([]byte, error) {
+	return json.Marshal(cvr)
+}
+
+// DecodeCancelVoteReply decodes a JSON byte slice into a CancelVoteReply.
+func DecodeCancelVoteReply(payload []byte) (*CancelVoteReply, error) {
+	var cvr CancelVoteReply
+	err := json.Unmarshal(payload, &cvr)
+	if err != nil {
+		return nil, err
+	}
+	return &cvr, nil
+}
+
 // StartVote instructs the plugin to commence voting on a proposal with the
 // provided vote bits.
 const VersionStartVote = 1
@@ -265,6 +411,95 @@ func DecodeStartVoteReply(payload []byte) (*StartVoteReply, error) {
 	return &v, nil
 }
 
+// StartVoteRunoff instructs the plugin to commence a runoff vote between
+// several proposals.  The start votes must all be of type VoteTypeRunoff and
+// must share the same duration, quorum percentage and pass percentage.  A
+// single ticket snapshot is used for all proposals in the runoff.
+type StartVoteRunoff struct {
+	StartVotes []StartVote `json:"startvotes"` // Start vote for each proposal
+}
+
+// EncodeStartVoteRunoff encodes StartVoteRunoff into a JSON byte slice.
+func EncodeStartVoteRunoff(v StartVoteRunoff) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeStartVoteRunoff decodes a JSON byte slice into a StartVoteRunoff.
+func DecodeStartVoteRunoff(payload []byte) (*StartVoteRunoff, error) {
+	var sv StartVoteRunoff
+
+	err := json.Unmarshal(payload, &sv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sv, nil
+}
+
+// StartVoteRunoffReply is the reply to StartVoteRunoff.  The vote snapshot is
+// shared by all proposals in the runoff.
+type StartVoteRunoffReply struct {
+	StartVoteReply StartVoteReply `json:"startvotereply"` // Shared vote snapshot
+}
+
+// EncodeStartVoteRunoffReply encodes StartVoteRunoffReply into a JSON byte
+// slice.
+func EncodeStartVoteRunoffReply(v StartVoteRunoffReply) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeStartVoteRunoffReply decodes a JSON byte slice into a
+// StartVoteRunoffReply.
+func DecodeStartVoteRunoffReply(payload []byte) (*StartVoteRunoffReply, error) {
+	var v StartVoteRunoffReply
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// StartScheduledVotesReply is the reply to the StartScheduledVotes command.
+// It contains the votes that were scheduled to start at or below the best
+// block height and that have been started.  The command has no payload.
+type StartScheduledVotesReply struct {
+	StartVotes []StartVoteTuple `json:"startvotes"` // Started votes
+}
+
+// EncodeStartScheduledVotesReply encodes a StartScheduledVotesReply into a
+// JSON byte slice.
+func EncodeStartScheduledVotesReply(v StartScheduledVotesReply) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeStartScheduledVotesReply decodes a JSON byte slice into a
+// StartScheduledVotesReply.
+func DecodeStartScheduledVotesReply(payload []byte) (*StartScheduledVotesReply, error) {
+	var v StartScheduledVotesReply
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// RunoffTokens returns the sorted tokens of the proposals that are part of
+// the passed in runoff vote.  Both politeiad and the cache use this to
+// populate Vote.RunoffTokens so that the runoff membership does not depend on
+// the order in which the start votes were sent.
+func RunoffTokens(sv StartVoteRunoff) []string {
+	tokens := make([]string, 0, len(sv.StartVotes))
+	for _, v := range sv.StartVotes {
+		tokens = append(tokens, v.Vote.Token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
 // VoteDetails is used to retrieve the voting period details for a record.
 type VoteDetails struct {
 	Token string `json:"token"` // Censorship token
@@ -292,6 +527,8 @@ type VoteDetailsReply struct {
 	AuthorizeVote  AuthorizeVote  `json:"authorizevote"`  // Vote authorization
 	StartVote      StartVote      `json:"startvote"`      // Vote ballot
 	StartVoteReply StartVoteReply `json:"startvotereply"` // Start vote snapshot
+	CancelVote     CancelVote     `json:"cancelvote"`     // Vote cancellation
+	ScheduledVote  StartVote      `json:"scheduledvote"`  // Vote that is scheduled to start
 }
 
 // EncodeVoteDetailsReply encodes VoteDetailsReply into a JSON byte slice.
@@ -354,6 +591,228 @@ func DecodeVoteResultsReply(payload []byte) (*VoteResultsReply, error) {
 	return &v, nil
 }
 
+// VersionVoteResultsBundle is the version of the VoteResultsBundle
+// structure.
+const VersionVoteResultsBundle = 1
+
+// GetVoteResultsBundle requests the signed results bundle of a finished
+// proposal vote.
+type GetVoteResultsBundle struct {
+	Token string `json:"token"` // Censorship token
+}
+
+// EncodeGetVoteResultsBundle encodes GetVoteResultsBundle into a JSON byte
+// slice.
+func EncodeGetVoteResultsBundle(v GetVoteResultsBundle) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeGetVoteResultsBundle decodes a JSON byte slice into a
+// GetVoteResultsBundle.
+func DecodeGetVoteResultsBundle(payload []byte) (*GetVoteResultsBundle, error) {
+	var v GetVoteResultsBundle
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// CastVoteReceipt is a cast vote along with the receipt that the server
+// returned when the vote was cast and the ticket commitment address that the
+// vote signature was verified against.
+type CastVoteReceipt struct {
+	CastVote CastVote `json:"castvote"` // Client side vote
+	Receipt  string   `json:"receipt"`  // Server signature of CastVote.Signature
+	Address  string   `json:"address"`  // Largest commitment address of the ticket
+}
+
+// VoteResultsBundle contains everything that is required to verify the
+// outcome of a proposal vote offline.  The bundle is signed by the politeiad
+// identity.  The signature covers the SHA256 digest of the JSON encoded
+// bundle with an empty Signature field, see VoteResultsBundleDigest.
+type VoteResultsBundle struct {
+	Version        uint               `json:"version"`        // Bundle version
+	Token          string             `json:"token"`          // Censorship token
+	StartVote      StartVote          `json:"startvote"`      // Signed vote parameters
+	StartVoteReply StartVoteReply     `json:"startvotereply"` // Eligible ticket snapshot
+	CastVotes      []CastVoteReceipt  `json:"castvotes"`      // All cast votes
+	TotalVotes     uint64             `json:"totalvotes"`     // Number of tickets that voted
+	Results        []VoteOptionResult `json:"results"`        // Vote tally
+	PublicKey      string             `json:"publickey"`      // politeiad public key
+	Signature      string             `json:"signature"`      // politeiad signature of the bundle digest
+}
+
+// EncodeVoteResultsBundle encodes VoteResultsBundle into a JSON byte slice.
+func EncodeVoteResultsBundle(v VoteResultsBundle) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeVoteResultsBundle decodes a JSON byte slice into a
+// VoteResultsBundle.
+func DecodeVoteResultsBundle(payload []byte) (*VoteResultsBundle, error) {
+	var v VoteResultsBundle
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// VoteResultsBundleDigest returns the SHA256 digest of the JSON encoded
+// bundle with its signature removed.  This is the digest that is signed by
+// politeiad.
+func VoteResultsBundleDigest(v VoteResultsBundle) ([]byte, error) {
+	v.Signature = ""
+	b, err := EncodeVoteResultsBundle(v)
+	if err != nil {
+		return nil, err
+	}
+	d := sha256.Sum256(b)
+	return d[:], nil
+}
+
+// VoteReceipt requests the vote that was cast by a ticket along with the
+// receipt that the server returned for it.  This is a cache only command.
+type VoteReceipt struct {
+	Token  string `json:"token"`  // Censorship token
+	Ticket string `json:"ticket"` // Ticket hash
+}
+
+// EncodeVoteReceipt encodes VoteReceipt into a JSON byte slice.
+func EncodeVoteReceipt(vr VoteReceipt) ([]byte, error) {
+	return json.Marshal(vr)
+}
+
+// DecodeVoteReceipt decodes a JSON byte slice into a VoteReceipt.
+func DecodeVoteReceipt(payload []byte) (*VoteReceipt, error) {
+	var vr VoteReceipt
+
+	err := json.Unmarshal(payload, &vr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vr, nil
+}
+
+// VoteReceiptReply is the reply to the VoteReceipt command.  Receipt is the
+// server signature of CastVote.Signature.
+type VoteReceiptReply struct {
+	CastVote CastVote `json:"castvote"` // Client side vote
+	Receipt  string   `json:"receipt"`  // Server signature of CastVote.Signature
+}
+
+// EncodeVoteReceiptReply encodes VoteReceiptReply into a JSON byte slice.
+func EncodeVoteReceiptReply(vrr VoteReceiptReply) ([]byte, error) {
+	return json.Marshal(vrr)
+}
+
+// DecodeVoteReceiptReply decodes a JSON byte slice into a VoteReceiptReply.
+func DecodeVoteReceiptReply(payload []byte) (*VoteReceiptReply, error) {
+	var vrr VoteReceiptReply
+
+	err := json.Unmarshal(payload, &vrr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vrr, nil
+}
+
+// VoteTimeSeries requests the number of votes that each vote bit received at
+// every block height of a proposal vote.  This is a cache only command.
+type VoteTimeSeries struct {
+	Token string `json:"token"` // Censorship token
+}
+
+// EncodeVoteTimeSeries encodes VoteTimeSeries into a JSON byte slice.
+func EncodeVoteTimeSeries(v VoteTimeSeries) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeVoteTimeSeries decodes a JSON byte slice into a VoteTimeSeries.
+func DecodeVoteTimeSeries(payload []byte) (*VoteTimeSeries, error) {
+	var v VoteTimeSeries
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// VoteBitCount is the number of votes that selected a vote bit at a block
+// height.  BlockHeight is the best block height at the time the votes were
+// cast.  Votes that were cast before block heights were recorded have a
+// BlockHeight of 0.
+type VoteBitCount struct {
+	BlockHeight uint32 `json:"blockheight"` // Best block height
+	VoteBit     string `json:"votebit"`     // Hex encoded vote bit
+	Votes       uint64 `json:"votes"`       // Number of votes
+}
+
+// VoteTimeSeriesReply is the reply to the VoteTimeSeries command.  Counts are
+// ordered by block height.
+type VoteTimeSeriesReply struct {
+	StartVote      StartVote      `json:"startvote"`      // Vote parameters
+	StartVoteReply StartVoteReply `json:"startvotereply"` // Vote details
+	Counts         []VoteBitCount `json:"counts"`         // Vote bit counts
+}
+
+// EncodeVoteTimeSeriesReply encodes VoteTimeSeriesReply into a JSON byte
+// slice.
+func EncodeVoteTimeSeriesReply(v VoteTimeSeriesReply) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeVoteTimeSeriesReply decodes a JSON byte slice into a
+// VoteTimeSeriesReply.
+func DecodeVoteTimeSeriesReply(payload []byte) (*VoteTimeSeriesReply, error) {
+	var v VoteTimeSeriesReply
+
+	err := json.Unmarshal(payload, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// TallyVotes counts the provided cast votes using the tally rules of the
+// provided vote and returns the number of votes each vote option received
+// along with the number of tickets that voted.
+func TallyVotes(sv StartVote, votes []CastVote) ([]VoteOptionResult, uint64, error) {
+	results := make([]VoteOptionResult, 0, len(sv.Vote.Options))
+	for _, v := range sv.Vote.Options {
+		results = append(results, VoteOptionResult{
+			ID:          v.Id,
+			Description: v.Description,
+			Bits:        v.Bits,
+		})
+	}
+
+	for _, v := range votes {
+		bits, err := strconv.ParseUint(v.VoteBit, 16, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid vote bit %v: %v",
+				v.Ticket, err)
+		}
+		for k := range results {
+			if VoteOptionSelected(sv.Vote.Type, bits, results[k].Bits) {
+				results[k].Votes++
+			}
+		}
+	}
+
+	return results, uint64(len(votes)), nil
+}
+
 // VoteSummary requests a summary of a proposal vote. This includes certain
 // voting period parameters and a summary of the vote results.
 type VoteSummary struct {
@@ -390,11 +849,18 @@ type VoteOptionResult struct {
 // voting period parameters as well as a summary of the vote results.
 type VoteSummaryReply struct {
 	Authorized          bool               `json:"authorized"`          // Vote is authorized
+	Type                VoteT              `json:"type"`                // Vote type
 	EndHeight           string             `json:"endheight"`           // End block height
 	EligibleTicketCount int                `json:"eligibleticketcount"` // Number of eligible tickets
 	QuorumPercentage    uint32             `json:"quorumpercentage"`    // Percent of eligible votes required for quorum
 	PassPercentage      uint32             `json:"passpercentage"`      // Percent of total votes required to pass
+	TotalVotes          uint64             `json:"totalvotes"`          // Number of tickets that voted
 	Results             []VoteOptionResult `json:"results"`             // Vote results
+	RunoffTokens        []string           `json:"runofftokens"`        // Tokens of all proposals in a runoff vote
+	Cancelled           bool               `json:"cancelled"`           // Vote has been cancelled
+	CancelReason        string             `json:"cancelreason"`        // Reason the vote was cancelled
+	Scheduled           bool               `json:"scheduled"`           // Vote is scheduled to start
+	StartHeight         uint32             `json:"startheight"`         // Height at which a scheduled vote starts
 }
 
 // EncodeVoteSummaryReply encodes VoteSummary into a JSON byte slice.
@@ -414,6 +880,117 @@ func DecodeVoteSummaryReply(payload []byte) (*VoteSummaryReply, error) {
 	return &v, nil
 }
 
+// VoteOptionSelected returns whether a cast vote with the provided vote bits
+// counts towards the vote option with the provided option bits.  Approval
+// votes may select several options at once; all other vote types select
+// exactly one option.
+func VoteOptionSelected(t VoteT, voteBits, optionBits uint64) bool {
+	if t == VoteTypeApproval {
+		return optionBits != 0 && voteBits&optionBits == optionBits
+	}
+	return voteBits == optionBits
+}
+
+// VoteWinner applies the tally rules of the vote type to the vote results and
+// returns the ID of the winning vote option.  An empty string is returned
+// when the vote did not produce a winner, which is the case when the quorum
+// was not met, the pass percentage was not met or, for plurality votes, when
+// the leading options are tied.  totalVotes is the number of tickets that
+// voted.  The winner of a runoff vote depends on the results of all
+// proposals in the runoff and must be determined using RunoffWinner.
+func VoteWinner(t VoteT, quorumPercentage, passPercentage uint32, eligibleTickets int, totalVotes uint64, results []VoteOptionResult) string {
+	quorum := uint64(float64(quorumPercentage) / 100 * float64(eligibleTickets))
+	pass := uint64(float64(passPercentage) / 100 * float64(totalVotes))
+	if totalVotes < quorum {
+		return ""
+	}
+
+	switch t {
+	case VoteTypeStandard:
+		for _, v := range results {
+			if v.ID == VoteOptionIDApprove && v.Votes >= pass {
+				return v.ID
+			}
+		}
+		return ""
+
+	case VoteTypeMultipleChoice, VoteTypeApproval:
+		var (
+			winner string
+			most   uint64
+			tied   bool
+		)
+		for _, v := range results {
+			switch {
+			case v.Votes > most:
+				winner = v.ID
+				most = v.Votes
+				tied = false
+			case v.Votes == most:
+				tied = true
+			}
+		}
+		if tied || most == 0 || most < pass {
+			return ""
+		}
+		return winner
+	}
+
+	return ""
+}
+
+// RunoffWinner applies the runoff tally rules to the vote results of all
+// proposals in a runoff vote and returns the token of the winning proposal.
+// The quorum is measured against the votes cast across the entire runoff.  A
+// proposal is only eligible to win when its approve votes meet the pass
+// percentage of the votes cast on that proposal.  The eligible proposal with
+// the most approve votes wins.  An empty string is returned when there is no
+// winner, including when the leading proposals are tied.
+func RunoffWinner(quorumPercentage, passPercentage uint32, eligibleTickets int, results map[string][]VoteOptionResult) string {
+	var total uint64
+	for _, r := range results {
+		for _, v := range r {
+			total += v.Votes
+		}
+	}
+	quorum := uint64(float64(quorumPercentage) / 100 * float64(eligibleTickets))
+	if total < quorum {
+		return ""
+	}
+
+	var (
+		winner string
+		most   uint64
+		tied   bool
+	)
+	for token, r := range results {
+		var votes, approve uint64
+		for _, v := range r {
+			votes += v.Votes
+			if v.ID == VoteOptionIDApprove {
+				approve = v.Votes
+			}
+		}
+		pass := uint64(float64(passPercentage) / 100 * float64(votes))
+		if approve == 0 || approve < pass {
+			continue
+		}
+		switch {
+		case approve > most:
+			winner = token
+			most = approve
+			tied = false
+		case approve == most:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+
+	return winner
+}
+
 // Comment is the structure that describes the full server side content.  It
 // includes server side meta-data as well. Note that the receipt is the server
 // side.
@@ -432,6 +1009,17 @@ type Comment struct {
 	TotalVotes  uint64 `json:"totalvotes"`  // Total number of up/down votes
 	ResultVotes int64  `json:"resultvotes"` // Vote score
 	Censored    bool   `json:"censored"`    // Has this comment been censored
+	Deleted     bool   `json:"deleted"`     // Has this comment been deleted by its author
+
+	// Edit metadata generated by bitum plugin
+	Edits         uint32 `json:"edits,omitempty"`         // Number of times the comment was edited
+	EditTimestamp int64  `json:"edittimestamp,omitempty"` // UNIX timestamp of latest edit
+	EditSignature string `json:"editsignature,omitempty"` // Client signature of latest edit
+	EditPublicKey string `json:"editpublickey,omitempty"` // Pubkey used for EditSignature
+	EditReceipt   string `json:"editreceipt,omitempty"`   // Server signature of EditSignature
+
+	// Thread metadata generated by the cache
+	Replies uint64 `json:"replies,omitempty"` // Number of direct replies
 }
 
 // EncodeComment encodes Comment into a JSON byte slice.
@@ -511,6 +1099,10 @@ type LikeComment struct {
 	Signature string `json:"signature"` // Client Signature of Token+CommentID+Action
 	PublicKey string `json:"publickey"` // Pubkey used for Signature
 
+	// Set by politeiawww so that the likes of a user are tallied
+	// together regardless of the key that was used
+	UserID string `json:"userid,omitempty"` // User ID of the author
+
 	// Only used on disk
 	Receipt   string `json:"receipt,omitempty"`   // Signature of Signature
 	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
@@ -568,8 +1160,17 @@ type CensorComment struct {
 	PublicKey string `json:"publickey"` // Pubkey used for signature
 
 	// Generated by bitumplugin
-	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
-	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
+	Receipt     string `json:"receipt,omitempty"`     // Server signature of client signature
+	Timestamp   int64  `json:"timestamp,omitempty"`   // Received UNIX timestamp
+	CommentHash string `json:"commenthash,omitempty"` // Hash of the censored comment text
+}
+
+// CommentHash returns the hex encoded SHA256 digest of a comment text.  It is
+// recorded when a comment is censored since the censored text is redacted from
+// the comment journal that is flushed to git.
+func CommentHash(comment string) string {
+	d := sha256.Sum256([]byte(comment))
+	return hex.EncodeToString(d[:])
 }
 
 // EncodeCensorComment encodes CensorComment into a JSON byte slice.
@@ -608,77 +1209,611 @@ func DecodeCensorCommentReply(payload []byte) (*CensorCommentReply, error) {
 	return &ccr, nil
 }
 
-// GetComment retrieves a single comment.
-type GetComment struct {
-	Token     string `json:"token"`     // Proposal ID
+// EditComment is a journal entry for an edited comment.  The signature and
+// public key must be from the author of the original comment.  The comment
+// journal keeps every revision so the full edit history can be reconstructed.
+type EditComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
 	CommentID string `json:"commentid"` // Comment ID
-}
+	Comment   string `json:"comment"`   // New comment text
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Comment
+	PublicKey string `json:"publickey"` // Pubkey used for signature
 
-// EncodeGetComment encodes a GetComment into a JSON byte slice.
-func EncodeGetComment(gc GetComment) ([]byte, error) {
-	return json.Marshal(gc)
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
 }
 
-// DecodeGetComment decodes a JSON byte slice into a GetComment.
-func DecodeGetComment(payload []byte) (*GetComment, error) {
-	var gc GetComment
+// EncodeEditComment encodes EditComment into a JSON byte slice.
+func EncodeEditComment(ec EditComment) ([]byte, error) {
+	return json.Marshal(ec)
+}
 
-	err := json.Unmarshal(payload, &gc)
+// DecodeEditComment decodes a JSON byte slice into a EditComment.
+func DecodeEditComment(payload []byte) (*EditComment, error) {
+	var ec EditComment
+	err := json.Unmarshal(payload, &ec)
 	if err != nil {
 		return nil, err
 	}
-
-	return &gc, nil
+	return &ec, nil
 }
 
-// GetCommentReply returns the provided comment.
-type GetCommentReply struct {
-	Comment Comment `json:"comment"` // Comment
+// EditCommentReply returns the receipt for the edit action.  The receipt is
+// the server side signature of EditComment.Signature.
+type EditCommentReply struct {
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 }
 
-// EncodeGetCommentReply encodes a GetCommentReply into a JSON byte slice.
-func EncodeGetCommentReply(gcr GetCommentReply) ([]byte, error) {
-	return json.Marshal(gcr)
+// EncodeEditCommentReply encodes EditCommentReply into a JSON byte slice.
+func EncodeEditCommentReply(ecr EditCommentReply) ([]byte, error) {
+	return json.Marshal(ecr)
 }
 
-// DecodeGetCommentReply decodes a JSON byte slice into a GetCommentReply.
-func DecodeGetCommentReply(payload []byte) (*GetCommentReply, error) {
-	var gcr GetCommentReply
-
-	err := json.Unmarshal(payload, &gcr)
+// DecodeEditCommentReply decodes a JSON byte slice into a EditCommentReply.
+func DecodeEditCommentReply(payload []byte) (*EditCommentReply, error) {
+	var ecr EditCommentReply
+	err := json.Unmarshal(payload, &ecr)
 	if err != nil {
 		return nil, err
 	}
-
-	return &gcr, nil
+	return &ecr, nil
 }
 
-// GetComments retrieve all comments for a given proposal. This call returns
-// the cooked comments; deleted/censored comments are not returned.
-type GetComments struct {
-	Token string `json:"token"` // Proposal ID
-}
+// DeleteComment is a journal entry for a comment that was deleted by its
+// author.  The comment text is removed but the comment is kept in place so
+// that replies, signatures and receipts remain intact.  The signature and
+// public key must be from the author of the comment.
+type DeleteComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Signature string `json:"signature"` // Client signature of Token+CommentID
+	PublicKey string `json:"publickey"` // Pubkey used for signature
 
-// EncodeGetComments encodes GetCommentsReply into a JSON byte slice.
-func EncodeGetComments(gc GetComments) ([]byte, error) {
-	return json.Marshal(gc)
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
 }
 
-// DecodeGetComments decodes a JSON byte slice into a GetComments.
-func DecodeGetComments(payload []byte) (*GetComments, error) {
-	var gc GetComments
+// EncodeDeleteComment encodes DeleteComment into a JSON byte slice.
+func EncodeDeleteComment(dc DeleteComment) ([]byte, error) {
+	return json.Marshal(dc)
+}
 
-	err := json.Unmarshal(payload, &gc)
+// DecodeDeleteComment decodes a JSON byte slice into a DeleteComment.
+func DecodeDeleteComment(payload []byte) (*DeleteComment, error) {
+	var dc DeleteComment
+	err := json.Unmarshal(payload, &dc)
 	if err != nil {
 		return nil, err
 	}
+	return &dc, nil
+}
 
-	return &gc, nil
+// DeleteCommentReply returns the receipt for the delete action.  The receipt
+// is the server side signature of DeleteComment.Signature.
+type DeleteCommentReply struct {
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 }
 
-// GetCommentsReply returns the provided number of comments.
+// EncodeDeleteCommentReply encodes DeleteCommentReply into a JSON byte slice.
+func EncodeDeleteCommentReply(dcr DeleteCommentReply) ([]byte, error) {
+	return json.Marshal(dcr)
+}
+
+// DecodeDeleteCommentReply decodes a JSON byte slice into a
+// DeleteCommentReply.
+func DecodeDeleteCommentReply(payload []byte) (*DeleteCommentReply, error) {
+	var dcr DeleteCommentReply
+	err := json.Unmarshal(payload, &dcr)
+	if err != nil {
+		return nil, err
+	}
+	return &dcr, nil
+}
+
+// CommentRevisions retrieves the full revision history of a comment.
+type CommentRevisions struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+}
+
+// EncodeCommentRevisions encodes CommentRevisions into a JSON byte slice.
+func EncodeCommentRevisions(cr CommentRevisions) ([]byte, error) {
+	return json.Marshal(cr)
+}
+
+// DecodeCommentRevisions decodes a JSON byte slice into a CommentRevisions.
+func DecodeCommentRevisions(payload []byte) (*CommentRevisions, error) {
+	var cr CommentRevisions
+	err := json.Unmarshal(payload, &cr)
+	if err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// CommentRevisionsReply returns the original comment along with every edit
+// that was made to it, ordered from oldest to newest.
+type CommentRevisionsReply struct {
+	Comment Comment       `json:"comment"` // Original comment
+	Edits   []EditComment `json:"edits"`   // Edits, oldest first
+}
+
+// EncodeCommentRevisionsReply encodes CommentRevisionsReply into a JSON byte
+// slice.
+func EncodeCommentRevisionsReply(crr CommentRevisionsReply) ([]byte, error) {
+	return json.Marshal(crr)
+}
+
+// DecodeCommentRevisionsReply decodes a JSON byte slice into a
+// CommentRevisionsReply.
+func DecodeCommentRevisionsReply(payload []byte) (*CommentRevisionsReply, error) {
+	var crr CommentRevisionsReply
+	err := json.Unmarshal(payload, &crr)
+	if err != nil {
+		return nil, err
+	}
+	return &crr, nil
+}
+
+// FlagComment is a journal entry for a user flagging a comment for moderator
+// attention.  Flags remain on a comment until they are dismissed by an admin.
+type FlagComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason category
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
+}
+
+// EncodeFlagComment encodes FlagComment into a JSON byte slice.
+func EncodeFlagComment(fc FlagComment) ([]byte, error) {
+	return json.Marshal(fc)
+}
+
+// DecodeFlagComment decodes a JSON byte slice into a FlagComment.
+func DecodeFlagComment(payload []byte) (*FlagComment, error) {
+	var fc FlagComment
+	err := json.Unmarshal(payload, &fc)
+	if err != nil {
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// FlagCommentReply returns the receipt for the flag action.  The receipt is
+// the server side signature of FlagComment.Signature.
+type FlagCommentReply struct {
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+}
+
+// EncodeFlagCommentReply encodes FlagCommentReply into a JSON byte slice.
+func EncodeFlagCommentReply(fcr FlagCommentReply) ([]byte, error) {
+	return json.Marshal(fcr)
+}
+
+// DecodeFlagCommentReply decodes a JSON byte slice into a FlagCommentReply.
+func DecodeFlagCommentReply(payload []byte) (*FlagCommentReply, error) {
+	var fcr FlagCommentReply
+	err := json.Unmarshal(payload, &fcr)
+	if err != nil {
+		return nil, err
+	}
+	return &fcr, nil
+}
+
+// DismissCommentFlags is a journal entry for an admin dismissing all of the
+// flags on a comment without censoring it.  The signature and public key are
+// from the admin.
+type DismissCommentFlags struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason comment flags were dismissed
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
+}
+
+// EncodeDismissCommentFlags encodes DismissCommentFlags into a JSON byte
+// slice.
+func EncodeDismissCommentFlags(dcf DismissCommentFlags) ([]byte, error) {
+	return json.Marshal(dcf)
+}
+
+// DecodeDismissCommentFlags decodes a JSON byte slice into a
+// DismissCommentFlags.
+func DecodeDismissCommentFlags(payload []byte) (*DismissCommentFlags, error) {
+	var dcf DismissCommentFlags
+	err := json.Unmarshal(payload, &dcf)
+	if err != nil {
+		return nil, err
+	}
+	return &dcf, nil
+}
+
+// DismissCommentFlagsReply returns the receipt for the dismiss action.  The
+// receipt is the server side signature of DismissCommentFlags.Signature.
+type DismissCommentFlagsReply struct {
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+}
+
+// EncodeDismissCommentFlagsReply encodes DismissCommentFlagsReply into a JSON
+// byte slice.
+func EncodeDismissCommentFlagsReply(dcfr DismissCommentFlagsReply) ([]byte, error) {
+	return json.Marshal(dcfr)
+}
+
+// DecodeDismissCommentFlagsReply decodes a JSON byte slice into a
+// DismissCommentFlagsReply.
+func DecodeDismissCommentFlagsReply(payload []byte) (*DismissCommentFlagsReply, error) {
+	var dcfr DismissCommentFlagsReply
+	err := json.Unmarshal(payload, &dcfr)
+	if err != nil {
+		return nil, err
+	}
+	return &dcfr, nil
+}
+
+// CommentFlags is used to retrieve the flags that have not been dismissed for
+// a single comment.
+type CommentFlags struct {
+	Token     string `json:"token"`     // Censorship token
+	CommentID string `json:"commentid"` // Comment ID
+}
+
+// EncodeCommentFlags encodes CommentFlags into a JSON byte slice.
+func EncodeCommentFlags(cf CommentFlags) ([]byte, error) {
+	return json.Marshal(cf)
+}
+
+// DecodeCommentFlags decodes a JSON byte slice into a CommentFlags.
+func DecodeCommentFlags(payload []byte) (*CommentFlags, error) {
+	var cf CommentFlags
+	err := json.Unmarshal(payload, &cf)
+	if err != nil {
+		return nil, err
+	}
+	return &cf, nil
+}
+
+// CommentFlagsReply is the reply to CommentFlags and returns the flags of the
+// specified comment in chronological order.
+type CommentFlagsReply struct {
+	Flags []FlagComment `json:"flags"`
+}
+
+// EncodeCommentFlagsReply encodes CommentFlagsReply into a JSON byte slice.
+func EncodeCommentFlagsReply(cfr CommentFlagsReply) ([]byte, error) {
+	return json.Marshal(cfr)
+}
+
+// DecodeCommentFlagsReply decodes a JSON byte slice into a CommentFlagsReply.
+func DecodeCommentFlagsReply(payload []byte) (*CommentFlagsReply, error) {
+	var cfr CommentFlagsReply
+	err := json.Unmarshal(payload, &cfr)
+	if err != nil {
+		return nil, err
+	}
+	return &cfr, nil
+}
+
+// FlaggedComment is a comment along with the flags on it that have not been
+// dismissed.
+type FlaggedComment struct {
+	Comment Comment       `json:"comment"` // Flagged comment
+	Flags   []FlagComment `json:"flags"`   // Flags in chronological order
+}
+
+// FlaggedComments is used to retrieve the comment moderation queue.
+type FlaggedComments struct{}
+
+// EncodeFlaggedComments encodes FlaggedComments into a JSON byte slice.
+func EncodeFlaggedComments(fc FlaggedComments) ([]byte, error) {
+	return json.Marshal(fc)
+}
+
+// DecodeFlaggedComments decodes a JSON byte slice into a FlaggedComments.
+func DecodeFlaggedComments(payload []byte) (*FlaggedComments, error) {
+	var fc FlaggedComments
+	err := json.Unmarshal(payload, &fc)
+	if err != nil {
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// FlaggedCommentsReply is the reply to the FlaggedComments command and
+// returns all comments that have flags which have not been dismissed.
+// Comments that have been censored or deleted are left out.  The comments
+// are sorted by number of flags, most flagged first.
+type FlaggedCommentsReply struct {
+	Comments []FlaggedComment `json:"comments"`
+}
+
+// EncodeFlaggedCommentsReply encodes FlaggedCommentsReply into a JSON byte
+// slice.
+func EncodeFlaggedCommentsReply(fcr FlaggedCommentsReply) ([]byte, error) {
+	return json.Marshal(fcr)
+}
+
+// DecodeFlaggedCommentsReply decodes a JSON byte slice into a
+// FlaggedCommentsReply.
+func DecodeFlaggedCommentsReply(payload []byte) (*FlaggedCommentsReply, error) {
+	var fcr FlaggedCommentsReply
+	err := json.Unmarshal(payload, &fcr)
+	if err != nil {
+		return nil, err
+	}
+	return &fcr, nil
+}
+
+// AppealCensorship is a journal entry for the author of a censored comment
+// appealing the censorship.  A censorship can only be appealed once.  The
+// signature and public key must be from the author of the comment.
+type AppealCensorship struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason for the appeal
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
+}
+
+// EncodeAppealCensorship encodes AppealCensorship into a JSON byte slice.
+func EncodeAppealCensorship(ac AppealCensorship) ([]byte, error) {
+	return json.Marshal(ac)
+}
+
+// DecodeAppealCensorship decodes a JSON byte slice into a AppealCensorship.
+func DecodeAppealCensorship(payload []byte) (*AppealCensorship, error) {
+	var ac AppealCensorship
+	err := json.Unmarshal(payload, &ac)
+	if err != nil {
+		return nil, err
+	}
+	return &ac, nil
+}
+
+// AppealCensorshipReply returns the receipt for the appeal.  The receipt is
+// the server side signature of AppealCensorship.Signature.
+type AppealCensorshipReply struct {
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+}
+
+// EncodeAppealCensorshipReply encodes AppealCensorshipReply into a JSON byte
+// slice.
+func EncodeAppealCensorshipReply(acr AppealCensorshipReply) ([]byte, error) {
+	return json.Marshal(acr)
+}
+
+// DecodeAppealCensorshipReply decodes a JSON byte slice into a
+// AppealCensorshipReply.
+func DecodeAppealCensorshipReply(payload []byte) (*AppealCensorshipReply, error) {
+	var acr AppealCensorshipReply
+	err := json.Unmarshal(payload, &acr)
+	if err != nil {
+		return nil, err
+	}
+	return &acr, nil
+}
+
+// ResolveAppeal is a journal entry for an admin resolving a censorship
+// appeal.  The appeal is either upheld, in which case the comment remains
+// censored, or the comment is restored.  The signature and public key must
+// be from an admin other than the one that censored the comment.
+type ResolveAppeal struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Action    string `json:"action"`    // Uphold or restore
+	Reason    string `json:"reason"`    // Reason for the resolution
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Action+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+
+	// Generated by bitumplugin
+	Receipt   string `json:"receipt,omitempty"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp,omitempty"` // Received UNIX timestamp
+}
+
+// EncodeResolveAppeal encodes ResolveAppeal into a JSON byte slice.
+func EncodeResolveAppeal(ra ResolveAppeal) ([]byte, error) {
+	return json.Marshal(ra)
+}
+
+// DecodeResolveAppeal decodes a JSON byte slice into a ResolveAppeal.
+func DecodeResolveAppeal(payload []byte) (*ResolveAppeal, error) {
+	var ra ResolveAppeal
+	err := json.Unmarshal(payload, &ra)
+	if err != nil {
+		return nil, err
+	}
+	return &ra, nil
+}
+
+// ResolveAppealReply returns the receipt for the resolution.  The receipt is
+// the server side signature of ResolveAppeal.Signature.  Comment is the
+// restored comment and is only set when the comment was restored.
+type ResolveAppealReply struct {
+	Receipt   string   `json:"receipt"`           // Server signature of client signature
+	Timestamp int64    `json:"timestamp"`         // Received UNIX timestamp
+	Comment   *Comment `json:"comment,omitempty"` // Restored comment
+}
+
+// EncodeResolveAppealReply encodes ResolveAppealReply into a JSON byte slice.
+func EncodeResolveAppealReply(rar ResolveAppealReply) ([]byte, error) {
+	return json.Marshal(rar)
+}
+
+// DecodeResolveAppealReply decodes a JSON byte slice into a
+// ResolveAppealReply.
+func DecodeResolveAppealReply(payload []byte) (*ResolveAppealReply, error) {
+	var rar ResolveAppealReply
+	err := json.Unmarshal(payload, &rar)
+	if err != nil {
+		return nil, err
+	}
+	return &rar, nil
+}
+
+// CensorshipAppeals retrieves the censorship appeals that have not been
+// resolved.  The appeals can be limited to a single comment by setting the
+// token and comment ID.  Upheld appeals are only returned when Resolved is
+// set.  The censored comment text is not kept in the cache so this command
+// is served by politeiad.
+type CensorshipAppeals struct {
+	Token     string `json:"token,omitempty"`     // Proposal censorship token
+	CommentID string `json:"commentid,omitempty"` // Comment ID
+	Resolved  bool   `json:"resolved,omitempty"`  // Include upheld appeals
+}
+
+// EncodeCensorshipAppeals encodes CensorshipAppeals into a JSON byte slice.
+func EncodeCensorshipAppeals(ca CensorshipAppeals) ([]byte, error) {
+	return json.Marshal(ca)
+}
+
+// DecodeCensorshipAppeals decodes a JSON byte slice into a
+// CensorshipAppeals.
+func DecodeCensorshipAppeals(payload []byte) (*CensorshipAppeals, error) {
+	var ca CensorshipAppeals
+	err := json.Unmarshal(payload, &ca)
+	if err != nil {
+		return nil, err
+	}
+	return &ca, nil
+}
+
+// CensorshipAppeal is an appeal along with the comment as it was before it
+// was censored, the edits that were made to the comment before it was
+// censored and the censor record.
+type CensorshipAppeal struct {
+	Comment  Comment          `json:"comment"`  // Comment before it was censored
+	Edits    []EditComment    `json:"edits"`    // Edits before it was censored
+	Censor   CensorComment    `json:"censor"`   // Censor record
+	Appeal   AppealCensorship `json:"appeal"`   // Appeal by the comment author
+	Resolved bool             `json:"resolved"` // Whether the appeal was upheld
+}
+
+// CensorshipAppealsReply is the reply to the CensorshipAppeals command.  The
+// appeals are sorted oldest first.
+type CensorshipAppealsReply struct {
+	Appeals []CensorshipAppeal `json:"appeals"`
+}
+
+// EncodeCensorshipAppealsReply encodes CensorshipAppealsReply into a JSON
+// byte slice.
+func EncodeCensorshipAppealsReply(car CensorshipAppealsReply) ([]byte, error) {
+	return json.Marshal(car)
+}
+
+// DecodeCensorshipAppealsReply decodes a JSON byte slice into a
+// CensorshipAppealsReply.
+func DecodeCensorshipAppealsReply(payload []byte) (*CensorshipAppealsReply, error) {
+	var car CensorshipAppealsReply
+	err := json.Unmarshal(payload, &car)
+	if err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+// GetComment retrieves a single comment.
+type GetComment struct {
+	Token     string `json:"token"`     // Proposal ID
+	CommentID string `json:"commentid"` // Comment ID
+}
+
+// EncodeGetComment encodes a GetComment into a JSON byte slice.
+func EncodeGetComment(gc GetComment) ([]byte, error) {
+	return json.Marshal(gc)
+}
+
+// DecodeGetComment decodes a JSON byte slice into a GetComment.
+func DecodeGetComment(payload []byte) (*GetComment, error) {
+	var gc GetComment
+
+	err := json.Unmarshal(payload, &gc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gc, nil
+}
+
+// GetCommentReply returns the provided comment.
+type GetCommentReply struct {
+	Comment Comment `json:"comment"` // Comment
+}
+
+// EncodeGetCommentReply encodes a GetCommentReply into a JSON byte slice.
+func EncodeGetCommentReply(gcr GetCommentReply) ([]byte, error) {
+	return json.Marshal(gcr)
+}
+
+// DecodeGetCommentReply decodes a JSON byte slice into a GetCommentReply.
+func DecodeGetCommentReply(payload []byte) (*GetCommentReply, error) {
+	var gcr GetCommentReply
+
+	err := json.Unmarshal(payload, &gcr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcr, nil
+}
+
+// GetComments retrieve all comments for a given proposal. This call returns
+// the cooked comments; deleted/censored comments are not returned.
+//
+// The comments can optionally be sorted and paginated.  Pagination is done on
+// root comments; the replies to the root comments of a page are returned
+// along with them.  MaxDepth limits the depth of the returned comments, where
+// root comments have a depth of 1.  A zero Limit or MaxDepth means no limit.
+type GetComments struct {
+	Token    string `json:"token"`              // Proposal ID
+	Sort     string `json:"sort,omitempty"`     // Sort order
+	Offset   uint32 `json:"offset,omitempty"`   // Number of root comments to skip
+	Limit    uint32 `json:"limit,omitempty"`    // Maximum number of root comments
+	MaxDepth uint32 `json:"maxdepth,omitempty"` // Maximum comment depth
+}
+
+// EncodeGetComments encodes GetCommentsReply into a JSON byte slice.
+func EncodeGetComments(gc GetComments) ([]byte, error) {
+	return json.Marshal(gc)
+}
+
+// DecodeGetComments decodes a JSON byte slice into a GetComments.
+func DecodeGetComments(payload []byte) (*GetComments, error) {
+	var gc GetComments
+
+	err := json.Unmarshal(payload, &gc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gc, nil
+}
+
+// GetCommentsReply returns the provided number of comments.  NextOffset is
+// the offset of the next page of root comments and is zero when there are no
+// more root comments.
 type GetCommentsReply struct {
-	Comments []Comment `json:"comments"` // Comments
+	Comments   []Comment `json:"comments"`             // Comments
+	NextOffset uint32    `json:"nextoffset,omitempty"` // Offset of next page
 }
 
 // EncodeGetCommentsReply encodes GetCommentsReply into a JSON byte slice.
@@ -698,6 +1833,118 @@ func DecodeGetCommentsReply(payload []byte) (*GetCommentsReply, error) {
 	return &gcr, nil
 }
 
+// UserComments is used to retrieve the comments made by a user across all
+// records.  A user may have multiple identities so all of the user's public
+// keys must be provided.  Censored comments are only returned when
+// IncludeCensored is set.  The text of censored comments is not kept by the
+// cache so they are returned with a blank comment.
+type UserComments struct {
+	PublicKeys      []string `json:"publickeys"`      // User public keys
+	IncludeCensored bool     `json:"includecensored"` // Return censored comments
+	Offset          uint32   `json:"offset"`          // Number of comments to skip
+	Limit           uint32   `json:"limit"`           // Max number of comments
+}
+
+// EncodeUserComments encodes UserComments into a JSON byte slice.
+func EncodeUserComments(uc UserComments) ([]byte, error) {
+	return json.Marshal(uc)
+}
+
+// DecodeUserComments decodes a JSON byte slice into a UserComments.
+func DecodeUserComments(payload []byte) (*UserComments, error) {
+	var uc UserComments
+
+	err := json.Unmarshal(payload, &uc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uc, nil
+}
+
+// UserCommentsReply returns the requested page of a user's comments, newest
+// first.  NextOffset is zero when there are no more comments.
+type UserCommentsReply struct {
+	Comments   []Comment `json:"comments"`             // Comments
+	NextOffset uint32    `json:"nextoffset,omitempty"` // Offset of next page
+}
+
+// EncodeUserCommentsReply encodes UserCommentsReply into a JSON byte slice.
+func EncodeUserCommentsReply(ucr UserCommentsReply) ([]byte, error) {
+	return json.Marshal(ucr)
+}
+
+// DecodeUserCommentsReply decodes a JSON byte slice into a
+// UserCommentsReply.
+func DecodeUserCommentsReply(payload []byte) (*UserCommentsReply, error) {
+	var ucr UserCommentsReply
+
+	err := json.Unmarshal(payload, &ucr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ucr, nil
+}
+
+// UnreadComments is used to count the comments that a user has not read yet.
+// AccessTimes contains the records to count comments for along with the UNIX
+// timestamp of when the user last read the comments of each record.  Comments
+// made by the user, which are identified by the user's public keys, and
+// comments that have been censored or deleted are not counted.
+type UnreadComments struct {
+	AccessTimes map[string]int64 `json:"accesstimes"` // [token]accessTime
+	PublicKeys  []string         `json:"publickeys"`  // User public keys
+}
+
+// EncodeUnreadComments encodes UnreadComments into a JSON byte slice.
+func EncodeUnreadComments(uc UnreadComments) ([]byte, error) {
+	return json.Marshal(uc)
+}
+
+// DecodeUnreadComments decodes a JSON byte slice into a UnreadComments.
+func DecodeUnreadComments(payload []byte) (*UnreadComments, error) {
+	var uc UnreadComments
+
+	err := json.Unmarshal(payload, &uc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uc, nil
+}
+
+// UnreadComment describes the comments of a record that a user has not read.
+type UnreadComment struct {
+	Count  uint64 `json:"count"`  // Number of unread comments
+	Latest int64  `json:"latest"` // Timestamp of the newest unread comment
+}
+
+// UnreadCommentsReply is the reply to the UnreadComments command.  Records
+// that do not have any unread comments are left out.
+type UnreadCommentsReply struct {
+	Unread map[string]UnreadComment `json:"unread"` // [token]UnreadComment
+}
+
+// EncodeUnreadCommentsReply encodes UnreadCommentsReply into a JSON byte
+// slice.
+func EncodeUnreadCommentsReply(ucr UnreadCommentsReply) ([]byte, error) {
+	return json.Marshal(ucr)
+}
+
+// DecodeUnreadCommentsReply decodes a JSON byte slice into a
+// UnreadCommentsReply.
+func DecodeUnreadCommentsReply(payload []byte) (*UnreadCommentsReply, error) {
+	var ucr UnreadCommentsReply
+
+	err := json.Unmarshal(payload, &ucr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ucr, nil
+}
+
 // CommentLikes is used to retrieve all of the comment likes for a single
 // record comment.
 type CommentLikes struct {
@@ -825,10 +2072,15 @@ type StartVoteTuple struct {
 type InventoryReply struct {
 	Comments             []Comment            `json:"comments"`             // Comments
 	LikeComments         []LikeComment        `json:"likecomments"`         // Like comments
+	CommentFlags         []FlagComment        `json:"commentflags"`         // Comment flags that have not been dismissed
 	AuthorizeVotes       []AuthorizeVote      `json:"authorizevotes"`       // Authorize votes
 	AuthorizeVoteReplies []AuthorizeVoteReply `json:"authorizevotereplies"` // Authorize vote replies
 	StartVoteTuples      []StartVoteTuple     `json:"startvotetuples"`      // Start vote tuples
 	CastVotes            []CastVote           `json:"castvotes"`            // Cast votes
+	CastVoteReceipts     []string             `json:"castvotereceipts"`     // Receipts of CastVotes, in the same order
+	CastVoteHeights      []uint32             `json:"castvoteheights"`      // Block heights of CastVotes, in the same order
+	CancelVotes          []CancelVote         `json:"cancelvotes"`          // Vote cancellations
+	ScheduledVotes       []StartVote          `json:"scheduledvotes"`       // Votes that are scheduled to start
 }
 
 // EncodeInventoryReply encodes a InventoryReply into a JSON byte slice.
@@ -882,6 +2134,8 @@ type TokenInventoryReply struct {
 	Approved  []string `json:"approved"`  // Tokens of records that have been approved by a vote
 	Rejected  []string `json:"rejected"`  // Tokens of records that have been rejected by a vote
 	Abandoned []string `json:"abandoned"` // Tokens of records that have been abandoned
+	Cancelled []string `json:"cancelled"` // Tokens of records whose vote has been cancelled
+	Scheduled []string `json:"scheduled"` // Tokens of records whose vote is scheduled to start
 }
 
 // EncodeTokenInventoryReply encodes a TokenInventoryReply into a JSON byte
@@ -946,3 +2200,171 @@ func DecodeLoadVoteResultsReply(payload []byte) (*LoadVoteResultsReply, error) {
 
 	return &reply, nil
 }
+
+// JournalEntryDigest returns the SHA256 digest of a plugin journal entry.  The
+// entry does not include the trailing newline.
+func JournalEntryDigest(entry string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(entry))
+}
+
+// journalMerkleParent returns the parent of two journal merkle tree nodes.
+func journalMerkleParent(left, right [sha256.Size]byte) [sha256.Size]byte {
+	var b [sha256.Size * 2]byte
+	copy(b[:sha256.Size], left[:])
+	copy(b[sha256.Size:], right[:])
+	return sha256.Sum256(b[:])
+}
+
+// journalMerkleLevel returns the parents of the passed in journal merkle tree
+// nodes.  The last node is paired with itself when there is an odd number of
+// nodes.
+func journalMerkleLevel(nodes [][sha256.Size]byte) [][sha256.Size]byte {
+	parents := make([][sha256.Size]byte, 0, (len(nodes)+1)/2)
+	for i := 0; i < len(nodes); i += 2 {
+		right := nodes[i]
+		if i+1 < len(nodes) {
+			right = nodes[i+1]
+		}
+		parents = append(parents, journalMerkleParent(nodes[i], right))
+	}
+	return parents
+}
+
+// JournalHead returns the merkle root of the passed in journal entry digests.
+// Unlike the merkle root of a record the digests are not sorted so that the
+// position of an entry in the journal is part of its proof.
+func JournalHead(digests [][sha256.Size]byte) [sha256.Size]byte {
+	if len(digests) == 0 {
+		return [sha256.Size]byte{}
+	}
+	nodes := digests
+	for len(nodes) > 1 {
+		nodes = journalMerkleLevel(nodes)
+	}
+	return nodes[0]
+}
+
+// JournalMerklePath returns the sibling digests that lead from the journal
+// entry digest at the passed in index up to the journal head.
+func JournalMerklePath(digests [][sha256.Size]byte, index int) [][sha256.Size]byte {
+	path := make([][sha256.Size]byte, 0, 32)
+	nodes := digests
+	for len(nodes) > 1 {
+		sibling := index ^ 1
+		if sibling >= len(nodes) {
+			sibling = index
+		}
+		path = append(path, nodes[sibling])
+		nodes = journalMerkleLevel(nodes)
+		index /= 2
+	}
+	return path
+}
+
+// JournalProof requests the proof that a comment or a cast vote was included
+// in a plugin journal head that was anchored in bitumtime.  Either CommentID
+// or Ticket must be set.  The proof of a ticket is for its latest vote.
+type JournalProof struct {
+	Token     string `json:"token"`               // Proposal censorship token
+	CommentID string `json:"commentid,omitempty"` // Comment ID
+	Ticket    string `json:"ticket,omitempty"`    // Ticket hash
+}
+
+// EncodeJournalProof encodes JournalProof into a JSON byte slice.
+func EncodeJournalProof(jp JournalProof) ([]byte, error) {
+	return json.Marshal(jp)
+}
+
+// DecodeJournalProof decodes a JSON byte slice into a JournalProof.
+func DecodeJournalProof(payload []byte) (*JournalProof, error) {
+	var jp JournalProof
+
+	err := json.Unmarshal(payload, &jp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jp, nil
+}
+
+// JournalProofReply is the proof that a journal entry existed when the
+// journal head that contains it was anchored.  The journal head is the merkle
+// root of the digests of the first Entries journal entries and MerklePath
+// leads from the digest of Entry up to it.  Each journal head is anchored as
+// an individual digest so it can be verified with bitumtime directly.  Anchor
+// is empty when the entry has not been anchored yet and ChainTimestamp and
+// Transaction are only set once the anchor has been confirmed.
+type JournalProofReply struct {
+	Journal        string   `json:"journal"`        // Journal filename
+	Entry          string   `json:"entry"`          // Journal entry
+	Index          uint64   `json:"index"`          // Position of the entry in the journal
+	Entries        uint64   `json:"entries"`        // Number of entries in the journal head
+	JournalHead    string   `json:"journalhead"`    // Merkle root of the journal head
+	MerklePath     []string `json:"merklepath"`     // Sibling digests from entry to journal head
+	Anchor         string   `json:"anchor"`         // Merkle root of the anchor
+	Timestamp      int64    `json:"timestamp"`      // Time the anchor was dropped
+	ChainTimestamp int64    `json:"chaintimestamp"` // Block timestamp of the anchor
+	Transaction    string   `json:"transaction"`    // Anchor transaction
+}
+
+// EncodeJournalProofReply encodes JournalProofReply into a JSON byte slice.
+func EncodeJournalProofReply(jpr JournalProofReply) ([]byte, error) {
+	return json.Marshal(jpr)
+}
+
+// DecodeJournalProofReply decodes a JSON byte slice into a JournalProofReply.
+func DecodeJournalProofReply(payload []byte) (*JournalProofReply, error) {
+	var jpr JournalProofReply
+
+	err := json.Unmarshal(payload, &jpr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jpr, nil
+}
+
+// VerifyJournalProof verifies that the merkle path of the passed in proof
+// leads from its journal entry to its journal head.  It does not verify that
+// the journal head was anchored in bitumtime.
+func VerifyJournalProof(jpr JournalProofReply) error {
+	if jpr.Index >= jpr.Entries {
+		return fmt.Errorf("entry index %v out of range %v", jpr.Index,
+			jpr.Entries)
+	}
+
+	// The merkle path has one digest per tree level
+	var depth int
+	for n := jpr.Entries; n > 1; n = (n + 1) / 2 {
+		depth++
+	}
+	if len(jpr.MerklePath) != depth {
+		return fmt.Errorf("invalid merkle path length: got %v, want %v",
+			len(jpr.MerklePath), depth)
+	}
+
+	node := JournalEntryDigest(jpr.Entry)
+	index := jpr.Index
+	for _, v := range jpr.MerklePath {
+		b, err := hex.DecodeString(v)
+		if err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("invalid merkle path digest: %v", v)
+		}
+		var sibling [sha256.Size]byte
+		copy(sibling[:], b)
+		if index%2 == 0 {
+			node = journalMerkleParent(node, sibling)
+		} else {
+			node = journalMerkleParent(sibling, node)
+		}
+		index /= 2
+	}
+
+	head := hex.EncodeToString(node[:])
+	if head != jpr.JournalHead {
+		return fmt.Errorf("invalid journal head: got %v, want %v", head,
+			jpr.JournalHead)
+	}
+
+	return nil
+}
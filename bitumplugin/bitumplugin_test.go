@@ -0,0 +1,274 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bitumplugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestVoteOptionSelected(t *testing.T) {
+	var tests = []struct {
+		name       string
+		voteT      VoteT
+		voteBits   uint64
+		optionBits uint64
+		want       bool
+	}{
+		{"standard match", VoteTypeStandard, 0x02, 0x02, true},
+		{"standard no match", VoteTypeStandard, 0x03, 0x02, false},
+		{"multiple choice match", VoteTypeMultipleChoice, 0x04, 0x04, true},
+		{"approval single", VoteTypeApproval, 0x04, 0x04, true},
+		{"approval several", VoteTypeApproval, 0x05, 0x04, true},
+		{"approval not selected", VoteTypeApproval, 0x05, 0x02, false},
+		{"approval zero option", VoteTypeApproval, 0x05, 0x00, false},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := VoteOptionSelected(v.voteT, v.voteBits, v.optionBits)
+			if got != v.want {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestVoteWinner(t *testing.T) {
+	yesNo := func(yes, no uint64) []VoteOptionResult {
+		return []VoteOptionResult{
+			{ID: "no", Bits: 0x01, Votes: no},
+			{ID: VoteOptionIDApprove, Bits: 0x02, Votes: yes},
+		}
+	}
+	abc := func(a, b, c uint64) []VoteOptionResult {
+		return []VoteOptionResult{
+			{ID: "a", Bits: 0x01, Votes: a},
+			{ID: "b", Bits: 0x02, Votes: b},
+			{ID: "c", Bits: 0x04, Votes: c},
+		}
+	}
+
+	// All test cases use 1000 eligible tickets, a 20% quorum and a
+	// 60% pass percentage unless noted otherwise.
+	var tests = []struct {
+		name    string
+		voteT   VoteT
+		pass    uint32
+		total   uint64
+		results []VoteOptionResult
+		want    string
+	}{
+		{"standard approved", VoteTypeStandard, 60, 300,
+			yesNo(200, 100), VoteOptionIDApprove},
+		{"standard rejected", VoteTypeStandard, 60, 300,
+			yesNo(100, 200), ""},
+		{"standard no quorum", VoteTypeStandard, 60, 150,
+			yesNo(150, 0), ""},
+		{"multiple choice plurality", VoteTypeMultipleChoice, 0, 300,
+			abc(120, 100, 80), "a"},
+		{"multiple choice tie", VoteTypeMultipleChoice, 0, 300,
+			abc(120, 120, 60), ""},
+		{"multiple choice pass not met", VoteTypeMultipleChoice, 60, 300,
+			abc(120, 100, 80), ""},
+		{"multiple choice no quorum", VoteTypeMultipleChoice, 0, 100,
+			abc(50, 30, 20), ""},
+		{"approval most approvals", VoteTypeApproval, 60, 300,
+			abc(250, 280, 10), "b"},
+		{"approval pass not met", VoteTypeApproval, 60, 300,
+			abc(150, 170, 10), ""},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := VoteWinner(v.voteT, 20, v.pass, 1000, v.total,
+				v.results)
+			if got != v.want {
+				t.Errorf("got %q, want %q", got, v.want)
+			}
+		})
+	}
+}
+
+func TestRunoffWinner(t *testing.T) {
+	yesNo := func(yes, no uint64) []VoteOptionResult {
+		return []VoteOptionResult{
+			{ID: "no", Bits: 0x01, Votes: no},
+			{ID: VoteOptionIDApprove, Bits: 0x02, Votes: yes},
+		}
+	}
+
+	// All test cases use 1000 eligible tickets, a 20% quorum and a
+	// 60% pass percentage.
+	var tests = []struct {
+		name    string
+		results map[string][]VoteOptionResult
+		want    string
+	}{
+		{"most approvals", map[string][]VoteOptionResult{
+			"a": yesNo(150, 50),
+			"b": yesNo(120, 30),
+		}, "a"},
+		{"leader fails pass percentage", map[string][]VoteOptionResult{
+			"a": yesNo(150, 150),
+			"b": yesNo(120, 30),
+		}, "b"},
+		{"no quorum", map[string][]VoteOptionResult{
+			"a": yesNo(80, 10),
+			"b": yesNo(60, 10),
+		}, ""},
+		{"quorum across runoff", map[string][]VoteOptionResult{
+			"a": yesNo(100, 10),
+			"b": yesNo(80, 20),
+		}, "a"},
+		{"tie", map[string][]VoteOptionResult{
+			"a": yesNo(150, 50),
+			"b": yesNo(150, 50),
+		}, ""},
+		{"no approvals", map[string][]VoteOptionResult{
+			"a": yesNo(0, 150),
+			"b": yesNo(0, 150),
+		}, ""},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := RunoffWinner(20, 60, 1000, v.results)
+			if got != v.want {
+				t.Errorf("got %q, want %q", got, v.want)
+			}
+		})
+	}
+}
+
+func TestTallyVotes(t *testing.T) {
+	sv := StartVote{
+		Vote: Vote{
+			Type: VoteTypeApproval,
+			Options: []VoteOption{
+				{Id: "a", Bits: 0x01},
+				{Id: "b", Bits: 0x02},
+				{Id: "c", Bits: 0x04},
+			},
+		},
+	}
+	votes := []CastVote{
+		{Ticket: "t1", VoteBit: "1"},
+		{Ticket: "t2", VoteBit: "3"},
+		{Ticket: "t3", VoteBit: "6"},
+	}
+
+	results, total, err := TallyVotes(sv, votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Fatalf("got %v total votes, want 3", total)
+	}
+	want := []uint64{2, 2, 1}
+	for k, v := range results {
+		if v.Votes != want[k] {
+			t.Errorf("option %v: got %v votes, want %v", v.ID,
+				v.Votes, want[k])
+		}
+	}
+
+	_, _, err = TallyVotes(sv, []CastVote{{Ticket: "t4", VoteBit: "x"}})
+	if err == nil {
+		t.Fatalf("expected error for invalid vote bit")
+	}
+}
+
+func TestVoteResultsBundleDigest(t *testing.T) {
+	b := VoteResultsBundle{
+		Version:    VersionVoteResultsBundle,
+		Token:      "token",
+		TotalVotes: 1,
+	}
+	d1, err := VoteResultsBundleDigest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature is not part of the digest
+	b.Signature = "signature"
+	d2, err := VoteResultsBundleDigest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d1) != string(d2) {
+		t.Fatalf("digest depends on signature")
+	}
+
+	// Every other field is
+	b.TotalVotes = 2
+	d3, err := VoteResultsBundleDigest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d1) == string(d3) {
+		t.Fatalf("digest does not cover the bundle")
+	}
+}
+
+func TestJournalProof(t *testing.T) {
+	// Verify the proof of every entry for various journal sizes
+	for n := 1; n <= 9; n++ {
+		entries := make([]string, 0, n)
+		digests := make([][sha256.Size]byte, 0, n)
+		for i := 0; i < n; i++ {
+			e := fmt.Sprintf(`{"version":"1","action":"add"}{"id":%v}`, i)
+			entries = append(entries, e)
+			digests = append(digests, JournalEntryDigest(e))
+		}
+		head := JournalHead(digests)
+
+		for i := 0; i < n; i++ {
+			path := JournalMerklePath(digests, i)
+			jpr := JournalProofReply{
+				Entry:       entries[i],
+				Index:       uint64(i),
+				Entries:     uint64(n),
+				JournalHead: hex.EncodeToString(head[:]),
+				MerklePath:  make([]string, 0, len(path)),
+			}
+			for _, v := range path {
+				jpr.MerklePath = append(jpr.MerklePath,
+					hex.EncodeToString(v[:]))
+			}
+			err := VerifyJournalProof(jpr)
+			if err != nil {
+				t.Fatalf("entries %v index %v: %v", n, i, err)
+			}
+
+			// A different entry must not verify
+			jpr.Entry += " "
+			err = VerifyJournalProof(jpr)
+			if err == nil {
+				t.Fatalf("entries %v index %v: tampered entry "+
+					"verified", n, i)
+			}
+		}
+	}
+
+	// A single entry journal head is the entry digest
+	d := JournalEntryDigest("entry")
+	if JournalHead([][sha256.Size]byte{d}) != d {
+		t.Fatalf("single entry journal head is not the entry digest")
+	}
+
+	// The index must be within the journal head
+	err := VerifyJournalProof(JournalProofReply{
+		Entry:       "entry",
+		Index:       1,
+		Entries:     1,
+		JournalHead: hex.EncodeToString(d[:]),
+	})
+	if err == nil {
+		t.Fatalf("out of range index verified")
+	}
+}
@@ -13,18 +13,19 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bitum-project/bitumd/chaincfg/chainhash"
 	"github.com/bitum-project/bitumd/bitumec/secp256k1"
 	"github.com/bitum-project/bitumd/bitumutil"
 	"github.com/bitum-project/bitumd/wire"
-	bitumdataapi "github.com/bitum-project/bitumdata/api/types"
 	"github.com/bitum-project/politeia/bitumplugin"
 	"github.com/bitum-project/politeia/politeiad/api/v1/identity"
 	"github.com/bitum-project/politeia/politeiad/backend"
@@ -49,9 +50,24 @@ const (
 	journalActionAdd     = "add"     // Add entry
 	journalActionDel     = "del"     // Delete entry
 	journalActionAddLike = "addlike" // Add comment like
+	journalActionCancel  = "cancel"  // Cancel vote
+	journalActionEdit    = "edit"    // Edit comment
+	journalActionDelete  = "delete"  // Delete comment by author
+	journalActionFlag    = "flag"    // Flag comment
+	journalActionDismiss = "dismiss" // Dismiss comment flags
+	journalActionAppeal  = "appeal"  // Appeal comment censorship
+	journalActionResolve = "resolve" // Resolve censorship appeal
 
 	flushRecordVersion = "1" // Version 1 of the flush journal
 
+	// ballotBatchSize is the maximum number of tickets whose
+	// commitment addresses are looked up in a single request.
+	ballotBatchSize = 500
+
+	// ballotLookupWorkers is the maximum number of concurrent
+	// commitment address lookups.
+	ballotLookupWorkers = 4
+
 	// Following are what should be well-known interface hooks
 	PluginPostHookEdit = "postedit" // Hook Post Edit
 
@@ -74,14 +90,23 @@ type FlushRecord struct {
 // journalActionAdd -> Add entry
 // journalActionDel -> Delete entry
 // journalActionAddLike -> Add comment like structure (comments only)
+// journalActionCancel -> Cancel vote structure (ballots only)
+// journalActionEdit -> Edit comment structure (comments only)
+// journalActionDelete -> Delete comment by author structure (comments only)
+// journalActionFlag -> Flag comment structure (comments only)
+// journalActionDismiss -> Dismiss comment flags structure (comments only)
+// journalActionAppeal -> Appeal censorship structure (comments only)
+// journalActionResolve -> Resolve appeal structure (comments only)
 type JournalAction struct {
 	Version string `json:"version"` // Version
 	Action  string `json:"action"`  // Add/Del
 }
 
 type CastVoteJournal struct {
-	CastVote bitumplugin.CastVote `json:"castvote"` // Client side vote
-	Receipt  string                `json:"receipt"`  // Signature of CastVote.Signature
+	CastVote    bitumplugin.CastVote `json:"castvote"`              // Client side vote
+	Receipt     string               `json:"receipt"`               // Signature of CastVote.Signature
+	Address     string               `json:"address,omitempty"`     // Largest commitment address
+	BlockHeight uint32               `json:"blockheight,omitempty"` // Best block height when the vote was cast
 }
 
 func encodeCastVoteJournal(cvj CastVoteJournal) ([]byte, error) {
@@ -104,6 +129,25 @@ func decodeCastVoteJournal(payload []byte) (*CastVoteJournal, error) {
 	return &cvj, nil
 }
 
+// commentRevisions contains the comment as it was originally submitted along
+// with all subsequent edits, oldest first.
+type commentRevisions struct {
+	original bitumplugin.Comment
+	edits    []bitumplugin.EditComment
+}
+
+// commentCensorship contains the state of a comment prior to it being
+// censored along with the censorship and any appeal of it.  The original
+// comment is retained so that it can be restored if an appeal succeeds.
+type commentCensorship struct {
+	original     bitumplugin.Comment           // Comment prior to censorship
+	revisions    commentRevisions              // Revisions prior to censorship
+	hasRevisions bool                          // Whether revisions are set
+	censor       bitumplugin.CensorComment     // Censor record
+	appeal       *bitumplugin.AppealCensorship // Appeal, nil if not appealed
+	resolved     bool                          // Whether the appeal was upheld
+}
+
 var (
 	bitumPluginSettings map[string]string             // [key]setting
 	bitumPluginHooks    map[string]func(string) error // [key]func(token) error
@@ -117,17 +161,49 @@ var (
 	journalAdd     []byte
 	journalDel     []byte
 	journalAddLike []byte
+	journalCancel  []byte
+	journalEdit    []byte
+	journalDelete  []byte
+	journalFlag    []byte
+	journalDismiss []byte
+	journalAppeal  []byte
+	journalResolve []byte
 
 	// Plugin specific data that CANNOT be treated as metadata
 	pluginDataDir = filepath.Join("plugins", "bitum")
 
-	// Individual votes cache
-	//bitumPluginVotesCache = make(map[string]map[string]bitumplugin.CastVote) // [token][ticket]castvote
-	bitumPluginVotesCache = make(map[string]map[string]struct{})
+	// Individual votes cache.  The signature of the latest vote cast
+	// by a ticket is recorded so that a resubmitted vote can be told
+	// apart from a revote.
+	bitumPluginVotesCache = make(map[string]map[string]string) // [token][ticket]signature
+
+	// Cancelled votes cache
+	bitumPluginVoteCancelCache = make(map[string]bool) // [token]cancelled
+
+	// Scheduled votes cache.  It is loaded from disk the first time
+	// the scheduled votes are checked.
+	bitumPluginScheduledVoteCache = make(map[string]bitumplugin.StartVote) // [token]startvote
+	scheduledVotesLoaded          bool
 
 	bitumPluginCommentsCache      = make(map[string]map[string]bitumplugin.Comment) // [token][commentid]comment
 	bitumPluginCommentsLikesCache = make(map[string][]bitumplugin.LikeComment)      // [token]LikeComment
 
+	// Comment revisions cache.  Only comments that have been edited
+	// have an entry.
+	bitumPluginCommentRevisionsCache = make(map[string]map[string]commentRevisions) // [token][commentid]revisions
+
+	// Comment flags cache.  Only flags that have not been dismissed
+	// are kept.
+	bitumPluginCommentFlagsCache = make(map[string]map[string][]bitumplugin.FlagComment) // [token][commentid]flags
+
+	// Censored comments cache.  Only comments that are currently
+	// censored have an entry.
+	bitumPluginCensoredCommentsCache = make(map[string]map[string]commentCensorship) // [token][commentid]censorship
+
+	// Deleted comments cache.  Only comments that have been deleted
+	// by their author have an entry.
+	bitumPluginDeletedCommentsCache = make(map[string]map[string]struct{}) // [token][commentid]
+
 	journalsReplayed bool = false
 )
 
@@ -156,6 +232,55 @@ func init() {
 	if err != nil {
 		panic(err.Error())
 	}
+	journalCancel, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionCancel,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalEdit, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionEdit,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalDelete, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionDelete,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalFlag, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionFlag,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalDismiss, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionDismiss,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalAppeal, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionAppeal,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	journalResolve, err = json.Marshal(JournalAction{
+		Version: journalVersion,
+		Action:  journalActionResolve,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
 }
 
 func getBitumPlugin(testnet bool) backend.Plugin {
@@ -382,181 +507,9 @@ func (g *gitBackEnd) verifyMessage(address, message, signature string) (bool, er
 	return a.EncodeAddress() == address, nil
 }
 
-func bestBlock() (*bitumdataapi.BlockDataBasic, error) {
-	url := bitumPluginSettings["bitumdata"] + "api/block/best"
-	log.Debugf("connecting to %v", url)
-	// XXX this http command needs a reasonable timeout.
-	r, err := http.Get(url)
-	log.Debugf("http connecting to %v", url)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	if r.StatusCode != http.StatusOK {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return nil, fmt.Errorf("bitumdata error: %v %v %v",
-				r.StatusCode, url, err)
-		}
-		return nil, fmt.Errorf("bitumdata error: %v %v %s",
-			r.StatusCode, url, body)
-	}
-
-	var bdb bitumdataapi.BlockDataBasic
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&bdb); err != nil {
-		return nil, err
-	}
-
-	return &bdb, nil
-}
-
-func block(block uint32) (*bitumdataapi.BlockDataBasic, error) {
-	h := strconv.FormatUint(uint64(block), 10)
-	url := bitumPluginSettings["bitumdata"] + "api/block/" + h
-	log.Debugf("connecting to %v", url)
-	r, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	if r.StatusCode != http.StatusOK {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return nil, fmt.Errorf("bitumdata error: %v %v %v",
-				r.StatusCode, url, err)
-		}
-		return nil, fmt.Errorf("bitumdata error: %v %v %s",
-			r.StatusCode, url, body)
-	}
-
-	var bdb bitumdataapi.BlockDataBasic
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&bdb); err != nil {
-		return nil, err
-	}
-
-	return &bdb, nil
-}
-
-func snapshot(hash string) ([]string, error) {
-	url := bitumPluginSettings["bitumdata"] + "api/stake/pool/b/" + hash +
-		"/full?sort=true"
-	log.Debugf("connecting to %v", url)
-	r, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	if r.StatusCode != http.StatusOK {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return nil, fmt.Errorf("bitumdata error: %v %v %v",
-				r.StatusCode, url, err)
-		}
-		return nil, fmt.Errorf("bitumdata error: %v %v %s",
-			r.StatusCode, url, body)
-	}
-
-	var tickets []string
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&tickets); err != nil {
-		return nil, err
-	}
-
-	return tickets, nil
-}
-
-func batchTransactions(hashes []string) ([]bitumdataapi.TrimmedTx, error) {
-	// Request body is bitumdataapi.Txns marshalled to JSON
-	reqBody, err := json.Marshal(bitumdataapi.Txns{
-		Transactions: hashes,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Make the POST request
-	url := bitumPluginSettings["bitumdata"] + "api/txs/trimmed"
-	log.Debugf("connecting to %v", url)
-	r, err := http.Post(url, "application/json; charset=utf-8",
-		bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	if r.StatusCode != http.StatusOK {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return nil, fmt.Errorf("bitumdata error: %v %v %v",
-				r.StatusCode, url, err)
-		}
-		return nil, fmt.Errorf("bitumdata error: %v %v %s",
-			r.StatusCode, url, body)
-	}
-
-	// Unmarshal the response
-	var ttx []bitumdataapi.TrimmedTx
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&ttx); err != nil {
-		return nil, err
-	}
-	return ttx, nil
-}
-
-// largestCommitmentResult returns the largest commitment address or an error.
-type largestCommitmentResult struct {
-	bestAddr string
-	err      error
-}
-
-func largestCommitmentAddresses(hashes []string) ([]largestCommitmentResult, error) {
-	// Batch request all of the transaction info from bitumdata.
-	ttxs, err := batchTransactions(hashes)
-	if err != nil {
-		return nil, err
-	}
-
-	// Find largest commitment address for each transaction.
-	r := make([]largestCommitmentResult, len(hashes))
-	for i := range ttxs {
-		// Best is address with largest commit amount.
-		var bestAddr string
-		var bestAmount float64
-		for _, v := range ttxs[i].Vout {
-			if v.ScriptPubKeyDecoded.CommitAmt == nil {
-				continue
-			}
-			if *v.ScriptPubKeyDecoded.CommitAmt > bestAmount {
-				if len(v.ScriptPubKeyDecoded.Addresses) == 0 {
-					// jrick, does this need to be printed?
-					log.Errorf("unexpected addresses "+
-						"length: %v", ttxs[i].TxID)
-					continue
-				}
-				bestAddr = v.ScriptPubKeyDecoded.Addresses[0]
-				bestAmount = *v.ScriptPubKeyDecoded.CommitAmt
-			}
-		}
-
-		if bestAddr == "" || bestAmount == 0.0 {
-			r[i].err = fmt.Errorf("no best commitment address found: %v",
-				ttxs[i].TxID)
-			continue
-		}
-		r[i].bestAddr = bestAddr
-	}
-
-	return r, nil
-}
-
 // pluginBestBlock returns current best block height from wallet.
 func (g *gitBackEnd) pluginBestBlock() (string, error) {
-	bb, err := bestBlock()
+	bb, err := g.chain.BestBlock()
 	if err != nil {
 		return "", err
 	}
@@ -630,7 +583,9 @@ func (g *gitBackEnd) flushJournalsUnwind(id string) error {
 }
 
 // flushCommentflushes comments journal to bitum plugin directory in
-// git. It returns the filename that was coppied into git repo.
+// git. It returns the filename that was coppied into git repo.  The text of
+// censored and deleted comments is redacted from the copy, the censor entry
+// retains the hash of the text.
 //
 // Must be called WITH the mutex held.
 func (g *gitBackEnd) flushComments(token string) (string, error) {
@@ -657,7 +612,20 @@ func (g *gitBackEnd) flushComments(token string) (string, error) {
 	_ = os.MkdirAll(dir, 0764)
 
 	// Move journal and comment id into place
-	err = g.journal.Copy(srcComments, comments)
+	redacted := make(map[string]struct{},
+		len(bitumPluginCensoredCommentsCache[token])+
+			len(bitumPluginDeletedCommentsCache[token]))
+	for k := range bitumPluginCensoredCommentsCache[token] {
+		redacted[k] = struct{}{}
+	}
+	for k := range bitumPluginDeletedCommentsCache[token] {
+		redacted[k] = struct{}{}
+	}
+	if len(redacted) == 0 {
+		err = g.journal.Copy(srcComments, comments)
+	} else {
+		err = g.copyRedactedComments(srcComments, comments, redacted)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -667,6 +635,90 @@ func (g *gitBackEnd) flushComments(token string) (string, error) {
 		nil
 }
 
+// copyRedactedComments copies a comments journal from source to destination
+// with the text of the provided comments removed.  During the copy process the
+// source file remains locked.
+func (g *gitBackEnd) copyRedactedComments(source, destination string, redacted map[string]struct{}) (err error) {
+	err = g.journal.Open(source)
+	if err != nil {
+		return
+	}
+	defer func() {
+		cerr := g.journal.Close(source)
+		if cerr != nil {
+			err = cerr
+		}
+	}()
+
+	entries, err := readJournalEntries(source)
+	if err != nil {
+		return
+	}
+	var b bytes.Buffer
+	for _, v := range entries {
+		var e string
+		e, err = redactCommentEntry(v, redacted)
+		if err != nil {
+			return
+		}
+		b.WriteString(e + "\n")
+	}
+
+	err = ioutil.WriteFile(destination, b.Bytes(), 0664)
+	return
+}
+
+// redactCommentEntry returns the provided comment journal entry with the
+// comment text removed when it adds or edits one of the provided redacted
+// comments.  All other entries are returned as is.
+func redactCommentEntry(entry string, redacted map[string]struct{}) (string, error) {
+	d := json.NewDecoder(strings.NewReader(entry))
+
+	var action JournalAction
+	err := d.Decode(&action)
+	if err != nil {
+		return "", fmt.Errorf("journal action: %v", err)
+	}
+
+	var blob []byte
+	switch action.Action {
+	case journalActionAdd:
+		var c bitumplugin.Comment
+		err = d.Decode(&c)
+		if err != nil {
+			return "", fmt.Errorf("journal add: %v", err)
+		}
+		if _, ok := redacted[c.CommentID]; !ok {
+			return entry, nil
+		}
+		c.Comment = ""
+		blob, err = bitumplugin.EncodeComment(c)
+	case journalActionEdit:
+		var ec bitumplugin.EditComment
+		err = d.Decode(&ec)
+		if err != nil {
+			return "", fmt.Errorf("journal edit: %v", err)
+		}
+		if _, ok := redacted[ec.CommentID]; !ok {
+			return entry, nil
+		}
+		ec.Comment = ""
+		blob, err = bitumplugin.EncodeEditComment(ec)
+	default:
+		return entry, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ab, err := json.Marshal(action)
+	if err != nil {
+		return "", err
+	}
+
+	return string(ab) + string(blob), nil
+}
+
 // flushCommentJournal flushes an individual comment journal.
 //
 // Must be called WITH the mutex held.
@@ -1185,6 +1237,7 @@ func (g *gitBackEnd) pluginLikeComment(payload string) (string, error) {
 		Action:    like.Action,
 		Signature: like.Signature,
 		PublicKey: like.PublicKey,
+		UserID:    like.UserID,
 		Receipt:   receipt,
 		Timestamp: time.Now().Unix(),
 	}
@@ -1284,11 +1337,14 @@ func (g *gitBackEnd) pluginCensorComment(payload string) (string, error) {
 			censor.Token, censor.CommentID)
 	}
 
-	// Update comments cache
+	// Update comments cache.  The revision history of a censored
+	// comment is no longer served.
 	oc := c
 	c.Comment = ""
 	c.Censored = true
 	bitumPluginCommentsCache[censor.Token][censor.CommentID] = c
+	ocr, hasRevisions := bitumPluginCommentRevisionsCache[censor.Token][censor.CommentID]
+	delete(bitumPluginCommentRevisionsCache[censor.Token], censor.CommentID)
 
 	g.Unlock()
 
@@ -1297,18 +1353,25 @@ func (g *gitBackEnd) pluginCensorComment(payload string) (string, error) {
 	unwind := func() {
 		g.Lock()
 		bitumPluginCommentsCache[censor.Token][censor.CommentID] = oc
+		if hasRevisions {
+			bitumPluginCommentRevisionsCache[censor.Token][censor.CommentID] = ocr
+		}
+		delete(bitumPluginCensoredCommentsCache[censor.Token], censor.CommentID)
 		g.Unlock()
 	}
 
-	// Create Journal entry
+	// Create Journal entry.  The hash of the censored text is
+	// recorded so that the comment can be verified if it is ever
+	// restored.
 	cc := bitumplugin.CensorComment{
-		Token:     censor.Token,
-		CommentID: censor.CommentID,
-		Reason:    censor.Reason,
-		Signature: censor.Signature,
-		PublicKey: censor.PublicKey,
-		Receipt:   receipt,
-		Timestamp: time.Now().Unix(),
+		Token:       censor.Token,
+		CommentID:   censor.CommentID,
+		Reason:      censor.Reason,
+		Signature:   censor.Signature,
+		PublicKey:   censor.PublicKey,
+		Receipt:     receipt,
+		Timestamp:   time.Now().Unix(),
+		CommentHash: bitumplugin.CommentHash(oc.Comment),
 	}
 	blob, err := bitumplugin.EncodeCensorComment(cc)
 	if err != nil {
@@ -1325,6 +1388,16 @@ func (g *gitBackEnd) pluginCensorComment(payload string) (string, error) {
 		return "", fmt.Errorf("could not journal %v: %v", cc.Token, err)
 	}
 
+	// Retain the original comment so that it can be restored
+	g.Lock()
+	addCommentCensorship(commentCensorship{
+		original:     oc,
+		revisions:    ocr,
+		hasRevisions: hasRevisions,
+		censor:       cc,
+	})
+	g.Unlock()
+
 	// Encode reply
 	ccr := bitumplugin.CensorCommentReply{
 		Receipt: cc.Receipt,
@@ -1338,203 +1411,419 @@ func (g *gitBackEnd) pluginCensorComment(payload string) (string, error) {
 	return string(ccrb), nil
 }
 
-// encodeGetCommentsReply converts a comment map into a JSON string that can be
-// returned as a bitumplugin reply. If the comment map is nil it returns a
-// valid empty reply structure.
-func encodeGetCommentsReply(cm map[string]bitumplugin.Comment) (string, error) {
-	if cm == nil {
-		cm = make(map[string]bitumplugin.Comment)
+// pluginEditComment replaces the text of an existing comment.  The edit is
+// journaled so that every revision of the comment is retained on disk.  The
+// caller is responsible for verifying that the edit was signed by the
+// comment author.
+func (g *gitBackEnd) pluginEditComment(payload string) (string, error) {
+	log.Tracef("pluginEditComment")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
 	}
 
-	// Encode reply
-	gcr := bitumplugin.GetCommentsReply{
-		Comments: make([]bitumplugin.Comment, 0, len(cm)),
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
 	}
-	for _, v := range cm {
-		gcr.Comments = append(gcr.Comments, v)
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
+	if err != nil {
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
 	}
 
-	gcrb, err := bitumplugin.EncodeGetCommentsReply(gcr)
+	// Decode edit comment
+	edit, err := bitumplugin.DecodeEditComment([]byte(payload))
 	if err != nil {
-		return "", fmt.Errorf("encodeGetCommentsReply: %v", err)
+		return "", fmt.Errorf("DecodeEditComment: %v", err)
 	}
 
-	return string(gcrb), nil
-}
-
-// replayComments replay the comments for a given proposal
-// the proposal is matched by the provided token
-// this function can be called WITHOUT the lock held
-func (g *gitBackEnd) replayComments(token string) (map[string]bitumplugin.Comment, error) {
-	log.Debugf("replayComments %s", token)
 	// Verify proposal exists, we can run this lockless
-	if !g.propExists(g.vetted, token) {
-		return nil, nil
+	if !g.propExists(g.vetted, edit.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", edit.Token)
 	}
 
-	// Do some cheap things before expensive calls
-	cfilename := pijoin(g.journals, token,
-		defaultCommentFilename)
-
-	// Replay journal
-	err := g.journal.Open(cfilename)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("journal.Open: %v", err)
-		}
-		return nil, nil
-	}
-	defer func() {
-		err = g.journal.Close(cfilename)
-		if err != nil {
-			log.Errorf("journal.Close: %v", err)
-		}
-	}()
+	// Sign signature
+	r := fi.SignMessage([]byte(edit.Signature))
+	receipt := hex.EncodeToString(r[:])
 
-	comments := make(map[string]bitumplugin.Comment)
-	commentsLikes := make([]bitumplugin.LikeComment, 0, 1024)
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, edit.Token,
+		defaultCommentsFlushed)
 
-	for {
-		err = g.journal.Replay(cfilename, func(s string) error {
-			ss := bytes.NewReader([]byte(s))
-			d := json.NewDecoder(ss)
+	// Ensure proposal exists in comments cache
+	g.Lock()
 
-			// Decode action
-			var action JournalAction
-			err = d.Decode(&action)
-			if err != nil {
-				return fmt.Errorf("journal action: %v", err)
-			}
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
 
-			switch action.Action {
-			case journalActionAdd:
-				var c bitumplugin.Comment
-				err = d.Decode(&c)
-				if err != nil {
-					return fmt.Errorf("journal add: %v",
-						err)
-				}
+	// Verify cache
+	_, ok = bitumPluginCommentsCache[edit.Token]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("proposal not found %v", edit.Token)
+	}
 
-				// Sanity
-				if _, ok := comments[c.CommentID]; ok {
-					log.Errorf("duplicate comment id %v",
-						c.CommentID)
-				}
-				comments[c.CommentID] = c
+	// Ensure comment exists in comments cache and has not been
+	// censored
+	c, ok := bitumPluginCommentsCache[edit.Token][edit.CommentID]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("comment not found %v:%v",
+			edit.Token, edit.CommentID)
+	}
+	if c.Censored {
+		g.Unlock()
+		return "", fmt.Errorf("comment censored %v: %v",
+			edit.Token, edit.CommentID)
+	}
+	if c.Deleted {
+		g.Unlock()
+		return "", fmt.Errorf("comment deleted %v: %v",
+			edit.Token, edit.CommentID)
+	}
 
-			case journalActionDel:
-				var cc bitumplugin.CensorComment
-				err = d.Decode(&cc)
-				if err != nil {
-					return fmt.Errorf("journal censor: %v",
-						err)
-				}
+	// Create journal entry
+	ec := bitumplugin.EditComment{
+		Token:     edit.Token,
+		CommentID: edit.CommentID,
+		Comment:   edit.Comment,
+		Signature: edit.Signature,
+		PublicKey: edit.PublicKey,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+	}
 
-				// Ensure comment has been added
-				c, ok := comments[cc.CommentID]
-				if !ok {
-					// Complain but we can't do anything
-					// about it. Can't return error or we'd
-					// abort journal loop.
-					log.Errorf("comment not found: %v",
-						cc.CommentID)
-					return nil
-				}
+	// Update comments and revisions caches
+	oc := c
+	ocr, hasRevisions := bitumPluginCommentRevisionsCache[edit.Token][edit.CommentID]
+	bitumPluginCommentsCache[edit.Token][edit.CommentID] = applyCommentEdit(c, ec)
+	addCommentRevision(edit.Token, oc, ec)
 
-				// Delete comment
-				c.Comment = ""
-				c.Censored = true
-				comments[cc.CommentID] = c
+	g.Unlock()
 
-			case journalActionAddLike:
-				var lc bitumplugin.LikeComment
-				err = d.Decode(&lc)
-				if err != nil {
-					return fmt.Errorf("journal addlike: %v",
-						err)
-				}
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		bitumPluginCommentsCache[edit.Token][edit.CommentID] = oc
+		if hasRevisions {
+			bitumPluginCommentRevisionsCache[edit.Token][edit.CommentID] = ocr
+		} else {
+			delete(bitumPluginCommentRevisionsCache[edit.Token],
+				edit.CommentID)
+		}
+		g.Unlock()
+	}
 
-				commentsLikes = append(commentsLikes, lc)
+	blob, err := bitumplugin.EncodeEditComment(ec)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeEditComment: %v", err)
+	}
 
-			default:
-				return fmt.Errorf("invalid action: %v",
-					action.Action)
-			}
-			return nil
-		})
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, err
-		}
+	// Add edit comment to journal
+	cfilename := pijoin(g.journals, edit.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalEdit)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", ec.Token, err)
 	}
 
-	g.Lock()
-	bitumPluginCommentsCache[token] = comments
-	bitumPluginCommentsLikesCache[token] = commentsLikes
-	g.Unlock()
+	// Encode reply
+	ecr := bitumplugin.EditCommentReply{
+		Receipt:   ec.Receipt,
+		Timestamp: ec.Timestamp,
+	}
+	ecrb, err := bitumplugin.EncodeEditCommentReply(ecr)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeEditCommentReply: %v", err)
+	}
 
-	return comments, nil
+	return string(ecrb), nil
 }
 
-// pluginGetProposalCommentLikes return all UserCommentVotes for a given proposal
-func (g *gitBackEnd) pluginGetProposalCommentsLikes(payload string) (string, error) {
-	var gpclr bitumplugin.GetProposalCommentsLikesReply
+// pluginDeleteComment deletes a comment on behalf of its author.  The comment
+// text is removed and the comment is marked as deleted.  The comment itself
+// remains in place so that the thread structure, signature and receipt are
+// preserved.  The caller is responsible for verifying that the delete was
+// signed by the comment author.
+func (g *gitBackEnd) pluginDeleteComment(payload string) (string, error) {
+	log.Tracef("pluginDeleteComment")
 
-	gpcl, err := bitumplugin.DecodeGetProposalCommentsLikes([]byte(payload))
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
 	if err != nil {
-		return "", fmt.Errorf("DecodeGetProposalCommentsLikes: %v", err)
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
+	}
+
+	// Decode delete comment
+	del, err := bitumplugin.DecodeDeleteComment([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeDeleteComment: %v", err)
 	}
 
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, del.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", del.Token)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(del.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, del.Token,
+		defaultCommentsFlushed)
+
+	// Ensure proposal exists in comments cache
 	g.Lock()
-	gpclr.CommentsLikes = bitumPluginCommentsLikesCache[gpcl.Token]
+
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
+
+	// Verify cache
+	_, ok = bitumPluginCommentsCache[del.Token]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("proposal not found %v", del.Token)
+	}
+
+	// Ensure comment exists in comments cache and has not
+	// already been censored or deleted
+	c, ok := bitumPluginCommentsCache[del.Token][del.CommentID]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("comment not found %v:%v",
+			del.Token, del.CommentID)
+	}
+	if c.Censored || c.Deleted {
+		g.Unlock()
+		return "", fmt.Errorf("comment already removed %v: %v",
+			del.Token, del.CommentID)
+	}
+
+	// Update comments cache.  The revision history of a deleted
+	// comment is no longer served.
+	oc := c
+	c.Comment = ""
+	c.Deleted = true
+	bitumPluginCommentsCache[del.Token][del.CommentID] = c
+	ocr, hasRevisions := bitumPluginCommentRevisionsCache[del.Token][del.CommentID]
+	delete(bitumPluginCommentRevisionsCache[del.Token], del.CommentID)
+	if _, ok := bitumPluginDeletedCommentsCache[del.Token]; !ok {
+		bitumPluginDeletedCommentsCache[del.Token] =
+			make(map[string]struct{})
+	}
+	bitumPluginDeletedCommentsCache[del.Token][del.CommentID] = struct{}{}
+
 	g.Unlock()
 
-	egpclr, err := bitumplugin.EncodeGetProposalCommentsLikesReply(gpclr)
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		bitumPluginCommentsCache[del.Token][del.CommentID] = oc
+		if hasRevisions {
+			bitumPluginCommentRevisionsCache[del.Token][del.CommentID] = ocr
+		}
+		delete(bitumPluginDeletedCommentsCache[del.Token], del.CommentID)
+		g.Unlock()
+	}
+
+	// Create journal entry
+	dc := bitumplugin.DeleteComment{
+		Token:     del.Token,
+		CommentID: del.CommentID,
+		Signature: del.Signature,
+		PublicKey: del.PublicKey,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+	}
+	blob, err := bitumplugin.EncodeDeleteComment(dc)
 	if err != nil {
-		return "", fmt.Errorf("EncodeGetProposalCommentsLikesReply: %v", err)
+		unwind()
+		return "", fmt.Errorf("EncodeDeleteComment: %v", err)
 	}
-	return string(egpclr), nil
+
+	// Add delete comment to journal
+	cfilename := pijoin(g.journals, del.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalDelete)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", dc.Token, err)
+	}
+
+	// Encode reply
+	dcr := bitumplugin.DeleteCommentReply{
+		Receipt:   dc.Receipt,
+		Timestamp: dc.Timestamp,
+	}
+	dcrb, err := bitumplugin.EncodeDeleteCommentReply(dcr)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeDeleteCommentReply: %v", err)
+	}
+
+	return string(dcrb), nil
 }
 
-func (g *gitBackEnd) pluginGetComments(payload string) (string, error) {
-	log.Tracef("pluginGetComments")
+// pluginFlagComment adds a flag to a comment.  A public key may only have
+// one flag on a comment until the flags of the comment are dismissed.
+func (g *gitBackEnd) pluginFlagComment(payload string) (string, error) {
+	log.Tracef("pluginFlagComment")
 
 	// Check if journals were replayed
 	if !journalsReplayed {
 		return "", backend.ErrJournalsNotReplayed
 	}
 
-	// Decode comment
-	gc, err := bitumplugin.DecodeGetComments([]byte(payload))
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
 	if err != nil {
-		return "", fmt.Errorf("DecodeGetComments: %v", err)
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
+	}
+
+	// Decode flag comment
+	flag, err := bitumplugin.DecodeFlagComment([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeFlagComment: %v", err)
+	}
+
+	// Make sure reason makes sense
+	if flag.Reason == "" {
+		return "", fmt.Errorf("invalid reason")
 	}
 
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, flag.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", flag.Token)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(flag.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, flag.Token,
+		defaultCommentsFlushed)
+
+	// Ensure proposal exists in comments cache
 	g.Lock()
-	comments := bitumPluginCommentsCache[gc.Token]
+
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
+
+	// Ensure comment exists in comments cache and has not been
+	// censored or deleted
+	c, ok := bitumPluginCommentsCache[flag.Token][flag.CommentID]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("comment not found %v:%v",
+			flag.Token, flag.CommentID)
+	}
+	if c.Censored || c.Deleted {
+		g.Unlock()
+		return "", fmt.Errorf("comment removed %v: %v",
+			flag.Token, flag.CommentID)
+	}
+
+	// Ensure public key has not already flagged the comment
+	cf := bitumPluginCommentFlagsCache[flag.Token][flag.CommentID]
+	for _, v := range cf {
+		if v.PublicKey == flag.PublicKey {
+			g.Unlock()
+			return "", fmt.Errorf("comment already flagged %v:%v",
+				flag.Token, flag.CommentID)
+		}
+	}
+
+	// Create journal entry
+	fc := bitumplugin.FlagComment{
+		Token:     flag.Token,
+		CommentID: flag.CommentID,
+		Reason:    flag.Reason,
+		Signature: flag.Signature,
+		PublicKey: flag.PublicKey,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+	}
+
+	// Update cache
+	if _, ok := bitumPluginCommentFlagsCache[flag.Token]; !ok {
+		bitumPluginCommentFlagsCache[flag.Token] =
+			make(map[string][]bitumplugin.FlagComment)
+	}
+	bitumPluginCommentFlagsCache[flag.Token][flag.CommentID] = append(cf, fc)
 	g.Unlock()
-	return encodeGetCommentsReply(comments)
-}
 
-// pluginAuthorizeVote updates the vetted repo with vote authorization
-// metadata from the proposal author.
-func (g *gitBackEnd) pluginAuthorizeVote(payload string) (string, error) {
-	log.Tracef("pluginAuthorizeVote")
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		if len(cf) == 0 {
+			delete(bitumPluginCommentFlagsCache[flag.Token], flag.CommentID)
+		} else {
+			bitumPluginCommentFlagsCache[flag.Token][flag.CommentID] = cf
+		}
+		g.Unlock()
+	}
 
-	// Decode authorize vote
-	authorize, err := bitumplugin.DecodeAuthorizeVote([]byte(payload))
+	blob, err := bitumplugin.EncodeFlagComment(fc)
 	if err != nil {
-		return "", fmt.Errorf("DecodeAuthorizeVote %v", err)
+		unwind()
+		return "", fmt.Errorf("EncodeFlagComment: %v", err)
 	}
-	token := authorize.Token
 
-	// Verify proposal exists
-	if !g.propExists(g.vetted, token) {
-		return "", fmt.Errorf("unknown proposal: %v", token)
+	// Add flag comment to journal
+	cfilename := pijoin(g.journals, flag.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalFlag)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", fc.Token, err)
+	}
+
+	// Encode reply
+	fcr := bitumplugin.FlagCommentReply{
+		Receipt:   fc.Receipt,
+		Timestamp: fc.Timestamp,
+	}
+	fcrb, err := bitumplugin.EncodeFlagCommentReply(fcr)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeFlagCommentReply: %v", err)
+	}
+
+	return string(fcrb), nil
+}
+
+// pluginDismissCommentFlags removes all of the flags from a comment.
+func (g *gitBackEnd) pluginDismissCommentFlags(payload string) (string, error) {
+	log.Tracef("pluginDismissCommentFlags")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
 	}
 
-	// Get identity
 	// XXX this should become part of some sort of context
 	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
 	if !ok {
@@ -1545,232 +1834,1685 @@ func (g *gitBackEnd) pluginAuthorizeVote(payload string) (string, error) {
 		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
 	}
 
-	// Sign signature
-	r := fi.SignMessage([]byte(authorize.Signature))
-	receipt := hex.EncodeToString(r[:])
+	// Decode dismiss comment flags
+	dismiss, err := bitumplugin.DecodeDismissCommentFlags([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeDismissCommentFlags: %v", err)
+	}
 
-	// Create on disk structure
-	t := time.Now().Unix()
-	av := bitumplugin.AuthorizeVote{
-		Version:   bitumplugin.VersionAuthorizeVote,
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, dismiss.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", dismiss.Token)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(dismiss.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, dismiss.Token,
+		defaultCommentsFlushed)
+
+	// Ensure proposal exists in comments cache
+	g.Lock()
+
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
+
+	// Ensure comment has flags
+	cf, ok := bitumPluginCommentFlagsCache[dismiss.Token][dismiss.CommentID]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("comment not flagged %v:%v",
+			dismiss.Token, dismiss.CommentID)
+	}
+
+	// Update cache
+	delete(bitumPluginCommentFlagsCache[dismiss.Token], dismiss.CommentID)
+	g.Unlock()
+
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		bitumPluginCommentFlagsCache[dismiss.Token][dismiss.CommentID] = cf
+		g.Unlock()
+	}
+
+	// Create journal entry
+	dcf := bitumplugin.DismissCommentFlags{
+		Token:     dismiss.Token,
+		CommentID: dismiss.CommentID,
+		Reason:    dismiss.Reason,
+		Signature: dismiss.Signature,
+		PublicKey: dismiss.PublicKey,
 		Receipt:   receipt,
-		Timestamp: t,
-		Action:    authorize.Action,
-		Token:     token,
-		Signature: authorize.Signature,
-		PublicKey: authorize.PublicKey,
+		Timestamp: time.Now().Unix(),
+	}
+	blob, err := bitumplugin.EncodeDismissCommentFlags(dcf)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeDismissCommentFlags: %v", err)
+	}
+
+	// Add dismiss comment flags to journal
+	cfilename := pijoin(g.journals, dismiss.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalDismiss)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", dcf.Token, err)
+	}
+
+	// Encode reply
+	dcfr := bitumplugin.DismissCommentFlagsReply{
+		Receipt:   dcf.Receipt,
+		Timestamp: dcf.Timestamp,
+	}
+	dcfrb, err := bitumplugin.EncodeDismissCommentFlagsReply(dcfr)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeDismissCommentFlagsReply: %v", err)
+	}
+
+	return string(dcfrb), nil
+}
+
+// pluginAppealCensorship records an appeal of a comment censorship.  A
+// censorship can only be appealed once.  The caller is responsible for
+// verifying that the appeal was signed by the comment author.
+func (g *gitBackEnd) pluginAppealCensorship(payload string) (string, error) {
+	log.Tracef("pluginAppealCensorship")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
+	if err != nil {
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
+	}
+
+	// Decode appeal censorship
+	appeal, err := bitumplugin.DecodeAppealCensorship([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeAppealCensorship: %v", err)
+	}
+
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, appeal.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", appeal.Token)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(appeal.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, appeal.Token,
+		defaultCommentsFlushed)
+
+	// Create journal entry
+	ac := bitumplugin.AppealCensorship{
+		Token:     appeal.Token,
+		CommentID: appeal.CommentID,
+		Reason:    appeal.Reason,
+		Signature: appeal.Signature,
+		PublicKey: appeal.PublicKey,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+	}
+
+	g.Lock()
+
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
+
+	// Ensure comment is censored and has not been appealed
+	cs, ok := bitumPluginCensoredCommentsCache[ac.Token][ac.CommentID]
+	if !ok {
+		g.Unlock()
+		return "", fmt.Errorf("comment not censored %v:%v",
+			ac.Token, ac.CommentID)
+	}
+	if cs.appeal != nil {
+		g.Unlock()
+		return "", fmt.Errorf("censorship already appealed %v:%v",
+			ac.Token, ac.CommentID)
+	}
+
+	// Update cache
+	cs.appeal = &ac
+	bitumPluginCensoredCommentsCache[ac.Token][ac.CommentID] = cs
+	g.Unlock()
+
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		cs.appeal = nil
+		bitumPluginCensoredCommentsCache[ac.Token][ac.CommentID] = cs
+		g.Unlock()
+	}
+
+	blob, err := bitumplugin.EncodeAppealCensorship(ac)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeAppealCensorship: %v", err)
+	}
+
+	// Add appeal censorship to journal
+	cfilename := pijoin(g.journals, ac.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalAppeal)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", ac.Token, err)
+	}
+
+	// Encode reply
+	acr := bitumplugin.AppealCensorshipReply{
+		Receipt:   ac.Receipt,
+		Timestamp: ac.Timestamp,
+	}
+	acrb, err := bitumplugin.EncodeAppealCensorshipReply(acr)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeAppealCensorshipReply: %v", err)
+	}
+
+	return string(acrb), nil
+}
+
+// pluginResolveAppeal resolves a pending censorship appeal.  An upheld
+// appeal leaves the comment censored.  A restored comment is verified
+// against the signature of its author before it is put back in place.  The
+// appeal must be resolved using a different key than the one that censored
+// the comment.
+func (g *gitBackEnd) pluginResolveAppeal(payload string) (string, error) {
+	log.Tracef("pluginResolveAppeal")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
+	if err != nil {
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
+	}
+
+	// Decode resolve appeal
+	resolve, err := bitumplugin.DecodeResolveAppeal([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeResolveAppeal: %v", err)
+	}
+	switch resolve.Action {
+	case bitumplugin.AppealActionUphold, bitumplugin.AppealActionRestore:
+	default:
+		return "", fmt.Errorf("invalid appeal action: %v", resolve.Action)
+	}
+
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, resolve.Token) {
+		return "", fmt.Errorf("unknown proposal: %v", resolve.Token)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(resolve.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Comment journal filename
+	flushFilename := pijoin(g.journals, resolve.Token,
+		defaultCommentsFlushed)
+
+	// Create journal entry
+	ra := bitumplugin.ResolveAppeal{
+		Token:     resolve.Token,
+		CommentID: resolve.CommentID,
+		Action:    resolve.Action,
+		Reason:    resolve.Reason,
+		Signature: resolve.Signature,
+		PublicKey: resolve.PublicKey,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+	}
+
+	g.Lock()
+
+	// Mark comment journal dirty
+	_ = os.Remove(flushFilename)
+
+	// Ensure the censorship has a pending appeal
+	cs, ok := bitumPluginCensoredCommentsCache[ra.Token][ra.CommentID]
+	if !ok || cs.appeal == nil || cs.resolved {
+		g.Unlock()
+		return "", fmt.Errorf("no pending appeal %v:%v",
+			ra.Token, ra.CommentID)
+	}
+	if cs.censor.PublicKey == ra.PublicKey {
+		g.Unlock()
+		return "", fmt.Errorf("appeal resolved by censor %v:%v",
+			ra.Token, ra.CommentID)
+	}
+	if ra.Action == bitumplugin.AppealActionRestore {
+		err := verifyCommentSignature(cs.original)
+		if err != nil {
+			g.Unlock()
+			return "", fmt.Errorf("restore %v:%v: %v",
+				ra.Token, ra.CommentID, err)
+		}
+	}
+
+	// Update cache
+	c := bitumPluginCommentsCache[ra.Token][ra.CommentID]
+	if _, ok := bitumPluginCommentRevisionsCache[ra.Token]; !ok {
+		bitumPluginCommentRevisionsCache[ra.Token] =
+			make(map[string]commentRevisions)
+	}
+	applyResolveAppeal(ra, bitumPluginCommentsCache[ra.Token],
+		bitumPluginCommentRevisionsCache[ra.Token],
+		bitumPluginCensoredCommentsCache[ra.Token])
+	g.Unlock()
+
+	// We create an unwind function that MUST be called from all error
+	// paths. If everything works ok it is a no-op.
+	unwind := func() {
+		g.Lock()
+		bitumPluginCommentsCache[ra.Token][ra.CommentID] = c
+		if ra.Action == bitumplugin.AppealActionRestore && cs.hasRevisions {
+			delete(bitumPluginCommentRevisionsCache[ra.Token],
+				ra.CommentID)
+		}
+		addCommentCensorship(cs)
+		g.Unlock()
+	}
+
+	blob, err := bitumplugin.EncodeResolveAppeal(ra)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeResolveAppeal: %v", err)
+	}
+
+	// Add resolve appeal to journal
+	cfilename := pijoin(g.journals, ra.Token,
+		defaultCommentFilename)
+	err = g.journal.Journal(cfilename, string(journalResolve)+string(blob))
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("could not journal %v: %v", ra.Token, err)
+	}
+
+	// Encode reply
+	rar := bitumplugin.ResolveAppealReply{
+		Receipt:   ra.Receipt,
+		Timestamp: ra.Timestamp,
+	}
+	if ra.Action == bitumplugin.AppealActionRestore {
+		oc := cs.original
+		rar.Comment = &oc
+	}
+	rarb, err := bitumplugin.EncodeResolveAppealReply(rar)
+	if err != nil {
+		unwind()
+		return "", fmt.Errorf("EncodeResolveAppealReply: %v", err)
+	}
+
+	return string(rarb), nil
+}
+
+// pluginCensorshipAppeals returns the censorship appeals that have not been
+// resolved, optionally limited to a single comment.  Upheld appeals are
+// included when requested.
+func (g *gitBackEnd) pluginCensorshipAppeals(payload string) (string, error) {
+	log.Tracef("pluginCensorshipAppeals")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	ca, err := bitumplugin.DecodeCensorshipAppeals([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeCensorshipAppeals: %v", err)
+	}
+
+	appeals := make([]bitumplugin.CensorshipAppeal, 0, 64)
+	g.Lock()
+	for token, censored := range bitumPluginCensoredCommentsCache {
+		if ca.Token != "" && ca.Token != token {
+			continue
+		}
+		for commentID, cs := range censored {
+			if ca.CommentID != "" && ca.CommentID != commentID {
+				continue
+			}
+			if cs.appeal == nil || (cs.resolved && !ca.Resolved) {
+				continue
+			}
+			edits := []bitumplugin.EditComment{}
+			if cs.hasRevisions {
+				edits = cs.revisions.edits
+			}
+			appeals = append(appeals, bitumplugin.CensorshipAppeal{
+				Comment:  cs.original,
+				Edits:    edits,
+				Censor:   cs.censor,
+				Appeal:   *cs.appeal,
+				Resolved: cs.resolved,
+			})
+		}
+	}
+	g.Unlock()
+
+	// Oldest appeals first
+	sort.Slice(appeals, func(i, j int) bool {
+		return appeals[i].Appeal.Timestamp < appeals[j].Appeal.Timestamp
+	})
+
+	reply, err := bitumplugin.EncodeCensorshipAppealsReply(
+		bitumplugin.CensorshipAppealsReply{
+			Appeals: appeals,
+		})
+	if err != nil {
+		return "", fmt.Errorf("EncodeCensorshipAppealsReply: %v", err)
+	}
+
+	return string(reply), nil
+}
+
+// addCommentCensorship records a censorship in the censored comments cache.
+//
+// This function must be called WITH the lock held.
+func addCommentCensorship(cs commentCensorship) {
+	token := cs.censor.Token
+	if _, ok := bitumPluginCensoredCommentsCache[token]; !ok {
+		bitumPluginCensoredCommentsCache[token] =
+			make(map[string]commentCensorship)
+	}
+	bitumPluginCensoredCommentsCache[token][cs.censor.CommentID] = cs
+}
+
+// applyResolveAppeal updates the comments, revisions and censorships of a
+// single proposal with the resolution of a censorship appeal.  An upheld
+// appeal is marked as resolved.  A restored comment is put back in place
+// along with its revisions and is no longer tracked as censored.
+func applyResolveAppeal(ra bitumplugin.ResolveAppeal, comments map[string]bitumplugin.Comment, revisions map[string]commentRevisions, censored map[string]commentCensorship) {
+	cs, ok := censored[ra.CommentID]
+	if !ok {
+		return
+	}
+
+	switch ra.Action {
+	case bitumplugin.AppealActionUphold:
+		cs.resolved = true
+		censored[ra.CommentID] = cs
+	case bitumplugin.AppealActionRestore:
+		comments[ra.CommentID] = cs.original
+		if cs.hasRevisions {
+			revisions[ra.CommentID] = cs.revisions
+		}
+		delete(censored, ra.CommentID)
+	}
+}
+
+// verifyCommentSignature verifies that the text of the provided comment was
+// signed by its author.  The text of an edited comment is verified against the
+// signature of the latest edit.
+func verifyCommentSignature(c bitumplugin.Comment) error {
+	msg := c.Token + c.ParentID + c.Comment
+	signature, publicKey := c.Signature, c.PublicKey
+	if c.Edits > 0 {
+		msg = c.Token + c.CommentID + c.Comment
+		signature, publicKey = c.EditSignature, c.EditPublicKey
+	}
+
+	id, err := util.IdentityFromString(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %v", err)
+	}
+	sig, err := util.ConvertSignature(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	if !id.VerifyMessage([]byte(msg), sig) {
+		return fmt.Errorf("comment signature does not verify")
+	}
+
+	return nil
+}
+
+// applyCommentEdit returns the provided comment updated with the text and
+// edit metadata of the provided edit.  The original signature and receipt are
+// left untouched; the signature and receipt of the edit are recorded
+// separately so that the latest text can be verified.
+func applyCommentEdit(c bitumplugin.Comment, ec bitumplugin.EditComment) bitumplugin.Comment {
+	c.Comment = ec.Comment
+	c.Edits++
+	c.EditTimestamp = ec.Timestamp
+	c.EditSignature = ec.Signature
+	c.EditPublicKey = ec.PublicKey
+	c.EditReceipt = ec.Receipt
+	return c
+}
+
+// addCommentRevision records an edit in the comment revisions cache.  The
+// provided comment must be the comment as it was prior to the edit so that
+// the original is recorded on the first edit.
+//
+// This function must be called WITH the lock held.
+func addCommentRevision(token string, c bitumplugin.Comment, ec bitumplugin.EditComment) {
+	if _, ok := bitumPluginCommentRevisionsCache[token]; !ok {
+		bitumPluginCommentRevisionsCache[token] =
+			make(map[string]commentRevisions)
+	}
+	cr, ok := bitumPluginCommentRevisionsCache[token][ec.CommentID]
+	if !ok {
+		cr = commentRevisions{
+			original: c,
+		}
+	}
+	cr.edits = append(cr.edits, ec)
+	bitumPluginCommentRevisionsCache[token][ec.CommentID] = cr
+}
+
+// pluginCommentRevisions returns the original version of a comment along with
+// every edit that has been made to it.
+func (g *gitBackEnd) pluginCommentRevisions(payload string) (string, error) {
+	log.Tracef("pluginCommentRevisions")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	cr, err := bitumplugin.DecodeCommentRevisions([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeCommentRevisions: %v", err)
+	}
+
+	g.Lock()
+	c, ok := bitumPluginCommentsCache[cr.Token][cr.CommentID]
+	revisions, edited := bitumPluginCommentRevisionsCache[cr.Token][cr.CommentID]
+	g.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("comment not found %v:%v",
+			cr.Token, cr.CommentID)
+	}
+
+	crr := bitumplugin.CommentRevisionsReply{
+		Comment: c,
+		Edits:   []bitumplugin.EditComment{},
+	}
+	if edited {
+		crr.Comment = revisions.original
+		crr.Edits = revisions.edits
+	}
+
+	reply, err := bitumplugin.EncodeCommentRevisionsReply(crr)
+	if err != nil {
+		return "", fmt.Errorf("EncodeCommentRevisionsReply: %v", err)
+	}
+
+	return string(reply), nil
+}
+
+// encodeGetCommentsReply converts a comment map into a JSON string that can be
+// returned as a bitumplugin reply. If the comment map is nil it returns a
+// valid empty reply structure.
+func encodeGetCommentsReply(cm map[string]bitumplugin.Comment) (string, error) {
+	if cm == nil {
+		cm = make(map[string]bitumplugin.Comment)
+	}
+
+	// Encode reply
+	gcr := bitumplugin.GetCommentsReply{
+		Comments: make([]bitumplugin.Comment, 0, len(cm)),
+	}
+	for _, v := range cm {
+		gcr.Comments = append(gcr.Comments, v)
+	}
+
+	gcrb, err := bitumplugin.EncodeGetCommentsReply(gcr)
+	if err != nil {
+		return "", fmt.Errorf("encodeGetCommentsReply: %v", err)
+	}
+
+	return string(gcrb), nil
+}
+
+// replayComments replay the comments for a given proposal
+// the proposal is matched by the provided token
+// this function can be called WITHOUT the lock held
+func (g *gitBackEnd) replayComments(token string) (map[string]bitumplugin.Comment, error) {
+	log.Debugf("replayComments %s", token)
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, token) {
+		return nil, nil
+	}
+
+	// Do some cheap things before expensive calls
+	cfilename := pijoin(g.journals, token,
+		defaultCommentFilename)
+
+	// Replay journal
+	err := g.journal.Open(cfilename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("journal.Open: %v", err)
+		}
+		return nil, nil
+	}
+	defer func() {
+		err = g.journal.Close(cfilename)
+		if err != nil {
+			log.Errorf("journal.Close: %v", err)
+		}
+	}()
+
+	comments := make(map[string]bitumplugin.Comment)
+	commentsLikes := make([]bitumplugin.LikeComment, 0, 1024)
+	revisions := make(map[string]commentRevisions)
+	flags := make(map[string][]bitumplugin.FlagComment)
+	censored := make(map[string]commentCensorship)
+	deleted := make(map[string]struct{})
+
+	for {
+		err = g.journal.Replay(cfilename, func(s string) error {
+			ss := bytes.NewReader([]byte(s))
+			d := json.NewDecoder(ss)
+
+			// Decode action
+			var action JournalAction
+			err = d.Decode(&action)
+			if err != nil {
+				return fmt.Errorf("journal action: %v", err)
+			}
+
+			switch action.Action {
+			case journalActionAdd:
+				var c bitumplugin.Comment
+				err = d.Decode(&c)
+				if err != nil {
+					return fmt.Errorf("journal add: %v",
+						err)
+				}
+
+				// Sanity
+				if _, ok := comments[c.CommentID]; ok {
+					log.Errorf("duplicate comment id %v",
+						c.CommentID)
+				}
+				comments[c.CommentID] = c
+
+			case journalActionDel:
+				var cc bitumplugin.CensorComment
+				err = d.Decode(&cc)
+				if err != nil {
+					return fmt.Errorf("journal censor: %v",
+						err)
+				}
+
+				// Ensure comment has been added
+				c, ok := comments[cc.CommentID]
+				if !ok {
+					// Complain but we can't do anything
+					// about it. Can't return error or we'd
+					// abort journal loop.
+					log.Errorf("comment not found: %v",
+						cc.CommentID)
+					return nil
+				}
+
+				// Retain the original comment.  Censorships
+				// journaled prior to the hash being recorded
+				// are hashed here.
+				if cc.CommentHash == "" {
+					cc.CommentHash = bitumplugin.CommentHash(c.Comment)
+				}
+				cr, ok := revisions[cc.CommentID]
+				censored[cc.CommentID] = commentCensorship{
+					original:     c,
+					revisions:    cr,
+					hasRevisions: ok,
+					censor:       cc,
+				}
+
+				// Delete comment
+				c.Comment = ""
+				c.Censored = true
+				comments[cc.CommentID] = c
+				delete(revisions, cc.CommentID)
+
+			case journalActionEdit:
+				var ec bitumplugin.EditComment
+				err = d.Decode(&ec)
+				if err != nil {
+					return fmt.Errorf("journal edit: %v",
+						err)
+				}
+
+				// Ensure comment has been added
+				c, ok := comments[ec.CommentID]
+				if !ok {
+					log.Errorf("comment not found: %v",
+						ec.CommentID)
+					return nil
+				}
+
+				// Record revision and update comment
+				cr, ok := revisions[ec.CommentID]
+				if !ok {
+					cr = commentRevisions{
+						original: c,
+					}
+				}
+				cr.edits = append(cr.edits, ec)
+				revisions[ec.CommentID] = cr
+				comments[ec.CommentID] = applyCommentEdit(c, ec)
+
+			case journalActionDelete:
+				var dc bitumplugin.DeleteComment
+				err = d.Decode(&dc)
+				if err != nil {
+					return fmt.Errorf("journal delete: %v",
+						err)
+				}
+
+				// Ensure comment has been added
+				c, ok := comments[dc.CommentID]
+				if !ok {
+					log.Errorf("comment not found: %v",
+						dc.CommentID)
+					return nil
+				}
+
+				// Delete comment
+				c.Comment = ""
+				c.Deleted = true
+				comments[dc.CommentID] = c
+				delete(revisions, dc.CommentID)
+				deleted[dc.CommentID] = struct{}{}
+
+			case journalActionAddLike:
+				var lc bitumplugin.LikeComment
+				err = d.Decode(&lc)
+				if err != nil {
+					return fmt.Errorf("journal addlike: %v",
+						err)
+				}
+
+				commentsLikes = append(commentsLikes, lc)
+
+			case journalActionFlag:
+				var fc bitumplugin.FlagComment
+				err = d.Decode(&fc)
+				if err != nil {
+					return fmt.Errorf("journal flag: %v",
+						err)
+				}
+
+				flags[fc.CommentID] = append(flags[fc.CommentID], fc)
+
+			case journalActionDismiss:
+				var dcf bitumplugin.DismissCommentFlags
+				err = d.Decode(&dcf)
+				if err != nil {
+					return fmt.Errorf("journal dismiss: %v",
+						err)
+				}
+
+				delete(flags, dcf.CommentID)
+
+			case journalActionAppeal:
+				var ac bitumplugin.AppealCensorship
+				err = d.Decode(&ac)
+				if err != nil {
+					return fmt.Errorf("journal appeal: %v",
+						err)
+				}
+
+				// Ensure comment has been censored
+				cs, ok := censored[ac.CommentID]
+				if !ok {
+					log.Errorf("censored comment not found: %v",
+						ac.CommentID)
+					return nil
+				}
+				cs.appeal = &ac
+				censored[ac.CommentID] = cs
+
+			case journalActionResolve:
+				var ra bitumplugin.ResolveAppeal
+				err = d.Decode(&ra)
+				if err != nil {
+					return fmt.Errorf("journal resolve: %v",
+						err)
+				}
+
+				applyResolveAppeal(ra, comments, revisions, censored)
+
+			default:
+				return fmt.Errorf("invalid action: %v",
+					action.Action)
+			}
+			return nil
+		})
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	g.Lock()
+	bitumPluginCommentsCache[token] = comments
+	bitumPluginCommentsLikesCache[token] = commentsLikes
+	bitumPluginCommentRevisionsCache[token] = revisions
+	bitumPluginCommentFlagsCache[token] = flags
+	bitumPluginCensoredCommentsCache[token] = censored
+	bitumPluginDeletedCommentsCache[token] = deleted
+	g.Unlock()
+
+	return comments, nil
+}
+
+// pluginGetProposalCommentLikes return all UserCommentVotes for a given proposal
+func (g *gitBackEnd) pluginGetProposalCommentsLikes(payload string) (string, error) {
+	var gpclr bitumplugin.GetProposalCommentsLikesReply
+
+	gpcl, err := bitumplugin.DecodeGetProposalCommentsLikes([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeGetProposalCommentsLikes: %v", err)
+	}
+
+	g.Lock()
+	gpclr.CommentsLikes = bitumPluginCommentsLikesCache[gpcl.Token]
+	g.Unlock()
+
+	egpclr, err := bitumplugin.EncodeGetProposalCommentsLikesReply(gpclr)
+	if err != nil {
+		return "", fmt.Errorf("EncodeGetProposalCommentsLikesReply: %v", err)
+	}
+	return string(egpclr), nil
+}
+
+func (g *gitBackEnd) pluginGetComments(payload string) (string, error) {
+	log.Tracef("pluginGetComments")
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	// Decode comment
+	gc, err := bitumplugin.DecodeGetComments([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeGetComments: %v", err)
+	}
+
+	g.Lock()
+	comments := bitumPluginCommentsCache[gc.Token]
+	g.Unlock()
+	return encodeGetCommentsReply(comments)
+}
+
+// pluginAuthorizeVote updates the vetted repo with vote authorization
+// metadata from the proposal author.
+func (g *gitBackEnd) pluginAuthorizeVote(payload string) (string, error) {
+	log.Tracef("pluginAuthorizeVote")
+
+	// Decode authorize vote
+	authorize, err := bitumplugin.DecodeAuthorizeVote([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeAuthorizeVote %v", err)
+	}
+	token := authorize.Token
+
+	// Verify proposal exists
+	if !g.propExists(g.vetted, token) {
+		return "", fmt.Errorf("unknown proposal: %v", token)
+	}
+
+	// Get identity
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
+	if err != nil {
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(authorize.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Create on disk structure
+	t := time.Now().Unix()
+	av := bitumplugin.AuthorizeVote{
+		Version:   bitumplugin.VersionAuthorizeVote,
+		Receipt:   receipt,
+		Timestamp: t,
+		Action:    authorize.Action,
+		Token:     token,
+		Signature: authorize.Signature,
+		PublicKey: authorize.PublicKey,
+	}
+	avb, err := bitumplugin.EncodeAuthorizeVote(av)
+	if err != nil {
+		return "", fmt.Errorf("EncodeAuthorizeVote: %v", err)
+	}
+	tokenb, err := util.ConvertStringToken(token)
+	if err != nil {
+		return "", fmt.Errorf("ConvertStringToken %v", err)
+	}
+
+	// Verify proposal state
+	g.Lock()
+	defer g.Unlock()
+	if g.shutdown {
+		return "", backend.ErrShutdown
+	}
+
+	_, err = os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
+			defaultMDFilenameSuffix)))
+	if err == nil {
+		// Vote has already started. This should not happen.
+		return "", fmt.Errorf("proposal vote already started: %v",
+			token)
+	}
+	_, err = os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamScheduledVote,
+			defaultMDFilenameSuffix)))
+	if err == nil {
+		// Vote has been scheduled. This should not happen.
+		return "", fmt.Errorf("proposal vote already scheduled: %v",
+			token)
+	}
+
+	// Update metadata
+	err = g._updateVettedMetadata(tokenb, nil, []backend.MetadataStream{
+		{
+			ID:      bitumplugin.MDStreamAuthorizeVote,
+			Payload: string(avb),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("_updateVettedMetadata: %v", err)
+	}
+
+	// Prepare reply
+	version, err := getLatest(pijoin(g.vetted, token))
+	if err != nil {
+		return "", fmt.Errorf("getLatest: %v", err)
+	}
+	avr := bitumplugin.AuthorizeVoteReply{
+		Action:        av.Action,
+		RecordVersion: version,
+		Receipt:       av.Receipt,
+		Timestamp:     av.Timestamp,
+	}
+	avrb, err := bitumplugin.EncodeAuthorizeVoteReply(avr)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Vote authorized for %v", token)
+
+	return string(avrb), nil
+}
+
+// voteSnapshot returns the ticket pool snapshot and the voting period for a
+// vote with the provided duration that starts at the current best block.
+func (g *gitBackEnd) voteSnapshot(duration uint32) (*bitumplugin.StartVoteReply, error) {
+	// 1. Get best block
+	bb, err := g.chain.BestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("bestBlock %v", err)
+	}
+	return g.voteSnapshotAt(bb.Height, duration)
+}
+
+// voteSnapshotAt returns the ticket pool snapshot and the voting period for a
+// vote with the provided duration that starts at the provided block height.
+func (g *gitBackEnd) voteSnapshotAt(height, duration uint32) (*bitumplugin.StartVoteReply, error) {
+	if height < uint32(g.activeNetParams.TicketMaturity) {
+		return nil, fmt.Errorf("invalid height")
+	}
+	// 2. Subtract TicketMaturity from block height to get into
+	// unforkable teritory
+	snapshotBlock, err := g.chain.Block(height -
+		uint32(g.activeNetParams.TicketMaturity))
+	if err != nil {
+		return nil, fmt.Errorf("bestBlock %v", err)
+	}
+	// 3. Get ticket pool snapshot
+	snapshot, err := g.chain.Snapshot(snapshotBlock.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %v", err)
+	}
+	if len(snapshot) == 0 {
+		return nil, fmt.Errorf("no eligible voters")
+	}
+
+	return &bitumplugin.StartVoteReply{
+		Version: bitumplugin.VersionStartVoteReply,
+		StartBlockHeight: strconv.FormatUint(uint64(snapshotBlock.Height),
+			10),
+		StartBlockHash: snapshotBlock.Hash,
+		// On EndHeight: we start in the past, add maturity to correct
+		EndHeight: strconv.FormatUint(uint64(snapshotBlock.Height+
+			duration+uint32(g.activeNetParams.TicketMaturity)), 10),
+		EligibleTickets: snapshot,
+	}, nil
+}
+
+// validateVoteDuration ensures that the vote duration is within the min/max
+// range.
+func validateVoteDuration(duration uint32) error {
+	// XXX calculate this value for testnet instead of using hard coded values.
+	if duration < bitumplugin.VoteDurationMin ||
+		duration > bitumplugin.VoteDurationMax {
+		// XXX return a user error instead of an internal error
+		return fmt.Errorf("invalid duration: %v (%v - %v)",
+			duration, bitumplugin.VoteDurationMin,
+			bitumplugin.VoteDurationMax)
+	}
+	return nil
+}
+
+// _voteCanStart ensures that the vote of the proposal has been authorized and
+// that the vote has not already been started.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) _voteCanStart(token string) error {
+	_, err1 := os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamAuthorizeVote,
+			defaultMDFilenameSuffix)))
+	_, err2 := os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
+			defaultMDFilenameSuffix)))
+	_, err3 := os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteSnapshot,
+			defaultMDFilenameSuffix)))
+
+	if err1 != nil {
+		// Authorize vote md is not present
+		return fmt.Errorf("no authorize vote metadata: %v",
+			token)
+	} else if err2 != nil && err3 != nil {
+		// Vote has not started, continue
+	} else if err2 == nil && err3 == nil {
+		// Vote has started
+		return fmt.Errorf("proposal vote already started: %v",
+			token)
+	} else {
+		// This is bad, both files should exist or not exist
+		return fmt.Errorf("proposal is unknown vote state: %v",
+			token)
+	}
+
+	// Ensure the vote has not been scheduled
+	_, err := os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamScheduledVote,
+			defaultMDFilenameSuffix)))
+	if err == nil {
+		return fmt.Errorf("proposal vote already scheduled: %v",
+			token)
+	}
+
+	// Ensure vote authorization has not been revoked
+	b, err := ioutil.ReadFile(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamAuthorizeVote,
+			defaultMDFilenameSuffix)))
+	if err != nil {
+		return fmt.Errorf("readfile authorizevote: %v", err)
+	}
+	av, err := bitumplugin.DecodeAuthorizeVote(b)
+	if err != nil {
+		return fmt.Errorf("DecodeAuthorizeVote: %v", err)
+	}
+	if av.Action == AuthVoteActionRevoke {
+		return fmt.Errorf("vote authorization revoked")
+	}
+
+	return nil
+}
+
+// _storeStartVote stores the start vote and the vote snapshot in the
+// metadata of the proposal.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) _storeStartVote(sv bitumplugin.StartVote, svr bitumplugin.StartVoteReply) error {
+	tokenB, err := util.ConvertStringToken(sv.Vote.Token)
+	if err != nil {
+		return fmt.Errorf("ConvertStringToken %v", err)
+	}
+	svrb, err := bitumplugin.EncodeStartVoteReply(svr)
+	if err != nil {
+		return fmt.Errorf("EncodeStartVoteReply: %v", err)
+	}
+
+	// Add version to on disk structure
+	sv.Version = bitumplugin.VersionStartVote
+	svb, err := bitumplugin.EncodeStartVote(sv)
+	if err != nil {
+		return fmt.Errorf("EncodeStartVote: %v", err)
+	}
+
+	// Store snapshot in metadata
+	err = g._updateVettedMetadata(tokenB, nil, []backend.MetadataStream{
+		{
+			ID:      bitumplugin.MDStreamVoteBits,
+			Payload: string(svb),
+		},
+		{
+			ID:      bitumplugin.MDStreamVoteSnapshot,
+			Payload: string(svrb),
+		}})
+	if err != nil {
+		return fmt.Errorf("_updateVettedMetadata: %v", err)
+	}
+
+	// Add vote to in-memory caches
+	bitumPluginVoteCache[sv.Vote.Token] = &sv
+	bitumPluginVoteSnapshotCache[sv.Vote.Token] = svr
+
+	return nil
+}
+
+func (g *gitBackEnd) pluginStartVote(payload string) (string, error) {
+	vote, err := bitumplugin.DecodeStartVote([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeStartVote %v", err)
+	}
+
+	// Runoff votes span several proposals and must be started
+	// using the start vote runoff command.
+	if vote.Vote.Type == bitumplugin.VoteTypeRunoff {
+		return "", fmt.Errorf("runoff votes must be started using %v",
+			bitumplugin.CmdStartVoteRunoff)
+	}
+
+	// Verify vote type and vote bits are somewhat sane
+	err = validateVoteOptions(vote.Vote)
+	if err != nil {
+		return "", fmt.Errorf("invalid vote options: %v", err)
+	}
+	for _, v := range vote.Vote.Options {
+		err = _validateVoteBit(vote.Vote, v.Bits)
+		if err != nil {
+			return "", fmt.Errorf("invalid vote bits: %v", err)
+		}
+	}
+
+	// Verify proposal exists
+	token := vote.Vote.Token
+	if !g.propExists(g.vetted, token) {
+		return "", fmt.Errorf("unknown proposal: %v", token)
+	}
+
+	// Votes that start at a future block height are scheduled.
+	// The snapshot is taken once the start height is reached.
+	if vote.Vote.StartHeight != 0 {
+		return g.scheduleVote(*vote)
+	}
+
+	// Get ticket pool snapshot
+	svr, err := g.voteSnapshot(vote.Vote.Duration)
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", token, err)
+	}
+
+	// Make sure vote duration is within min/max range
+	err = validateVoteDuration(vote.Vote.Duration)
+	if err != nil {
+		return "", err
+	}
+
+	svrb, err := bitumplugin.EncodeStartVoteReply(*svr)
+	if err != nil {
+		return "", fmt.Errorf("EncodeStartVoteReply: %v", err)
+	}
+
+	// Verify proposal state
+	g.Lock()
+	defer g.Unlock()
+	if g.shutdown {
+		// Make sure we are not shutting down
+		return "", backend.ErrShutdown
+	}
+
+	err = g._voteCanStart(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Store snapshot in metadata
+	err = g._storeStartVote(*vote, *svr)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Vote started for: %v snapshot %v start %v end %v",
+		token, svr.StartBlockHash, svr.StartBlockHeight,
+		svr.EndHeight)
+
+	// return success and encoded answer
+	return string(svrb), nil
+}
+
+// scheduleVote stores a vote that starts once the chain reaches the start
+// height of the vote.  The reply does not contain a snapshot since it is only
+// taken when the vote is started.
+func (g *gitBackEnd) scheduleVote(sv bitumplugin.StartVote) (string, error) {
+	token := sv.Vote.Token
+
+	// Make sure vote duration is within min/max range
+	err := validateVoteDuration(sv.Vote.Duration)
+	if err != nil {
+		return "", err
+	}
+
+	bb, err := g.chain.BestBlock()
+	if err != nil {
+		return "", fmt.Errorf("bestBlock %v", err)
+	}
+	if sv.Vote.StartHeight <= bb.Height {
+		return "", fmt.Errorf("start height must be in the future: "+
+			"%v <= %v", sv.Vote.StartHeight, bb.Height)
+	}
+
+	svrb, err := bitumplugin.EncodeStartVoteReply(
+		bitumplugin.StartVoteReply{
+			Version: bitumplugin.VersionStartVoteReply,
+		})
+	if err != nil {
+		return "", fmt.Errorf("EncodeStartVoteReply: %v", err)
+	}
+
+	// Verify proposal state
+	g.Lock()
+	defer g.Unlock()
+	if g.shutdown {
+		// Make sure we are not shutting down
+		return "", backend.ErrShutdown
+	}
+
+	err = g._voteCanStart(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Store scheduled vote in metadata
+	tokenB, err := util.ConvertStringToken(token)
+	if err != nil {
+		return "", fmt.Errorf("ConvertStringToken %v", err)
+	}
+	sv.Version = bitumplugin.VersionStartVote
+	svb, err := bitumplugin.EncodeStartVote(sv)
+	if err != nil {
+		return "", fmt.Errorf("EncodeStartVote: %v", err)
+	}
+	err = g._updateVettedMetadata(tokenB, nil, []backend.MetadataStream{
+		{
+			ID:      bitumplugin.MDStreamScheduledVote,
+			Payload: string(svb),
+		}})
+	if err != nil {
+		return "", fmt.Errorf("_updateVettedMetadata: %v", err)
+	}
+	bitumPluginScheduledVoteCache[token] = sv
+
+	log.Infof("Vote scheduled for: %v start %v", token,
+		sv.Vote.StartHeight)
+
+	return string(svrb), nil
+}
+
+// _loadScheduledVotes loads the votes that have been scheduled but not yet
+// started into the scheduled votes cache.  The metadata is only read from disk
+// the first time this function is called.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) _loadScheduledVotes() error {
+	if scheduledVotesLoaded {
+		return nil
+	}
+
+	ssFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamScheduledVote,
+		defaultMDFilenameSuffix)
+	svFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
+		defaultMDFilenameSuffix)
+	err := filepath.Walk(g.vetted,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Name() != ssFile {
+				return nil
+			}
+
+			// Skip votes that have already been started
+			_, err = os.Stat(filepath.Join(filepath.Dir(path), svFile))
+			if err == nil {
+				return nil
+			}
+
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("ReadFile %v: %v", path, err)
+			}
+			sv, err := bitumplugin.DecodeStartVote(b)
+			if err != nil {
+				return fmt.Errorf("DecodeStartVote: %v", err)
+			}
+			bitumPluginScheduledVoteCache[sv.Vote.Token] = *sv
+
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("walk vetted: %v", err)
+	}
+
+	scheduledVotesLoaded = true
+
+	return nil
+}
+
+// pluginStartScheduledVotes starts all scheduled votes whose start height has
+// been reached.  The snapshot of a scheduled vote is taken relative to its
+// start height so that the voting period does not depend on when this command
+// runs.
+func (g *gitBackEnd) pluginStartScheduledVotes() (string, error) {
+	log.Tracef("pluginStartScheduledVotes")
+
+	bb, err := g.chain.BestBlock()
+	if err != nil {
+		return "", fmt.Errorf("bestBlock %v", err)
+	}
+
+	// Compile the votes that are due
+	g.Lock()
+	if g.shutdown {
+		g.Unlock()
+		return "", backend.ErrShutdown
+	}
+	err = g._loadScheduledVotes()
+	if err != nil {
+		g.Unlock()
+		return "", err
+	}
+	due := make([]bitumplugin.StartVote, 0,
+		len(bitumPluginScheduledVoteCache))
+	for _, v := range bitumPluginScheduledVoteCache {
+		if v.Vote.StartHeight <= bb.Height {
+			due = append(due, v)
+		}
+	}
+	g.Unlock()
+
+	// Get the ticket pool snapshots. This requires chain lookups
+	// so it is done without the lock held.
+	svt := make([]bitumplugin.StartVoteTuple, 0, len(due))
+	for _, v := range due {
+		svr, err := g.voteSnapshotAt(v.Vote.StartHeight,
+			v.Vote.Duration)
+		if err != nil {
+			log.Errorf("pluginStartScheduledVotes: voteSnapshotAt "+
+				"%v: %v", v.Vote.Token, err)
+			continue
+		}
+		svt = append(svt, bitumplugin.StartVoteTuple{
+			StartVote:      v,
+			StartVoteReply: *svr,
+		})
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	if g.shutdown {
+		return "", backend.ErrShutdown
+	}
+
+	started := make([]bitumplugin.StartVoteTuple, 0, len(svt))
+	for _, v := range svt {
+		token := v.StartVote.Vote.Token
+
+		// The vote may have been started concurrently
+		if _, ok := bitumPluginScheduledVoteCache[token]; !ok {
+			continue
+		}
+
+		err = g._storeStartVote(v.StartVote, v.StartVoteReply)
+		if err != nil {
+			return "", err
+		}
+		delete(bitumPluginScheduledVoteCache, token)
+		started = append(started, v)
+
+		log.Infof("Scheduled vote started for: %v snapshot %v "+
+			"start %v end %v", token, v.StartVoteReply.StartBlockHash,
+			v.StartVoteReply.StartBlockHeight,
+			v.StartVoteReply.EndHeight)
+	}
+
+	reply, err := bitumplugin.EncodeStartScheduledVotesReply(
+		bitumplugin.StartScheduledVotesReply{
+			StartVotes: started,
+		})
+	if err != nil {
+		return "", fmt.Errorf("EncodeStartScheduledVotesReply: %v",
+			err)
+	}
+
+	return string(reply), nil
+}
+
+// pluginStartVoteRunoff starts a runoff vote between several proposals.  All
+// proposals share a single ticket snapshot and voting period.  The vote of
+// every proposal must have been authorized and none of the votes may have
+// been started.
+func (g *gitBackEnd) pluginStartVoteRunoff(payload string) (string, error) {
+	sv, err := bitumplugin.DecodeStartVoteRunoff([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeStartVoteRunoff %v", err)
+	}
+	if len(sv.StartVotes) < 2 {
+		return "", fmt.Errorf("runoff vote requires at least 2 proposals")
+	}
+
+	// Verify the votes are sane and share the same parameters
+	tokens := bitumplugin.RunoffTokens(*sv)
+	first := sv.StartVotes[0].Vote
+	seen := make(map[string]struct{}, len(sv.StartVotes))
+	for _, v := range sv.StartVotes {
+		if v.Vote.Type != bitumplugin.VoteTypeRunoff {
+			return "", fmt.Errorf("invalid vote type %v: %v",
+				v.Vote.Token, v.Vote.Type)
+		}
+		if v.Vote.StartHeight != 0 {
+			return "", fmt.Errorf("runoff votes cannot be "+
+				"scheduled: %v", v.Vote.Token)
+		}
+		if v.Vote.Duration != first.Duration ||
+			v.Vote.QuorumPercentage != first.QuorumPercentage ||
+			v.Vote.PassPercentage != first.PassPercentage {
+			return "", fmt.Errorf("vote parameters differ: %v",
+				v.Vote.Token)
+		}
+		if _, ok := seen[v.Vote.Token]; ok {
+			return "", fmt.Errorf("duplicate proposal: %v",
+				v.Vote.Token)
+		}
+		seen[v.Vote.Token] = struct{}{}
+
+		err = validateVoteOptions(v.Vote)
+		if err != nil {
+			return "", fmt.Errorf("invalid vote options %v: %v",
+				v.Vote.Token, err)
+		}
+		for _, o := range v.Vote.Options {
+			err = _validateVoteBit(v.Vote, o.Bits)
+			if err != nil {
+				return "", fmt.Errorf("invalid vote bits %v: %v",
+					v.Vote.Token, err)
+			}
+		}
+
+		if !g.propExists(g.vetted, v.Vote.Token) {
+			return "", fmt.Errorf("unknown proposal: %v",
+				v.Vote.Token)
+		}
+	}
+
+	err = validateVoteDuration(first.Duration)
+	if err != nil {
+		return "", err
 	}
-	avb, err := bitumplugin.EncodeAuthorizeVote(av)
+
+	// Get the ticket pool snapshot that is shared by all proposals
+	svr, err := g.voteSnapshot(first.Duration)
 	if err != nil {
-		return "", fmt.Errorf("EncodeAuthorizeVote: %v", err)
+		return "", err
 	}
-	tokenb, err := util.ConvertStringToken(token)
+	reply, err := bitumplugin.EncodeStartVoteRunoffReply(
+		bitumplugin.StartVoteRunoffReply{
+			StartVoteReply: *svr,
+		})
 	if err != nil {
-		return "", fmt.Errorf("ConvertStringToken %v", err)
+		return "", fmt.Errorf("EncodeStartVoteRunoffReply: %v", err)
 	}
 
-	// Verify proposal state
 	g.Lock()
 	defer g.Unlock()
 	if g.shutdown {
+		// Make sure we are not shutting down
 		return "", backend.ErrShutdown
 	}
 
-	_, err = os.Stat(pijoin(joinLatest(g.vetted, token),
-		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
-			defaultMDFilenameSuffix)))
-	if err == nil {
-		// Vote has already started. This should not happen.
-		return "", fmt.Errorf("proposal vote already started: %v",
-			token)
-	}
-
-	// Update metadata
-	err = g._updateVettedMetadata(tokenb, nil, []backend.MetadataStream{
-		{
-			ID:      bitumplugin.MDStreamAuthorizeVote,
-			Payload: string(avb),
-		},
-	})
-	if err != nil {
-		return "", fmt.Errorf("_updateVettedMetadata: %v", err)
+	// Verify the state of all proposals before any vote is started
+	// so that a runoff is never partially started.
+	for _, v := range sv.StartVotes {
+		err = g._voteCanStart(v.Vote.Token)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	// Prepare reply
-	version, err := getLatest(pijoin(g.vetted, token))
-	if err != nil {
-		return "", fmt.Errorf("getLatest: %v", err)
-	}
-	avr := bitumplugin.AuthorizeVoteReply{
-		Action:        av.Action,
-		RecordVersion: version,
-		Receipt:       av.Receipt,
-		Timestamp:     av.Timestamp,
-	}
-	avrb, err := bitumplugin.EncodeAuthorizeVoteReply(avr)
-	if err != nil {
-		return "", err
+	for _, v := range sv.StartVotes {
+		v.Vote.RunoffTokens = tokens
+		err = g._storeStartVote(v, *svr)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	log.Infof("Vote authorized for %v", token)
+	log.Infof("Runoff vote started for: %v snapshot %v start %v end %v",
+		tokens, svr.StartBlockHash, svr.StartBlockHeight,
+		svr.EndHeight)
 
-	return string(avrb), nil
+	return string(reply), nil
 }
 
-func (g *gitBackEnd) pluginStartVote(payload string) (string, error) {
-	vote, err := bitumplugin.DecodeStartVote([]byte(payload))
-	if err != nil {
-		return "", fmt.Errorf("DecodeStartVote %v", err)
-	}
+// pluginCancelVote cancels an active proposal vote.  The cancellation is
+// stored in the proposal metadata and journaled in the ballot journal so that
+// no further votes are accepted.  Cancelling a runoff vote cancels the vote of
+// every proposal in the runoff.
+func (g *gitBackEnd) pluginCancelVote(payload string) (string, error) {
+	log.Tracef("pluginCancelVote")
 
-	// Verify vote bits are somewhat sane
-	for _, v := range vote.Vote.Options {
-		err = _validateVoteBit(vote.Vote, v.Bits)
-		if err != nil {
-			return "", fmt.Errorf("invalid vote bits: %v", err)
-		}
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
 	}
 
-	// Verify proposal exists
-	tokenB, err := util.ConvertStringToken(vote.Vote.Token)
+	// Decode cancel vote
+	cancel, err := bitumplugin.DecodeCancelVote([]byte(payload))
 	if err != nil {
-		return "", fmt.Errorf("ConvertStringToken %v", err)
+		return "", fmt.Errorf("DecodeCancelVote %v", err)
+	}
+	token := cancel.Token
+	if cancel.Reason == "" {
+		return "", fmt.Errorf("cancel vote reason cannot be blank")
 	}
-	token := vote.Vote.Token
 
+	// Verify proposal exists
 	if !g.propExists(g.vetted, token) {
 		return "", fmt.Errorf("unknown proposal: %v", token)
 	}
 
-	// 1. Get best block
-	bb, err := bestBlock()
+	// Verify the vote has started and has not ended
+	sv, err := g.startVote(token)
 	if err != nil {
-		return "", fmt.Errorf("bestBlock %v", err)
-	}
-	if bb.Height < uint32(g.activeNetParams.TicketMaturity) {
-		return "", fmt.Errorf("invalid height")
+		return "", fmt.Errorf("proposal vote not started: %v", token)
 	}
-	// 2. Subtract TicketMaturity from block height to get into
-	// unforkable teritory
-	snapshotBlock, err := block(bb.Height -
-		uint32(g.activeNetParams.TicketMaturity))
+	endHeight, err := g.voteEndHeight(token)
 	if err != nil {
-		return "", fmt.Errorf("bestBlock %v", err)
+		return "", fmt.Errorf("voteEndHeight: %v", err)
 	}
-	// 3. Get ticket pool snapshot
-	snapshot, err := snapshot(snapshotBlock.Hash)
+	bb, err := g.chain.BestBlock()
 	if err != nil {
-		return "", fmt.Errorf("snapshot %v", err)
+		return "", fmt.Errorf("bestBlock %v", err)
 	}
-	if len(snapshot) == 0 {
-		return "", fmt.Errorf("no eligible voters for %v", token)
+	if bb.Height >= endHeight {
+		return "", fmt.Errorf("proposal vote has ended: %v", token)
 	}
 
-	// Make sure vote duration is within min/max range
-	// XXX calculate this value for testnet instead of using hard coded values.
-	if vote.Vote.Duration < bitumplugin.VoteDurationMin ||
-		vote.Vote.Duration > bitumplugin.VoteDurationMax {
-		// XXX return a user error instead of an internal error
-		return "", fmt.Errorf("invalid duration: %v (%v - %v)",
-			vote.Vote.Duration, bitumplugin.VoteDurationMin,
-			bitumplugin.VoteDurationMax)
+	tokens := []string{token}
+	if sv.Vote.Type == bitumplugin.VoteTypeRunoff {
+		tokens = sv.Vote.RunoffTokens
 	}
 
-	svr := bitumplugin.StartVoteReply{
-		Version: bitumplugin.VersionStartVoteReply,
-		StartBlockHeight: strconv.FormatUint(uint64(snapshotBlock.Height),
-			10),
-		StartBlockHash: snapshotBlock.Hash,
-		// On EndHeight: we start in the past, add maturity to correct
-		EndHeight: strconv.FormatUint(uint64(snapshotBlock.Height+
-			vote.Vote.Duration+
-			uint32(g.activeNetParams.TicketMaturity)), 10),
-		EligibleTickets: snapshot,
+	// Get identity
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
 	}
-	svrb, err := bitumplugin.EncodeStartVoteReply(svr)
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
 	if err != nil {
-		return "", fmt.Errorf("EncodeStartVoteReply: %v", err)
+		return "", fmt.Errorf("UnmarshalFullIdentity: %v", err)
 	}
 
-	// Add version to on disk structure
-	vote.Version = bitumplugin.VersionStartVote
-	voteb, err := bitumplugin.EncodeStartVote(*vote)
+	// Sign signature
+	r := fi.SignMessage([]byte(cancel.Signature))
+	receipt := hex.EncodeToString(r[:])
+
+	// Create on disk structure
+	cv := bitumplugin.CancelVote{
+		Version:   bitumplugin.VersionCancelVote,
+		Receipt:   receipt,
+		Timestamp: time.Now().Unix(),
+		Token:     token,
+		Reason:    cancel.Reason,
+		Signature: cancel.Signature,
+		PublicKey: cancel.PublicKey,
+	}
+	cvb, err := bitumplugin.EncodeCancelVote(cv)
 	if err != nil {
-		return "", fmt.Errorf("EncodeStartVote: %v", err)
+		return "", fmt.Errorf("EncodeCancelVote: %v", err)
 	}
 
-	// Verify proposal state
 	g.Lock()
 	defer g.Unlock()
 	if g.shutdown {
-		// Make sure we are not shutting down
 		return "", backend.ErrShutdown
 	}
 
-	_, err1 := os.Stat(pijoin(joinLatest(g.vetted, token),
-		fmt.Sprintf("%02v%v", bitumplugin.MDStreamAuthorizeVote,
-			defaultMDFilenameSuffix)))
-	_, err2 := os.Stat(pijoin(joinLatest(g.vetted, token),
-		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
-			defaultMDFilenameSuffix)))
-	_, err3 := os.Stat(pijoin(joinLatest(g.vetted, token),
-		fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteSnapshot,
-			defaultMDFilenameSuffix)))
+	for _, v := range tokens {
+		if g._voteCancelled(v) {
+			return "", fmt.Errorf("proposal vote already "+
+				"cancelled: %v", v)
+		}
+	}
 
-	if err1 != nil {
-		// Authorize vote md is not present
-		return "", fmt.Errorf("no authorize vote metadata: %v",
-			token)
-	} else if err2 != nil && err3 != nil {
-		// Vote has not started, continue
-	} else if err2 == nil && err3 == nil {
-		// Vote has started
-		return "", fmt.Errorf("proposal vote already started: %v",
-			token)
-	} else {
-		// This is bad, both files should exist or not exist
-		return "", fmt.Errorf("proposal is unknown vote state: %v",
-			token)
+	updates := make([]vettedMetadataUpdate, 0, len(tokens))
+	for _, v := range tokens {
+		tokenb, err := util.ConvertStringToken(v)
+		if err != nil {
+			return "", fmt.Errorf("ConvertStringToken %v", err)
+		}
+		updates = append(updates, vettedMetadataUpdate{
+			token: tokenb,
+			mdOverwrite: []backend.MetadataStream{
+				{
+					ID:      bitumplugin.MDStreamCancelVote,
+					Payload: string(cvb),
+				},
+			},
+		})
 	}
 
-	// Ensure vote authorization has not been revoked
-	b, err := ioutil.ReadFile(pijoin(joinLatest(g.vetted, token),
-		fmt.Sprintf("%02v%v", bitumplugin.MDStreamAuthorizeVote,
-			defaultMDFilenameSuffix)))
-	if err != nil {
-		return "", fmt.Errorf("readfile authorizevote: %v", err)
+	// A runoff vote is cancelled for all proposals or for none of them.
+	// The ballot journals are restored to their prior size if any of
+	// the journal or metadata updates fails.
+	journals := make(map[string]int64, len(tokens)) // [filename]size
+	unwind := func() {
+		for filename, size := range journals {
+			var err error
+			if size < 0 {
+				err = os.Remove(filename)
+			} else {
+				err = os.Truncate(filename, size)
+			}
+			if err != nil && !os.IsNotExist(err) {
+				// We are in trouble! Consider a panic.
+				log.Criticalf("pluginCancelVote: unwind %v: %v",
+					filename, err)
+			}
+		}
 	}
-	av, err := bitumplugin.DecodeAuthorizeVote(b)
+
+	// Journal the cancellations before storing them in the metadata
+	for _, v := range tokens {
+		dir := pijoin(g.journals, v)
+		err = os.MkdirAll(dir, 0774)
+		if err != nil {
+			unwind()
+			return "", fmt.Errorf("MkdirAll: %v", err)
+		}
+		filename := pijoin(dir, defaultBallotFilename)
+		size := int64(-1)
+		st, err := os.Stat(filename)
+		switch {
+		case err == nil:
+			size = st.Size()
+		case !os.IsNotExist(err):
+			unwind()
+			return "", fmt.Errorf("Stat: %v", err)
+		}
+		journals[filename] = size
+		err = g.journal.Journal(filename,
+			string(journalCancel)+string(cvb))
+		if err != nil {
+			unwind()
+			return "", fmt.Errorf("could not journal cancel "+
+				"vote %v: %v", v, err)
+		}
+	}
+
+	// Store cancellations in metadata
+	err = g._updateVettedMetadatas(updates)
 	if err != nil {
-		return "", fmt.Errorf("DecodeAuthorizeVote: %v", err)
+		unwind()
+		return "", fmt.Errorf("_updateVettedMetadatas: %v", err)
 	}
-	if av.Action == AuthVoteActionRevoke {
-		return "", fmt.Errorf("vote authorization revoked")
+
+	for _, v := range tokens {
+		bitumPluginVoteCancelCache[v] = true
+
+		// Mark ballot journal dirty
+		_ = os.Remove(pijoin(g.journals, v, defaultBallotFlushed))
 	}
 
-	// Store snapshot in metadata
-	err = g._updateVettedMetadata(tokenB, nil, []backend.MetadataStream{
-		{
-			ID:      bitumplugin.MDStreamVoteBits,
-			Payload: string(voteb),
-		},
-		{
-			ID:      bitumplugin.MDStreamVoteSnapshot,
-			Payload: string(svrb),
-		}})
+	cvrb, err := bitumplugin.EncodeCancelVoteReply(
+		bitumplugin.CancelVoteReply{
+			Tokens:    tokens,
+			Receipt:   cv.Receipt,
+			Timestamp: cv.Timestamp,
+		})
 	if err != nil {
-		return "", fmt.Errorf("_updateVettedMetadata: %v", err)
+		return "", err
 	}
 
-	// Add vote snapshot to in-memory cache
-	bitumPluginVoteSnapshotCache[token] = svr
-
-	log.Infof("Vote started for: %v snapshot %v start %v end %v",
-		token, svr.StartBlockHash, svr.StartBlockHeight,
-		svr.EndHeight)
+	log.Infof("Vote cancelled for %v: %v", tokens, cv.Reason)
 
-	// return success and encoded answer
-	return string(svrb), nil
+	return string(cvrb), nil
 }
 
 // validateVoteByAddress validates that vote, as specified by the commitment
@@ -1807,8 +3549,50 @@ func (i invalidVoteBitError) Error() string {
 	return i.err.Error()
 }
 
+// validateVoteOptions ensures that the vote type is known and that the vote
+// options are valid for the vote type.
+func validateVoteOptions(vote bitumplugin.Vote) error {
+	switch vote.Type {
+	case bitumplugin.VoteTypeStandard:
+		// Standard votes have no additional requirements
+		return nil
+	case bitumplugin.VoteTypeMultipleChoice:
+		if len(vote.Options) < 2 {
+			return fmt.Errorf("multiple choice vote requires at " +
+				"least 2 options")
+		}
+		return nil
+	case bitumplugin.VoteTypeApproval:
+		if len(vote.Options) < 2 {
+			return fmt.Errorf("approval vote requires at least 2 " +
+				"options")
+		}
+		// The option bits must not overlap, otherwise a cast vote
+		// that approves several options can't be decoded.
+		var seen uint64
+		for _, v := range vote.Options {
+			if seen&v.Bits != 0 {
+				return fmt.Errorf("overlapping option bits 0x%x",
+					v.Bits)
+			}
+			seen |= v.Bits
+		}
+		return nil
+	case bitumplugin.VoteTypeRunoff:
+		// Runoff votes are yes/no votes on each proposal
+		for _, v := range vote.Options {
+			if v.Id == bitumplugin.VoteOptionIDApprove {
+				return nil
+			}
+		}
+		return fmt.Errorf("runoff vote requires a %v option",
+			bitumplugin.VoteOptionIDApprove)
+	}
+	return fmt.Errorf("invalid vote type %v", vote.Type)
+}
+
 // _validateVoteBit iterates over all vote bits and ensure the sent in vote bit
-// exists.
+// exists.  Approval votes may set the bits of several options.
 func _validateVoteBit(vote bitumplugin.Vote, bit uint64) error {
 	if len(vote.Options) == 0 {
 		return fmt.Errorf("_validateVoteBit vote corrupt")
@@ -1824,6 +3608,18 @@ func _validateVoteBit(vote bitumplugin.Vote, bit uint64) error {
 				vote.Mask, bit),
 		}
 	}
+	if vote.Type == bitumplugin.VoteTypeApproval {
+		var options uint64
+		for _, v := range vote.Options {
+			options |= v.Bits
+		}
+		if options&bit == bit {
+			return nil
+		}
+		return invalidVoteBitError{
+			err: fmt.Errorf("bit not found 0x%x", bit),
+		}
+	}
 	for _, v := range vote.Options {
 		if v.Bits == bit {
 			return nil
@@ -1834,57 +3630,108 @@ func _validateVoteBit(vote bitumplugin.Vote, bit uint64) error {
 	}
 }
 
-// validateVoteBits ensures that the passed in bit is a valid vote option.
-// This function is expensive due to it's filesystem touches and therefore is
-// lazily cached. This could stand a rewrite.
-func (g *gitBackEnd) validateVoteBit(token, bit string) error {
-	b, err := strconv.ParseUint(bit, 16, 64)
-	if err != nil {
-		return err
-	}
-
+// startVote returns the start vote of the passed in proposal.  This function
+// is expensive due to it's filesystem touches and therefore is lazily cached.
+func (g *gitBackEnd) startVote(token string) (*bitumplugin.StartVote, error) {
 	g.Lock()
 	defer g.Unlock()
 	if g.shutdown {
-		return backend.ErrShutdown
+		return nil, backend.ErrShutdown
 	}
 
 	sv, ok := bitumPluginVoteCache[token]
 	if ok {
-		return _validateVoteBit(sv.Vote, b)
+		return sv, nil
 	}
 
 	// git checkout master
-	err = g.gitCheckout(g.unvetted, "master")
+	err := g.gitCheckout(g.unvetted, "master")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// git pull --ff-only --rebase
 	err = g.gitPull(g.unvetted, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Load md stream
 	f, err := os.Open(mdFilename(g.vetted, token,
 		bitumplugin.MDStreamVoteBits))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f.Close()
 
 	d := json.NewDecoder(f)
 	err = d.Decode(&sv)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	bitumPluginVoteCache[token] = sv
 
+	return sv, nil
+}
+
+// validateVoteBits ensures that the passed in bit is a valid vote option.
+// This function is expensive due to it's filesystem touches and therefore is
+// lazily cached. This could stand a rewrite.
+func (g *gitBackEnd) validateVoteBit(token, bit string) error {
+	b, err := strconv.ParseUint(bit, 16, 64)
+	if err != nil {
+		return err
+	}
+
+	sv, err := g.startVote(token)
+	if err != nil {
+		return err
+	}
+
 	return _validateVoteBit(sv.Vote, b)
 }
 
+// runoffVoteExists returns whether the ticket of the passed in vote has
+// already voted on any of the other proposals of the runoff vote that the
+// vote belongs to.  It always returns false for votes that are not part of a
+// runoff.
+func (g *gitBackEnd) runoffVoteExists(v bitumplugin.CastVote) (bool, error) {
+	sv, err := g.startVote(v.Token)
+	if err != nil {
+		return false, err
+	}
+	if sv.Vote.Type != bitumplugin.VoteTypeRunoff {
+		return false, nil
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	return _runoffVoteExists(sv, v), nil
+}
+
+// _runoffVoteExists returns whether the ticket of the passed in vote has
+// already voted on any of the other proposals of the provided runoff vote.
+//
+// This function must be called WITH the lock held.
+func _runoffVoteExists(sv *bitumplugin.StartVote, v bitumplugin.CastVote) bool {
+	if sv.Vote.Type != bitumplugin.VoteTypeRunoff {
+		return false
+	}
+
+	for _, token := range sv.Vote.RunoffTokens {
+		if token == v.Token {
+			continue
+		}
+		if _, ok := bitumPluginVotesCache[token][v.Ticket]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 // replayBallot replays voting journalfor given proposal.
 //
 // Functions must be called WITH the lock held.
@@ -1939,15 +3786,26 @@ func (g *gitBackEnd) replayBallot(token string) error {
 				// See if the prop already exists
 				if _, ok := bitumPluginVotesCache[token]; !ok {
 					// Create map to track tickets
-					bitumPluginVotesCache[token] = make(map[string]struct{})
+					bitumPluginVotesCache[token] = make(map[string]string)
 				}
-				// See if we have a duplicate vote
-				if _, ok := bitumPluginVotesCache[token][ticket]; ok {
+				// See if we have a duplicate vote.  A revote
+				// differs from the latest vote of the ticket.
+				if _isLatestVote(cvj.CastVote) {
 					log.Errorf("duplicate cast vote %v %v",
 						token, ticket)
 				}
-				// All good, record vote in cache
-				bitumPluginVotesCache[token][ticket] = struct{}{}
+				// All good, record vote in cache
+				bitumPluginVotesCache[token][ticket] =
+					cvj.CastVote.Signature
+
+			case journalActionCancel:
+				var cv bitumplugin.CancelVote
+				err = d.Decode(&cv)
+				if err != nil {
+					return fmt.Errorf("journal cancel: %v",
+						err)
+				}
+				bitumPluginVoteCancelCache[token] = true
 
 			default:
 				return fmt.Errorf("invalid action: %v",
@@ -1965,14 +3823,14 @@ func (g *gitBackEnd) replayBallot(token string) error {
 	return nil
 }
 
-// voteEndHeight returns the end height for the voting period of the passed in
-// proposal.  This function is expensive due to it's filesystem touches and
-// therefore is lazily cached.
-func (g *gitBackEnd) voteEndHeight(token string) (uint32, error) {
+// startVoteReply returns the StartVoteReply of the passed in proposal.  This
+// function is expensive due to it's filesystem touches and therefore is lazily
+// cached.
+func (g *gitBackEnd) startVoteReply(token string) (*bitumplugin.StartVoteReply, error) {
 	g.Lock()
 	defer g.Unlock()
 	if g.shutdown {
-		return 0, backend.ErrShutdown
+		return nil, backend.ErrShutdown
 	}
 
 	svr, ok := bitumPluginVoteSnapshotCache[token]
@@ -1980,32 +3838,43 @@ func (g *gitBackEnd) voteEndHeight(token string) (uint32, error) {
 		// git checkout master
 		err := g.gitCheckout(g.unvetted, "master")
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		// git pull --ff-only --rebase
 		err = g.gitPull(g.unvetted, true)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		// Load md stream
 		f, err := os.Open(mdFilename(g.vetted, token,
 			bitumplugin.MDStreamVoteSnapshot))
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		defer f.Close()
 
 		d := json.NewDecoder(f)
 		err = d.Decode(&svr)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		bitumPluginVoteSnapshotCache[token] = svr
 	}
 
+	return &svr, nil
+}
+
+// voteEndHeight returns the end height for the voting period of the passed in
+// proposal.
+func (g *gitBackEnd) voteEndHeight(token string) (uint32, error) {
+	svr, err := g.startVoteReply(token)
+	if err != nil {
+		return 0, err
+	}
+
 	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 64)
 	if err != nil {
 		return 0, err
@@ -2018,11 +3887,245 @@ func (g *gitBackEnd) voteEndHeight(token string) (uint32, error) {
 // not exists it is replayed from disk. If the vote exists in the cache the
 // functions returns true.
 //
+// When revotes are allowed a ticket that has already voted may vote again, in
+// which case the vote only exists when it is identical to the latest vote of
+// the ticket.  A ticket may therefore change its vote back to a vote bit it
+// voted for before.
+//
 // Functions must be called WITH the lock held.
-func (g *gitBackEnd) voteExists(v bitumplugin.CastVote) (bool, error) {
+func (g *gitBackEnd) voteExists(v bitumplugin.CastVote, allowRevote bool) (bool, error) {
 	// Check if journal exists, use fast path
 	_, ok := bitumPluginVotesCache[v.Token][v.Ticket]
-	return ok, nil
+	if !ok || !allowRevote {
+		return ok, nil
+	}
+	return _isLatestVote(v), nil
+}
+
+// _isLatestVote returns whether the passed in vote is identical to the latest
+// vote cast by its ticket.  Vote signatures are deterministic so the
+// signatures are compared.
+//
+// This function must be called WITH the lock held.
+func _isLatestVote(v bitumplugin.CastVote) bool {
+	sig, ok := bitumPluginVotesCache[v.Token][v.Ticket]
+	return ok && sig == v.Signature
+}
+
+// _voteCancelled returns whether the vote of the passed in proposal has been
+// cancelled.  The result is cached in memory.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) _voteCancelled(token string) bool {
+	cancelled, ok := bitumPluginVoteCancelCache[token]
+	if ok {
+		return cancelled
+	}
+
+	_, err := os.Stat(pijoin(joinLatest(g.vetted, token),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamCancelVote,
+			defaultMDFilenameSuffix)))
+	cancelled = err == nil
+	bitumPluginVoteCancelCache[token] = cancelled
+
+	return cancelled
+}
+
+// ballotVote is a cast vote of a ballot that passed the initial validation
+// and is pending signature verification.
+type ballotVote struct {
+	index   int                  // Index of the vote in the ballot
+	vote    bitumplugin.CastVote // Cast vote
+	address CommitmentAddress    // Largest commitment address of the ticket
+	receipt string               // Server signature of the vote signature
+	err     string               // Receipt error
+}
+
+// validateBallotVote runs the validation of a cast vote that does not require
+// any chain data besides the best block height.  The returned error is meant
+// to be sent back to the client.
+func (g *gitBackEnd) validateBallotVote(v bitumplugin.CastVote, bestBlock uint32) error {
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, v.Token) {
+		log.Errorf("pluginBallot: proposal not found: %v", v.Token)
+		return fmt.Errorf("proposal not found: %v", v.Token)
+	}
+
+	// Lookup whether the vote allows tickets to revote
+	sv, err := g.startVote(v.Token)
+	if err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: startVote %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		return fmt.Errorf("internal error %v", t)
+	}
+
+	// Replay individual votes journal
+	g.Lock()
+	cancelled := g._voteCancelled(v.Token)
+	dup, err := g.voteExists(v, sv.Vote.AllowRevote)
+	g.Unlock()
+	if cancelled {
+		return fmt.Errorf("vote has been cancelled: %v", v.Token)
+	}
+	if err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: voteExists %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		return fmt.Errorf("internal error %v", t)
+	}
+	if dup {
+		return fmt.Errorf("duplicate vote: %v", v.Token)
+	}
+
+	// Ensure that the votebits are correct
+	err = g.validateVoteBit(v.Token, v.VoteBit)
+	if err != nil {
+		if e, ok := err.(invalidVoteBitError); ok {
+			return e.err
+		}
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: validateVoteBit %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		return fmt.Errorf("internal error %v", t)
+	}
+
+	// Each ticket may only vote once across all proposals of
+	// a runoff vote.
+	dup, err = g.runoffVoteExists(v)
+	if err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: runoffVoteExists %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		return fmt.Errorf("internal error %v", t)
+	}
+	if dup {
+		return fmt.Errorf("duplicate runoff vote: %v", v.Token)
+	}
+
+	// Verify voting period has not ended
+	endHeight, err := g.voteEndHeight(v.Token)
+	if err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: voteEndHeight %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		return fmt.Errorf("internal error %v", t)
+	}
+	if bestBlock >= endHeight {
+		return fmt.Errorf("vote has ended: %v", v.Token)
+	}
+
+	return nil
+}
+
+// batchCommitmentAddresses looks up the largest commitment addresses of the
+// provided tickets in batches of ballotBatchSize tickets.  The batches are
+// looked up concurrently.  The returned slice is in the same order as the
+// provided tickets.
+func (g *gitBackEnd) batchCommitmentAddresses(tickets []string) ([]CommitmentAddress, error) {
+	r := make([]CommitmentAddress, len(tickets))
+
+	var (
+		wg   sync.WaitGroup
+		mtx  sync.Mutex
+		rerr error
+	)
+	sem := make(chan struct{}, ballotLookupWorkers)
+	for start := 0; start < len(tickets); start += ballotBatchSize {
+		end := start + ballotBatchSize
+		if end > len(tickets) {
+			end = len(tickets)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start, end int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			ca, err := g.chain.LargestCommitmentAddresses(
+				tickets[start:end])
+			if err == nil && len(ca) != end-start {
+				err = fmt.Errorf("unexpected number of "+
+					"commitment addresses: got %v, want %v",
+					len(ca), end-start)
+			}
+			if err != nil {
+				mtx.Lock()
+				if rerr == nil {
+					rerr = err
+				}
+				mtx.Unlock()
+				return
+			}
+			copy(r[start:end], ca)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if rerr != nil {
+		return nil, rerr
+	}
+	return r, nil
+}
+
+// verifyBallotVotes verifies the signatures of the provided ballot votes
+// using a pool of workers and signs the receipts of the votes that are valid.
+// The result of each vote is recorded in the ballot vote itself.
+func (g *gitBackEnd) verifyBallotVotes(votes []ballotVote, fi *identity.FullIdentity) {
+	workers := runtime.NumCPU()
+	if workers > len(votes) {
+		workers = len(votes)
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				g.verifyBallotVote(&votes[k], fi)
+			}
+		}()
+	}
+	for k := range votes {
+		jobs <- k
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// verifyBallotVote verifies the signature of a single ballot vote and signs
+// its receipt when it is valid.
+func (g *gitBackEnd) verifyBallotVote(bv *ballotVote, fi *identity.FullIdentity) {
+	v := bv.vote
+
+	// See if there was an error for this address
+	if bv.address.Err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: ticketAddresses %v %v %v %v",
+			v.Ticket, v.Token, t, bv.address.Err)
+		bv.err = fmt.Sprintf("internal error %v", t)
+		return
+	}
+
+	// Verify that vote is signed correctly
+	err := g.validateVoteByAddress(v.Token, v.Ticket, bv.address.Address,
+		v.VoteBit, v.Signature)
+	if err != nil {
+		t := time.Now().Unix()
+		log.Errorf("pluginBallot: validateVote %v %v %v %v",
+			v.Ticket, v.Token, t, err)
+		bv.err = fmt.Sprintf("internal error %v", t)
+		return
+	}
+
+	// Sign signature
+	r := fi.SignMessage([]byte(v.Signature))
+	bv.receipt = hex.EncodeToString(r[:])
 }
 
 func (g *gitBackEnd) pluginBallot(payload string) (string, error) {
@@ -2050,123 +4153,140 @@ func (g *gitBackEnd) pluginBallot(payload string) (string, error) {
 	}
 
 	// Get best block
-	bb, err := bestBlock()
+	bb, err := g.chain.BestBlock()
 	if err != nil {
 		return "", fmt.Errorf("bestBlock %v", err)
 	}
 
+	br := bitumplugin.BallotReply{
+		Receipts: make([]bitumplugin.CastVoteReply, len(ballot.Votes)),
+	}
+
+	// Run the inexpensive validation first so that the commitment
+	// addresses are only looked up for votes that can be valid.
+	candidates := make([]ballotVote, 0, len(ballot.Votes))
+	for k, v := range ballot.Votes {
+		err := g.validateBallotVote(v, bb.Height)
+		if err != nil {
+			br.Receipts[k].Error = err.Error()
+			continue
+		}
+		candidates = append(candidates, ballotVote{
+			index: k,
+			vote:  v,
+		})
+	}
+
 	// Obtain all largest commitment addresses. Assume everything was sent
 	// in correct.
-	tickets := make([]string, 0, len(ballot.Votes))
-	for _, v := range ballot.Votes {
-		tickets = append(tickets, v.Ticket)
+	tickets := make([]string, 0, len(candidates))
+	for _, v := range candidates {
+		tickets = append(tickets, v.vote.Ticket)
 	}
-	ticketAddresses, err := largestCommitmentAddresses(tickets)
+	ticketAddresses, err := g.batchCommitmentAddresses(tickets)
 	if err != nil {
 		return "", err
 	}
-
-	br := bitumplugin.BallotReply{
-		Receipts: make([]bitumplugin.CastVoteReply, len(ballot.Votes)),
+	for k := range candidates {
+		candidates[k].address = ticketAddresses[k]
 	}
-	for k, v := range ballot.Votes {
-		// Verify proposal exists, we can run this lockless
-		if !g.propExists(g.vetted, v.Token) {
-			log.Errorf("pluginBallot: proposal not found: %v",
-				v.Token)
-			br.Receipts[k].Error = "proposal not found: " + v.Token
+
+	// Verify the vote signatures and sign the receipts in parallel.
+	g.verifyBallotVotes(candidates, fi)
+
+	// Journal the valid votes in ballot order.
+	for _, c := range candidates {
+		k, v := c.index, c.vote
+		if c.err != "" {
+			br.Receipts[k].Error = c.err
 			continue
 		}
 
-		// Replay individual votes journal
-		dup, err := g.voteExists(v)
+		sv, err := g.startVote(v.Token)
 		if err != nil {
 			t := time.Now().Unix()
-			log.Errorf("pluginBallot: voteExists %v %v %v %v",
+			log.Errorf("pluginBallot: startVote %v %v %v %v",
 				v.Ticket, v.Token, t, err)
 			br.Receipts[k].Error = fmt.Sprintf("internal error %v",
 				t)
 			continue
 		}
-		if dup {
-			br.Receipts[k].Error = "duplicate vote: " + v.Token
-			continue
-		}
 
-		// Ensure that the votebits are correct
-		err = g.validateVoteBit(v.Token, v.VoteBit)
-		if err != nil {
-			if e, ok := err.(invalidVoteBitError); ok {
-				br.Receipts[k].Error = e.err.Error()
-				continue
+		// The duplicate vote checks are repeated since the same
+		// ticket may appear more than once in a ballot or be cast
+		// by a concurrent ballot.  The vote may also have been
+		// cancelled in the meantime.  The checks and the reservation
+		// of the ticket in the votes cache happen in a single
+		// critical section so that only one vote per ticket makes it
+		// to the journal.
+		g.Lock()
+		prev, revote := bitumPluginVotesCache[v.Token][v.Ticket]
+		dup, err := g.voteExists(v, sv.Vote.AllowRevote)
+		switch {
+		case err != nil:
+		case g._voteCancelled(v.Token):
+			br.Receipts[k].Error = "vote has been cancelled: " +
+				v.Token
+		case dup:
+			br.Receipts[k].Error = "duplicate vote: " + v.Token
+		case _runoffVoteExists(sv, v):
+			br.Receipts[k].Error = "duplicate runoff vote: " +
+				v.Token
+		default:
+			if _, ok := bitumPluginVotesCache[v.Token]; !ok {
+				bitumPluginVotesCache[v.Token] =
+					make(map[string]string)
 			}
-			t := time.Now().Unix()
-			log.Errorf("pluginBallot: validateVoteBit %v %v %v %v",
-				v.Ticket, v.Token, t, err)
-			br.Receipts[k].Error = fmt.Sprintf("internal error %v",
-				t)
-			continue
+			bitumPluginVotesCache[v.Token][v.Ticket] = v.Signature
 		}
-
-		// Verify voting period has not ended
-		endHeight, err := g.voteEndHeight(v.Token)
+		g.Unlock()
 		if err != nil {
 			t := time.Now().Unix()
-			log.Errorf("pluginBallot: voteEndHeight %v %v %v %v",
+			log.Errorf("pluginBallot: voteExists %v %v %v %v",
 				v.Ticket, v.Token, t, err)
 			br.Receipts[k].Error = fmt.Sprintf("internal error %v",
 				t)
 			continue
 		}
-		if bb.Height >= endHeight {
-			br.Receipts[k].Error = "vote has ended: " + v.Token
+		if br.Receipts[k].Error != "" {
 			continue
 		}
 
-		// See if there was an error for this address
-		if ticketAddresses[k].err != nil {
-			t := time.Now().Unix()
-			log.Errorf("pluginBallot: ticketAddresses %v %v %v %v",
-				v.Ticket, v.Token, t, err)
-			br.Receipts[k].Error = fmt.Sprintf("internal error %v",
-				t)
-			continue
-
+		// We create an unwind function that MUST be called from all
+		// error paths.  It releases the reservation of the ticket.
+		unwind := func() {
+			g.Lock()
+			if revote {
+				bitumPluginVotesCache[v.Token][v.Ticket] = prev
+			} else {
+				delete(bitumPluginVotesCache[v.Token], v.Ticket)
+			}
+			g.Unlock()
 		}
 
-		// Verify that vote is signed correctly
-		err = g.validateVoteByAddress(v.Token, v.Ticket,
-			ticketAddresses[k].bestAddr, v.VoteBit, v.Signature)
-		if err != nil {
-			t := time.Now().Unix()
-			log.Errorf("pluginBallot: validateVote %v %v %v %v",
-				v.Ticket, v.Token, t, err)
-			br.Receipts[k].Error = fmt.Sprintf("internal error %v",
-				t)
-			continue
-		}
 		br.Receipts[k].ClientSignature = v.Signature
+		br.Receipts[k].Signature = c.receipt
+		br.Receipts[k].BlockHeight = bb.Height
 
 		dir := pijoin(g.journals, v.Token)
 		bfilename := pijoin(dir, defaultBallotFilename)
 		err = os.MkdirAll(dir, 0774)
 		if err != nil {
+			unwind()
 			// Should not fail, so return failure to alert people
 			return "", fmt.Errorf("EncodeCastVoteJournal: %v", err)
 		}
 
-		// Sign signature
-		r := fi.SignMessage([]byte(v.Signature))
-		receipt := hex.EncodeToString(r[:])
-		br.Receipts[k].Signature = receipt
-
 		// Create Journal entry
 		cvj := CastVoteJournal{
-			CastVote: v,
-			Receipt:  receipt,
+			CastVote:    v,
+			Receipt:     c.receipt,
+			Address:     c.address.Address,
+			BlockHeight: bb.Height,
 		}
 		blob, err := encodeCastVoteJournal(cvj)
 		if err != nil {
+			unwind()
 			// Should not fail, so return failure to alert people
 			return "", fmt.Errorf("EncodeCastVoteJournal: %v", err)
 		}
@@ -2175,19 +4295,12 @@ func (g *gitBackEnd) pluginBallot(payload string) (string, error) {
 		err = g.journal.Journal(bfilename, string(journalAdd)+
 			string(blob))
 		if err != nil {
+			unwind()
 			// Should not fail, so return failure to alert people
 			return "", fmt.Errorf("could not journal vote %v: %v %v",
 				v.Token, v.Ticket, err)
 		}
 
-		// Add to cache
-		g.Lock()
-		if _, ok := bitumPluginVotesCache[v.Token]; !ok {
-			bitumPluginVotesCache[v.Token] = make(map[string]struct{})
-		}
-		bitumPluginVotesCache[v.Token][v.Ticket] = struct{}{}
-		g.Unlock()
-
 		// Mark comment journal dirty
 		flushFilename := pijoin(g.journals, v.Token,
 			defaultBallotFlushed)
@@ -2204,10 +4317,10 @@ func (g *gitBackEnd) pluginBallot(payload string) (string, error) {
 	return string(brb), nil
 }
 
-// tallyVotes replays the ballot journal for a proposal and tallies the votes.
-//
-// Function must be called WITH the lock held.
-func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
+// castVoteJournals replays the ballot journal for a proposal and returns all
+// cast vote journal entries.  This includes the votes that were superseded by
+// a revote.
+func (g *gitBackEnd) castVoteJournals(token string) ([]CastVoteJournal, error) {
 	// Do some cheap things before expensive calls
 	bfilename := pijoin(g.journals, token, defaultBallotFilename)
 
@@ -2217,7 +4330,7 @@ func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
 		if !os.IsNotExist(err) {
 			return nil, fmt.Errorf("journal.Open: %v", err)
 		}
-		return []bitumplugin.CastVote{}, nil
+		return []CastVoteJournal{}, nil
 	}
 	defer func() {
 		err = g.journal.Close(bfilename)
@@ -2226,7 +4339,7 @@ func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
 		}
 	}()
 
-	cv := make([]bitumplugin.CastVote, 0, 41000)
+	cv := make([]CastVoteJournal, 0, 41000)
 	for {
 		err = g.journal.Replay(bfilename, func(s string) error {
 			ss := bytes.NewReader([]byte(s))
@@ -2247,7 +4360,10 @@ func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
 					return fmt.Errorf("journal add: %v",
 						err)
 				}
-				cv = append(cv, cvj.CastVote)
+				cv = append(cv, cvj)
+
+			case journalActionCancel:
+				// Vote cancellations are not votes
 
 			default:
 				return fmt.Errorf("invalid action: %v",
@@ -2265,6 +4381,44 @@ func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
 	return cv, nil
 }
 
+// latestCastVoteJournals returns the latest cast vote journal entry of every
+// ticket.  A ticket only has more than one entry when it revoted.  The
+// entries are returned in journal order.
+func latestCastVoteJournals(cvj []CastVoteJournal) []CastVoteJournal {
+	latest := make(map[string]int, len(cvj)) // [ticket]index
+	for k, v := range cvj {
+		latest[v.CastVote.Ticket] = k
+	}
+	if len(latest) == len(cvj) {
+		return cvj
+	}
+
+	r := make([]CastVoteJournal, 0, len(latest))
+	for k, v := range cvj {
+		if latest[v.CastVote.Ticket] == k {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// tallyVotes returns the latest cast vote of every ticket that voted on the
+// passed in proposal.
+func (g *gitBackEnd) tallyVotes(token string) ([]bitumplugin.CastVote, error) {
+	cvj, err := g.castVoteJournals(token)
+	if err != nil {
+		return nil, err
+	}
+	cvj = latestCastVoteJournals(cvj)
+
+	cv := make([]bitumplugin.CastVote, 0, len(cvj))
+	for _, v := range cvj {
+		cv = append(cv, v.CastVote)
+	}
+
+	return cv, nil
+}
+
 // pluginProposalVotes tallies all votes for a proposal. We can run the tally
 // unlocked and just replay the journal. If the replay becomes an issue we
 // could cache it. The Vote that is returned does have to be locked.
@@ -2341,6 +4495,138 @@ nodata:
 	return string(reply), nil
 }
 
+// pluginVoteResultsBundle returns the signed results bundle of a finished
+// proposal vote.  The bundle can be used to verify the vote outcome offline.
+func (g *gitBackEnd) pluginVoteResultsBundle(payload string) (string, error) {
+	log.Tracef("pluginVoteResultsBundle: %v", payload)
+
+	// Check if journals were replayed
+	if !journalsReplayed {
+		return "", backend.ErrJournalsNotReplayed
+	}
+
+	gvrb, err := bitumplugin.DecodeGetVoteResultsBundle([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeGetVoteResultsBundle: %v", err)
+	}
+	token := gvrb.Token
+
+	// Verify proposal exists, we can run this lockless
+	if !g.propExists(g.vetted, token) {
+		return "", fmt.Errorf("proposal not found: %v", token)
+	}
+
+	// XXX this should become part of some sort of context
+	fiJSON, ok := bitumPluginSettings[bitumPluginIdentity]
+	if !ok {
+		return "", fmt.Errorf("full identity not set")
+	}
+	fi, err := identity.UnmarshalFullIdentity([]byte(fiJSON))
+	if err != nil {
+		return "", err
+	}
+
+	// A cancelled vote has no final results
+	g.Lock()
+	cancelled := g._voteCancelled(token)
+	g.Unlock()
+	if cancelled {
+		return "", fmt.Errorf("vote has been cancelled: %v", token)
+	}
+
+	// Lookup the vote parameters and ensure the vote has ended
+	sv, err := g.startVote(token)
+	if err != nil {
+		return "", fmt.Errorf("startVote: %v", err)
+	}
+	svr, err := g.startVoteReply(token)
+	if err != nil {
+		return "", fmt.Errorf("startVoteReply: %v", err)
+	}
+	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	bb, err := g.chain.BestBlock()
+	if err != nil {
+		return "", fmt.Errorf("bestBlock %v", err)
+	}
+	if uint64(bb.Height) < endHeight {
+		return "", fmt.Errorf("vote has not ended: %v", token)
+	}
+
+	// Lookup the cast votes. Only the latest vote of each ticket
+	// counts. Votes that were journaled before the commitment
+	// address was journaled along with them need their address
+	// looked up.
+	cvj, err := g.castVoteJournals(token)
+	if err != nil {
+		return "", fmt.Errorf("castVoteJournals: %v", err)
+	}
+	cvj = latestCastVoteJournals(cvj)
+	missing := make([]int, 0, len(cvj))
+	tickets := make([]string, 0, len(cvj))
+	for k, v := range cvj {
+		if v.Address == "" {
+			missing = append(missing, k)
+			tickets = append(tickets, v.CastVote.Ticket)
+		}
+	}
+	if len(tickets) > 0 {
+		addrs, err := g.batchCommitmentAddresses(tickets)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range missing {
+			if addrs[k].Err != nil {
+				return "", fmt.Errorf("commitment address %v: %v",
+					tickets[k], addrs[k].Err)
+			}
+			cvj[v].Address = addrs[k].Address
+		}
+	}
+
+	votes := make([]bitumplugin.CastVote, 0, len(cvj))
+	receipts := make([]bitumplugin.CastVoteReceipt, 0, len(cvj))
+	for _, v := range cvj {
+		votes = append(votes, v.CastVote)
+		receipts = append(receipts, bitumplugin.CastVoteReceipt{
+			CastVote: v.CastVote,
+			Receipt:  v.Receipt,
+			Address:  v.Address,
+		})
+	}
+	results, total, err := bitumplugin.TallyVotes(*sv, votes)
+	if err != nil {
+		return "", err
+	}
+
+	// Sign the bundle
+	vrb := bitumplugin.VoteResultsBundle{
+		Version:        bitumplugin.VersionVoteResultsBundle,
+		Token:          token,
+		StartVote:      *sv,
+		StartVoteReply: *svr,
+		CastVotes:      receipts,
+		TotalVotes:     total,
+		Results:        results,
+		PublicKey:      fi.Public.String(),
+	}
+	digest, err := bitumplugin.VoteResultsBundleDigest(vrb)
+	if err != nil {
+		return "", err
+	}
+	sig := fi.SignMessage(digest)
+	vrb.Signature = hex.EncodeToString(sig[:])
+
+	reply, err := bitumplugin.EncodeVoteResultsBundle(vrb)
+	if err != nil {
+		return "", fmt.Errorf("EncodeVoteResultsBundle: %v", err)
+	}
+
+	return string(reply), nil
+}
+
 // pluginInventory returns the bitum plugin inventory for all proposals.  The
 // inventory consists of comments, like comments, vote authorizations, vote
 // details, and cast votes.
@@ -2378,6 +4664,15 @@ func (g *gitBackEnd) pluginInventory() (string, error) {
 		likes = append(likes, v...)
 	}
 
+	// Walk in-memory comment flags cache and compile all comment
+	// flags that have not been dismissed
+	flags := make([]bitumplugin.FlagComment, 0, 256)
+	for _, v := range bitumPluginCommentFlagsCache {
+		for _, f := range v {
+			flags = append(flags, f...)
+		}
+	}
+
 	// Walk vetted repo and compile all file paths
 	paths := make([]string, 0, 2048) // PNOOMA
 	err := filepath.Walk(g.vetted,
@@ -2392,20 +4687,30 @@ func (g *gitBackEnd) pluginInventory() (string, error) {
 		return "", fmt.Errorf("walk vetted: %v", err)
 	}
 
-	// Filter out the file paths for authorize vote metadata and
-	// start vote metadata
+	// Filter out the file paths for authorize vote metadata, start
+	// vote metadata, cancel vote metadata and scheduled vote metadata
 	avPaths := make([]string, 0, len(paths))
 	svPaths := make([]string, 0, len(paths))
+	cvPaths := make([]string, 0, len(paths))
+	ssPaths := make([]string, 0, len(paths))
 	avFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamAuthorizeVote,
 		defaultMDFilenameSuffix)
 	svFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamVoteBits,
 		defaultMDFilenameSuffix)
+	cvFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamCancelVote,
+		defaultMDFilenameSuffix)
+	ssFile := fmt.Sprintf("%02v%v", bitumplugin.MDStreamScheduledVote,
+		defaultMDFilenameSuffix)
 	for _, v := range paths {
 		switch filepath.Base(v) {
 		case avFile:
 			avPaths = append(avPaths, v)
 		case svFile:
 			svPaths = append(svPaths, v)
+		case cvFile:
+			cvPaths = append(cvPaths, v)
+		case ssFile:
+			ssPaths = append(ssPaths, v)
 		}
 	}
 
@@ -2481,13 +4786,53 @@ func (g *gitBackEnd) pluginInventory() (string, error) {
 		})
 	}
 
+	// Compile vote cancellations. The cancel vote of a runoff vote
+	// is stored with every proposal of the runoff so duplicates are
+	// filtered out using the receipt.
+	cvs := make([]bitumplugin.CancelVote, 0, len(cvPaths))
+	receipts := make(map[string]struct{}, len(cvPaths))
+	for _, v := range cvPaths {
+		b, err := ioutil.ReadFile(v)
+		if err != nil {
+			return "", fmt.Errorf("ReadFile %v: %v", v, err)
+		}
+		c, err := bitumplugin.DecodeCancelVote(b)
+		if err != nil {
+			return "", fmt.Errorf("DecodeCancelVote: %v", err)
+		}
+		if _, ok := receipts[c.Receipt]; ok {
+			continue
+		}
+		receipts[c.Receipt] = struct{}{}
+		cvs = append(cvs, *c)
+	}
+
+	// Compile the scheduled votes that have not been started yet
+	ss := make([]bitumplugin.StartVote, 0, len(ssPaths))
+	for _, v := range ssPaths {
+		_, err := os.Stat(filepath.Join(filepath.Dir(v), svFile))
+		if err == nil {
+			// Vote has been started
+			continue
+		}
+		b, err := ioutil.ReadFile(v)
+		if err != nil {
+			return "", fmt.Errorf("ReadFile %v: %v", v, err)
+		}
+		sv, err := bitumplugin.DecodeStartVote(b)
+		if err != nil {
+			return "", fmt.Errorf("DecodeStartVote: %v", err)
+		}
+		ss = append(ss, *sv)
+	}
+
 	// Compile cast votes. The in-memory votes cache does not
 	// store the full cast vote struct so we need to replay the
 	// vote journals.
 
-	// Walk journals directory and tally votes for all ballot
-	// journals that are found.
-	cv := make([][]bitumplugin.CastVote, 0, len(svt))
+	// Walk journals directory and replay all ballot journals
+	// that are found.
+	cv := make([][]CastVoteJournal, 0, len(svt))
 	err = filepath.Walk(g.journals,
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
@@ -2496,12 +4841,13 @@ func (g *gitBackEnd) pluginInventory() (string, error) {
 
 			if info.Name() == defaultBallotFilename {
 				token := filepath.Base(filepath.Dir(path))
-				votes, err := g.tallyVotes(token)
+				cvj, err := g.castVoteJournals(token)
 				if err != nil {
-					return fmt.Errorf("tallyVotes %v: %v", token, err)
+					return fmt.Errorf("castVoteJournals %v: %v",
+						token, err)
 				}
 
-				cv = append(cv, votes)
+				cv = append(cv, latestCastVoteJournals(cvj))
 			}
 
 			return nil
@@ -2516,18 +4862,29 @@ func (g *gitBackEnd) pluginInventory() (string, error) {
 		count += len(v)
 	}
 	votes := make([]bitumplugin.CastVote, 0, count)
+	voteReceipts := make([]string, 0, count)
+	voteHeights := make([]uint32, 0, count)
 	for _, v := range cv {
-		votes = append(votes, v...)
+		for _, j := range v {
+			votes = append(votes, j.CastVote)
+			voteReceipts = append(voteReceipts, j.Receipt)
+			voteHeights = append(voteHeights, j.BlockHeight)
+		}
 	}
 
 	// Prepare reply
 	ir := bitumplugin.InventoryReply{
 		Comments:             comments,
 		LikeComments:         likes,
+		CommentFlags:         flags,
 		AuthorizeVotes:       av,
 		AuthorizeVoteReplies: avr,
 		StartVoteTuples:      svt,
 		CastVotes:            votes,
+		CastVoteReceipts:     voteReceipts,
+		CastVoteHeights:      voteHeights,
+		CancelVotes:          cvs,
+		ScheduledVotes:       ss,
 	}
 
 	payload, err := bitumplugin.EncodeInventoryReply(ir)
@@ -0,0 +1,1007 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bitum-project/bitumd/bitumec/secp256k1"
+	"github.com/bitum-project/bitumd/bitumutil"
+	"github.com/bitum-project/bitumd/chaincfg"
+	"github.com/bitum-project/bitumd/chaincfg/chainhash"
+	"github.com/bitum-project/bitumd/wire"
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/api/v1/identity"
+	"github.com/bitum-project/politeia/politeiad/api/v1/mime"
+	"github.com/bitum-project/politeia/politeiad/backend"
+	"github.com/bitum-project/politeia/util"
+	"github.com/decred/slog"
+)
+
+// newTestBallotVotes returns count signed ballot votes for the provided
+// token along with their commitment addresses.
+func newTestBallotVotes(t testing.TB, params *chaincfg.Params, token string, count int) []ballotVote {
+	t.Helper()
+
+	votes := make([]ballotVote, 0, count)
+	for i := 0; i < count; i++ {
+		// Derive a deterministic key for the ticket
+		seed := sha256.Sum256([]byte(strconv.Itoa(i)))
+		key, pub := secp256k1.PrivKeyFromBytes(seed[:])
+		a, err := bitumutil.NewAddressSecpPubKey(pub.SerializeCompressed(),
+			params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ticket := fmt.Sprintf("%064x", i)
+		cv := bitumplugin.CastVote{
+			Token:   token,
+			Ticket:  ticket,
+			VoteBit: "2",
+		}
+
+		// Sign the vote the same way a wallet signs a message
+		var buf bytes.Buffer
+		wire.WriteVarString(&buf, 0, "Bitum Signed Message:\n")
+		wire.WriteVarString(&buf, 0, cv.Token+cv.Ticket+cv.VoteBit)
+		sig, err := secp256k1.SignCompact(key,
+			chainhash.HashB(buf.Bytes()), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cv.Signature = hex.EncodeToString(sig)
+
+		votes = append(votes, ballotVote{
+			index: i,
+			vote:  cv,
+			address: CommitmentAddress{
+				Address: a.EncodeAddress(),
+			},
+		})
+	}
+
+	return votes
+}
+
+func TestVerifyBallotVotes(t *testing.T) {
+	g := &gitBackEnd{
+		activeNetParams: &chaincfg.TestNetParams,
+	}
+	fi, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votes := newTestBallotVotes(t, g.activeNetParams,
+		hex.EncodeToString(make([]byte, 32)), 20)
+
+	// Invalidate a few votes
+	votes[3].vote.Signature = votes[4].vote.Signature
+	votes[7].address = CommitmentAddress{
+		Err: fmt.Errorf("lookup failed"),
+	}
+	votes[11].address.Address = votes[12].address.Address
+
+	g.verifyBallotVotes(votes, fi)
+
+	for k, v := range votes {
+		if v.index != k {
+			t.Fatalf("vote %v: order not preserved, got index %v",
+				k, v.index)
+		}
+		switch k {
+		case 3, 7, 11:
+			if v.err == "" || v.receipt != "" {
+				t.Fatalf("vote %v: expected error", k)
+			}
+			continue
+		}
+		if v.err != "" {
+			t.Fatalf("vote %v: unexpected error %v", k, v.err)
+		}
+		r := fi.SignMessage([]byte(v.vote.Signature))
+		if v.receipt != hex.EncodeToString(r[:]) {
+			t.Fatalf("vote %v: invalid receipt", k)
+		}
+	}
+}
+
+func TestLatestCastVoteJournals(t *testing.T) {
+	vote := func(ticket, voteBit string) CastVoteJournal {
+		return CastVoteJournal{
+			CastVote: bitumplugin.CastVote{
+				Ticket:    ticket,
+				VoteBit:   voteBit,
+				Signature: ticket + voteBit,
+			},
+		}
+	}
+
+	cvj := []CastVoteJournal{
+		vote("a", "1"),
+		vote("b", "1"),
+		vote("a", "2"),
+		vote("c", "2"),
+		vote("b", "2"),
+		vote("a", "1"),
+	}
+	latest := latestCastVoteJournals(cvj)
+
+	expected := []string{"c2", "b2", "a1"}
+	if len(latest) != len(expected) {
+		t.Fatalf("got %v votes, want %v", len(latest), len(expected))
+	}
+	for k, v := range latest {
+		if v.CastVote.Signature != expected[k] {
+			t.Fatalf("vote %v: got %v, want %v", k,
+				v.CastVote.Signature, expected[k])
+		}
+	}
+
+	// Without revotes the journal is returned as is
+	cvj = cvj[:2]
+	if len(latestCastVoteJournals(cvj)) != len(cvj) {
+		t.Fatalf("unexpected votes removed")
+	}
+}
+
+func TestVoteExistsRevote(t *testing.T) {
+	g := &gitBackEnd{}
+	token := "token"
+	bitumPluginVotesCache[token] = map[string]string{
+		"ticket": "sig1",
+	}
+	defer delete(bitumPluginVotesCache, token)
+
+	tests := []struct {
+		name        string
+		vote        bitumplugin.CastVote
+		allowRevote bool
+		want        bool
+	}{
+		{"new ticket", bitumplugin.CastVote{Token: token,
+			Ticket: "other", Signature: "sig1"}, false, false},
+		{"duplicate", bitumplugin.CastVote{Token: token,
+			Ticket: "ticket", Signature: "sig2"}, false, true},
+		{"revote", bitumplugin.CastVote{Token: token,
+			Ticket: "ticket", Signature: "sig2"}, true, false},
+		{"replayed revote", bitumplugin.CastVote{Token: token,
+			Ticket: "ticket", Signature: "sig1"}, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := g.voteExists(test.vote, test.allowRevote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVoteExistsRevoteBack(t *testing.T) {
+	g := &gitBackEnd{}
+	token := "token"
+	defer delete(bitumPluginVotesCache, token)
+
+	// Vote signatures are deterministic so a ticket voting A, B and A
+	// again produces the same signature for both A votes.
+	votes := []struct {
+		signature string
+		want      bool
+	}{
+		{"sigA", false},
+		{"sigB", false},
+		{"sigA", false},
+		{"sigA", true},
+	}
+	for k, v := range votes {
+		cv := bitumplugin.CastVote{
+			Token:     token,
+			Ticket:    "ticket",
+			Signature: v.signature,
+		}
+		got, err := g.voteExists(cv, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.want {
+			t.Fatalf("vote %v: got %v, want %v", k, got, v.want)
+		}
+		if got {
+			continue
+		}
+		if _, ok := bitumPluginVotesCache[token]; !ok {
+			bitumPluginVotesCache[token] = make(map[string]string)
+		}
+		bitumPluginVotesCache[token]["ticket"] = cv.Signature
+	}
+}
+
+func TestAddCommentRevision(t *testing.T) {
+	token := "token"
+	defer delete(bitumPluginCommentRevisionsCache, token)
+
+	c := bitumplugin.Comment{
+		Token:     token,
+		CommentID: "1",
+		Comment:   "original",
+		Signature: "sig",
+		PublicKey: "pubkey",
+		Receipt:   "receipt",
+	}
+	for k, v := range []string{"first", "second"} {
+		ec := bitumplugin.EditComment{
+			Token:     token,
+			CommentID: c.CommentID,
+			Comment:   v,
+			Signature: "sig-" + v,
+			PublicKey: "pubkey",
+			Receipt:   "receipt-" + v,
+			Timestamp: int64(k + 1),
+		}
+		addCommentRevision(token, c, ec)
+		c = applyCommentEdit(c, ec)
+	}
+
+	if c.Comment != "second" || c.Edits != 2 || c.EditTimestamp != 2 {
+		t.Fatalf("unexpected edited comment: %+v", c)
+	}
+	if c.Signature != "sig" || c.Receipt != "receipt" {
+		t.Fatalf("original signature not kept: %+v", c)
+	}
+	if c.EditSignature != "sig-second" || c.EditPublicKey != "pubkey" ||
+		c.EditReceipt != "receipt-second" {
+		t.Fatalf("unexpected edit signature: %+v", c)
+	}
+	cr := bitumPluginCommentRevisionsCache[token][c.CommentID]
+	if cr.original.Comment != "original" || cr.original.Edits != 0 {
+		t.Fatalf("unexpected original comment: %+v", cr.original)
+	}
+	if len(cr.edits) != 2 || cr.edits[0].Comment != "first" ||
+		cr.edits[1].Comment != "second" {
+		t.Fatalf("unexpected edits: %+v", cr.edits)
+	}
+}
+
+func TestVerifyCommentSignature(t *testing.T) {
+	fi, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := hex.EncodeToString(fi.Public.Key[:])
+	sign := func(msg string) string {
+		s := fi.SignMessage([]byte(msg))
+		return hex.EncodeToString(s[:])
+	}
+
+	c := bitumplugin.Comment{
+		Token:     "token",
+		ParentID:  "0",
+		CommentID: "1",
+		Comment:   "original",
+		PublicKey: pk,
+	}
+	c.Signature = sign(c.Token + c.ParentID + c.Comment)
+	if err := verifyCommentSignature(c); err != nil {
+		t.Fatalf("original: %v", err)
+	}
+
+	// Altered text
+	altered := c
+	altered.Comment = "altered"
+	if verifyCommentSignature(altered) == nil {
+		t.Fatalf("altered comment verified")
+	}
+
+	// An edited comment is verified against the edit signature
+	ec := bitumplugin.EditComment{
+		Token:     c.Token,
+		CommentID: c.CommentID,
+		Comment:   "edited",
+		PublicKey: pk,
+	}
+	ec.Signature = sign(ec.Token + ec.CommentID + ec.Comment)
+	edited := applyCommentEdit(c, ec)
+	if err := verifyCommentSignature(edited); err != nil {
+		t.Fatalf("edited: %v", err)
+	}
+	edited.Comment = c.Comment
+	if verifyCommentSignature(edited) == nil {
+		t.Fatalf("edited comment verified with original text")
+	}
+}
+
+func TestRedactCommentEntry(t *testing.T) {
+	entry := func(action []byte, payload interface{}) string {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(action) + string(b)
+	}
+	redacted := map[string]struct{}{
+		"1": {},
+	}
+
+	add := entry(journalAdd, bitumplugin.Comment{CommentID: "1",
+		Comment: "censored"})
+	edit := entry(journalEdit, bitumplugin.EditComment{CommentID: "1",
+		Comment: "censored edit"})
+	other := entry(journalAdd, bitumplugin.Comment{CommentID: "2",
+		Comment: "visible"})
+	censor := entry(journalDel, bitumplugin.CensorComment{CommentID: "1",
+		CommentHash: bitumplugin.CommentHash("censored edit")})
+
+	tests := []struct {
+		name  string
+		entry string
+		want  string
+	}{
+		{"censored add", add, entry(journalAdd,
+			bitumplugin.Comment{CommentID: "1"})},
+		{"censored edit", edit, entry(journalEdit,
+			bitumplugin.EditComment{CommentID: "1"})},
+		{"other comment", other, other},
+		{"censor", censor, censor},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := redactCommentEntry(test.entry, redacted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFlushCommentsRedaction(t *testing.T) {
+	g, cleanup := newTestBackEnd(t, NewFixtureChain(ChainFixture{}))
+	defer cleanup()
+	journalsReplayed = true
+	defer func() {
+		journalsReplayed = false
+	}()
+
+	token := newTestAuthorizedProposal(t, g)
+	newComment := func(comment string) string {
+		t.Helper()
+		payload, err := bitumplugin.EncodeNewComment(bitumplugin.NewComment{
+			Token:   token,
+			Comment: comment,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply, err := g.pluginNewComment(string(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ncr, err := bitumplugin.DecodeNewCommentReply([]byte(reply))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ncr.CommentID
+	}
+
+	// Comment 1 is deleted by its author, comment 2 is censored and
+	// comment 3 is left alone.
+	deleted := newComment("deleted text")
+	censored := newComment("censored text")
+	newComment("visible text")
+
+	payload, err := bitumplugin.EncodeDeleteComment(bitumplugin.DeleteComment{
+		Token:     token,
+		CommentID: deleted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.pluginDeleteComment(string(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err = bitumplugin.EncodeCensorComment(bitumplugin.CensorComment{
+		Token:     token,
+		CommentID: censored,
+		Reason:    "spam",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.pluginCensorComment(string(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() string {
+		t.Helper()
+		g.Lock()
+		filename, err := g.flushComments(token)
+		g.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(pijoin(g.unvetted, filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// The text of the removed comments must not be flushed into git
+	got := flush()
+	for _, v := range []string{"deleted text", "censored text"} {
+		if strings.Contains(got, v) {
+			t.Fatalf("flushed journal contains %q", v)
+		}
+	}
+	if !strings.Contains(got, "visible text") {
+		t.Fatalf("flushed journal is missing visible comment")
+	}
+
+	// The deletion is also redacted after the journal is replayed
+	bitumPluginDeletedCommentsCache = make(map[string]map[string]struct{})
+	_, err = g.replayComments(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(flush(), "deleted text") {
+		t.Fatalf("flushed journal contains deleted text after replay")
+	}
+}
+
+func BenchmarkVerifyBallotVotes(b *testing.B) {
+	g := &gitBackEnd{
+		activeNetParams: &chaincfg.TestNetParams,
+	}
+	fi, err := identity.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	votes := newTestBallotVotes(b, g.activeNetParams,
+		hex.EncodeToString(make([]byte, 32)), 1000)
+
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for k := range votes {
+				g.verifyBallotVote(&votes[k], fi)
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.verifyBallotVotes(votes, fi)
+		}
+	})
+}
+
+// newTestBackEnd returns a gitBackEnd in a temporary directory that uses the
+// provided chain.  The returned function removes the directory and resets the
+// in-memory plugin caches.
+func newTestBackEnd(t *testing.T, chain ChainProvider) (*gitBackEnd, func()) {
+	t.Helper()
+
+	log := slog.NewBackend(&testWriter{t}).Logger("TEST")
+	UseLogger(log)
+
+	dir, err := ioutil.TempDir("", "politeia.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := New(&chaincfg.TestNetParams, dir, "", "", fi,
+		testing.Verbose(), chain)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	g.test = true
+
+	return g, func() {
+		os.RemoveAll(dir)
+		bitumPluginScheduledVoteCache = make(map[string]bitumplugin.StartVote)
+		scheduledVotesLoaded = false
+	}
+}
+
+// newTestAuthorizedProposal creates a vetted record whose vote has been
+// authorized and returns its token.
+func newTestAuthorizedProposal(t *testing.T, g *gitBackEnd) string {
+	t.Helper()
+
+	r, err := util.Random(64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := hex.EncodeToString(r)
+	rm, err := g.New([]backend.MetadataStream{}, []backend.File{{
+		Name:    "index.md",
+		MIME:    mime.DetectMimeType([]byte(payload)),
+		Digest:  hex.EncodeToString(util.Digest([]byte(payload))),
+		Payload: base64.StdEncoding.EncodeToString([]byte(payload)),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	av, err := bitumplugin.EncodeAuthorizeVote(bitumplugin.AuthorizeVote{
+		Version: bitumplugin.VersionAuthorizeVote,
+		Action:  AuthVoteActionAuthorize,
+		Token:   rm.Token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := hex.DecodeString(rm.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.SetUnvettedStatus(token, backend.MDStatusVetted,
+		[]backend.MetadataStream{}, []backend.MetadataStream{{
+			ID:      bitumplugin.MDStreamAuthorizeVote,
+			Payload: string(av),
+		}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rm.Token
+}
+
+// newTestStartVote returns a standard start vote for the provided token that
+// starts at the provided height.
+func newTestStartVote(t *testing.T, token string, startHeight uint32) string {
+	t.Helper()
+
+	sv := bitumplugin.StartVote{
+		Vote: bitumplugin.Vote{
+			Token:            token,
+			Mask:             0x03,
+			Duration:         bitumplugin.VoteDurationMin,
+			QuorumPercentage: 20,
+			PassPercentage:   60,
+			Type:             bitumplugin.VoteTypeStandard,
+			StartHeight:      startHeight,
+			Options: []bitumplugin.VoteOption{
+				{Id: "no", Bits: 0x01},
+				{Id: "yes", Bits: 0x02},
+			},
+		},
+	}
+	b, err := bitumplugin.EncodeStartVote(sv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestScheduledVotes(t *testing.T) {
+	chain := NewFixtureChain(ChainFixture{
+		BestBlock: 1000,
+		Tickets:   []string{"ticket1", "ticket2"},
+	})
+	g, cleanup := newTestBackEnd(t, chain)
+	defer cleanup()
+
+	startScheduled := func() []bitumplugin.StartVoteTuple {
+		t.Helper()
+		reply, err := g.pluginStartScheduledVotes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ssvr, err := bitumplugin.DecodeStartScheduledVotesReply(
+			[]byte(reply))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ssvr.StartVotes
+	}
+
+	// A scheduled vote must start in the future
+	token1 := newTestAuthorizedProposal(t, g)
+	_, err := g.pluginStartVote(newTestStartVote(t, token1, 1000))
+	if err == nil {
+		t.Fatalf("expected error for start height in the past")
+	}
+
+	// Schedule the vote.  No snapshot is taken yet.
+	reply, err := g.pluginStartVote(newTestStartVote(t, token1, 1010))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr, err := bitumplugin.DecodeStartVoteReply([]byte(reply))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svr.StartBlockHash != "" || len(svr.EligibleTickets) != 0 {
+		t.Fatalf("unexpected snapshot for scheduled vote: %+v", svr)
+	}
+
+	// The vote can neither be scheduled nor started twice
+	_, err = g.pluginStartVote(newTestStartVote(t, token1, 1020))
+	if err == nil {
+		t.Fatalf("expected error for rescheduled vote")
+	}
+	_, err = g.pluginStartVote(newTestStartVote(t, token1, 0))
+	if err == nil {
+		t.Fatalf("expected error for starting a scheduled vote")
+	}
+
+	// Nothing starts before the start height is reached
+	if sv := startScheduled(); len(sv) != 0 {
+		t.Fatalf("unexpected started votes: %v", len(sv))
+	}
+
+	// Schedule a second vote and forget the in-memory cache so
+	// that the scheduled votes are loaded from disk.
+	token2 := newTestAuthorizedProposal(t, g)
+	_, err = g.pluginStartVote(newTestStartVote(t, token2, 1020))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitumPluginScheduledVoteCache = make(map[string]bitumplugin.StartVote)
+	scheduledVotesLoaded = false
+
+	// The first vote starts once its start height is reached.  The
+	// snapshot is taken relative to the start height.
+	chain.SetBestBlock(1015)
+	sv := startScheduled()
+	if len(sv) != 1 || sv[0].StartVote.Vote.Token != token1 {
+		t.Fatalf("unexpected started votes: %+v", sv)
+	}
+	maturity := uint32(g.activeNetParams.TicketMaturity)
+	svr = &sv[0].StartVoteReply
+	wantStart := strconv.FormatUint(uint64(1010-maturity), 10)
+	wantEnd := strconv.FormatUint(uint64(1010+
+		bitumplugin.VoteDurationMin), 10)
+	if svr.StartBlockHeight != wantStart || svr.EndHeight != wantEnd ||
+		len(svr.EligibleTickets) != 2 {
+		t.Fatalf("unexpected snapshot: %+v", svr)
+	}
+	err = g._voteCanStart(token1)
+	if err == nil {
+		t.Fatalf("expected started vote")
+	}
+
+	// The second vote starts later and neither starts again
+	chain.SetBestBlock(1020)
+	sv = startScheduled()
+	if len(sv) != 1 || sv[0].StartVote.Vote.Token != token2 {
+		t.Fatalf("unexpected started votes: %+v", sv)
+	}
+	if sv := startScheduled(); len(sv) != 0 {
+		t.Fatalf("unexpected started votes: %v", len(sv))
+	}
+
+	// Started votes are not loaded from disk again
+	bitumPluginScheduledVoteCache = make(map[string]bitumplugin.StartVote)
+	scheduledVotesLoaded = false
+	if sv := startScheduled(); len(sv) != 0 {
+		t.Fatalf("unexpected started votes after reload: %v", len(sv))
+	}
+}
+
+func TestCancelVote(t *testing.T) {
+	g, cleanup := newTestBackEnd(t, NewFixtureChain(ChainFixture{}))
+	defer cleanup()
+	journalsReplayed = true
+	defer func() {
+		journalsReplayed = false
+	}()
+
+	// Start a vote that two tickets are eligible to vote in
+	token := newTestAuthorizedProposal(t, g)
+	votes := newTestBallotVotes(t, g.activeNetParams, token, 2)
+	fixture := ChainFixture{
+		BestBlock:   1000,
+		Commitments: make(map[string]string, len(votes)),
+	}
+	for _, v := range votes {
+		fixture.Tickets = append(fixture.Tickets, v.vote.Ticket)
+		fixture.Commitments[v.vote.Ticket] = v.address.Address
+	}
+	chain := NewFixtureChain(fixture)
+	g.chain = chain
+	reply, err := g.pluginStartVote(newTestStartVote(t, token, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr, err := bitumplugin.DecodeStartVoteReply([]byte(reply))
+	if err != nil {
+		t.Fatal(err)
+	}
+	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ballot := func(cv bitumplugin.CastVote) bitumplugin.CastVoteReply {
+		t.Helper()
+		payload, err := bitumplugin.EncodeBallot(bitumplugin.Ballot{
+			Votes: []bitumplugin.CastVote{cv},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply, err := g.pluginBallot(string(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		br, err := bitumplugin.DecodeBallotReply([]byte(reply))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return br.Receipts[0]
+	}
+	cancel := func(reason string) error {
+		t.Helper()
+		payload, err := bitumplugin.EncodeCancelVote(bitumplugin.CancelVote{
+			Token:     token,
+			Reason:    reason,
+			Signature: "signature",
+			PublicKey: "publickey",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.pluginCancelVote(string(payload))
+		return err
+	}
+
+	// Votes are accepted until the vote is cancelled
+	if r := ballot(votes[0].vote); r.Error != "" {
+		t.Fatalf("unexpected ballot error: %v", r.Error)
+	}
+	if err = cancel(""); err == nil {
+		t.Fatalf("expected error for blank reason")
+	}
+	if err = cancel("reason"); err != nil {
+		t.Fatal(err)
+	}
+	if err = cancel("reason"); err == nil {
+		t.Fatalf("expected error for cancelling twice")
+	}
+	if r := ballot(votes[1].vote); r.Error == "" {
+		t.Fatalf("expected ballot error for cancelled vote")
+	}
+
+	// A cancelled vote has no results bundle once the vote has ended
+	chain.SetBestBlock(uint32(endHeight))
+	payload, err := bitumplugin.EncodeGetVoteResultsBundle(
+		bitumplugin.GetVoteResultsBundle{
+			Token: token,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.pluginVoteResultsBundle(string(payload))
+	if err == nil {
+		t.Fatalf("expected error for results of a cancelled vote")
+	}
+}
+
+func TestCancelVoteRunoffUnwind(t *testing.T) {
+	g, cleanup := newTestBackEnd(t, NewFixtureChain(ChainFixture{
+		BestBlock: 1000,
+		Tickets:   []string{"ticket1", "ticket2"},
+	}))
+	defer cleanup()
+	journalsReplayed = true
+	defer func() {
+		journalsReplayed = false
+	}()
+
+	// Start a runoff vote between two proposals
+	tokens := []string{
+		newTestAuthorizedProposal(t, g),
+		newTestAuthorizedProposal(t, g),
+	}
+	sort.Strings(tokens) // Runoff tokens are cancelled in sorted order
+	var svr bitumplugin.StartVoteRunoff
+	for _, v := range tokens {
+		sv, err := bitumplugin.DecodeStartVote([]byte(newTestStartVote(t,
+			v, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sv.Vote.Type = bitumplugin.VoteTypeRunoff
+		svr.StartVotes = append(svr.StartVotes, *sv)
+	}
+	payload, err := bitumplugin.EncodeStartVoteRunoff(svr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.pluginStartVoteRunoff(string(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel := func() error {
+		t.Helper()
+		payload, err := bitumplugin.EncodeCancelVote(bitumplugin.CancelVote{
+			Token:     tokens[0],
+			Reason:    "reason",
+			Signature: "signature",
+			PublicKey: "publickey",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.pluginCancelVote(string(payload))
+		return err
+	}
+	mdFilename := func(token string) string {
+		return pijoin(joinLatest(g.vetted, token),
+			fmt.Sprintf("%02v%v", bitumplugin.MDStreamCancelVote,
+				defaultMDFilenameSuffix))
+	}
+	ballotFilename := func(token string) string {
+		return pijoin(g.journals, token, defaultBallotFilename)
+	}
+
+	// Neither proposal may be cancelled after a failed cancellation
+	verifyNotCancelled := func() {
+		t.Helper()
+		for _, v := range tokens {
+			if bitumPluginVoteCancelCache[v] {
+				t.Fatalf("cached cancellation: %v", v)
+			}
+			if util.FileExists(mdFilename(v)) {
+				t.Fatalf("cancel vote metadata stored: %v", v)
+			}
+			b, err := ioutil.ReadFile(ballotFilename(v))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if len(b) != 0 {
+				t.Fatalf("cancel vote journaled: %v", v)
+			}
+		}
+	}
+
+	// Fail journaling the cancellation of the second proposal after
+	// the first one has been journaled.
+	journalDir := pijoin(g.journals, tokens[1])
+	err = os.RemoveAll(journalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(journalDir, []byte{}, 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cancel(); err == nil {
+		t.Fatalf("expected error for failed journal")
+	}
+	verifyNotCancelled()
+	err = os.Remove(journalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail storing the metadata of the second proposal after the
+	// metadata of the first one has been written.
+	mdDir := pijoin(joinLatest(g.unvetted, tokens[1]),
+		fmt.Sprintf("%02v%v", bitumplugin.MDStreamCancelVote,
+			defaultMDFilenameSuffix))
+	err = os.Mkdir(mdDir, 0774)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cancel(); err == nil {
+		t.Fatalf("expected error for failed metadata update")
+	}
+	verifyNotCancelled()
+
+	// The vote can be cancelled once nothing fails
+	if err = cancel(); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range tokens {
+		if !g._voteCancelled(v) || !util.FileExists(mdFilename(v)) {
+			t.Fatalf("vote not cancelled: %v", v)
+		}
+		entries, err := readJournalEntries(ballotFilename(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("got %v journal entries, want 1: %v",
+				len(entries), v)
+		}
+	}
+}
+
+func TestConcurrentBallots(t *testing.T) {
+	g, cleanup := newTestBackEnd(t, NewFixtureChain(ChainFixture{}))
+	defer cleanup()
+	journalsReplayed = true
+	defer func() {
+		journalsReplayed = false
+	}()
+
+	token := newTestAuthorizedProposal(t, g)
+	votes := newTestBallotVotes(t, g.activeNetParams, token, 1)
+	g.chain = NewFixtureChain(ChainFixture{
+		BestBlock: 1000,
+		Tickets:   []string{votes[0].vote.Ticket},
+		Commitments: map[string]string{
+			votes[0].vote.Ticket: votes[0].address.Address,
+		},
+	})
+	_, err := g.pluginStartVote(newTestStartVote(t, token, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := bitumplugin.EncodeBallot(bitumplugin.Ballot{
+		Votes: []bitumplugin.CastVote{votes[0].vote},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cast the same vote from several ballots at once.  Only one of
+	// them may be accepted.
+	const ballots = 8
+	errs := make([]string, ballots)
+	var wg sync.WaitGroup
+	for i := 0; i < ballots; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := g.pluginBallot(string(payload))
+			if err != nil {
+				errs[i] = err.Error()
+				return
+			}
+			br, err := bitumplugin.DecodeBallotReply([]byte(reply))
+			if err != nil {
+				errs[i] = err.Error()
+				return
+			}
+			errs[i] = br.Receipts[0].Error
+		}(i)
+	}
+	wg.Wait()
+
+	var accepted int
+	for _, v := range errs {
+		if v == "" {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Fatalf("got %v accepted ballots, want 1: %v", accepted, errs)
+	}
+	cvj, err := g.castVoteJournals(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cvj) != 1 {
+		t.Fatalf("got %v journaled votes, want 1", len(cvj))
+	}
+}
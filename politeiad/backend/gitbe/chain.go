@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"fmt"
+)
+
+const (
+	// Chain provider types
+	ChainProviderBitumdata = "bitumdata" // bitumdata HTTP API
+	ChainProviderBitumd    = "bitumd"    // bitumd JSON-RPC
+	ChainProviderFixture   = "fixture"   // Local fixture file
+)
+
+// ChainBlock identifies a block on the main chain.
+type ChainBlock struct {
+	Height uint32 // Block height
+	Hash   string // Block hash
+}
+
+// CommitmentAddress is the largest commitment address of a ticket or the
+// error that prevented it from being looked up.
+type CommitmentAddress struct {
+	Address string // Largest commitment address
+	Err     error  // Lookup error
+}
+
+// ChainProvider provides the chain data that the bitum plugin requires in
+// order to start votes and to validate ballots.
+type ChainProvider interface {
+	// BestBlock returns the current best block.
+	BestBlock() (*ChainBlock, error)
+
+	// Block returns the main chain block at the provided height.
+	Block(height uint32) (*ChainBlock, error)
+
+	// Snapshot returns the live ticket pool of the block with the
+	// provided hash.
+	Snapshot(hash string) ([]string, error)
+
+	// LargestCommitmentAddresses returns the largest commitment
+	// address of each of the provided tickets.  The returned slice
+	// is in the same order as the provided tickets.
+	LargestCommitmentAddresses(tickets []string) ([]CommitmentAddress, error)
+}
+
+// largestCommitment returns the address with the largest commitment amount
+// out of the provided ticket commitment outputs.
+func largestCommitment(ticket string, commitments []ticketCommitment) CommitmentAddress {
+	var bestAddr string
+	var bestAmount float64
+	for _, v := range commitments {
+		if v.amount > bestAmount {
+			if v.address == "" {
+				log.Errorf("unexpected addresses length: %v",
+					ticket)
+				continue
+			}
+			bestAddr = v.address
+			bestAmount = v.amount
+		}
+	}
+
+	if bestAddr == "" || bestAmount == 0.0 {
+		return CommitmentAddress{
+			Err: fmt.Errorf("no best commitment address found: %v",
+				ticket),
+		}
+	}
+
+	return CommitmentAddress{
+		Address: bestAddr,
+	}
+}
+
+// ticketCommitment is a ticket commitment output.
+type ticketCommitment struct {
+	address string  // Commitment address
+	amount  float64 // Commitment amount
+}
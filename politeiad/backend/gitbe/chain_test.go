@@ -0,0 +1,284 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFixtureChain(t *testing.T) {
+	c := NewFixtureChain(ChainFixture{
+		BestBlock: 300,
+		Blocks: map[uint32]string{
+			44: "snapshotblock",
+		},
+		Tickets: []string{"ticket2", "ticket1"},
+		Commitments: map[string]string{
+			"ticket1": "address1",
+		},
+	})
+
+	bb, err := c.BestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bb.Height != 300 || bb.Hash == "" {
+		t.Fatalf("unexpected best block %v", bb)
+	}
+
+	// Listed blocks use the fixture hash
+	b, err := c.Block(44)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Hash != "snapshotblock" {
+		t.Fatalf("unexpected block hash %v", b.Hash)
+	}
+
+	// Blocks past the best block don't exist
+	_, err = c.Block(301)
+	if err == nil {
+		t.Fatalf("expected error for block past best block")
+	}
+
+	// The snapshot is sorted and only available for chain blocks
+	// bitumd only knows the live ticket pool of the best block
+	_, err = c.Snapshot(b.Hash)
+	if err == nil {
+		t.Fatalf("expected error for snapshot of a past block")
+	}
+	tickets, err := c.Snapshot(bb.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tickets, []string{"ticket1", "ticket2"}) {
+		t.Fatalf("unexpected snapshot %v", tickets)
+	}
+	_, err = c.Snapshot("unknownblock")
+	if err == nil {
+		t.Fatalf("expected error for unknown block")
+	}
+
+	// Moving the chain forward makes new blocks available
+	c.SetBestBlock(301)
+	b, err = c.Block(301)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Snapshot(b.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := c.LargestCommitmentAddresses([]string{"ticket1", "ticket2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca[0].Address != "address1" || ca[0].Err != nil {
+		t.Fatalf("unexpected commitment %v", ca[0])
+	}
+	if ca[1].Err == nil {
+		t.Fatalf("expected error for ticket without commitment")
+	}
+}
+
+func TestLoadFixtureChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "politeia.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "chainfixture.json")
+	err = ioutil.WriteFile(filename, []byte(`{"bestblock":10,`+
+		`"tickets":["ticket1"],"commitments":{"ticket1":"address1"}}`),
+		0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadFixtureChain(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bb, err := c.BestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bb.Height != 10 {
+		t.Fatalf("unexpected best block %v", bb.Height)
+	}
+}
+
+func TestBitumdataChain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/block/best":
+			w.Write([]byte(`{"height":300,"hash":"bestblock"}`))
+		case "/api/block/44":
+			w.Write([]byte(`{"height":44,"hash":"snapshotblock"}`))
+		case "/api/stake/pool/b/snapshotblock/full":
+			w.Write([]byte(`["ticket1","ticket2"]`))
+		case "/api/txs/trimmed":
+			w.Write([]byte(`[{"txid":"ticket1","vout":[` +
+				`{"scriptPubKey":{"addresses":["small"],"commitamt":1}},` +
+				`{"scriptPubKey":{"addresses":["change"]}},` +
+				`{"scriptPubKey":{"addresses":["large"],"commitamt":2}}]}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewBitumdataChain(ts.URL)
+
+	bb, err := c.BestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bb.Height != 300 || bb.Hash != "bestblock" {
+		t.Fatalf("unexpected best block %v", bb)
+	}
+
+	b, err := c.Block(44)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bitumd only knows the live ticket pool of the best block
+	_, err = c.Snapshot(b.Hash)
+	if err == nil {
+		t.Fatalf("expected error for snapshot of a past block")
+	}
+	tickets, err := c.Snapshot(bb.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tickets, []string{"ticket1", "ticket2"}) {
+		t.Fatalf("unexpected snapshot %v", tickets)
+	}
+
+	_, err = c.Block(45)
+	if err == nil {
+		t.Fatalf("expected error for unknown block")
+	}
+
+	// The reply is missing the second ticket
+	ca, err := c.LargestCommitmentAddresses([]string{"ticket1", "ticket2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca[0].Address != "large" || ca[0].Err != nil {
+		t.Fatalf("unexpected commitment %v", ca[0])
+	}
+	if ca[1].Err == nil {
+		t.Fatalf("expected error for missing transaction")
+	}
+}
+
+func TestBitumdChain(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req rpcRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var result interface{}
+		var rpcErr *rpcError
+		switch req.Method {
+		case "getbestblock":
+			result = bestBlockResult{Hash: "bestblock", Height: 300}
+		case "getblockhash":
+			result = "snapshotblock"
+		case "livetickets":
+			result = liveTicketsResult{
+				Tickets: []string{"ticket2", "ticket1"},
+			}
+		case "getrawtransaction":
+			if req.Params[0] != "ticket1" {
+				rpcErr = &rpcError{Code: -5,
+					Message: "No information available about transaction"}
+				break
+			}
+			result = json.RawMessage(`{"txid":"ticket1","vout":[` +
+				`{"scriptPubKey":{"addresses":["small"],"commitamt":1}},` +
+				`{"scriptPubKey":{"addresses":["large"],"commitamt":2}}]}`)
+		default:
+			rpcErr = &rpcError{Code: -32601, Message: "Method not found"}
+		}
+
+		b, err := json.Marshal(result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		json.NewEncoder(w).Encode(rpcResponse{
+			ID:     req.ID,
+			Result: b,
+			Error:  rpcErr,
+		})
+	}))
+	defer ts.Close()
+
+	c, err := NewBitumdChain(ts.Listener.Addr().String(), "user", "pass",
+		"", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bb, err := c.BestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bb.Height != 300 || bb.Hash != "bestblock" {
+		t.Fatalf("unexpected best block %v", bb)
+	}
+
+	b, err := c.Block(44)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Height != 44 || b.Hash != "snapshotblock" {
+		t.Fatalf("unexpected block %v", b)
+	}
+
+	// bitumd only knows the live ticket pool of the best block
+	_, err = c.Snapshot(b.Hash)
+	if err == nil {
+		t.Fatalf("expected error for snapshot of a past block")
+	}
+	tickets, err := c.Snapshot(bb.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tickets, []string{"ticket1", "ticket2"}) {
+		t.Fatalf("unexpected snapshot %v", tickets)
+	}
+
+	ca, err := c.LargestCommitmentAddresses([]string{"ticket1", "ticket2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca[0].Address != "large" || ca[0].Err != nil {
+		t.Fatalf("unexpected commitment %v", ca[0])
+	}
+	if ca[1].Err == nil {
+		t.Fatalf("expected error for unknown transaction")
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitum-project/politeia/util"
+)
+
+const (
+	bitumdTimeout = 1 * time.Minute
+)
+
+// bitumdChain is a ChainProvider that uses the bitumd JSON-RPC API.  It is not
+// offered as a politeiad chain provider since it can not snapshot the ticket
+// pool of past blocks, which is required to start votes.
+type bitumdChain struct {
+	id     uint64       // JSON-RPC request id, atomic
+	host   string       // bitumd RPC URL
+	user   string       // RPC user
+	pass   string       // RPC password
+	client *http.Client // HTTP client
+}
+
+// rpcRequest is a JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcError is a JSON-RPC error.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcResponse is a JSON-RPC response.
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// bestBlockResult is the result of the getbestblock command.
+type bestBlockResult struct {
+	Hash   string `json:"hash"`
+	Height uint32 `json:"height"`
+}
+
+// liveTicketsResult is the result of the livetickets command.
+type liveTicketsResult struct {
+	Tickets []string `json:"tickets"`
+}
+
+// rawTransactionResult is the result of the verbose getrawtransaction
+// command.
+type rawTransactionResult struct {
+	Txid string `json:"txid"`
+	Vout []struct {
+		ScriptPubKey struct {
+			Addresses []string `json:"addresses"`
+			CommitAmt *float64 `json:"commitamt"`
+		} `json:"scriptPubKey"`
+	} `json:"vout"`
+}
+
+// NewBitumdChain returns a ChainProvider that retrieves chain data from the
+// bitumd instance at the provided host using its JSON-RPC API.  The provided
+// certificate is used to verify the bitumd RPC server.
+func NewBitumdChain(host, user, pass, cert string, skipVerify bool) (ChainProvider, error) {
+	client, err := util.NewClient(skipVerify, cert)
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = bitumdTimeout
+
+	return &bitumdChain{
+		host:   "https://" + host,
+		user:   user,
+		pass:   pass,
+		client: client,
+	}, nil
+}
+
+// call executes the provided bitumd RPC command and decodes its result into
+// v.
+func (c *bitumdChain) call(method string, v interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	reqBody, err := json.Marshal(rpcRequest{
+		JSONRPC: "1.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("bitumd rpc %v", method)
+	req, err := http.NewRequest(http.MethodPost, c.host,
+		bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.user, c.pass)
+
+	r, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("bitumd error: %v %v %v", r.StatusCode,
+			method, err)
+	}
+
+	// bitumd replies with an error status code when the command
+	// fails so the body is decoded regardless of the status code.
+	var resp rpcResponse
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return fmt.Errorf("bitumd error: %v %v %s", r.StatusCode,
+			method, body)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("bitumd error: %v %v %v", method,
+			resp.Error.Code, resp.Error.Message)
+	}
+
+	return json.Unmarshal(resp.Result, v)
+}
+
+// BestBlock returns the current best block.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdChain) BestBlock() (*ChainBlock, error) {
+	var bb bestBlockResult
+	err := c.call("getbestblock", &bb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainBlock{
+		Height: bb.Height,
+		Hash:   bb.Hash,
+	}, nil
+}
+
+// Block returns the main chain block at the provided height.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdChain) Block(height uint32) (*ChainBlock, error) {
+	var hash string
+	err := c.call("getblockhash", &hash, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainBlock{
+		Height: height,
+		Hash:   hash,
+	}, nil
+}
+
+// Snapshot returns the live ticket pool of the provided block.  bitumd is
+// only able to report the live ticket pool of the current best block so an
+// error is returned for any other block rather than substituting the pool of
+// the best block.  Since votes snapshot a block that is TicketMaturity blocks
+// behind the best block, the bitumd provider can not be used to start votes.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdChain) Snapshot(hash string) ([]string, error) {
+	bb, err := c.BestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if bb.Hash != hash {
+		return nil, fmt.Errorf("bitumd can only snapshot the best block "+
+			"%v, not %v", bb.Hash, hash)
+	}
+
+	var lt liveTicketsResult
+	err = c.call("livetickets", &lt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the pool was not reported for a newer block
+	bb, err = c.BestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if bb.Hash != hash {
+		return nil, fmt.Errorf("best block changed during snapshot of %v",
+			hash)
+	}
+	sort.Strings(lt.Tickets)
+
+	return lt.Tickets, nil
+}
+
+// LargestCommitmentAddresses returns the largest commitment address of each
+// of the provided tickets.  bitumd must be run with the transaction index
+// enabled.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdChain) LargestCommitmentAddresses(tickets []string) ([]CommitmentAddress, error) {
+	r := make([]CommitmentAddress, len(tickets))
+	for i, ticket := range tickets {
+		var tx rawTransactionResult
+		err := c.call("getrawtransaction", &tx, ticket, 1)
+		if err != nil {
+			r[i].Err = err
+			continue
+		}
+
+		commitments := make([]ticketCommitment, 0, len(tx.Vout))
+		for _, v := range tx.Vout {
+			if v.ScriptPubKey.CommitAmt == nil {
+				continue
+			}
+			var addr string
+			if len(v.ScriptPubKey.Addresses) != 0 {
+				addr = v.ScriptPubKey.Addresses[0]
+			}
+			commitments = append(commitments, ticketCommitment{
+				address: addr,
+				amount:  *v.ScriptPubKey.CommitAmt,
+			})
+		}
+		r[i] = largestCommitment(tx.Txid, commitments)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	bitumdataapi "github.com/bitum-project/bitumdata/api/types"
+)
+
+const (
+	bitumdataTimeout = 1 * time.Minute
+)
+
+// bitumdataChain is a ChainProvider that uses the bitumdata HTTP API.
+type bitumdataChain struct {
+	host   string       // bitumdata URL, must end with a /
+	client *http.Client // HTTP client
+}
+
+// NewBitumdataChain returns a ChainProvider that retrieves chain data from the
+// bitumdata instance at the provided URL.
+func NewBitumdataChain(host string) ChainProvider {
+	if host != "" && host[len(host)-1] != '/' {
+		host += "/"
+	}
+	return &bitumdataChain{
+		host: host,
+		client: &http.Client{
+			Timeout: bitumdataTimeout,
+		},
+	}
+}
+
+// get makes a GET request to the provided bitumdata route and decodes the
+// JSON reply into v.
+func (c *bitumdataChain) get(route string, v interface{}) error {
+	url := c.host + route
+	log.Debugf("connecting to %v", url)
+	r, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	return c.decode(url, r, v)
+}
+
+// post makes a POST request with a JSON body to the provided bitumdata route
+// and decodes the JSON reply into v.
+func (c *bitumdataChain) post(route string, body, v interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := c.host + route
+	log.Debugf("connecting to %v", url)
+	r, err := c.client.Post(url, "application/json; charset=utf-8",
+		bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	return c.decode(url, r, v)
+}
+
+// decode decodes a bitumdata reply into v.
+func (c *bitumdataChain) decode(url string, r *http.Response, v interface{}) error {
+	if r.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("bitumdata error: %v %v %v",
+				r.StatusCode, url, err)
+		}
+		return fmt.Errorf("bitumdata error: %v %v %s",
+			r.StatusCode, url, body)
+	}
+
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// BestBlock returns the current best block.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdataChain) BestBlock() (*ChainBlock, error) {
+	var bdb bitumdataapi.BlockDataBasic
+	err := c.get("api/block/best", &bdb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainBlock{
+		Height: bdb.Height,
+		Hash:   bdb.Hash,
+	}, nil
+}
+
+// Block returns the main chain block at the provided height.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdataChain) Block(height uint32) (*ChainBlock, error) {
+	var bdb bitumdataapi.BlockDataBasic
+	h := strconv.FormatUint(uint64(height), 10)
+	err := c.get("api/block/"+h, &bdb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainBlock{
+		Height: bdb.Height,
+		Hash:   bdb.Hash,
+	}, nil
+}
+
+// Snapshot returns the live ticket pool of the block with the provided hash.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdataChain) Snapshot(hash string) ([]string, error) {
+	var tickets []string
+	err := c.get("api/stake/pool/b/"+hash+"/full?sort=true", &tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// batchTransactions returns the trimmed transactions of the provided
+// transaction hashes.
+func (c *bitumdataChain) batchTransactions(hashes []string) ([]bitumdataapi.TrimmedTx, error) {
+	var ttx []bitumdataapi.TrimmedTx
+	err := c.post("api/txs/trimmed", bitumdataapi.Txns{
+		Transactions: hashes,
+	}, &ttx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ttx, nil
+}
+
+// LargestCommitmentAddresses returns the largest commitment address of each
+// of the provided tickets.
+//
+// This function satisfies the ChainProvider interface.
+func (c *bitumdataChain) LargestCommitmentAddresses(tickets []string) ([]CommitmentAddress, error) {
+	// Batch request all of the transaction info from bitumdata.
+	ttxs, err := c.batchTransactions(tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find largest commitment address for each transaction.
+	r := make([]CommitmentAddress, len(tickets))
+	for i := range tickets {
+		if i >= len(ttxs) {
+			r[i].Err = fmt.Errorf("transaction not found: %v",
+				tickets[i])
+			continue
+		}
+		commitments := make([]ticketCommitment, 0, len(ttxs[i].Vout))
+		for _, v := range ttxs[i].Vout {
+			if v.ScriptPubKeyDecoded.CommitAmt == nil {
+				continue
+			}
+			var addr string
+			if len(v.ScriptPubKeyDecoded.Addresses) != 0 {
+				addr = v.ScriptPubKeyDecoded.Addresses[0]
+			}
+			commitments = append(commitments, ticketCommitment{
+				address: addr,
+				amount:  *v.ScriptPubKeyDecoded.CommitAmt,
+			})
+		}
+		r[i] = largestCommitment(ttxs[i].TxID, commitments)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+)
+
+// ChainFixture describes the chain that is served by the fixture chain
+// provider.  Blocks that are not listed are given a deterministic hash that is
+// derived from their height.
+type ChainFixture struct {
+	BestBlock   uint32            `json:"bestblock"`   // Best block height
+	Blocks      map[uint32]string `json:"blocks"`      // [height]hash
+	Tickets     []string          `json:"tickets"`     // Live ticket pool
+	Commitments map[string]string `json:"commitments"` // [ticket]address
+}
+
+// FixtureChain is a ChainProvider that serves chain data from a fixture.  It
+// is used in tests and on simnet so that the vote lifecycle can be exercised
+// without a block explorer.
+type FixtureChain struct {
+	sync.RWMutex
+	fixture ChainFixture
+}
+
+// NewFixtureChain returns a FixtureChain that serves the provided fixture.
+func NewFixtureChain(f ChainFixture) *FixtureChain {
+	if f.Blocks == nil {
+		f.Blocks = make(map[uint32]string)
+	}
+	if f.Commitments == nil {
+		f.Commitments = make(map[string]string)
+	}
+	tickets := make([]string, len(f.Tickets))
+	copy(tickets, f.Tickets)
+	sort.Strings(tickets)
+	f.Tickets = tickets
+
+	return &FixtureChain{
+		fixture: f,
+	}
+}
+
+// LoadFixtureChain returns a FixtureChain that serves the JSON encoded
+// ChainFixture in the provided file.
+func LoadFixtureChain(filename string) (*FixtureChain, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var f ChainFixture
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain fixture %v: %v",
+			filename, err)
+	}
+
+	return NewFixtureChain(f), nil
+}
+
+// SetBestBlock sets the height of the best block.  It is used to move the
+// chain forward, e.g. to end a vote.
+func (c *FixtureChain) SetBestBlock(height uint32) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.fixture.BestBlock = height
+}
+
+// blockHash returns the hash of the block at the provided height.
+//
+// This function must be called WITH the lock held.
+func (c *FixtureChain) blockHash(height uint32) string {
+	hash, ok := c.fixture.Blocks[height]
+	if ok {
+		return hash
+	}
+
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], height)
+	h := sha256.Sum256(b[:])
+	return hex.EncodeToString(h[:])
+}
+
+// BestBlock returns the current best block.
+//
+// This function satisfies the ChainProvider interface.
+func (c *FixtureChain) BestBlock() (*ChainBlock, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return &ChainBlock{
+		Height: c.fixture.BestBlock,
+		Hash:   c.blockHash(c.fixture.BestBlock),
+	}, nil
+}
+
+// Block returns the main chain block at the provided height.
+//
+// This function satisfies the ChainProvider interface.
+func (c *FixtureChain) Block(height uint32) (*ChainBlock, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if height > c.fixture.BestBlock {
+		return nil, fmt.Errorf("block not found: %v", height)
+	}
+
+	return &ChainBlock{
+		Height: height,
+		Hash:   c.blockHash(height),
+	}, nil
+}
+
+// Snapshot returns the fixture ticket pool.  The same pool is returned for
+// every main chain block.
+//
+// This function satisfies the ChainProvider interface.
+func (c *FixtureChain) Snapshot(hash string) ([]string, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	// Verify the block is part of the fixture chain
+	var found bool
+	for h := uint32(0); h <= c.fixture.BestBlock; h++ {
+		if c.blockHash(h) == hash {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("block not found: %v", hash)
+	}
+
+	tickets := make([]string, len(c.fixture.Tickets))
+	copy(tickets, c.fixture.Tickets)
+	return tickets, nil
+}
+
+// LargestCommitmentAddresses returns the fixture commitment address of each
+// of the provided tickets.
+//
+// This function satisfies the ChainProvider interface.
+func (c *FixtureChain) LargestCommitmentAddresses(tickets []string) ([]CommitmentAddress, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	r := make([]CommitmentAddress, len(tickets))
+	for i, ticket := range tickets {
+		addr, ok := c.fixture.Commitments[ticket]
+		if !ok {
+			r[i].Err = fmt.Errorf("no best commitment address "+
+				"found: %v", ticket)
+			continue
+		}
+		r[i].Address = addr
+	}
+
+	return r, nil
+}
@@ -129,6 +129,7 @@ type gitBackEnd struct {
 	exit            chan struct{}    // Close channel
 	checkAnchor     chan struct{}    // Work notification
 	plugins         []backend.Plugin // Plugins
+	chain           ChainProvider    // Chain data provider
 
 	// The following items are used for testing only
 	testAnchors map[string]bool // [digest]anchored
@@ -699,7 +700,8 @@ func (g *gitBackEnd) appendAuditTrail(path string, ts int64, merkle [sha256.Size
 	return nil
 }
 
-// anchorRepo drops an anchor for an individual repo.
+// anchorRepo drops an anchor for an individual repo.  The heads of the plugin
+// journals that changed since they were last anchored are included as well.
 // It prints the basename during its actions.
 //
 // This function should be called with the lock held.
@@ -728,13 +730,27 @@ func (g *gitBackEnd) anchorRepo(path string) (*[sha256.Size]byte, error) {
 
 	// Fill out unvetted digests
 	digests, messages, _, err := g.deltaCommits(path, last.Last)
-	if err != nil {
-		if err == errNothingToDo {
-			return nil, err
-		}
+	if err != nil && err != errNothingToDo {
 		return nil, fmt.Errorf("could not determine delta %v: %v",
 			repo, err)
 	}
+
+	// Fill out plugin journal heads that changed since they were last
+	// anchored.  These are anchored even when there are no new commits.
+	heads, err := g.unanchoredJournalHeads()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine journal heads: %v",
+			err)
+	}
+	if len(digests) == 0 && len(heads) == 0 {
+		return nil, errNothingToDo
+	}
+	for k := range heads {
+		digests = append(digests, &heads[k].digest)
+		messages = append(messages, fmt.Sprintf("Journal head %v %v "+
+			"%v entries", heads[k].token, heads[k].journal,
+			heads[k].entries))
+	}
 	if len(digests) != len(messages) {
 		// Really can't happen
 		return nil, fmt.Errorf("invalid digests(%v)/messages(%v) count",
@@ -793,6 +809,12 @@ func (g *gitBackEnd) anchorRepo(path string) (*[sha256.Size]byte, error) {
 		return nil, fmt.Errorf("gitCommit: %v", err)
 	}
 
+	// Record which journal heads went into the anchor
+	err = g.storeJournalAnchors(heads, *anchorKey, anchorRecord.Time)
+	if err != nil {
+		return nil, fmt.Errorf("storeJournalAnchors: %v", err)
+	}
+
 	return anchorKey, nil
 }
 
@@ -1661,10 +1683,19 @@ func (g *gitBackEnd) UpdateUnvettedRecord(token []byte, mdAppend []backend.Metad
 		false)
 }
 
-// updateVettedMetadata updates metadata in the unvetted repo and pushes it
-// upstream followed by a rebase.  Record is not updated.
+// vettedMetadataUpdate contains the metadata changes of a single vetted
+// record.
+type vettedMetadataUpdate struct {
+	token       []byte
+	mdAppend    []backend.MetadataStream
+	mdOverwrite []backend.MetadataStream
+}
+
+// updateVettedMetadata updates metadata of the provided records in the
+// unvetted repo and pushes it upstream followed by a rebase.  All changes are
+// stored in a single commit.  Records are not updated.
 // This function must be called with the lock held.
-func (g *gitBackEnd) updateVettedMetadata(id, idTmp string, mdAppend []backend.MetadataStream, mdOverwrite []backend.MetadataStream) error {
+func (g *gitBackEnd) updateVettedMetadata(ids []string, idTmp string, updates []vettedMetadataUpdate) error {
 	_ = g.gitBranchDelete(g.unvetted, idTmp) // Delete leftovers
 
 	// Checkout temporary branch
@@ -1674,9 +1705,11 @@ func (g *gitBackEnd) updateVettedMetadata(id, idTmp string, mdAppend []backend.M
 	}
 
 	// Update metadata changes
-	err = g.updateMetadata(id, mdAppend, mdOverwrite)
-	if err != nil {
-		return err
+	for k, v := range updates {
+		err = g.updateMetadata(ids[k], v.mdAppend, v.mdOverwrite)
+		if err != nil {
+			return err
+		}
 	}
 
 	// If there are no changes DO NOT update the record and reply with no
@@ -1686,7 +1719,8 @@ func (g *gitBackEnd) updateVettedMetadata(id, idTmp string, mdAppend []backend.M
 	}
 
 	// Commit change
-	err = g.gitCommit(g.unvetted, "Update record metadata "+id)
+	err = g.gitCommit(g.unvetted, "Update record metadata "+
+		strings.Join(ids, " "))
 	if err != nil {
 		return err
 	}
@@ -1702,6 +1736,26 @@ func (g *gitBackEnd) updateVettedMetadata(id, idTmp string, mdAppend []backend.M
 //
 // This function must be called with the lock held.
 func (g *gitBackEnd) _updateVettedMetadata(token []byte, mdAppend []backend.MetadataStream, mdOverwrite []backend.MetadataStream) error {
+	return g._updateVettedMetadatas([]vettedMetadataUpdate{
+		{
+			token:       token,
+			mdAppend:    mdAppend,
+			mdOverwrite: mdOverwrite,
+		},
+	})
+}
+
+// _updateVettedMetadatas updates the metadata of several vetted records in a
+// single commit.  Either all records are updated or, if any of the updates
+// fails, none of them are.  Note that the content must have been validated
+// before this call.  Records themselves are not changed.
+//
+// This function must be called with the lock held.
+func (g *gitBackEnd) _updateVettedMetadatas(updates []vettedMetadataUpdate) error {
+	if len(updates) == 0 {
+		return backend.ErrNoChanges
+	}
+
 	// git checkout master
 	err := g.gitCheckout(g.unvetted, "master")
 	if err != nil {
@@ -1714,31 +1768,37 @@ func (g *gitBackEnd) _updateVettedMetadata(token []byte, mdAppend []backend.Meta
 		return err
 	}
 
-	// Check if temporary branch exists (should never be the case)
-	id := hex.EncodeToString(token)
-	idTmp := id + "_tmp"
+	ids := make([]string, 0, len(updates))
+	for _, v := range updates {
+		id := hex.EncodeToString(v.token)
 
-	// Make sure vetted exists
-	_, err = os.Stat(pijoin(g.unvetted, id))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return backend.ErrRecordNotFound
+		// Make sure vetted exists
+		_, err = os.Stat(pijoin(g.unvetted, id))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return backend.ErrRecordNotFound
+			}
 		}
-	}
 
-	// Make sure record is not locked.
-	md, err := loadMD(g.unvetted, id, "")
-	if err != nil {
-		return err
-	}
-	if md.Status == backend.MDStatusArchived {
-		return backend.ErrRecordArchived
+		// Make sure record is not locked.
+		md, err := loadMD(g.unvetted, id, "")
+		if err != nil {
+			return err
+		}
+		if md.Status == backend.MDStatusArchived {
+			return backend.ErrRecordArchived
+		}
+
+		log.Debugf("updating vetted metadata %v", id)
+
+		ids = append(ids, id)
 	}
 
-	log.Debugf("updating vetted metadata %x", token)
+	// Check if temporary branch exists (should never be the case)
+	idTmp := ids[0] + "_tmp"
 
 	// Do the work, if there is an error we must unwind git.
-	err = g.updateVettedMetadata(id, idTmp, mdAppend, mdOverwrite)
+	err = g.updateVettedMetadata(ids, idTmp, updates)
 	if err != nil {
 		err2 := g.gitUnwindBranch(g.unvetted, idTmp)
 		if err2 != nil {
@@ -2355,12 +2415,24 @@ func (g *gitBackEnd) Plugin(command, payload string) (string, string, error) {
 	case bitumplugin.CmdStartVote:
 		payload, err := g.pluginStartVote(payload)
 		return bitumplugin.CmdStartVote, payload, err
+	case bitumplugin.CmdStartVoteRunoff:
+		payload, err := g.pluginStartVoteRunoff(payload)
+		return bitumplugin.CmdStartVoteRunoff, payload, err
 	case bitumplugin.CmdBallot:
 		payload, err := g.pluginBallot(payload)
 		return bitumplugin.CmdBallot, payload, err
 	case bitumplugin.CmdProposalVotes:
 		payload, err := g.pluginProposalVotes(payload)
 		return bitumplugin.CmdProposalVotes, payload, err
+	case bitumplugin.CmdVoteResultsBundle:
+		payload, err := g.pluginVoteResultsBundle(payload)
+		return bitumplugin.CmdVoteResultsBundle, payload, err
+	case bitumplugin.CmdCancelVote:
+		payload, err := g.pluginCancelVote(payload)
+		return bitumplugin.CmdCancelVote, payload, err
+	case bitumplugin.CmdStartScheduledVotes:
+		payload, err := g.pluginStartScheduledVotes()
+		return bitumplugin.CmdStartScheduledVotes, payload, err
 	case bitumplugin.CmdBestBlock:
 		payload, err := g.pluginBestBlock()
 		return bitumplugin.CmdBestBlock, payload, err
@@ -2373,6 +2445,33 @@ func (g *gitBackEnd) Plugin(command, payload string) (string, string, error) {
 	case bitumplugin.CmdCensorComment:
 		payload, err := g.pluginCensorComment(payload)
 		return bitumplugin.CmdCensorComment, payload, err
+	case bitumplugin.CmdEditComment:
+		payload, err := g.pluginEditComment(payload)
+		return bitumplugin.CmdEditComment, payload, err
+	case bitumplugin.CmdDeleteComment:
+		payload, err := g.pluginDeleteComment(payload)
+		return bitumplugin.CmdDeleteComment, payload, err
+	case bitumplugin.CmdCommentRevisions:
+		payload, err := g.pluginCommentRevisions(payload)
+		return bitumplugin.CmdCommentRevisions, payload, err
+	case bitumplugin.CmdFlagComment:
+		payload, err := g.pluginFlagComment(payload)
+		return bitumplugin.CmdFlagComment, payload, err
+	case bitumplugin.CmdDismissCommentFlags:
+		payload, err := g.pluginDismissCommentFlags(payload)
+		return bitumplugin.CmdDismissCommentFlags, payload, err
+	case bitumplugin.CmdAppealCensorship:
+		payload, err := g.pluginAppealCensorship(payload)
+		return bitumplugin.CmdAppealCensorship, payload, err
+	case bitumplugin.CmdResolveAppeal:
+		payload, err := g.pluginResolveAppeal(payload)
+		return bitumplugin.CmdResolveAppeal, payload, err
+	case bitumplugin.CmdCensorshipAppeals:
+		payload, err := g.pluginCensorshipAppeals(payload)
+		return bitumplugin.CmdCensorshipAppeals, payload, err
+	case bitumplugin.CmdJournalProof:
+		payload, err := g.pluginJournalProof(payload)
+		return bitumplugin.CmdJournalProof, payload, err
 	case bitumplugin.CmdGetComments:
 		payload, err := g.pluginGetComments(payload)
 		return bitumplugin.CmdGetComments, payload, err
@@ -2527,8 +2626,10 @@ func (g *gitBackEnd) rebasePR(id string) error {
 	return g.gitBranchDelete(g.unvetted, id)
 }
 
-// New returns a gitBackEnd context.  It verifies that git is installed.
-func New(anp *chaincfg.Params, root string, bitumtimeHost string, gitPath string, id *identity.FullIdentity, gitTrace bool) (*gitBackEnd, error) {
+// New returns a gitBackEnd context.  It verifies that git is installed.  The
+// bitum plugin retrieves chain data using the provided chain provider, which
+// defaults to the network's bitumdata instance when nil.
+func New(anp *chaincfg.Params, root string, bitumtimeHost string, gitPath string, id *identity.FullIdentity, gitTrace bool, chain ChainProvider) (*gitBackEnd, error) {
 	// Default to system git
 	if gitPath == "" {
 		gitPath = "git"
@@ -2557,6 +2658,12 @@ func New(anp *chaincfg.Params, root string, bitumtimeHost string, gitPath string
 	setBitumPluginSetting(bitumPluginJournals, g.journals)
 	setBitumPluginHook(PluginPostHookEdit, g.bitumPluginPostEdit)
 
+	// Default to bitumdata for chain data
+	if chain == nil {
+		chain = NewBitumdataChain(bitumPluginSettings["bitumdata"])
+	}
+	g.chain = chain
+
 	// Create jounals path
 	// XXX this needs to move into plugin init
 	log.Infof("Journals directory: %v", g.journals)
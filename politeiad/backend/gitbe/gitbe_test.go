@@ -66,7 +66,7 @@ func TestAnchorWithCommits(t *testing.T) {
 
 	// Initialize stuff we need
 	g, err := New(&chaincfg.TestNetParams, dir, "", "", nil,
-		testing.Verbose())
+		testing.Verbose(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,362 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/bitum-project/bitumtime/api/v1"
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/util"
+)
+
+// Comments and ballots live in plugin journals that are only flushed to git
+// periodically.  In order to timestamp them without having to wait for a
+// flush, the head of every plugin journal that changed since it was last
+// anchored is included in the next anchor.  A journal head is the merkle root
+// of the digests of all journal entries, see bitumplugin.JournalHead.  The
+// anchored heads of a journal are recorded in an anchors file that sits next
+// to the journal so that a proof can be assembled for any entry.
+
+const (
+	journalAnchorVersion = "1" // Version 1 of the journal anchor record
+
+	defaultCommentsAnchors = "comments.anchors"
+	defaultBallotAnchors   = "ballot.anchors"
+)
+
+// anchoredJournals lists the plugin journals that are anchored along with
+// the filename of the file their anchored heads are recorded in.
+var anchoredJournals = []struct {
+	journal string
+	anchors string
+}{
+	{defaultCommentFilename, defaultCommentsAnchors},
+	{defaultBallotFilename, defaultBallotAnchors},
+}
+
+// JournalAnchor is stored on disk, one per line, when a journal head has been
+// included in an anchor.
+type JournalAnchor struct {
+	Version     string `json:"version"`     // Version of this structure
+	Entries     uint64 `json:"entries"`     // Number of entries in the journal head
+	JournalHead string `json:"journalhead"` // Merkle root of the journal entries
+	Anchor      string `json:"anchor"`      // Merkle root of the anchor
+	Timestamp   int64  `json:"timestamp"`   // Time the anchor was dropped
+}
+
+// journalHead is a journal head that has not been anchored yet.
+type journalHead struct {
+	token   string            // Proposal censorship token
+	journal string            // Journal filename
+	anchors string            // Anchors filename
+	entries uint64            // Number of entries in the journal head
+	digest  [sha256.Size]byte // Merkle root of the journal entries
+}
+
+// readJournalEntries returns all complete entries of a journal.  An entry that
+// is still being written does not end in a newline yet and is ignored.  A
+// journal that does not exist has no entries.
+func readJournalEntries(filename string) ([]string, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	i := bytes.LastIndexByte(b, '\n')
+	if i == -1 {
+		return []string{}, nil
+	}
+
+	return strings.Split(string(b[:i]), "\n"), nil
+}
+
+// journalDigests returns the digests of the passed in journal entries.
+func journalDigests(entries []string) [][sha256.Size]byte {
+	digests := make([][sha256.Size]byte, 0, len(entries))
+	for _, v := range entries {
+		digests = append(digests, bitumplugin.JournalEntryDigest(v))
+	}
+	return digests
+}
+
+// readJournalAnchors returns the anchored heads of a journal, oldest first.
+func readJournalAnchors(filename string) ([]JournalAnchor, error) {
+	entries, err := readJournalEntries(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ja := make([]JournalAnchor, 0, len(entries))
+	for _, v := range entries {
+		var a JournalAnchor
+		err = json.Unmarshal([]byte(v), &a)
+		if err != nil {
+			return nil, fmt.Errorf("journal anchor: %v", err)
+		}
+		ja = append(ja, a)
+	}
+
+	return ja, nil
+}
+
+// unanchoredJournalHeads returns the heads of all plugin journals that have
+// entries that have not been anchored yet.  Journals are append only so a
+// journal head only needs to be anchored when the number of entries changed.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) unanchoredJournalHeads() ([]journalHead, error) {
+	dirs, err := ioutil.ReadDir(g.journals)
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make([]journalHead, 0, len(dirs))
+	for _, v := range dirs {
+		for _, j := range anchoredJournals {
+			entries, err := readJournalEntries(pijoin(g.journals,
+				v.Name(), j.journal))
+			if err != nil {
+				return nil, err
+			}
+			if len(entries) == 0 {
+				continue
+			}
+
+			ja, err := readJournalAnchors(pijoin(g.journals,
+				v.Name(), j.anchors))
+			if err != nil {
+				return nil, err
+			}
+			if len(ja) != 0 &&
+				ja[len(ja)-1].Entries == uint64(len(entries)) {
+				continue
+			}
+
+			heads = append(heads, journalHead{
+				token:   v.Name(),
+				journal: j.journal,
+				anchors: j.anchors,
+				entries: uint64(len(entries)),
+				digest: bitumplugin.JournalHead(
+					journalDigests(entries)),
+			})
+		}
+	}
+
+	return heads, nil
+}
+
+// storeJournalAnchors records that the passed in journal heads were included
+// in the anchor with the passed in merkle root.
+//
+// This function must be called WITH the lock held.
+func (g *gitBackEnd) storeJournalAnchors(heads []journalHead, anchorKey [sha256.Size]byte, ts int64) error {
+	for _, v := range heads {
+		ja, err := json.Marshal(JournalAnchor{
+			Version:     journalAnchorVersion,
+			Entries:     v.entries,
+			JournalHead: hex.EncodeToString(v.digest[:]),
+			Anchor:      hex.EncodeToString(anchorKey[:]),
+			Timestamp:   ts,
+		})
+		if err != nil {
+			return err
+		}
+		err = g.journal.Journal(pijoin(g.journals, v.token, v.anchors),
+			string(ja))
+		if err != nil {
+			return fmt.Errorf("could not journal anchor %v %v: %v",
+				v.token, v.journal, err)
+		}
+	}
+
+	return nil
+}
+
+// journalEntryIndex returns the index of the journal entry that the passed in
+// proof is requested for.  A comment is proven by the entry that added it and
+// a ticket by the entry of its latest vote.  -1 is returned when there is no
+// such entry.
+func journalEntryIndex(jp bitumplugin.JournalProof, entries []string) (int, error) {
+	index := -1
+	for k, v := range entries {
+		d := json.NewDecoder(strings.NewReader(v))
+
+		// Decode action
+		var action JournalAction
+		err := d.Decode(&action)
+		if err != nil {
+			return -1, fmt.Errorf("journal action: %v", err)
+		}
+		if action.Action != journalActionAdd {
+			continue
+		}
+
+		if jp.CommentID != "" {
+			var c bitumplugin.Comment
+			err = d.Decode(&c)
+			if err != nil {
+				return -1, fmt.Errorf("journal add: %v", err)
+			}
+			if c.CommentID == jp.CommentID {
+				return k, nil
+			}
+			continue
+		}
+
+		var cvj CastVoteJournal
+		err = d.Decode(&cvj)
+		if err != nil {
+			return -1, fmt.Errorf("journal add: %v", err)
+		}
+		if cvj.CastVote.Ticket == jp.Ticket {
+			index = k
+		}
+	}
+
+	return index, nil
+}
+
+// pluginJournalProof returns the proof that a comment or a cast vote was
+// included in an anchored journal head.
+func (g *gitBackEnd) pluginJournalProof(payload string) (string, error) {
+	log.Tracef("pluginJournalProof")
+
+	jp, err := bitumplugin.DecodeJournalProof([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("DecodeJournalProof: %v", err)
+	}
+
+	// Validate token since it is used as a path
+	_, err = util.ConvertStringToken(jp.Token)
+	if err != nil {
+		return "", fmt.Errorf("invalid token %v: %v", jp.Token, err)
+	}
+
+	var journal, anchors string
+	switch {
+	case jp.CommentID != "" && jp.Ticket == "":
+		journal = defaultCommentFilename
+		anchors = defaultCommentsAnchors
+	case jp.Ticket != "" && jp.CommentID == "":
+		journal = defaultBallotFilename
+		anchors = defaultBallotAnchors
+	default:
+		return "", fmt.Errorf("either a comment id or a ticket is " +
+			"required")
+	}
+
+	// The journal entry of a censored or deleted comment contains the
+	// removed text so no proof is served for it.
+	if jp.CommentID != "" {
+		g.Lock()
+		_, censored := bitumPluginCensoredCommentsCache[jp.Token][jp.CommentID]
+		_, deleted := bitumPluginDeletedCommentsCache[jp.Token][jp.CommentID]
+		g.Unlock()
+		if censored {
+			return "", fmt.Errorf("comment censored %v:%v",
+				jp.Token, jp.CommentID)
+		}
+		if deleted {
+			return "", fmt.Errorf("comment deleted %v:%v",
+				jp.Token, jp.CommentID)
+		}
+	}
+
+	// Lookup journal entry
+	entries, err := readJournalEntries(pijoin(g.journals, jp.Token,
+		journal))
+	if err != nil {
+		return "", err
+	}
+	index, err := journalEntryIndex(*jp, entries)
+	if err != nil {
+		return "", fmt.Errorf("journalEntryIndex: %v", err)
+	}
+	if index == -1 {
+		return "", fmt.Errorf("journal entry not found %v:%v%v",
+			jp.Token, jp.CommentID, jp.Ticket)
+	}
+
+	jpr := bitumplugin.JournalProofReply{
+		Journal:    journal,
+		Entry:      entries[index],
+		Index:      uint64(index),
+		MerklePath: []string{},
+	}
+
+	// Lookup the first anchored journal head that contains the entry
+	g.Lock()
+	defer g.Unlock()
+
+	ja, err := readJournalAnchors(pijoin(g.journals, jp.Token, anchors))
+	if err != nil {
+		return "", err
+	}
+	var anchor *JournalAnchor
+	for k, v := range ja {
+		if v.Entries > uint64(index) {
+			anchor = &ja[k]
+			break
+		}
+	}
+
+	if anchor != nil {
+		// Rebuild the journal head from the journal
+		if anchor.Entries > uint64(len(entries)) {
+			return "", fmt.Errorf("journal %v:%v is shorter than "+
+				"its anchored head", jp.Token, journal)
+		}
+		digests := journalDigests(entries[:anchor.Entries])
+		head := bitumplugin.JournalHead(digests)
+		if hex.EncodeToString(head[:]) != anchor.JournalHead {
+			return "", fmt.Errorf("journal %v:%v does not match "+
+				"its anchored head", jp.Token, journal)
+		}
+		for _, v := range bitumplugin.JournalMerklePath(digests, index) {
+			jpr.MerklePath = append(jpr.MerklePath,
+				hex.EncodeToString(v[:]))
+		}
+
+		jpr.Entries = anchor.Entries
+		jpr.JournalHead = anchor.JournalHead
+		jpr.Anchor = anchor.Anchor
+		jpr.Timestamp = anchor.Timestamp
+
+		// Confirmed anchors are stored in the vetted repo
+		b, err := ioutil.ReadFile(pijoin(g.vetted,
+			defaultAnchorsDirectory, anchor.Anchor))
+		if err == nil {
+			var ci v1.ChainInformation
+			err = json.Unmarshal(b, &ci)
+			if err != nil {
+				return "", fmt.Errorf("chain information %v: %v",
+					anchor.Anchor, err)
+			}
+			jpr.ChainTimestamp = ci.ChainTimestamp
+			jpr.Transaction = ci.Transaction
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	reply, err := bitumplugin.EncodeJournalProofReply(jpr)
+	if err != nil {
+		return "", fmt.Errorf("EncodeJournalProofReply: %v", err)
+	}
+
+	return string(reply), nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gitbe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/bitum-project/bitumtime/api/v1"
+	"github.com/bitum-project/politeia/bitumplugin"
+	pd "github.com/bitum-project/politeia/politeiad/api/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+func TestJournalAnchors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journalanchors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := &gitBackEnd{
+		journal:  NewJournal(),
+		vetted:   pijoin(dir, DefaultVettedPath),
+		journals: pijoin(dir, DefaultJournalsPath),
+	}
+
+	r, err := util.Random(pd.TokenSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := hex.EncodeToString(r)
+	err = os.MkdirAll(pijoin(g.journals, token), 0774)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Journal comments and votes.  Ticket t1 votes twice.
+	addComment := func(commentID string) {
+		blob, err := bitumplugin.EncodeComment(bitumplugin.Comment{
+			Token:     token,
+			CommentID: commentID,
+			Comment:   "comment " + commentID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = g.journal.Journal(pijoin(g.journals, token,
+			defaultCommentFilename), string(journalAdd)+string(blob))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	addVote := func(ticket, voteBit string) {
+		blob, err := encodeCastVoteJournal(CastVoteJournal{
+			CastVote: bitumplugin.CastVote{
+				Token:   token,
+				Ticket:  ticket,
+				VoteBit: voteBit,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = g.journal.Journal(pijoin(g.journals, token,
+			defaultBallotFilename), string(journalAdd)+string(blob))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []string{"1", "2", "3"} {
+		addComment(v)
+	}
+	addVote("t1", "1")
+	addVote("t2", "1")
+	addVote("t1", "2")
+
+	proof := func(jp bitumplugin.JournalProof) *bitumplugin.JournalProofReply {
+		payload, err := bitumplugin.EncodeJournalProof(jp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply, err := g.pluginJournalProof(string(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		jpr, err := bitumplugin.DecodeJournalProofReply([]byte(reply))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return jpr
+	}
+
+	// Nothing has been anchored yet
+	heads, err := g.unanchoredJournalHeads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heads) != 2 {
+		t.Fatalf("unanchored heads got %v want 2", len(heads))
+	}
+	jpr := proof(bitumplugin.JournalProof{Token: token, CommentID: "2"})
+	if jpr.Anchor != "" || jpr.Index != 1 {
+		t.Fatalf("unanchored proof got anchor %v index %v", jpr.Anchor,
+			jpr.Index)
+	}
+
+	// Anchor both journals
+	var anchor1 [sha256.Size]byte
+	anchor1[0] = 1
+	err = g.storeJournalAnchors(heads, anchor1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heads, err = g.unanchoredJournalHeads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heads) != 0 {
+		t.Fatalf("unanchored heads got %v want 0", len(heads))
+	}
+
+	// An entry that is still being written is not part of the head
+	f, err := os.OpenFile(pijoin(g.journals, token,
+		defaultCommentFilename), os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write(journalAdd)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	heads, err = g.unanchoredJournalHeads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heads) != 0 {
+		t.Fatalf("partial entry: unanchored heads got %v want 0",
+			len(heads))
+	}
+
+	// Complete the entry and anchor the comments journal again
+	f, err = os.OpenFile(pijoin(g.journals, token,
+		defaultCommentFilename), os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := bitumplugin.EncodeComment(bitumplugin.Comment{
+		Token:     token,
+		CommentID: "4",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write(append(blob, '\n'))
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	heads, err = g.unanchoredJournalHeads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heads) != 1 || heads[0].entries != 4 {
+		t.Fatalf("unanchored heads got %v want 1 with 4 entries",
+			len(heads))
+	}
+	var anchor2 [sha256.Size]byte
+	anchor2[0] = 2
+	err = g.storeJournalAnchors(heads, anchor2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Confirm the first anchor
+	ci, err := json.Marshal(v1.ChainInformation{
+		ChainTimestamp: 3,
+		Transaction:    expectedTestTX,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(pijoin(g.vetted, defaultAnchorsDirectory), 0774)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(pijoin(g.vetted, defaultAnchorsDirectory,
+		hex.EncodeToString(anchor1[:])), ci, 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are proven by the first anchored head that contains them
+	var tests = []struct {
+		jp          bitumplugin.JournalProof
+		index       uint64
+		anchor      [sha256.Size]byte
+		transaction string
+	}{
+		{bitumplugin.JournalProof{Token: token, CommentID: "1"}, 0,
+			anchor1, expectedTestTX},
+		{bitumplugin.JournalProof{Token: token, CommentID: "3"}, 2,
+			anchor1, expectedTestTX},
+		{bitumplugin.JournalProof{Token: token, CommentID: "4"}, 3,
+			anchor2, ""},
+		{bitumplugin.JournalProof{Token: token, Ticket: "t1"}, 2,
+			anchor1, expectedTestTX},
+		{bitumplugin.JournalProof{Token: token, Ticket: "t2"}, 1,
+			anchor1, expectedTestTX},
+	}
+	for _, test := range tests {
+		jpr := proof(test.jp)
+		if jpr.Index != test.index {
+			t.Fatalf("%v%v: index got %v want %v", test.jp.CommentID,
+				test.jp.Ticket, jpr.Index, test.index)
+		}
+		if jpr.Anchor != hex.EncodeToString(test.anchor[:]) {
+			t.Fatalf("%v%v: anchor got %v want %x", test.jp.CommentID,
+				test.jp.Ticket, jpr.Anchor, test.anchor)
+		}
+		if jpr.Transaction != test.transaction {
+			t.Fatalf("%v%v: transaction got %v want %v",
+				test.jp.CommentID, test.jp.Ticket, jpr.Transaction,
+				test.transaction)
+		}
+		err = bitumplugin.VerifyJournalProof(*jpr)
+		if err != nil {
+			t.Fatalf("%v%v: %v", test.jp.CommentID, test.jp.Ticket,
+				err)
+		}
+	}
+
+	// No proof is served for censored or deleted comments since their
+	// journal entry contains the removed text.
+	bitumPluginCensoredCommentsCache[token] = map[string]commentCensorship{
+		"1": {},
+	}
+	bitumPluginDeletedCommentsCache[token] = map[string]struct{}{
+		"3": {},
+	}
+	defer func() {
+		delete(bitumPluginCensoredCommentsCache, token)
+		delete(bitumPluginDeletedCommentsCache, token)
+	}()
+	for _, commentID := range []string{"1", "3"} {
+		payload, err := bitumplugin.EncodeJournalProof(
+			bitumplugin.JournalProof{
+				Token:     token,
+				CommentID: commentID,
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.pluginJournalProof(string(payload))
+		if err == nil {
+			t.Fatalf("%v: expected error for removed comment",
+				commentID)
+		}
+	}
+}
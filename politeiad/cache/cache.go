@@ -10,6 +10,15 @@ import (
 
 type RecordStatusT int
 
+// SortOrderT specifies the order in which the records of a RecordQuery are
+// returned.
+type SortOrderT int
+
+// VoteStatusT is the status of the voting period of a record.  Vote statuses
+// are derived from the bitum plugin tables and are only available when the
+// bitum plugin has been registered with the cache.
+type VoteStatusT int
+
 var (
 	// ErrNoVersionRecord is emitted when no version record exists.
 	ErrNoVersionRecord = errors.New("no version record")
@@ -46,6 +55,19 @@ const (
 	RecordStatusPublic            RecordStatusT = 4 // Record is publicly visible
 	RecordStatusUnreviewedChanges RecordStatusT = 5 // NotReviewed record that has been changed
 	RecordStatusArchived          RecordStatusT = 6 // Public record that has been archived
+
+	// Sort orders
+	SortOrderNewest SortOrderT = 0 // Newest timestamp first
+	SortOrderOldest SortOrderT = 1 // Oldest timestamp first
+
+	// Vote status codes
+	VoteStatusInvalid       VoteStatusT = 0 // Invalid vote status
+	VoteStatusNotAuthorized VoteStatusT = 1 // Vote has not been authorized
+	VoteStatusAuthorized    VoteStatusT = 2 // Vote has been authorized
+	VoteStatusScheduled     VoteStatusT = 3 // Vote starts at a future block height
+	VoteStatusStarted       VoteStatusT = 4 // Vote has been started
+	VoteStatusFinished      VoteStatusT = 5 // Vote has finished
+	VoteStatusCancelled     VoteStatusT = 6 // Vote has been cancelled
 )
 
 // File describes an individual file that is part of the record.
@@ -95,6 +117,40 @@ type InventoryStats struct {
 	Archived          int // Number of archived records
 }
 
+// MetadataFilter matches the records that contain a metadata stream with the
+// specified ID whose payload is a JSON object in which the specified field is
+// set to one of the specified values.
+type MetadataFilter struct {
+	StreamID uint64   // Metadata stream identity
+	Field    string   // JSON field of the metadata stream payload
+	Values   []string // Field values to include
+}
+
+// RecordQuery is used to request a page of records that match the specified
+// filtering parameters.  Only the latest version of each record is considered.
+// Filters that are left at their zero value are not applied.
+//
+// Records are sorted by timestamp and then by token.  The After and Before
+// fields are record tokens that act as pagination cursors.  After returns the
+// records that follow the cursor record in the specified sort order and Before
+// returns the records that precede it.  Only one cursor may be used at a time.
+//
+// The vote status of a record depends on the best block so BestBlock must be
+// set when filtering by vote status.  A scheduled vote whose start height has
+// been reached is considered started.
+type RecordQuery struct {
+	Statuses        []RecordStatusT  // Record statuses to include
+	Metadata        []MetadataFilter // Metadata filters; all must match
+	TimestampAfter  int64            // Only include records updated after this UNIX timestamp
+	TimestampBefore int64            // Only include records updated before this UNIX timestamp
+	VoteStatuses    []VoteStatusT    // Vote statuses to include
+	BestBlock       uint64           // Best block; required for vote status filters
+	Sort            SortOrderT       // Sort order
+	After           string           // Return records after this token
+	Before          string           // Return records before this token
+	Limit           int              // Maximum number of records; 0 means no limit
+}
+
 // PluginCommand is used to execute a plugin command.  The reply payload
 // contains the reply from politeiad, which is sometimes required by commands
 // that write data to the cache.  The reply payload will be empty for commands
@@ -174,6 +230,14 @@ type Cache interface {
 	// Get a summary of the number of records by status
 	InventoryStats() (*InventoryStats, error)
 
+	// Get a page of the latest version of the records that match
+	// the query
+	Query(RecordQuery) ([]Record, error)
+
+	// Get a summary of the number of records by status for all of
+	// the records that match the query.  Pagination is ignored.
+	QueryStats(RecordQuery) (*InventoryStats, error)
+
 	// Setup the record cache tables
 	Setup() error
 
@@ -48,6 +48,16 @@ func (c *cachestub) InventoryStats() (*cache.InventoryStats, error) {
 	return &cache.InventoryStats{}, nil
 }
 
+// Query is a stub to satisfy the cache interface.
+func (c *cachestub) Query(q cache.RecordQuery) ([]cache.Record, error) {
+	return make([]cache.Record, 0), nil
+}
+
+// QueryStats is a stub to satisfy the cache interface.
+func (c *cachestub) QueryStats(q cache.RecordQuery) (*cache.InventoryStats, error) {
+	return &cache.InventoryStats{}, nil
+}
+
 // Setup is a stub to satisfy the cache interface.
 func (c *cachestub) Setup() error {
 	return nil
@@ -0,0 +1,639 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package cachetest contains a test suite that is shared by all cache
+// implementations.  Each implementation runs the suite against a freshly
+// setup cache to ensure that all drivers sort, filter and paginate record
+// queries the same way.
+package cachetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/cache"
+)
+
+const (
+	// metadataStreamTest is the ID of the metadata stream that is used to
+	// test the metadata filters.
+	metadataStreamTest = 7
+)
+
+// newRecord returns a new cache record.  The token is made up of the passed
+// in character so that the token sort order is easy to reason about.
+func newRecord(c byte, version string, status cache.RecordStatusT, timestamp int64, owner string) cache.Record {
+	return cache.Record{
+		Version:   version,
+		Status:    status,
+		Timestamp: timestamp,
+		CensorshipRecord: cache.CensorshipRecord{
+			Token:     strings.Repeat(string(c), 64),
+			Merkle:    strings.Repeat("0", 64),
+			Signature: strings.Repeat("0", 128),
+		},
+		Metadata: []cache.MetadataStream{
+			{
+				ID:      metadataStreamTest,
+				Payload: `{"owner":"` + owner + `"}`,
+			},
+		},
+	}
+}
+
+// tokens returns the tokens of the passed in records.
+func tokens(records []cache.Record) []string {
+	t := make([]string, 0, len(records))
+	for _, v := range records {
+		t = append(t, v.CensorshipRecord.Token)
+	}
+	return t
+}
+
+// newComment adds a comment to the cache using the bitum plugin.
+func newComment(t *testing.T, c cache.Cache, token, commentID, publicKey string, timestamp int64) {
+	t.Helper()
+
+	nc, err := bitumplugin.EncodeNewComment(bitumplugin.NewComment{
+		Token:     token,
+		ParentID:  "0",
+		Comment:   "comment " + commentID,
+		Signature: strings.Repeat("0", 128),
+		PublicKey: publicKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ncr, err := bitumplugin.EncodeNewCommentReply(bitumplugin.NewCommentReply{
+		CommentID: commentID,
+		Receipt:   strings.Repeat("0", 128),
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.PluginExec(cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdNewComment,
+		CommandPayload: string(nc),
+		ReplyPayload:   string(ncr),
+	})
+	if err != nil {
+		t.Fatalf("PluginExec %v: %v", bitumplugin.CmdNewComment, err)
+	}
+}
+
+// censorComment censors a comment in the cache using the bitum plugin.
+func censorComment(t *testing.T, c cache.Cache, token, commentID string) {
+	t.Helper()
+
+	cc, err := bitumplugin.EncodeCensorComment(bitumplugin.CensorComment{
+		Token:     token,
+		CommentID: commentID,
+		Reason:    "spam",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.PluginExec(cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdCensorComment,
+		CommandPayload: string(cc),
+		ReplyPayload:   "{}",
+	})
+	if err != nil {
+		t.Fatalf("PluginExec %v: %v", bitumplugin.CmdCensorComment, err)
+	}
+}
+
+// execVoteCmd executes a bitum plugin vote command with the passed in
+// encoded payloads.
+func execVoteCmd(t *testing.T, c cache.Cache, cmd string, payload, reply []byte) {
+	t.Helper()
+
+	_, err := c.PluginExec(cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        cmd,
+		CommandPayload: string(payload),
+		ReplyPayload:   string(reply),
+	})
+	if err != nil {
+		t.Fatalf("PluginExec %v: %v", cmd, err)
+	}
+}
+
+// authorizeVote authorizes the vote of the passed in record version.
+func authorizeVote(t *testing.T, c cache.Cache, token, version string) {
+	t.Helper()
+
+	av, err := bitumplugin.EncodeAuthorizeVote(bitumplugin.AuthorizeVote{
+		Action:    bitumplugin.AuthVoteActionAuthorize,
+		Token:     token,
+		Signature: strings.Repeat("0", 128),
+		PublicKey: strings.Repeat("0", 64),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	avr, err := bitumplugin.EncodeAuthorizeVoteReply(
+		bitumplugin.AuthorizeVoteReply{
+			Action:        bitumplugin.AuthVoteActionAuthorize,
+			RecordVersion: version,
+			Receipt:       strings.Repeat("0", 128),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	execVoteCmd(t, c, bitumplugin.CmdAuthorizeVote, av, avr)
+}
+
+// startVote starts the vote of the passed in record.  A non zero start height
+// schedules the vote, in which case the end height is ignored.
+func startVote(t *testing.T, c cache.Cache, token string, startHeight uint32, endHeight string) {
+	t.Helper()
+
+	var svr bitumplugin.StartVoteReply
+	if startHeight == 0 {
+		svr = bitumplugin.StartVoteReply{
+			StartBlockHeight: "1",
+			StartBlockHash:   strings.Repeat("0", 64),
+			EndHeight:        endHeight,
+		}
+	}
+	sv, err := bitumplugin.EncodeStartVote(bitumplugin.StartVote{
+		PublicKey: strings.Repeat("0", 64),
+		Signature: strings.Repeat("0", 128),
+		Vote: bitumplugin.Vote{
+			Token:       token,
+			StartHeight: startHeight,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svrb, err := bitumplugin.EncodeStartVoteReply(svr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execVoteCmd(t, c, bitumplugin.CmdStartVote, sv, svrb)
+}
+
+// cancelVote cancels the vote of the passed in record.
+func cancelVote(t *testing.T, c cache.Cache, token string) {
+	t.Helper()
+
+	cv, err := bitumplugin.EncodeCancelVote(bitumplugin.CancelVote{
+		Token:     token,
+		Reason:    "cancel",
+		Signature: strings.Repeat("0", 128),
+		PublicKey: strings.Repeat("0", 64),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cvr, err := bitumplugin.EncodeCancelVoteReply(bitumplugin.CancelVoteReply{
+		Tokens:  []string{token},
+		Receipt: strings.Repeat("0", 128),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	execVoteCmd(t, c, bitumplugin.CmdCancelVote, cv, cvr)
+}
+
+// Run runs the cache test suite against the passed in cache.  The cache must
+// already be setup, the bitum plugin must be registered and setup and the
+// cache must not contain any records or comments.
+func Run(t *testing.T, c cache.Cache) {
+	t.Run("query", func(t *testing.T) { testQuery(t, c) })
+	t.Run("bitum user comments", func(t *testing.T) { testUserComments(t, c) })
+	t.Run("vote statuses", func(t *testing.T) { testVoteStatuses(t, c) })
+}
+
+func testQuery(t *testing.T, c cache.Cache) {
+	// r2 and r3 share a timestamp so that the token tie breaker is
+	// exercised. The first version of r1 has the newest timestamp
+	// but must be ignored since it is not the latest version.
+	r1v1 := newRecord('1', "1", cache.RecordStatusNotReviewed, 400, "alice")
+	r1 := newRecord('1', "2", cache.RecordStatusPublic, 100, "alice")
+	r2 := newRecord('3', "1", cache.RecordStatusPublic, 200, "alice")
+	r3 := newRecord('2', "1", cache.RecordStatusPublic, 200, "bob")
+	r4 := newRecord('4', "1", cache.RecordStatusCensored, 300, "bob")
+	for _, v := range []cache.Record{r1v1, r1, r2, r3, r4} {
+		err := c.NewRecord(v)
+		if err != nil {
+			t.Fatalf("NewRecord: %v", err)
+		}
+	}
+
+	token := func(r cache.Record) string {
+		return r.CensorshipRecord.Token
+	}
+	owner := func(values ...string) []cache.MetadataFilter {
+		return []cache.MetadataFilter{
+			{
+				StreamID: metadataStreamTest,
+				Field:    "owner",
+				Values:   values,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query cache.RecordQuery
+		want  []cache.Record
+	}{
+		{
+			"newest",
+			cache.RecordQuery{},
+			[]cache.Record{r4, r3, r2, r1},
+		},
+		{
+			"oldest",
+			cache.RecordQuery{Sort: cache.SortOrderOldest},
+			[]cache.Record{r1, r2, r3, r4},
+		},
+		{
+			"newest limit",
+			cache.RecordQuery{Limit: 2},
+			[]cache.Record{r4, r3},
+		},
+		{
+			"newest after",
+			cache.RecordQuery{After: token(r3), Limit: 2},
+			[]cache.Record{r2, r1},
+		},
+		{
+			"newest after last",
+			cache.RecordQuery{After: token(r1)},
+			[]cache.Record{},
+		},
+		{
+			"newest before",
+			cache.RecordQuery{Before: token(r2)},
+			[]cache.Record{r4, r3},
+		},
+		{
+			"newest before limit",
+			cache.RecordQuery{Before: token(r2), Limit: 1},
+			[]cache.Record{r3},
+		},
+		{
+			"oldest after",
+			cache.RecordQuery{
+				Sort:  cache.SortOrderOldest,
+				After: token(r2),
+			},
+			[]cache.Record{r3, r4},
+		},
+		{
+			"oldest before limit",
+			cache.RecordQuery{
+				Sort:   cache.SortOrderOldest,
+				Before: token(r3),
+				Limit:  1,
+			},
+			[]cache.Record{r2},
+		},
+		{
+			"statuses",
+			cache.RecordQuery{
+				Statuses: []cache.RecordStatusT{
+					cache.RecordStatusPublic,
+				},
+			},
+			[]cache.Record{r3, r2, r1},
+		},
+		{
+			"metadata",
+			cache.RecordQuery{Metadata: owner("alice")},
+			[]cache.Record{r2, r1},
+		},
+		{
+			"metadata after",
+			cache.RecordQuery{
+				Metadata: owner("bob"),
+				After:    token(r4),
+			},
+			[]cache.Record{r3},
+		},
+		{
+			"timestamps",
+			cache.RecordQuery{
+				TimestampAfter:  100,
+				TimestampBefore: 300,
+			},
+			[]cache.Record{r3, r2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := c.Query(test.query)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			got := tokens(records)
+			want := tokens(test.want)
+			if len(got) != len(want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("got %v, want %v", got, want)
+				}
+			}
+		})
+	}
+
+	// Invalid cursors
+	_, err := c.Query(cache.RecordQuery{
+		After:  token(r1),
+		Before: token(r2),
+	})
+	if err == nil {
+		t.Fatalf("Query: want error for after and before cursors")
+	}
+	_, err = c.Query(cache.RecordQuery{
+		After: strings.Repeat("f", 64),
+	})
+	if err != cache.ErrRecordNotFound {
+		t.Fatalf("Query: got error %v, want %v", err,
+			cache.ErrRecordNotFound)
+	}
+
+	// Summary statistics ignore pagination
+	is, err := c.QueryStats(cache.RecordQuery{
+		Metadata: owner("bob"),
+		Limit:    1,
+	})
+	if err != nil {
+		t.Fatalf("QueryStats: %v", err)
+	}
+	if is.Public != 1 || is.Censored != 1 || is.NotReviewed != 0 {
+		t.Fatalf("QueryStats: got %+v, want 1 public and 1 censored",
+			*is)
+	}
+}
+
+func testUserComments(t *testing.T, c cache.Cache) {
+	tokenA := strings.Repeat("a", 64)
+	tokenB := strings.Repeat("b", 64)
+
+	// The user has two identities, key1 and key2.  Comments a2 and
+	// a3 share a timestamp so that the key tie breaker is exercised.
+	key1 := strings.Repeat("1", 64)
+	key2 := strings.Repeat("2", 64)
+	other := strings.Repeat("3", 64)
+	newComment(t, c, tokenA, "1", key1, 100)
+	newComment(t, c, tokenA, "2", key2, 200)
+	newComment(t, c, tokenA, "3", key1, 200)
+	newComment(t, c, tokenB, "1", key1, 300)
+	newComment(t, c, tokenB, "2", other, 400)
+	censorComment(t, c, tokenB, "1")
+
+	a1 := tokenA + "1"
+	a2 := tokenA + "2"
+	a3 := tokenA + "3"
+	b1 := tokenB + "1"
+
+	tests := []struct {
+		name           string
+		uc             bitumplugin.UserComments
+		want           []string // [token+commentID]
+		wantNextOffset uint32
+	}{
+		{
+			"all identities",
+			bitumplugin.UserComments{
+				PublicKeys: []string{key1, key2},
+			},
+			[]string{a3, a2, a1},
+			0,
+		},
+		{
+			"single identity",
+			bitumplugin.UserComments{
+				PublicKeys: []string{key2},
+			},
+			[]string{a2},
+			0,
+		},
+		{
+			"include censored",
+			bitumplugin.UserComments{
+				PublicKeys:      []string{key1, key2},
+				IncludeCensored: true,
+			},
+			[]string{b1, a3, a2, a1},
+			0,
+		},
+		{
+			"first page",
+			bitumplugin.UserComments{
+				PublicKeys:      []string{key1, key2},
+				IncludeCensored: true,
+				Limit:           2,
+			},
+			[]string{b1, a3},
+			2,
+		},
+		{
+			"last page",
+			bitumplugin.UserComments{
+				PublicKeys:      []string{key1, key2},
+				IncludeCensored: true,
+				Offset:          2,
+				Limit:           2,
+			},
+			[]string{a2, a1},
+			0,
+		},
+		{
+			"offset past end",
+			bitumplugin.UserComments{
+				PublicKeys: []string{key1, key2},
+				Offset:     10,
+				Limit:      2,
+			},
+			[]string{},
+			0,
+		},
+		{
+			"unknown identity",
+			bitumplugin.UserComments{
+				PublicKeys: []string{strings.Repeat("4", 64)},
+			},
+			[]string{},
+			0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload, err := bitumplugin.EncodeUserComments(test.uc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reply, err := c.PluginExec(cache.PluginCommand{
+				ID:             bitumplugin.ID,
+				Command:        bitumplugin.CmdUserComments,
+				CommandPayload: string(payload),
+			})
+			if err != nil {
+				t.Fatalf("PluginExec: %v", err)
+			}
+			ucr, err := bitumplugin.DecodeUserCommentsReply(
+				[]byte(reply.Payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(ucr.Comments))
+			for _, v := range ucr.Comments {
+				got = append(got, v.Token+v.CommentID)
+
+				// The text of a censored comment is not kept
+				// by the cache, not even for admins.
+				if v.Censored != (v.Token+v.CommentID == b1) {
+					t.Errorf("comment %v: got censored %v",
+						v.CommentID, v.Censored)
+				}
+				if v.Censored && v.Comment != "" {
+					t.Errorf("censored comment %v has text %q",
+						v.CommentID, v.Comment)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			if ucr.NextOffset != test.wantNextOffset {
+				t.Fatalf("got next offset %v, want %v",
+					ucr.NextOffset, test.wantNextOffset)
+			}
+		})
+	}
+}
+
+func testVoteStatuses(t *testing.T, c cache.Cache) {
+	// The records of this test have their own owner so that the
+	// records of the other tests can be filtered out. The vote of
+	// the first version of notAuthorized was authorized but a new
+	// version has been submitted since.
+	const bestBlock = 100
+	public := cache.RecordStatusPublic
+	notAuthorizedV1 := newRecord('5', "1", public, 100, "voter")
+	notAuthorized := newRecord('5', "2", public, 100, "voter")
+	authorized := newRecord('6', "1", public, 200, "voter")
+	scheduled := newRecord('7', "1", public, 300, "voter")
+	scheduledStarted := newRecord('8', "1", public, 400, "voter")
+	started := newRecord('9', "1", public, 500, "voter")
+	finished := newRecord('c', "1", public, 600, "voter")
+	cancelled := newRecord('d', "1", public, 700, "voter")
+	for _, v := range []cache.Record{notAuthorizedV1, notAuthorized,
+		authorized, scheduled, scheduledStarted, started, finished,
+		cancelled} {
+		err := c.NewRecord(v)
+		if err != nil {
+			t.Fatalf("NewRecord: %v", err)
+		}
+	}
+
+	token := func(r cache.Record) string {
+		return r.CensorshipRecord.Token
+	}
+	authorizeVote(t, c, token(notAuthorizedV1), notAuthorizedV1.Version)
+	for _, v := range []cache.Record{authorized, scheduled,
+		scheduledStarted, started, finished, cancelled} {
+		authorizeVote(t, c, token(v), v.Version)
+	}
+	startVote(t, c, token(scheduled), bestBlock+1, "")
+	startVote(t, c, token(scheduledStarted), bestBlock, "")
+	startVote(t, c, token(started), 0, "101")
+	startVote(t, c, token(finished), 0, "100")
+	startVote(t, c, token(cancelled), 0, "101")
+	cancelVote(t, c, token(cancelled))
+
+	voter := []cache.MetadataFilter{
+		{
+			StreamID: metadataStreamTest,
+			Field:    "owner",
+			Values:   []string{"voter"},
+		},
+	}
+	tests := []struct {
+		name     string
+		statuses []cache.VoteStatusT
+		want     []cache.Record
+	}{
+		{
+			"not authorized",
+			[]cache.VoteStatusT{cache.VoteStatusNotAuthorized},
+			[]cache.Record{notAuthorized},
+		},
+		{
+			"authorized",
+			[]cache.VoteStatusT{cache.VoteStatusAuthorized},
+			[]cache.Record{authorized},
+		},
+		{
+			"scheduled",
+			[]cache.VoteStatusT{cache.VoteStatusScheduled},
+			[]cache.Record{scheduled},
+		},
+		{
+			"started",
+			[]cache.VoteStatusT{cache.VoteStatusStarted},
+			[]cache.Record{started, scheduledStarted},
+		},
+		{
+			"finished",
+			[]cache.VoteStatusT{cache.VoteStatusFinished},
+			[]cache.Record{finished},
+		},
+		{
+			"cancelled",
+			[]cache.VoteStatusT{cache.VoteStatusCancelled},
+			[]cache.Record{cancelled},
+		},
+		{
+			"active",
+			[]cache.VoteStatusT{
+				cache.VoteStatusScheduled,
+				cache.VoteStatusStarted,
+			},
+			[]cache.Record{started, scheduledStarted, scheduled},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := c.Query(cache.RecordQuery{
+				Metadata:     voter,
+				VoteStatuses: test.statuses,
+				BestBlock:    bestBlock,
+			})
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			got := strings.Join(tokens(records), ",")
+			want := strings.Join(tokens(test.want), ",")
+			if got != want {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Invalid vote status
+	_, err := c.Query(cache.RecordQuery{
+		VoteStatuses: []cache.VoteStatusT{cache.VoteStatusInvalid},
+	})
+	if err == nil {
+		t.Fatalf("Query: want error for invalid vote status")
+	}
+}
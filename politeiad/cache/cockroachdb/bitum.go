@@ -6,6 +6,7 @@ package cockroachdb
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,20 +21,20 @@ const (
 	// bitumVersion is the version of the cache implementation of
 	// bitum plugin. This may differ from the bitumplugin package
 	// version.
-	bitumVersion = "1.1"
+	bitumVersion = "1.14"
 
 	// Bitum plugin table names
 	tableComments          = "comments"
 	tableCommentLikes      = "comment_likes"
+	tableCommentFlags      = "comment_flags"
 	tableCastVotes         = "cast_votes"
 	tableAuthorizeVotes    = "authorize_votes"
 	tableVoteOptions       = "vote_options"
 	tableStartVotes        = "start_votes"
 	tableVoteOptionResults = "vote_option_results"
 	tableVoteResults       = "vote_results"
-
-	// Vote option IDs
-	voteOptionIDApproved = "yes"
+	tableCancelVotes       = "cancel_votes"
+	tableScheduledVotes    = "scheduled_votes"
 )
 
 // bitum implements the PluginDriver interface.
@@ -43,10 +44,38 @@ type bitum struct {
 	settings  []cache.PluginSetting // Plugin settings
 }
 
-// newComment inserts a Comment record into the database.  This function has a
-// database parameter so that it can be called inside of a transaction when
-// required.
+// newComment inserts a Comment record into the database.  The comment is
+// placed in its thread using the parent comment, which must already exist in
+// the database, and the reply count of the parent is incremented.  This
+// function has a database parameter so that it can be called inside of a
+// transaction when required.
 func (d *bitum) newComment(db *gorm.DB, c Comment) error {
+	c.RootID = c.CommentID
+	if c.ParentID != "" && c.ParentID != "0" {
+		parent := Comment{
+			Key: c.Token + c.ParentID,
+		}
+		err := db.Find(&parent).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			// Complain but treat the comment as a root
+			// comment so that it is not lost.
+			log.Errorf("newComment: parent not found %v %v",
+				c.Token, c.ParentID)
+		case err != nil:
+			return err
+		default:
+			c.Depth = parent.Depth + 1
+			c.RootID = parent.RootID
+			err = db.Model(&parent).
+				Update("replies", gorm.Expr("replies + 1")).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return db.Create(&c).Error
 }
 
@@ -65,9 +94,15 @@ func (d *bitum) cmdNewComment(cmdPayload, replyPayload string) (string, error) {
 	}
 
 	c := convertNewCommentFromBitum(*nc, *ncr)
-	err = d.newComment(d.recordsdb, c)
 
-	return replyPayload, err
+	tx := d.recordsdb.Begin()
+	err = d.newComment(tx, c)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	return replyPayload, tx.Commit().Error
 }
 
 // newLikeComment inserts a LikeComment record into the database.  This
@@ -77,6 +112,63 @@ func (d *bitum) newLikeComment(db *gorm.DB, lc LikeComment) error {
 	return db.Create(&lc).Error
 }
 
+// commentScore returns the vote score of a comment using the provided likes,
+// which must be in the order that they were cast.  Each user counts once, even
+// when they liked the comment using different public keys.  Likes that were
+// cast without a user ID are attributed to their public key.  Repeating the
+// previous action removes it and a different action replaces it.
+func commentScore(likes []LikeComment) (int64, error) {
+	var score int64
+	actions := make(map[string]int64, len(likes)) // [userID]action
+	for _, v := range likes {
+		action, err := strconv.ParseInt(v.Action, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse action '%v' failed on "+
+				"commentID %v: %v", v.Action, v.CommentID, err)
+		}
+
+		voter := v.UserID
+		if voter == "" {
+			voter = v.PublicKey
+		}
+		prev := actions[voter]
+		score -= prev
+		if prev == action {
+			actions[voter] = 0
+			continue
+		}
+		score += action
+		actions[voter] = action
+	}
+
+	return score, nil
+}
+
+// updateCommentScore recalculates the vote score of the specified comment
+// and updates the comment record.  This function has a database parameter so
+// that it can be called inside of a transaction when required.
+func (d *bitum) updateCommentScore(db *gorm.DB, token, commentID string) error {
+	likes := make([]LikeComment, 0, 1024) // PNOOMA
+	err := db.
+		Where("token = ? AND comment_id = ?", token, commentID).
+		Order("key ASC").
+		Find(&likes).
+		Error
+	if err != nil {
+		return err
+	}
+
+	score, err := commentScore(likes)
+	if err != nil {
+		return err
+	}
+
+	c := Comment{
+		Key: token + commentID,
+	}
+	return db.Model(&c).Update("result_votes", score).Error
+}
+
 // cmdLikeComment creates a LikeComment record using the passed in payloads
 // and inserts it into the database.
 func (d *bitum) cmdLikeComment(cmdPayload, replyPayload string) (string, error) {
@@ -88,7 +180,19 @@ func (d *bitum) cmdLikeComment(cmdPayload, replyPayload string) (string, error)
 	}
 
 	lc := convertLikeCommentFromBitum(*dlc)
-	err = d.newLikeComment(d.recordsdb, lc)
+
+	tx := d.recordsdb.Begin()
+	err = d.newLikeComment(tx, lc)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	err = d.updateCommentScore(tx, lc.Token, lc.CommentID)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	err = tx.Commit().Error
 
 	return replyPayload, err
 }
@@ -115,6 +219,231 @@ func (d *bitum) cmdCensorComment(cmdPayload, replyPayload string) (string, error
 	return replyPayload, err
 }
 
+// cmdResolveAppeal applies the resolution of a censorship appeal.  Only a
+// restored comment requires the cache to be updated.  The restored text and
+// edit metadata are returned by politeiad in the reply payload.
+func (d *bitum) cmdResolveAppeal(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdResolveAppeal")
+
+	rar, err := bitumplugin.DecodeResolveAppealReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+	if rar.Comment == nil {
+		return replyPayload, nil
+	}
+
+	c := Comment{
+		Key: rar.Comment.Token + rar.Comment.CommentID,
+	}
+	err = d.recordsdb.Model(&c).
+		Updates(map[string]interface{}{
+			"comment":         rar.Comment.Comment,
+			"censored":        false,
+			"edits":           rar.Comment.Edits,
+			"edit_timestamp":  rar.Comment.EditTimestamp,
+			"edit_signature":  rar.Comment.EditSignature,
+			"edit_public_key": rar.Comment.EditPublicKey,
+			"edit_receipt":    rar.Comment.EditReceipt,
+		}).Error
+
+	return replyPayload, err
+}
+
+// cmdDeleteComment deletes an existing comment on behalf of its author.  A
+// deleted comment has its comment message removed and is marked as deleted.
+func (d *bitum) cmdDeleteComment(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdDeleteComment")
+
+	dc, err := bitumplugin.DecodeDeleteComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c := Comment{
+		Key: dc.Token + dc.CommentID,
+	}
+	err = d.recordsdb.Model(&c).
+		Updates(map[string]interface{}{
+			"comment": "",
+			"deleted": true,
+		}).Error
+
+	return replyPayload, err
+}
+
+// cmdEditComment updates the text of an existing comment and increments its
+// edit count.  Only the latest revision of a comment is stored in the cache.
+func (d *bitum) cmdEditComment(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdEditComment")
+
+	ec, err := bitumplugin.DecodeEditComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+	ecr, err := bitumplugin.DecodeEditCommentReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c := Comment{
+		Key: ec.Token + ec.CommentID,
+	}
+	err = d.recordsdb.Model(&c).
+		Updates(map[string]interface{}{
+			"comment":         ec.Comment,
+			"edits":           gorm.Expr("edits + 1"),
+			"edit_timestamp":  ecr.Timestamp,
+			"edit_signature":  ec.Signature,
+			"edit_public_key": ec.PublicKey,
+			"edit_receipt":    ecr.Receipt,
+		}).Error
+
+	return replyPayload, err
+}
+
+// cmdFlagComment creates a CommentFlag record using the passed in payloads
+// and inserts it into the database.
+func (d *bitum) cmdFlagComment(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdFlagComment")
+
+	fc, err := bitumplugin.DecodeFlagComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+	fcr, err := bitumplugin.DecodeFlagCommentReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	fc.Receipt = fcr.Receipt
+	fc.Timestamp = fcr.Timestamp
+	cf := convertCommentFlagFromBitum(*fc)
+	err = d.recordsdb.Create(&cf).Error
+
+	return replyPayload, err
+}
+
+// cmdDismissCommentFlags deletes all of the flags of the specified comment.
+func (d *bitum) cmdDismissCommentFlags(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdDismissCommentFlags")
+
+	dcf, err := bitumplugin.DecodeDismissCommentFlags([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	err = d.recordsdb.
+		Where("token = ? AND comment_id = ?", dcf.Token, dcf.CommentID).
+		Delete(CommentFlag{}).
+		Error
+
+	return replyPayload, err
+}
+
+// cmdCommentFlags returns the flags of the passed in comment.
+func (d *bitum) cmdCommentFlags(payload string) (string, error) {
+	log.Tracef("bitum cmdCommentFlags")
+
+	cf, err := bitumplugin.DecodeCommentFlags([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	var flags []CommentFlag
+	err = d.recordsdb.
+		Where("token = ? AND comment_id = ?", cf.Token, cf.CommentID).
+		Order("key ASC").
+		Find(&flags).
+		Error
+	if err != nil {
+		return "", err
+	}
+
+	f := make([]bitumplugin.FlagComment, 0, len(flags))
+	for _, v := range flags {
+		f = append(f, convertCommentFlagToBitum(v))
+	}
+
+	cfr := bitumplugin.CommentFlagsReply{
+		Flags: f,
+	}
+	cfrb, err := bitumplugin.EncodeCommentFlagsReply(cfr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(cfrb), nil
+}
+
+// cmdFlaggedComments returns all comments that have flags, along with their
+// flags.  Comments that have been censored or deleted are left out.  The
+// comments are sorted by number of flags, most flagged first, and then by
+// the time that they were first flagged.
+func (d *bitum) cmdFlaggedComments() (string, error) {
+	log.Tracef("bitum cmdFlaggedComments")
+
+	var flags []CommentFlag
+	err := d.recordsdb.
+		Order("key ASC").
+		Find(&flags).
+		Error
+	if err != nil {
+		return "", err
+	}
+
+	// Group the flags by comment
+	keys := make([]string, 0, len(flags))
+	grouped := make(map[string][]bitumplugin.FlagComment) // [token+commentID]flags
+	for _, v := range flags {
+		k := v.Token + v.CommentID
+		if _, ok := grouped[k]; !ok {
+			keys = append(keys, k)
+		}
+		grouped[k] = append(grouped[k], convertCommentFlagToBitum(v))
+	}
+
+	// Lookup the flagged comments
+	var comments []Comment
+	err = d.recordsdb.
+		Where("key IN (?) AND censored = ? AND deleted = ?",
+			keys, false, false).
+		Find(&comments).
+		Error
+	if err != nil {
+		return "", err
+	}
+	cm := make(map[string]Comment, len(comments)) // [key]comment
+	for _, v := range comments {
+		cm[v.Key] = v
+	}
+
+	fc := make([]bitumplugin.FlaggedComment, 0, len(comments))
+	for _, k := range keys {
+		c, ok := cm[k]
+		if !ok {
+			continue
+		}
+		fc = append(fc, bitumplugin.FlaggedComment{
+			Comment: convertCommentToBitum(c),
+			Flags:   grouped[k],
+		})
+	}
+	sort.SliceStable(fc, func(i, j int) bool {
+		return len(fc[i].Flags) > len(fc[j].Flags)
+	})
+
+	fcr := bitumplugin.FlaggedCommentsReply{
+		Comments: fc,
+	}
+	fcrb, err := bitumplugin.EncodeFlaggedCommentsReply(fcr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(fcrb), nil
+}
+
 // cmdGetComment retreives the passed in comment from the database.
 func (d *bitum) cmdGetComment(payload string) (string, error) {
 	log.Tracef("bitum cmdGetComment")
@@ -155,13 +484,81 @@ func (d *bitum) cmdGetComments(payload string) (string, error) {
 		return "", err
 	}
 
+	// Comment IDs are stored as strings so they are cast in order
+	// to sort them numerically.
+	var order string
+	switch gc.Sort {
+	case "":
+		// No particular order
+	case bitumplugin.CommentSortTop:
+		order = "result_votes DESC, timestamp ASC, " +
+			"CAST(comment_id AS INT) ASC"
+	case bitumplugin.CommentSortNew:
+		order = "timestamp DESC, CAST(comment_id AS INT) DESC"
+	case bitumplugin.CommentSortOld:
+		order = "timestamp ASC, CAST(comment_id AS INT) ASC"
+	default:
+		return "", fmt.Errorf("invalid sort '%v'", gc.Sort)
+	}
+
+	// Limit the depth of the returned comments.  The depth of root
+	// comments is stored as 0.
+	query := d.recordsdb.Where("token = ?", gc.Token)
+	if gc.MaxDepth > 0 {
+		query = query.Where("depth < ?", gc.MaxDepth)
+	}
+	if order != "" {
+		query = query.Order(order)
+	}
+
 	comments := make([]Comment, 0, 1024) // PNOOMA
-	err = d.recordsdb.
-		Where("token = ?", gc.Token).
-		Find(&comments).
-		Error
-	if err != nil {
-		return "", err
+	var nextOffset uint32
+	if gc.Offset == 0 && gc.Limit == 0 {
+		// Return all comments
+		err = query.Find(&comments).Error
+		if err != nil {
+			return "", err
+		}
+	} else {
+		// Lookup the requested page of root comments. One extra
+		// root comment is requested to determine whether there is
+		// a next page.
+		roots := make([]Comment, 0, gc.Limit+1)
+		q := d.recordsdb.
+			Where("token = ? AND depth = 0", gc.Token).
+			Offset(gc.Offset)
+		if order != "" {
+			q = q.Order(order)
+		}
+		if gc.Limit > 0 {
+			q = q.Limit(gc.Limit + 1)
+		}
+		err = q.Find(&roots).Error
+		if err != nil {
+			return "", err
+		}
+		if gc.Limit > 0 && uint32(len(roots)) > gc.Limit {
+			roots = roots[:gc.Limit]
+			nextOffset = gc.Offset + gc.Limit
+		}
+
+		// Lookup the replies of the root comments
+		rootIDs := make([]string, 0, len(roots))
+		for _, v := range roots {
+			rootIDs = append(rootIDs, v.CommentID)
+		}
+		comments = append(comments, roots...)
+		if len(rootIDs) > 0 && gc.MaxDepth != 1 {
+			replies := make([]Comment, 0, 1024) // PNOOMA
+			err = query.
+				Where("depth > 0 AND root_id IN (?)", rootIDs).
+				Find(&replies).
+				Error
+			if err != nil {
+				return "", err
+			}
+			comments = append(comments, replies...)
+		}
 	}
 
 	dpc := make([]bitumplugin.Comment, 0, len(comments))
@@ -170,7 +567,8 @@ func (d *bitum) cmdGetComments(payload string) (string, error) {
 	}
 
 	gcr := bitumplugin.GetCommentsReply{
-		Comments: dpc,
+		Comments:   dpc,
+		NextOffset: nextOffset,
 	}
 	gcrb, err := bitumplugin.EncodeGetCommentsReply(gcr)
 	if err != nil {
@@ -180,6 +578,133 @@ func (d *bitum) cmdGetComments(payload string) (string, error) {
 	return string(gcrb), nil
 }
 
+// cmdUserComments returns a page of the comments made by the passed in public
+// keys across all records, newest first.
+func (d *bitum) cmdUserComments(payload string) (string, error) {
+	log.Tracef("bitum cmdUserComments")
+
+	uc, err := bitumplugin.DecodeUserComments([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	// One extra comment is requested to determine whether
+	// there is a next page.
+	query := d.recordsdb.
+		Where("public_key IN (?)", uc.PublicKeys).
+		Order("timestamp DESC, token DESC, CAST(comment_id AS INT) DESC").
+		Offset(uc.Offset)
+	if !uc.IncludeCensored {
+		query = query.Where("censored = ?", false)
+	}
+	if uc.Limit > 0 {
+		query = query.Limit(uc.Limit + 1)
+	}
+
+	comments := make([]Comment, 0, uc.Limit+1)
+	err = query.Find(&comments).Error
+	if err != nil {
+		return "", err
+	}
+
+	var nextOffset uint32
+	if uc.Limit > 0 && uint32(len(comments)) > uc.Limit {
+		comments = comments[:uc.Limit]
+		nextOffset = uc.Offset + uc.Limit
+	}
+
+	dpc := make([]bitumplugin.Comment, 0, len(comments))
+	for _, c := range comments {
+		dpc = append(dpc, convertCommentToBitum(c))
+	}
+
+	ucr := bitumplugin.UserCommentsReply{
+		Comments:   dpc,
+		NextOffset: nextOffset,
+	}
+	ucrb, err := bitumplugin.EncodeUserCommentsReply(ucr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(ucrb), nil
+}
+
+// cmdUnreadComments counts the comments of each of the passed in records that
+// were made after the passed in access time of the record.  Comments made by
+// the passed in public keys and comments that have been censored or deleted
+// are not counted.
+func (d *bitum) cmdUnreadComments(payload string) (string, error) {
+	log.Tracef("bitum cmdUnreadComments")
+
+	uc, err := bitumplugin.DecodeUnreadComments([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	unread, err := d.unreadComments(uc.AccessTimes, uc.PublicKeys)
+	if err != nil {
+		return "", err
+	}
+
+	ucr := bitumplugin.UnreadCommentsReply{
+		Unread: unread,
+	}
+	ucrb, err := bitumplugin.EncodeUnreadCommentsReply(ucr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(ucrb), nil
+}
+
+// unreadComments counts the comments of each record that were made after the
+// access time of the record.  Records without unread comments are left out
+// of the returned map.
+func (d *bitum) unreadComments(accessTimes map[string]int64, pubkeys []string) (map[string]bitumplugin.UnreadComment, error) {
+	unread := make(map[string]bitumplugin.UnreadComment, len(accessTimes))
+	if len(accessTimes) == 0 {
+		return unread, nil
+	}
+
+	// Each record has its own access time so a condition is
+	// added for every record.
+	conds := make([]string, 0, len(accessTimes))
+	args := make([]interface{}, 0, len(accessTimes)*2)
+	for token, accessTime := range accessTimes {
+		conds = append(conds, "(token = ? AND timestamp > ?)")
+		args = append(args, token, accessTime)
+	}
+	query := d.recordsdb.
+		Model(&Comment{}).
+		Select("token, count(*), max(timestamp)").
+		Where("censored = ? AND deleted = ?", false, false).
+		Where(strings.Join(conds, " OR "), args...)
+	if len(pubkeys) > 0 {
+		query = query.Where("public_key NOT IN (?)", pubkeys)
+	}
+
+	rows, err := query.Group("token").Rows()
+	if err != nil {
+		return nil, fmt.Errorf("count unread comments: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			token string
+			c     bitumplugin.UnreadComment
+		)
+		err = rows.Scan(&token, &c.Count, &c.Latest)
+		if err != nil {
+			return nil, fmt.Errorf("scan unread comments: %v", err)
+		}
+		unread[token] = c
+	}
+
+	return unread, rows.Err()
+}
+
 // cmdCommentLikes returns all of the comment likes for the passed in comment.
 func (d *bitum) cmdCommentLikes(payload string) (string, error) {
 	log.Tracef("bitum cmdCommentLikes")
@@ -318,37 +843,191 @@ func (d *bitum) newStartVote(db *gorm.DB, sv StartVote) error {
 	return db.Create(&sv).Error
 }
 
-// cmdStartVote creates a StartVote record using the passed in payloads and
-// inserts it into the database.
-func (d *bitum) cmdStartVote(cmdPayload, replyPayload string) (string, error) {
-	log.Tracef("bitum cmdStartVote")
+// newScheduledVote inserts a ScheduledVote record into the database.  This
+// function has a database parameter so that it can be called inside of a
+// transaction when required.
+func (d *bitum) newScheduledVote(db *gorm.DB, sv bitumplugin.StartVote) error {
+	s, err := convertScheduledVoteFromBitum(sv)
+	if err != nil {
+		return err
+	}
+	return db.Create(s).Error
+}
+
+// cmdStartVote creates a StartVote record using the passed in payloads and
+// inserts it into the database.  A ScheduledVote record is created instead
+// when the vote is scheduled to start at a future block height.
+func (d *bitum) cmdStartVote(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdStartVote")
+
+	sv, err := bitumplugin.DecodeStartVote([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+	if sv.Vote.StartHeight != 0 {
+		err = d.newScheduledVote(d.recordsdb, *sv)
+		if err != nil {
+			return "", err
+		}
+		return replyPayload, nil
+	}
+	svr, err := bitumplugin.DecodeStartVoteReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("parse end height '%v': %v",
+			svr.EndHeight, err)
+	}
+
+	s := convertStartVoteFromBitum(*sv, *svr, endHeight)
+	err = d.newStartVote(d.recordsdb, s)
+	if err != nil {
+		return "", err
+	}
+
+	return replyPayload, nil
+}
+
+// cmdStartVoteRunoff creates a StartVote record for each of the proposals in
+// a runoff vote using the passed in payloads and inserts them into the
+// database.
+func (d *bitum) cmdStartVoteRunoff(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdStartVoteRunoff")
+
+	sv, err := bitumplugin.DecodeStartVoteRunoff([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+	svr, err := bitumplugin.DecodeStartVoteRunoffReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	endHeight, err := strconv.ParseUint(svr.StartVoteReply.EndHeight, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("parse end height '%v': %v",
+			svr.StartVoteReply.EndHeight, err)
+	}
+
+	// The runoff tokens are populated by politeiad and are
+	// therefore not part of the command payload.
+	tokens := bitumplugin.RunoffTokens(*sv)
+
+	tx := d.recordsdb.Begin()
+	for _, v := range sv.StartVotes {
+		v.Vote.RunoffTokens = tokens
+		s := convertStartVoteFromBitum(v, svr.StartVoteReply, endHeight)
+		err = d.newStartVote(tx, s)
+		if err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return "", err
+	}
+
+	return replyPayload, nil
+}
+
+// cmdStartScheduledVotes replaces the ScheduledVote records of the votes that
+// were started by politeiad with StartVote records.
+func (d *bitum) cmdStartScheduledVotes(replyPayload string) (string, error) {
+	log.Tracef("bitum cmdStartScheduledVotes")
+
+	ssvr, err := bitumplugin.DecodeStartScheduledVotesReply(
+		[]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	tx := d.recordsdb.Begin()
+	for _, v := range ssvr.StartVotes {
+		token := v.StartVote.Vote.Token
+		endHeight, err := strconv.ParseUint(v.StartVoteReply.EndHeight,
+			10, 64)
+		if err != nil {
+			tx.Rollback()
+			return "", fmt.Errorf("parse end height '%v': %v",
+				v.StartVoteReply.EndHeight, err)
+		}
+
+		err = tx.Where("token = ?", token).
+			Delete(ScheduledVote{}).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return "", fmt.Errorf("delete scheduled vote %v: %v",
+				token, err)
+		}
+
+		s := convertStartVoteFromBitum(v.StartVote, v.StartVoteReply,
+			endHeight)
+		err = d.newStartVote(tx, s)
+		if err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return "", fmt.Errorf("commit transaction: %v", err)
+	}
+
+	return replyPayload, nil
+}
+
+// newCancelVote inserts a CancelVote record into the database.  This
+// function has a database parameter so that it can be called inside of a
+// transaction when required.
+func (d *bitum) newCancelVote(db *gorm.DB, cv CancelVote) error {
+	return db.Create(&cv).Error
+}
+
+// cmdCancelVote creates a CancelVote record for each of the proposals whose
+// vote was cancelled using the passed in payloads and inserts them into the
+// database.
+func (d *bitum) cmdCancelVote(cmdPayload, replyPayload string) (string, error) {
+	log.Tracef("bitum cmdCancelVote")
 
-	sv, err := bitumplugin.DecodeStartVote([]byte(cmdPayload))
+	cv, err := bitumplugin.DecodeCancelVote([]byte(cmdPayload))
 	if err != nil {
 		return "", err
 	}
-	svr, err := bitumplugin.DecodeStartVoteReply([]byte(replyPayload))
+	cvr, err := bitumplugin.DecodeCancelVoteReply([]byte(replyPayload))
 	if err != nil {
 		return "", err
 	}
 
-	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 64)
-	if err != nil {
-		return "", fmt.Errorf("parse end height '%v': %v",
-			svr.EndHeight, err)
+	// The receipt and timestamp are generated by the plugin
+	cv.Receipt = cvr.Receipt
+	cv.Timestamp = cvr.Timestamp
+
+	// Run update in a transaction
+	tx := d.recordsdb.Begin()
+	for _, v := range cvr.Tokens {
+		err = d.newCancelVote(tx, convertCancelVoteFromBitum(*cv, v))
+		if err != nil {
+			tx.Rollback()
+			return "", fmt.Errorf("newCancelVote %v: %v", v, err)
+		}
 	}
 
-	s := convertStartVoteFromBitum(*sv, *svr, endHeight)
-	err = d.newStartVote(d.recordsdb, s)
+	// Commit transaction
+	err = tx.Commit().Error
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("commit transaction: %v", err)
 	}
 
 	return replyPayload, nil
 }
 
-// cmdVoteDetails returns the AuthorizeVote and StartVote records for the
-// passed in record token.
+// cmdVoteDetails returns the AuthorizeVote, StartVote, CancelVote and
+// ScheduledVote records for the passed in record token.
 func (d *bitum) cmdVoteDetails(payload string) (string, error) {
 	log.Tracef("bitum cmdVoteDetails")
 
@@ -398,6 +1077,41 @@ func (d *bitum) cmdVoteDetails(payload string) (string, error) {
 		return "", fmt.Errorf("start vote lookup failed: %v", err)
 	}
 
+	// Lookup cancel vote
+	var cv CancelVote
+	var dcv bitumplugin.CancelVote
+	err = d.recordsdb.
+		Where("token = ?", vd.Token).
+		Find(&cv).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		// A cancel vote may not exist. This is ok.
+	} else if err != nil {
+		return "", fmt.Errorf("cancel vote lookup failed: %v", err)
+	} else {
+		dcv = convertCancelVoteToBitum(cv)
+	}
+
+	// Lookup scheduled vote
+	var ss ScheduledVote
+	var dss bitumplugin.StartVote
+	err = d.recordsdb.
+		Where("token = ?", vd.Token).
+		Find(&ss).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		// A scheduled vote may not exist. This is ok.
+	} else if err != nil {
+		return "", fmt.Errorf("scheduled vote lookup failed: %v", err)
+	} else {
+		s, err := convertScheduledVoteToBitum(ss)
+		if err != nil {
+			return "", fmt.Errorf("convertScheduledVoteToBitum: %v",
+				err)
+		}
+		dss = *s
+	}
+
 	// Prepare reply
 	dav := convertAuthorizeVoteToBitum(av)
 	dsv, dsvr := convertStartVoteToBitum(sv)
@@ -405,6 +1119,8 @@ func (d *bitum) cmdVoteDetails(payload string) (string, error) {
 		AuthorizeVote:  dav,
 		StartVote:      dsv,
 		StartVoteReply: dsvr,
+		CancelVote:     dcv,
+		ScheduledVote:  dss,
 	}
 	vdrb, err := bitumplugin.EncodeVoteDetailsReply(vdr)
 	if err != nil {
@@ -422,7 +1138,8 @@ func (d *bitum) newCastVote(db *gorm.DB, cv CastVote) error {
 }
 
 // cmdNewBallot creates CastVote records using the passed in payloads and
-// inserts them into the database.
+// inserts them into the database.  Only the votes that were accepted by
+// politeiad are inserted.  A revote replaces the previous vote of the ticket.
 func (d *bitum) cmdNewBallot(cmdPayload, replyPayload string) (string, error) {
 	log.Tracef("bitum cmdNewBallot")
 
@@ -430,11 +1147,46 @@ func (d *bitum) cmdNewBallot(cmdPayload, replyPayload string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	br, err := bitumplugin.DecodeBallotReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
 
 	// Add votes to database
 	tx := d.recordsdb.Begin()
-	for _, v := range b.Votes {
-		cv := convertCastVoteFromBitum(v)
+	revote := make(map[string]bool) // [token]allowRevote
+	for k, v := range b.Votes {
+		if k >= len(br.Receipts) || br.Receipts[k].Error != "" {
+			continue
+		}
+
+		allowRevote, ok := revote[v.Token]
+		if !ok {
+			var sv StartVote
+			err = tx.Where("token = ?", v.Token).
+				Find(&sv).
+				Error
+			if err != nil {
+				tx.Rollback()
+				return "", fmt.Errorf("lookup start vote %v: %v",
+					v.Token, err)
+			}
+			allowRevote = sv.AllowRevote
+			revote[v.Token] = allowRevote
+		}
+		if allowRevote {
+			err = tx.Where("token = ? AND ticket = ?", v.Token,
+				v.Ticket).
+				Delete(CastVote{}).
+				Error
+			if err != nil {
+				tx.Rollback()
+				return "", fmt.Errorf("delete cast vote: %v", err)
+			}
+		}
+
+		cv := convertCastVoteFromBitum(v, br.Receipts[k].Signature,
+			br.Receipts[k].BlockHeight)
 		err = d.newCastVote(tx, cv)
 		if err != nil {
 			tx.Rollback()
@@ -450,6 +1202,104 @@ func (d *bitum) cmdNewBallot(cmdPayload, replyPayload string) (string, error) {
 	return replyPayload, nil
 }
 
+// cmdVoteReceipt returns the CastVote record of the passed in ticket along
+// with the receipt that politeiad returned when the vote was cast.
+func (d *bitum) cmdVoteReceipt(payload string) (string, error) {
+	log.Tracef("bitum cmdVoteReceipt")
+
+	vr, err := bitumplugin.DecodeVoteReceipt([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	var cv CastVote
+	err = d.recordsdb.
+		Where("token = ? AND ticket = ?", vr.Token, vr.Ticket).
+		Find(&cv).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = cache.ErrRecordNotFound
+		}
+		return "", err
+	}
+
+	vrr := bitumplugin.VoteReceiptReply{
+		CastVote: convertCastVoteToBitum(cv),
+		Receipt:  cv.Receipt,
+	}
+	vrrb, err := bitumplugin.EncodeVoteReceiptReply(vrr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(vrrb), nil
+}
+
+// cmdVoteTimeSeries returns the StartVote record for the passed in record
+// token along with the number of votes that each vote bit received at every
+// block height.
+func (d *bitum) cmdVoteTimeSeries(payload string) (string, error) {
+	log.Tracef("bitum cmdVoteTimeSeries")
+
+	vts, err := bitumplugin.DecodeVoteTimeSeries([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	// Lookup start vote
+	var sv StartVote
+	err = d.recordsdb.
+		Where("token = ?", vts.Token).
+		Preload("Options").
+		Find(&sv).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = cache.ErrRecordNotFound
+		}
+		return "", err
+	}
+
+	// Count the votes of each vote bit per block height
+	q := `SELECT block_height, vote_bit, COUNT(*)
+        FROM cast_votes
+        WHERE token = ?
+        GROUP BY block_height, vote_bit
+        ORDER BY block_height ASC`
+	rows, err := d.recordsdb.Raw(q, vts.Token).Rows()
+	if err != nil {
+		return "", fmt.Errorf("cast votes: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make([]bitumplugin.VoteBitCount, 0, 1024)
+	for rows.Next() {
+		var c bitumplugin.VoteBitCount
+		err = rows.Scan(&c.BlockHeight, &c.VoteBit, &c.Votes)
+		if err != nil {
+			return "", err
+		}
+		counts = append(counts, c)
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	dsv, dsvr := convertStartVoteToBitum(sv)
+	vtsr, err := bitumplugin.EncodeVoteTimeSeriesReply(
+		bitumplugin.VoteTimeSeriesReply{
+			StartVote:      dsv,
+			StartVoteReply: dsvr,
+			Counts:         counts,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(vtsr), nil
+}
+
 // cmdProposalVotes returns the StartVote record and all CastVote records for
 // the passed in record token.
 func (d *bitum) cmdProposalVotes(payload string) (string, error) {
@@ -576,54 +1426,42 @@ func (d *bitum) newVoteResults(token string) error {
 	}
 
 	// Create vote option results
+	voteType := bitumplugin.VoteT(sv.Type)
 	results := make([]VoteOptionResult, 0, len(sv.Options))
 	for _, v := range sv.Options {
 		voteBit := strconv.FormatUint(v.Bits, 16)
-		voteCount := tally[voteBit]
-
 		results = append(results, VoteOptionResult{
 			Key:    token + voteBit,
-			Votes:  voteCount,
+			Votes:  optionVotes(voteType, v.Bits, tally),
 			Option: v,
 		})
 	}
 
-	// Check whether vote was approved
-	var total uint64
-	for _, v := range results {
-		total += v.Votes
-	}
-
-	eligible := len(strings.Split(sv.EligibleTickets, ","))
-	quorum := uint64(float64(sv.QuorumPercentage) / 100 * float64(eligible))
-	pass := uint64(float64(sv.PassPercentage) / 100 * float64(total))
-
-	// XXX: this only supports proposals with yes/no
-	// voting options. Multiple voting option support
-	// will need to be added in the future.
-	var approvedVotes uint64
-	for _, v := range results {
-		if v.Option.ID == voteOptionIDApproved {
-			approvedVotes = v.Votes
-		}
-	}
-
+	// Check whether the vote was approved using the tally
+	// rules of the vote type. A runoff vote is only approved
+	// for the proposal that won the runoff.
 	var approved bool
-	switch {
-	case total < quorum:
-		// Quorum not met
-	case approvedVotes < pass:
-		// Pass percentage not met
+	total := uint64(len(cv))
+	switch voteType {
+	case bitumplugin.VoteTypeRunoff:
+		winner, err := d.runoffWinner(sv)
+		if err != nil {
+			return err
+		}
+		approved = winner == token
 	default:
-		// Vote was approved
-		approved = true
+		winner := bitumplugin.VoteWinner(voteType, sv.QuorumPercentage,
+			sv.PassPercentage, sv.EligibleTicketCount, total,
+			convertVoteOptionResultsToBitum(results))
+		approved = winner != ""
 	}
 
 	// Create a vote results entry
 	err = d.recordsdb.Create(&VoteResults{
-		Token:    token,
-		Approved: approved,
-		Results:  results,
+		Token:      token,
+		Approved:   approved,
+		TotalVotes: total,
+		Results:    results,
 	}).Error
 	if err != nil {
 		return fmt.Errorf("new vote results: %v", err)
@@ -632,6 +1470,109 @@ func (d *bitum) newVoteResults(token string) error {
 	return nil
 }
 
+// optionVotes returns the number of votes that a vote option received given
+// the number of cast votes for each hex encoded vote bit.
+func optionVotes(t bitumplugin.VoteT, optionBits uint64, tally map[string]uint64) uint64 {
+	if t != bitumplugin.VoteTypeApproval {
+		return tally[strconv.FormatUint(optionBits, 16)]
+	}
+
+	// An approval vote counts towards every option that it selects
+	var votes uint64
+	for voteBit, count := range tally {
+		b, err := strconv.ParseUint(voteBit, 16, 64)
+		if err != nil {
+			log.Errorf("optionVotes: invalid vote bit %v: %v",
+				voteBit, err)
+			continue
+		}
+		if bitumplugin.VoteOptionSelected(t, b, optionBits) {
+			votes += count
+		}
+	}
+	return votes
+}
+
+// voteOptionResults counts the cast votes of the passed in start vote and
+// returns the number of votes that each vote option received along with the
+// number of tickets that voted.  The cast votes are counted per vote bit and
+// then attributed to the vote options.
+func (d *bitum) voteOptionResults(sv StartVote) ([]bitumplugin.VoteOptionResult, uint64, error) {
+	rows, err := d.recordsdb.
+		Model(&CastVote{}).
+		Select("vote_bit, count(*)").
+		Where("token = ?", sv.Token).
+		Group("vote_bit").
+		Rows()
+	if err != nil {
+		return nil, 0, fmt.Errorf("count cast votes: %v", err)
+	}
+	defer rows.Close()
+
+	var total uint64
+	tally := make(map[string]uint64) // [voteBit]voteCount
+	for rows.Next() {
+		var (
+			voteBit string
+			count   uint64
+		)
+		err = rows.Scan(&voteBit, &count)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan cast votes: %v", err)
+		}
+		tally[voteBit] = count
+		total += count
+	}
+
+	results := make([]bitumplugin.VoteOptionResult, 0, len(sv.Options))
+	for _, v := range sv.Options {
+		results = append(results,
+			bitumplugin.VoteOptionResult{
+				ID:          v.ID,
+				Description: v.Description,
+				Bits:        v.Bits,
+				Votes: optionVotes(bitumplugin.VoteT(sv.Type),
+					v.Bits, tally),
+			})
+	}
+
+	return results, total, nil
+}
+
+// runoffWinner returns the token of the proposal that won the runoff vote
+// that the passed in start vote is part of.
+func (d *bitum) runoffWinner(sv StartVote) (string, error) {
+	results := make(map[string][]bitumplugin.VoteOptionResult)
+	for _, token := range splitTokens(sv.RunoffTokens) {
+		var rsv StartVote
+		err := d.recordsdb.
+			Where("token = ?", token).
+			Preload("Options").
+			Find(&rsv).
+			Error
+		if err != nil {
+			return "", fmt.Errorf("lookup runoff start vote %v: %v",
+				token, err)
+		}
+		r, _, err := d.voteOptionResults(rsv)
+		if err != nil {
+			return "", err
+		}
+		results[token] = r
+	}
+
+	return bitumplugin.RunoffWinner(sv.QuorumPercentage, sv.PassPercentage,
+		sv.EligibleTicketCount, results), nil
+}
+
+// splitTokens splits a comma separated list of tokens.
+func splitTokens(tokens string) []string {
+	if tokens == "" {
+		return nil
+	}
+	return strings.Split(tokens, ",")
+}
+
 // cmdLoadVoteResults creates vote results entries for any proposals that have
 // a finished voting period but have not yet been added to the vote results
 // table. The vote results table is lazy loaded.
@@ -723,17 +1664,21 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
 
 	// Pre voting period tokens. This query returns the
 	// tokens of the most recent version of all records that
-	// are public and do not have an associated StartVote
-	// record, ordered by timestamp in descending order.
+	// are public and do not have an associated StartVote or
+	// ScheduledVote record, ordered by timestamp in descending
+	// order.
 	q = `SELECT a.token
         FROM records a
         LEFT OUTER JOIN start_votes
           ON a.token = start_votes.token
+        LEFT OUTER JOIN scheduled_votes
+          ON a.token = scheduled_votes.token
         LEFT OUTER JOIN records b
           ON a.token = b.token
           AND a.version < b.version
         WHERE b.token IS NULL
           AND start_votes.token IS NULL
+          AND scheduled_votes.token IS NULL
           AND a.status = ?
         ORDER BY a.timestamp DESC`
 	rows, err = d.recordsdb.Raw(q, pd.RecordStatusPublic).Rows()
@@ -748,11 +1693,15 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
 		pre = append(pre, token)
 	}
 
-	// Active voting period tokens
-	q = `SELECT token
+	// Active voting period tokens. Cancelled votes are not
+	// active.
+	q = `SELECT start_votes.token
        FROM start_votes
-       WHERE end_height > ?
-       ORDER BY end_height DESC`
+       LEFT OUTER JOIN cancel_votes
+         ON start_votes.token = cancel_votes.token
+       WHERE start_votes.end_height > ?
+         AND cancel_votes.token IS NULL
+       ORDER BY start_votes.end_height DESC`
 	rows, err = d.recordsdb.Raw(q, ti.BestBlock).Rows()
 	if err != nil {
 		return "", fmt.Errorf("active: %v", err)
@@ -765,12 +1714,16 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
 		active = append(active, token)
 	}
 
-	// Approved vote tokens
+	// Approved vote tokens. Cancelled votes are neither
+	// approved nor rejected.
 	q = `SELECT vote_results.token
        FROM vote_results
        INNER JOIN start_votes
          ON vote_results.token = start_votes.token
+       LEFT OUTER JOIN cancel_votes
+         ON vote_results.token = cancel_votes.token
          WHERE vote_results.approved = true
+         AND cancel_votes.token IS NULL
        ORDER BY start_votes.end_height DESC`
 	rows, err = d.recordsdb.Raw(q).Rows()
 	if err != nil {
@@ -789,7 +1742,10 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
        FROM vote_results
        INNER JOIN start_votes
          ON vote_results.token = start_votes.token
+       LEFT OUTER JOIN cancel_votes
+         ON vote_results.token = cancel_votes.token
          WHERE vote_results.approved = false
+         AND cancel_votes.token IS NULL
        ORDER BY start_votes.end_height DESC`
 	rows, err = d.recordsdb.Raw(q).Rows()
 	if err != nil {
@@ -820,6 +1776,38 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
 		abandoned = append(abandoned, token)
 	}
 
+	// Cancelled vote tokens
+	q = `SELECT token
+       FROM cancel_votes
+       ORDER BY timestamp DESC`
+	rows, err = d.recordsdb.Raw(q).Rows()
+	if err != nil {
+		return "", fmt.Errorf("cancelled: %v", err)
+	}
+	defer rows.Close()
+
+	cancelled := make([]string, 0, 1024)
+	for rows.Next() {
+		rows.Scan(&token)
+		cancelled = append(cancelled, token)
+	}
+
+	// Scheduled vote tokens, ordered by start height
+	q = `SELECT token
+       FROM scheduled_votes
+       ORDER BY start_height ASC`
+	rows, err = d.recordsdb.Raw(q).Rows()
+	if err != nil {
+		return "", fmt.Errorf("scheduled: %v", err)
+	}
+	defer rows.Close()
+
+	scheduled := make([]string, 0, 1024)
+	for rows.Next() {
+		rows.Scan(&token)
+		scheduled = append(scheduled, token)
+	}
+
 	// Prepare reply
 	reply, err := bitumplugin.EncodeTokenInventoryReply(
 		bitumplugin.TokenInventoryReply{
@@ -828,6 +1816,8 @@ func (d *bitum) cmdTokenInventory(payload string) (string, error) {
 			Approved:  approved,
 			Rejected:  rejected,
 			Abandoned: abandoned,
+			Cancelled: cancelled,
+			Scheduled: scheduled,
 		})
 	if err != nil {
 		return "", err
@@ -862,9 +1852,12 @@ func (d *bitum) cmdVoteSummary(payload string) (string, error) {
 	// Declare here to prevent goto errors
 	results := make([]bitumplugin.VoteOptionResult, 0, 16)
 	var (
-		av AuthorizeVote
-		sv StartVote
-		vr VoteResults
+		av    AuthorizeVote
+		sv    StartVote
+		cv    CancelVote
+		ss    ScheduledVote
+		vr    VoteResults
+		total uint64
 	)
 
 	// Lookup authorize vote
@@ -888,13 +1881,30 @@ func (d *bitum) cmdVoteSummary(payload string) (string, error) {
 		Find(&sv).
 		Error
 	if err == gorm.ErrRecordNotFound {
-		// If an start vote doesn't exist then
-		// there is no need to continue.
+		// If an start vote doesn't exist then the vote
+		// may have been scheduled. There is no need to
+		// continue either way.
+		err = d.recordsdb.
+			Where("token = ?", vs.Token).
+			Find(&ss).
+			Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("lookup scheduled vote: %v", err)
+		}
 		goto sendReply
 	} else if err != nil {
 		return "", fmt.Errorf("lookup start vote: %v", err)
 	}
 
+	// Lookup cancel vote
+	err = d.recordsdb.
+		Where("token = ?", vs.Token).
+		Find(&cv).
+		Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("lookup cancel vote: %v", err)
+	}
+
 	// Lookup vote results
 	err = d.recordsdb.
 		Where("token = ?", vs.Token).
@@ -912,31 +1922,15 @@ func (d *bitum) cmdVoteSummary(payload string) (string, error) {
 	} else {
 		// Vote results record exists. We have all of the data
 		// that we need to send the reply.
-		vor := convertVoteOptionResultsToBitum(vr.Results)
-		results = append(results, vor...)
+		results = convertVoteOptionResultsToBitum(vr.Results)
+		total = vr.TotalVotes
 		goto sendReply
 	}
 
 	// Lookup vote results manually
-	for _, v := range sv.Options {
-		var votes uint64
-		tokenVoteBit := v.Token + strconv.FormatUint(v.Bits, 16)
-		err := d.recordsdb.
-			Model(&CastVote{}).
-			Where("token_vote_bit = ?", tokenVoteBit).
-			Count(&votes).
-			Error
-		if err != nil {
-			return "", fmt.Errorf("count cast votes: %v", err)
-		}
-
-		results = append(results,
-			bitumplugin.VoteOptionResult{
-				ID:          v.ID,
-				Description: v.Description,
-				Bits:        v.Bits,
-				Votes:       votes,
-			})
+	results, total, err = d.voteOptionResults(sv)
+	if err != nil {
+		return "", err
 	}
 
 sendReply:
@@ -948,11 +1942,29 @@ sendReply:
 
 	vsr := bitumplugin.VoteSummaryReply{
 		Authorized:          (av.Action == bitumplugin.AuthVoteActionAuthorize),
+		Type:                bitumplugin.VoteT(sv.Type),
 		EndHeight:           endHeight,
 		EligibleTicketCount: sv.EligibleTicketCount,
 		QuorumPercentage:    sv.QuorumPercentage,
 		PassPercentage:      sv.PassPercentage,
+		TotalVotes:          total,
 		Results:             results,
+		RunoffTokens:        splitTokens(sv.RunoffTokens),
+		Cancelled:           cv.Token != "",
+		CancelReason:        cv.Reason,
+		Scheduled:           ss.Token != "",
+		StartHeight:         ss.StartHeight,
+	}
+	if vsr.Scheduled {
+		// Return the parameters of the scheduled vote
+		s, err := convertScheduledVoteToBitum(ss)
+		if err != nil {
+			return "", fmt.Errorf("convertScheduledVoteToBitum: %v",
+				err)
+		}
+		vsr.Type = s.Vote.Type
+		vsr.QuorumPercentage = s.Vote.QuorumPercentage
+		vsr.PassPercentage = s.Vote.PassPercentage
 	}
 	reply, err := bitumplugin.EncodeVoteSummaryReply(vsr)
 	if err != nil {
@@ -974,22 +1986,60 @@ func (d *bitum) Exec(cmd, cmdPayload, replyPayload string) (string, error) {
 		return d.cmdAuthorizeVote(cmdPayload, replyPayload)
 	case bitumplugin.CmdStartVote:
 		return d.cmdStartVote(cmdPayload, replyPayload)
+	case bitumplugin.CmdStartVoteRunoff:
+		return d.cmdStartVoteRunoff(cmdPayload, replyPayload)
 	case bitumplugin.CmdVoteDetails:
 		return d.cmdVoteDetails(cmdPayload)
 	case bitumplugin.CmdBallot:
 		return d.cmdNewBallot(cmdPayload, replyPayload)
 	case bitumplugin.CmdBestBlock:
 		return "", nil
+	case bitumplugin.CmdVoteResultsBundle:
+		return "", nil
+	case bitumplugin.CmdCancelVote:
+		return d.cmdCancelVote(cmdPayload, replyPayload)
+	case bitumplugin.CmdStartScheduledVotes:
+		return d.cmdStartScheduledVotes(replyPayload)
 	case bitumplugin.CmdNewComment:
 		return d.cmdNewComment(cmdPayload, replyPayload)
 	case bitumplugin.CmdLikeComment:
 		return d.cmdLikeComment(cmdPayload, replyPayload)
 	case bitumplugin.CmdCensorComment:
 		return d.cmdCensorComment(cmdPayload, replyPayload)
+	case bitumplugin.CmdEditComment:
+		return d.cmdEditComment(cmdPayload, replyPayload)
+	case bitumplugin.CmdDeleteComment:
+		return d.cmdDeleteComment(cmdPayload, replyPayload)
+	case bitumplugin.CmdCommentRevisions:
+		return "", nil
+	case bitumplugin.CmdFlagComment:
+		return d.cmdFlagComment(cmdPayload, replyPayload)
+	case bitumplugin.CmdDismissCommentFlags:
+		return d.cmdDismissCommentFlags(cmdPayload, replyPayload)
+	case bitumplugin.CmdCommentFlags:
+		return d.cmdCommentFlags(cmdPayload)
+	case bitumplugin.CmdFlaggedComments:
+		return d.cmdFlaggedComments()
+	case bitumplugin.CmdAppealCensorship:
+		return "", nil
+	case bitumplugin.CmdResolveAppeal:
+		return d.cmdResolveAppeal(cmdPayload, replyPayload)
+	case bitumplugin.CmdCensorshipAppeals:
+		return "", nil
+	case bitumplugin.CmdJournalProof:
+		return "", nil
 	case bitumplugin.CmdGetComment:
 		return d.cmdGetComment(cmdPayload)
 	case bitumplugin.CmdGetComments:
 		return d.cmdGetComments(cmdPayload)
+	case bitumplugin.CmdUserComments:
+		return d.cmdUserComments(cmdPayload)
+	case bitumplugin.CmdUnreadComments:
+		return d.cmdUnreadComments(cmdPayload)
+	case bitumplugin.CmdVoteReceipt:
+		return d.cmdVoteReceipt(cmdPayload)
+	case bitumplugin.CmdVoteTimeSeries:
+		return d.cmdVoteTimeSeries(cmdPayload)
 	case bitumplugin.CmdProposalVotes:
 		return d.cmdProposalVotes(cmdPayload)
 	case bitumplugin.CmdCommentLikes:
@@ -1030,6 +2080,12 @@ func (d *bitum) createTables(tx *gorm.DB) error {
 			return err
 		}
 	}
+	if !tx.HasTable(tableCommentFlags) {
+		err := tx.CreateTable(&CommentFlag{}).Error
+		if err != nil {
+			return err
+		}
+	}
 	if !tx.HasTable(tableCastVotes) {
 		err := tx.CreateTable(&CastVote{}).Error
 		if err != nil {
@@ -1066,6 +2122,18 @@ func (d *bitum) createTables(tx *gorm.DB) error {
 			return err
 		}
 	}
+	if !tx.HasTable(tableCancelVotes) {
+		err := tx.CreateTable(&CancelVote{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableScheduledVotes) {
+		err := tx.CreateTable(&ScheduledVote{}).Error
+		if err != nil {
+			return err
+		}
+	}
 
 	// Check if a bitum version record exists. Insert one
 	// if no version record is found.
@@ -1095,8 +2163,9 @@ func (d *bitum) createTables(tx *gorm.DB) error {
 func (d *bitum) dropTables(tx *gorm.DB) error {
 	// Drop bitum plugin tables
 	err := tx.DropTableIfExists(tableComments, tableCommentLikes,
-		tableCastVotes, tableAuthorizeVotes, tableVoteOptions,
-		tableStartVotes, tableVoteOptionResults, tableVoteResults).
+		tableCommentFlags, tableCastVotes, tableAuthorizeVotes, tableVoteOptions,
+		tableStartVotes, tableVoteOptionResults, tableVoteResults,
+		tableCancelVotes, tableScheduledVotes).
 		Error
 	if err != nil {
 		return err
@@ -1139,8 +2208,20 @@ func (d *bitum) build(ir *bitumplugin.InventoryReply) error {
 		return err
 	}
 
-	// Build comments cache
+	// Build comments cache. Comments must be inserted after their
+	// parent comments so they are sorted by comment ID, which is
+	// sequential for each record.
 	log.Tracef("bitum: building comments cache")
+	sort.SliceStable(ir.Comments, func(i, j int) bool {
+		ci, cj := ir.Comments[i], ir.Comments[j]
+		if ci.Token != cj.Token {
+			return ci.Token < cj.Token
+		}
+		if len(ci.CommentID) != len(cj.CommentID) {
+			return len(ci.CommentID) < len(cj.CommentID)
+		}
+		return ci.CommentID < cj.CommentID
+	})
 	for _, v := range ir.Comments {
 		c := convertCommentFromBitum(v)
 		err := d.newComment(d.recordsdb, c)
@@ -1152,6 +2233,7 @@ func (d *bitum) build(ir *bitumplugin.InventoryReply) error {
 
 	// Build like comments cache
 	log.Tracef("bitum: building like comments cache")
+	likes := make(map[string][]LikeComment) // [token+commentID]likes
 	for _, v := range ir.LikeComments {
 		lc := convertLikeCommentFromBitum(v)
 		err := d.newLikeComment(d.recordsdb, lc)
@@ -1159,6 +2241,35 @@ func (d *bitum) build(ir *bitumplugin.InventoryReply) error {
 			log.Debugf("newLikeComment failed on '%v'", lc)
 			return fmt.Errorf("newLikeComment: %v", err)
 		}
+		likes[lc.Token+lc.CommentID] = append(likes[lc.Token+lc.CommentID], lc)
+	}
+
+	// Tally comment scores
+	for k, v := range likes {
+		score, err := commentScore(v)
+		if err != nil {
+			return fmt.Errorf("commentScore: %v", err)
+		}
+		err = d.recordsdb.Model(&Comment{Key: k}).
+			Update("result_votes", score).Error
+		if err != nil {
+			return fmt.Errorf("update comment score %v: %v", k, err)
+		}
+	}
+
+	// Build comment flags cache.  Flags are inserted in
+	// chronological order.
+	log.Tracef("bitum: building comment flags cache")
+	sort.SliceStable(ir.CommentFlags, func(i, j int) bool {
+		return ir.CommentFlags[i].Timestamp < ir.CommentFlags[j].Timestamp
+	})
+	for _, v := range ir.CommentFlags {
+		cf := convertCommentFlagFromBitum(v)
+		err := d.recordsdb.Create(&cf).Error
+		if err != nil {
+			log.Debugf("create comment flag failed on '%v'", cf)
+			return fmt.Errorf("create comment flag: %v", err)
+		}
 	}
 
 	// Put authorize vote replies in a map for quick lookups
@@ -1211,10 +2322,52 @@ func (d *bitum) build(ir *bitumplugin.InventoryReply) error {
 		}
 	}
 
+	// Build scheduled vote cache
+	log.Tracef("bitum: building scheduled vote cache")
+	for _, v := range ir.ScheduledVotes {
+		err := d.newScheduledVote(d.recordsdb, v)
+		if err != nil {
+			log.Debugf("newScheduledVote failed on '%v'", v)
+			return fmt.Errorf("newScheduledVote: %v", err)
+		}
+	}
+
+	// Build cancel vote cache. The cancellation of a runoff vote
+	// applies to every proposal in the runoff.
+	log.Tracef("bitum: building cancel vote cache")
+	runoffs := make(map[string][]string, len(ir.StartVoteTuples))
+	for _, v := range ir.StartVoteTuples {
+		runoffs[v.StartVote.Vote.Token] = v.StartVote.Vote.RunoffTokens
+	}
+	for _, v := range ir.CancelVotes {
+		tokens := runoffs[v.Token]
+		if len(tokens) == 0 {
+			tokens = []string{v.Token}
+		}
+		for _, t := range tokens {
+			cv := convertCancelVoteFromBitum(v, t)
+			err := d.newCancelVote(d.recordsdb, cv)
+			if err != nil {
+				log.Debugf("newCancelVote failed on '%v'", cv)
+				return fmt.Errorf("newCancelVote: %v", err)
+			}
+		}
+	}
+
 	// Build cast vote cache
 	log.Tracef("bitum: building cast vote cache")
-	for _, v := range ir.CastVotes {
-		cv := convertCastVoteFromBitum(v)
+	for k, v := range ir.CastVotes {
+		var (
+			receipt string
+			height  uint32
+		)
+		if k < len(ir.CastVoteReceipts) {
+			receipt = ir.CastVoteReceipts[k]
+		}
+		if k < len(ir.CastVoteHeights) {
+			height = ir.CastVoteHeights[k]
+		}
+		cv := convertCastVoteFromBitum(v, receipt, height)
 		err := d.newCastVote(d.recordsdb, cv)
 		if err != nil {
 			log.Debugf("newCastVote failed on '%v'", cv)
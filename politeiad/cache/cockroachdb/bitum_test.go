@@ -0,0 +1,65 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cockroachdb
+
+import "testing"
+
+func TestCommentScore(t *testing.T) {
+	like := func(userID, publicKey, action string) LikeComment {
+		return LikeComment{
+			CommentID: "1",
+			Action:    action,
+			PublicKey: publicKey,
+			UserID:    userID,
+		}
+	}
+
+	var tests = []struct {
+		name  string
+		likes []LikeComment
+		want  int64
+	}{
+		{"no likes", []LikeComment{}, 0},
+		{"upvote", []LikeComment{like("a", "k1", "1")}, 1},
+		{"upvote removed", []LikeComment{
+			like("a", "k1", "1"),
+			like("a", "k1", "1"),
+		}, 0},
+		{"upvote replaced", []LikeComment{
+			like("a", "k1", "1"),
+			like("a", "k1", "-1"),
+		}, -1},
+		{"several users", []LikeComment{
+			like("a", "k1", "1"),
+			like("b", "k2", "1"),
+			like("c", "k3", "-1"),
+		}, 1},
+		{"user with new key", []LikeComment{
+			like("a", "k1", "1"),
+			like("a", "k2", "1"),
+		}, 0},
+		{"no user id", []LikeComment{
+			like("", "k1", "1"),
+			like("", "k2", "1"),
+			like("", "k1", "-1"),
+		}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := commentScore(test.likes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	_, err := commentScore([]LikeComment{like("a", "k1", "up")})
+	if err == nil {
+		t.Fatalf("expected error for invalid action")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -487,6 +488,280 @@ func (c *cockroachdb) InventoryStats() (*cache.InventoryStats, error) {
 	return &is, nil
 }
 
+// recordQuery returns a gorm query that selects the latest version of all
+// records that match the filtering parameters of the passed in RecordQuery.
+// Pagination and sorting are not applied.  The latest version of each record
+// is aliased as "a" in the returned query.
+func (c *cockroachdb) recordQuery(q cache.RecordQuery) (*gorm.DB, error) {
+	// Select the latest version of each record
+	db := c.recordsdb.
+		Table(tableRecords + " a").
+		Joins("LEFT OUTER JOIN " + tableRecords + " b " +
+			"ON a.token = b.token AND a.version < b.version").
+		Where("b.token IS NULL")
+
+	if len(q.Statuses) > 0 {
+		statuses := make([]int, 0, len(q.Statuses))
+		for _, v := range q.Statuses {
+			statuses = append(statuses, int(v))
+		}
+		db = db.Where("a.status IN (?)", statuses)
+	}
+
+	for i, v := range q.Metadata {
+		// Each metadata filter joins its own copy of the
+		// metadata streams table.
+		alias := fmt.Sprintf("m%v", i)
+		db = db.Joins("INNER JOIN "+tableMetadataStreams+" "+alias+" "+
+			"ON "+alias+".record_key = a.key AND "+alias+".id = ?",
+			v.StreamID).
+			Where(alias+".payload::JSONB->>(?::STRING) IN (?)",
+				v.Field, v.Values)
+	}
+
+	if q.TimestampAfter != 0 {
+		db = db.Where("a.timestamp > ?", q.TimestampAfter)
+	}
+	if q.TimestampBefore != 0 {
+		db = db.Where("a.timestamp < ?", q.TimestampBefore)
+	}
+
+	if len(q.VoteStatuses) > 0 {
+		// Vote statuses are derived from the bitum plugin tables
+		c.RLock()
+		_, ok := c.plugins[bitumplugin.ID]
+		c.RUnlock()
+		if !ok {
+			return nil, cache.ErrInvalidPlugin
+		}
+
+		// The authorize vote applies to a single record version
+		// while the remaining vote records apply to the token.
+		db = db.
+			Joins("LEFT OUTER JOIN " + tableAuthorizeVotes + " av " +
+				"ON a.key = av.key").
+			Joins("LEFT OUTER JOIN " + tableStartVotes + " sv " +
+				"ON a.token = sv.token").
+			Joins("LEFT OUTER JOIN " + tableScheduledVotes + " ss " +
+				"ON a.token = ss.token").
+			Joins("LEFT OUTER JOIN " + tableCancelVotes + " cv " +
+				"ON a.token = cv.token")
+
+		authorize := bitumplugin.AuthVoteActionAuthorize
+		conds := make([]string, 0, len(q.VoteStatuses))
+		args := make([]interface{}, 0, 2*len(q.VoteStatuses))
+		for _, v := range q.VoteStatuses {
+			switch v {
+			case cache.VoteStatusNotAuthorized:
+				conds = append(conds, "(cv.token IS NULL AND "+
+					"(av.key IS NULL OR av.action != ?))")
+				args = append(args, authorize)
+			case cache.VoteStatusAuthorized:
+				conds = append(conds, "(cv.token IS NULL AND "+
+					"av.action = ? AND ss.token IS NULL AND "+
+					"sv.token IS NULL)")
+				args = append(args, authorize)
+			case cache.VoteStatusScheduled:
+				conds = append(conds, "(cv.token IS NULL AND "+
+					"av.action = ? AND ss.start_height > ?)")
+				args = append(args, authorize, q.BestBlock)
+			case cache.VoteStatusStarted:
+				conds = append(conds, "(cv.token IS NULL AND "+
+					"av.action = ? AND (ss.start_height <= ? OR "+
+					"(ss.token IS NULL AND sv.end_height > ?)))")
+				args = append(args, authorize, q.BestBlock,
+					q.BestBlock)
+			case cache.VoteStatusFinished:
+				conds = append(conds, "(cv.token IS NULL AND "+
+					"av.action = ? AND ss.token IS NULL AND "+
+					"sv.end_height <= ?)")
+				args = append(args, authorize, q.BestBlock)
+			case cache.VoteStatusCancelled:
+				conds = append(conds, "cv.token IS NOT NULL")
+			default:
+				return nil, fmt.Errorf("invalid vote status %v", v)
+			}
+		}
+		db = db.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+
+	return db, nil
+}
+
+// Query returns a page of the latest version of the records that match the
+// passed in RecordQuery.  The filtering, sorting, and pagination are all
+// performed by the database.
+func (c *cockroachdb) Query(q cache.RecordQuery) ([]cache.Record, error) {
+	log.Tracef("Query")
+
+	c.RLock()
+	shutdown := c.shutdown
+	c.RUnlock()
+
+	if shutdown {
+		return nil, cache.ErrShutdown
+	}
+
+	if q.After != "" && q.Before != "" {
+		return nil, fmt.Errorf("after and before cursors cannot " +
+			"be used together")
+	}
+
+	db, err := c.recordQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are listed by timestamp and then by token. The
+	// newest sort order lists newer timestamps first and breaks
+	// ties using ascending token order. The oldest sort order is
+	// the exact reverse. A before cursor requires walking the
+	// listing backwards from the cursor, so the order is reversed
+	// for the query and the results are reversed afterwards.
+	newest := q.Sort != cache.SortOrderOldest
+	reverse := q.Before != ""
+	if reverse {
+		newest = !newest
+	}
+
+	cursor := q.After
+	if reverse {
+		cursor = q.Before
+	}
+	if cursor != "" {
+		r, err := record(c.recordsdb, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if newest {
+			db = db.Where("(a.timestamp < ? OR "+
+				"(a.timestamp = ? AND a.token > ?))",
+				r.Timestamp, r.Timestamp, r.Token)
+		} else {
+			db = db.Where("(a.timestamp > ? OR "+
+				"(a.timestamp = ? AND a.token < ?))",
+				r.Timestamp, r.Timestamp, r.Token)
+		}
+	}
+
+	if newest {
+		db = db.Order("a.timestamp DESC, a.token ASC")
+	} else {
+		db = db.Order("a.timestamp ASC, a.token DESC")
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+
+	rows, err := db.Select("a.key").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 1024) // PNOOMA
+	for rows.Next() {
+		var key string
+		err := rows.Scan(&key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return []cache.Record{}, nil
+	}
+	if reverse {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	// Lookup the full records and return them in the order
+	// specified by the query.
+	records := make([]Record, 0, len(keys))
+	err = c.recordsdb.
+		Preload("Files").
+		Preload("Metadata").
+		Where(keys).
+		Find(&records).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]Record, len(records)) // [key]Record
+	for _, r := range records {
+		byKey[r.Key] = r
+	}
+
+	cr := make([]cache.Record, 0, len(keys))
+	for _, k := range keys {
+		r, ok := byKey[k]
+		if !ok {
+			// This should not happen
+			return nil, fmt.Errorf("record not found %v", k)
+		}
+		cr = append(cr, convertRecordToCache(r))
+	}
+
+	return cr, nil
+}
+
+// QueryStats compiles summary statistics on the number of records grouped by
+// record status for all records that match the passed in RecordQuery.  The
+// pagination and sort fields of the query are ignored.
+func (c *cockroachdb) QueryStats(q cache.RecordQuery) (*cache.InventoryStats, error) {
+	log.Tracef("QueryStats")
+
+	c.RLock()
+	shutdown := c.shutdown
+	c.RUnlock()
+
+	if shutdown {
+		return nil, cache.ErrShutdown
+	}
+
+	db, err := c.recordQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.
+		Select("a.status, COUNT(*)").
+		Group("a.status").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var is cache.InventoryStats
+	for rows.Next() {
+		var status, count int
+		err := rows.Scan(&status, &count)
+		if err != nil {
+			return nil, err
+		}
+		switch cache.RecordStatusT(status) {
+		case cache.RecordStatusNotReviewed:
+			is.NotReviewed += count
+		case cache.RecordStatusCensored:
+			is.Censored += count
+		case cache.RecordStatusPublic:
+			is.Public += count
+		case cache.RecordStatusUnreviewedChanges:
+			is.UnreviewedChanges += count
+		case cache.RecordStatusArchived:
+			is.Archived += count
+		default:
+			is.Invalid += count
+		}
+	}
+
+	return &is, nil
+}
+
 func (c *cockroachdb) getPlugin(id string) (cache.PluginDriver, error) {
 	c.Lock()
 	defer c.Unlock()
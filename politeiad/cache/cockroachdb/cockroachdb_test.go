@@ -0,0 +1,77 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cockroachdb
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/cache"
+	"github.com/bitum-project/politeia/politeiad/cache/cachetest"
+	"github.com/bitum-project/politeia/util"
+)
+
+// TestCockroachdb runs the shared cache test suite against a running
+// CockroachDB instance.  The test is skipped unless the CACHE_HOST,
+// CACHE_ROOTCERT, CACHE_CERT and CACHE_KEY environment variables are set.
+// The client certificate must belong to the root user since the suite is run
+// against a throwaway database that is created and dropped by the test.
+func TestCockroachdb(t *testing.T) {
+	host := os.Getenv("CACHE_HOST")
+	rootCert := os.Getenv("CACHE_ROOTCERT")
+	cert := os.Getenv("CACHE_CERT")
+	key := os.Getenv("CACHE_KEY")
+	if host == "" || rootCert == "" || cert == "" || key == "" {
+		t.Skip("cockroachdb connection not configured")
+	}
+
+	b, err := util.Random(8)
+	if err != nil {
+		t.Fatalf("Random: %v", err)
+	}
+	net := "test" + hex.EncodeToString(b)
+
+	// The database does not exist yet so the version record will
+	// not be found.
+	c, err := New("root", host, net, rootCert, cert, key)
+	if err != nil && err != cache.ErrNoVersionRecord {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	dbName := cacheID + "_" + net
+	err = c.recordsdb.Exec("CREATE DATABASE " + dbName).Error
+	if err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+	defer func() {
+		err := c.recordsdb.Exec("DROP DATABASE " + dbName + " CASCADE").Error
+		if err != nil {
+			t.Errorf("drop database: %v", err)
+		}
+	}()
+
+	err = c.Setup()
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	// The plugin version record does not exist yet either.
+	err = c.RegisterPlugin(cache.Plugin{
+		ID:      bitumplugin.ID,
+		Version: bitumplugin.Version,
+	})
+	if err != nil && err != cache.ErrNoVersionRecord {
+		t.Fatalf("RegisterPlugin: %v", err)
+	}
+	err = c.PluginSetup(bitumplugin.ID)
+	if err != nil {
+		t.Fatalf("PluginSetup: %v", err)
+	}
+
+	cachetest.Run(t, c)
+}
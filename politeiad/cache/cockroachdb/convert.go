@@ -107,32 +107,45 @@ func convertNewCommentFromBitum(nc bitumplugin.NewComment, ncr bitumplugin.NewCo
 
 func convertCommentFromBitum(c bitumplugin.Comment) Comment {
 	return Comment{
-		Key:       c.Token + c.CommentID,
-		Token:     c.Token,
-		ParentID:  c.ParentID,
-		Comment:   c.Comment,
-		Signature: c.Signature,
-		PublicKey: c.PublicKey,
-		CommentID: c.CommentID,
-		Receipt:   c.Receipt,
-		Timestamp: c.Timestamp,
-		Censored:  false,
+		Key:           c.Token + c.CommentID,
+		Token:         c.Token,
+		ParentID:      c.ParentID,
+		Comment:       c.Comment,
+		Signature:     c.Signature,
+		PublicKey:     c.PublicKey,
+		CommentID:     c.CommentID,
+		Receipt:       c.Receipt,
+		Timestamp:     c.Timestamp,
+		Censored:      false,
+		Deleted:       false,
+		Edits:         c.Edits,
+		EditTimestamp: c.EditTimestamp,
+		EditSignature: c.EditSignature,
+		EditPublicKey: c.EditPublicKey,
+		EditReceipt:   c.EditReceipt,
 	}
 }
 
 func convertCommentToBitum(c Comment) bitumplugin.Comment {
 	return bitumplugin.Comment{
-		Token:       c.Token,
-		ParentID:    c.ParentID,
-		Comment:     c.Comment,
-		Signature:   c.Signature,
-		PublicKey:   c.PublicKey,
-		CommentID:   c.CommentID,
-		Receipt:     c.Receipt,
-		Timestamp:   c.Timestamp,
-		TotalVotes:  0,
-		ResultVotes: 0,
-		Censored:    c.Censored,
+		Token:         c.Token,
+		ParentID:      c.ParentID,
+		Comment:       c.Comment,
+		Signature:     c.Signature,
+		PublicKey:     c.PublicKey,
+		CommentID:     c.CommentID,
+		Receipt:       c.Receipt,
+		Timestamp:     c.Timestamp,
+		TotalVotes:    0,
+		ResultVotes:   c.ResultVotes,
+		Censored:      c.Censored,
+		Deleted:       c.Deleted,
+		Edits:         c.Edits,
+		EditTimestamp: c.EditTimestamp,
+		EditSignature: c.EditSignature,
+		EditPublicKey: c.EditPublicKey,
+		EditReceipt:   c.EditReceipt,
+		Replies:       c.Replies,
 	}
 }
 
@@ -143,6 +156,7 @@ func convertLikeCommentFromBitum(lc bitumplugin.LikeComment) LikeComment {
 		Action:    lc.Action,
 		Signature: lc.Signature,
 		PublicKey: lc.PublicKey,
+		UserID:    lc.UserID,
 	}
 }
 
@@ -153,6 +167,31 @@ func convertLikeCommentToBitum(lc LikeComment) bitumplugin.LikeComment {
 		Action:    lc.Action,
 		Signature: lc.Signature,
 		PublicKey: lc.PublicKey,
+		UserID:    lc.UserID,
+	}
+}
+
+func convertCommentFlagFromBitum(fc bitumplugin.FlagComment) CommentFlag {
+	return CommentFlag{
+		Token:     fc.Token,
+		CommentID: fc.CommentID,
+		Reason:    fc.Reason,
+		Signature: fc.Signature,
+		PublicKey: fc.PublicKey,
+		Receipt:   fc.Receipt,
+		Timestamp: fc.Timestamp,
+	}
+}
+
+func convertCommentFlagToBitum(cf CommentFlag) bitumplugin.FlagComment {
+	return bitumplugin.FlagComment{
+		Token:     cf.Token,
+		CommentID: cf.CommentID,
+		Reason:    cf.Reason,
+		Signature: cf.Signature,
+		PublicKey: cf.PublicKey,
+		Receipt:   cf.Receipt,
+		Timestamp: cf.Timestamp,
 	}
 }
 
@@ -180,6 +219,46 @@ func convertAuthorizeVoteToBitum(av AuthorizeVote) bitumplugin.AuthorizeVote {
 	}
 }
 
+func convertCancelVoteFromBitum(cv bitumplugin.CancelVote, token string) CancelVote {
+	return CancelVote{
+		Token:        token,
+		RequestToken: cv.Token,
+		Reason:       cv.Reason,
+		Signature:    cv.Signature,
+		PublicKey:    cv.PublicKey,
+		Receipt:      cv.Receipt,
+		Timestamp:    cv.Timestamp,
+	}
+}
+
+func convertCancelVoteToBitum(cv CancelVote) bitumplugin.CancelVote {
+	return bitumplugin.CancelVote{
+		Version:   bitumplugin.VersionCancelVote,
+		Receipt:   cv.Receipt,
+		Timestamp: cv.Timestamp,
+		Token:     cv.RequestToken,
+		Reason:    cv.Reason,
+		Signature: cv.Signature,
+		PublicKey: cv.PublicKey,
+	}
+}
+
+func convertScheduledVoteFromBitum(sv bitumplugin.StartVote) (*ScheduledVote, error) {
+	b, err := bitumplugin.EncodeStartVote(sv)
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduledVote{
+		Token:       sv.Vote.Token,
+		StartHeight: sv.Vote.StartHeight,
+		Payload:     string(b),
+	}, nil
+}
+
+func convertScheduledVoteToBitum(sv ScheduledVote) (*bitumplugin.StartVote, error) {
+	return bitumplugin.DecodeStartVote([]byte(sv.Payload))
+}
+
 func convertStartVoteFromBitum(sv bitumplugin.StartVote, svr bitumplugin.StartVoteReply, endHeight uint64) StartVote {
 	opts := make([]VoteOption, 0, len(sv.Vote.Options))
 	for _, v := range sv.Vote.Options {
@@ -192,6 +271,7 @@ func convertStartVoteFromBitum(sv bitumplugin.StartVote, svr bitumplugin.StartVo
 	}
 	return StartVote{
 		Token:               sv.Vote.Token,
+		Type:                int(sv.Vote.Type),
 		Mask:                sv.Vote.Mask,
 		Duration:            sv.Vote.Duration,
 		QuorumPercentage:    sv.Vote.QuorumPercentage,
@@ -204,6 +284,8 @@ func convertStartVoteFromBitum(sv bitumplugin.StartVote, svr bitumplugin.StartVo
 		EndHeight:           endHeight,
 		EligibleTickets:     strings.Join(svr.EligibleTickets, ","),
 		EligibleTicketCount: len(svr.EligibleTickets),
+		RunoffTokens:        strings.Join(sv.Vote.RunoffTokens, ","),
+		AllowRevote:         sv.Vote.AllowRevote,
 	}
 }
 
@@ -222,11 +304,14 @@ func convertStartVoteToBitum(sv StartVote) (bitumplugin.StartVote, bitumplugin.S
 		Signature: sv.Signature,
 		Vote: bitumplugin.Vote{
 			Token:            sv.Token,
+			Type:             bitumplugin.VoteT(sv.Type),
 			Mask:             sv.Mask,
 			Duration:         sv.Duration,
 			QuorumPercentage: sv.QuorumPercentage,
 			PassPercentage:   sv.PassPercentage,
 			Options:          opts,
+			RunoffTokens:     splitTokens(sv.RunoffTokens),
+			AllowRevote:      sv.AllowRevote,
 		},
 	}
 
@@ -244,12 +329,14 @@ func convertStartVoteToBitum(sv StartVote) (bitumplugin.StartVote, bitumplugin.S
 	return dsv, dsvr
 }
 
-func convertCastVoteFromBitum(cv bitumplugin.CastVote) CastVote {
+func convertCastVoteFromBitum(cv bitumplugin.CastVote, receipt string, blockHeight uint32) CastVote {
 	return CastVote{
 		Token:        cv.Token,
 		Ticket:       cv.Ticket,
 		VoteBit:      cv.VoteBit,
 		Signature:    cv.Signature,
+		Receipt:      receipt,
+		BlockHeight:  blockHeight,
 		TokenVoteBit: cv.Token + cv.VoteBit,
 	}
 }
@@ -69,16 +69,26 @@ func (Record) TableName() string {
 //
 // This is a bitum plugin model.
 type Comment struct {
-	Key       string `gorm:"primary_key"`       // Primary key (token+commentID)
-	Token     string `gorm:"not null;size:64"`  // Censorship token
-	ParentID  string `gorm:"not null"`          // Parent comment ID
-	Comment   string `gorm:"not null"`          // Comment
-	Signature string `gorm:"not null;size:128"` // Client Signature of Token+ParentID+Comment
-	PublicKey string `gorm:"not null;size:64"`  // Pubkey used for Signature
-	CommentID string `gorm:"not null"`          // Comment ID
-	Receipt   string `gorm:"not null"`          // Server signature of the client Signature
-	Timestamp int64  `gorm:"not null"`          // Received UNIX timestamp
-	Censored  bool   `gorm:"not null"`          // Has this comment been censored
+	Key           string `gorm:"primary_key"`            // Primary key (token+commentID)
+	Token         string `gorm:"not null;size:64"`       // Censorship token
+	ParentID      string `gorm:"not null"`               // Parent comment ID
+	Comment       string `gorm:"not null"`               // Comment
+	Signature     string `gorm:"not null;size:128"`      // Client Signature of Token+ParentID+Comment
+	PublicKey     string `gorm:"not null;size:64;index"` // Pubkey used for Signature
+	CommentID     string `gorm:"not null"`               // Comment ID
+	Receipt       string `gorm:"not null"`               // Server signature of the client Signature
+	Timestamp     int64  `gorm:"not null"`               // Received UNIX timestamp
+	Censored      bool   `gorm:"not null"`               // Has this comment been censored
+	Deleted       bool   `gorm:"not null"`               // Has this comment been deleted by its author
+	Edits         uint32 `gorm:"not null"`               // Number of times the comment was edited
+	EditTimestamp int64  `gorm:"not null"`               // UNIX timestamp of latest edit
+	EditSignature string `gorm:"not null"`               // Client signature of latest edit
+	EditPublicKey string `gorm:"not null"`               // Pubkey used for EditSignature
+	EditReceipt   string `gorm:"not null"`               // Server signature of EditSignature
+	Depth         uint32 `gorm:"not null"`               // Depth in thread, root comments are 0
+	RootID        string `gorm:"not null"`               // Comment ID of the thread root
+	Replies       uint64 `gorm:"not null"`               // Number of direct replies
+	ResultVotes   int64  `gorm:"not null"`               // Vote score, tallied per public key
 }
 
 // TableName returns the name of the Comment database table.
@@ -97,6 +107,7 @@ type LikeComment struct {
 	Action    string `gorm:"not null;size:2"`   // Up or downvote (1, -1)
 	Signature string `gorm:"not null;size:128"` // Client Signature of Token+CommentID+Action
 	PublicKey string `gorm:"not null;size:64"`  // Public key used for Signature
+	UserID    string `gorm:"not null"`          // User ID of the author
 }
 
 // TableName returns the name of the LikeComment database table.
@@ -104,6 +115,26 @@ func (LikeComment) TableName() string {
 	return tableCommentLikes
 }
 
+// CommentFlag describes a flag that a user put on a comment.  Only flags that
+// have not been dismissed are stored.
+//
+// This is a bitum plugin model.
+type CommentFlag struct {
+	Key       uint   `gorm:"primary_key"`       // Primary key
+	Token     string `gorm:"not null;size:64"`  // Censorship token
+	CommentID string `gorm:"not null"`          // Comment ID
+	Reason    string `gorm:"not null"`          // Reason category
+	Signature string `gorm:"not null;size:128"` // Client Signature of Token+CommentID+Reason
+	PublicKey string `gorm:"not null;size:64"`  // Public key used for Signature
+	Receipt   string `gorm:"not null;size:128"` // Server signature of client signature
+	Timestamp int64  `gorm:"not null"`          // UNIX timestamp of flag
+}
+
+// TableName returns the name of the CommentFlag database table.
+func (CommentFlag) TableName() string {
+	return tableCommentFlags
+}
+
 // AuthorizeVote is used to indicate that a record has been finalized and is
 // ready to be voted on.
 //
@@ -124,6 +155,41 @@ func (AuthorizeVote) TableName() string {
 	return tableAuthorizeVotes
 }
 
+// CancelVote describes an admin cancellation of a proposal vote.  A
+// cancellation of a runoff vote is stored once for every proposal in the
+// runoff.
+//
+// This is a bitum plugin model.
+type CancelVote struct {
+	Token        string `gorm:"primary_key;size:64"` // Censorship token of the cancelled vote
+	RequestToken string `gorm:"not null;size:64"`    // Token the cancellation was requested for
+	Reason       string `gorm:"not null"`            // Reason for cancelling the vote
+	Signature    string `gorm:"not null;size:128"`   // Signature of token+reason
+	PublicKey    string `gorm:"not null;size:64"`    // Pubkey used for signature
+	Receipt      string `gorm:"not null;size:128"`   // Server signature of client signature
+	Timestamp    int64  `gorm:"not null"`            // Received UNIX timestamp
+}
+
+// TableName returns the name of the CancelVote database table.
+func (CancelVote) TableName() string {
+	return tableCancelVotes
+}
+
+// ScheduledVote describes a proposal vote that has been scheduled to start
+// at a future block height.  The record is removed once the vote starts.
+//
+// This is a bitum plugin model.
+type ScheduledVote struct {
+	Token       string `gorm:"primary_key;size:64"` // Censorship token
+	StartHeight uint32 `gorm:"not null"`            // Height at which the vote starts
+	Payload     string `gorm:"not null"`            // JSON encoded StartVote
+}
+
+// TableName returns the name of the ScheduledVote database table.
+func (ScheduledVote) TableName() string {
+	return tableScheduledVotes
+}
+
 // VoteOption describes a single vote option.
 //
 // This is a bitum plugin model.
@@ -146,6 +212,7 @@ func (VoteOption) TableName() string {
 type StartVote struct {
 	Token               string       `gorm:"primary_key;size:64"` // Censorship token
 	Version             uint64       `gorm:"not null"`            // Version of files
+	Type                int          `gorm:"not null"`            // Vote type
 	Mask                uint64       `gorm:"not null"`            // Valid votebits
 	Duration            uint32       `gorm:"not null"`            // Duration in blocks
 	QuorumPercentage    uint32       `gorm:"not null"`            // Percent of eligible votes required for quorum
@@ -158,6 +225,8 @@ type StartVote struct {
 	EndHeight           uint64       `gorm:"not null"`            // Height of vote end
 	EligibleTickets     string       `gorm:"not null"`            // Valid voting tickets
 	EligibleTicketCount int          `gorm:"not null"`            // Number of eligible tickets
+	RunoffTokens        string       `gorm:"not null"`            // Tokens of all proposals in a runoff vote
+	AllowRevote         bool         `gorm:"not null"`            // Tickets may change their vote
 }
 
 // TableName returns the name of the StartVote database table.
@@ -165,15 +234,18 @@ func (StartVote) TableName() string {
 	return tableStartVotes
 }
 
-// CastVote records a signed vote.
+// CastVote records a signed vote.  Only the latest vote of a ticket is
+// recorded when a vote allows tickets to revote.
 //
 // This is a bitum plugin model.
 type CastVote struct {
-	Key       uint   `gorm:"primary_key"`       // Primary key
-	Token     string `gorm:"not null;size:64"`  // Censorship token
-	Ticket    string `gorm:"not null"`          // Ticket ID
-	VoteBit   string `gorm:"not null"`          // Hex encoded vote bit that was selected
-	Signature string `gorm:"not null;size:130"` // Signature of Token+Ticket+VoteBit
+	Key         uint   `gorm:"primary_key"`                                        // Primary key
+	Token       string `gorm:"not null;size:64;index:idx_cast_votes_token_ticket"` // Censorship token
+	Ticket      string `gorm:"not null;index:idx_cast_votes_token_ticket"`         // Ticket ID
+	VoteBit     string `gorm:"not null"`                                           // Hex encoded vote bit that was selected
+	Signature   string `gorm:"not null;size:130"`                                  // Signature of Token+Ticket+VoteBit
+	Receipt     string `gorm:"size:130"`                                           // Server signature of Signature
+	BlockHeight uint32 `gorm:"not null"`                                           // Best block height when the vote was cast
 
 	// TokenVoteBit is the Token+VoteBit. Indexing TokenVoteBit allows
 	// for quick lookups of the number of votes cast for each vote bit.
@@ -208,9 +280,10 @@ func (VoteOptionResult) TableName() string {
 //
 // This is a bitum plugin model.
 type VoteResults struct {
-	Token    string             `gorm:"primary_key;size:64"` // Censorship token
-	Approved bool               `gorm:"not null"`            // Vote was approved
-	Results  []VoteOptionResult `gorm:"foreignkey:Token"`    // Results for the vote options
+	Token      string             `gorm:"primary_key;size:64"` // Censorship token
+	Approved   bool               `gorm:"not null"`            // Vote was approved
+	TotalVotes uint64             `gorm:"not null"`            // Number of tickets that voted
+	Results    []VoteOptionResult `gorm:"foreignkey:Token"`    // Results for the vote options
 }
 
 // TableName returns the name of the VoteResults database table.
@@ -5,6 +5,8 @@
 package testcache
 
 import (
+	"sort"
+
 	bitum "github.com/bitum-project/politeia/bitumplugin"
 	"github.com/bitum-project/politeia/politeiad/cache"
 )
@@ -29,6 +31,158 @@ func (c *testcache) getComments(payload string) (string, error) {
 	return string(gcrb), nil
 }
 
+func (c *testcache) getComment(payload string) (string, error) {
+	gc, err := bitum.DecodeGetComment([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	for _, v := range c.comments[gc.Token] {
+		if v.CommentID != gc.CommentID {
+			continue
+		}
+		gcrb, err := bitum.EncodeGetCommentReply(
+			bitum.GetCommentReply{
+				Comment: v,
+			})
+		if err != nil {
+			return "", err
+		}
+		return string(gcrb), nil
+	}
+
+	return "", cache.ErrRecordNotFound
+}
+
+func (c *testcache) userComments(payload string) (string, error) {
+	uc, err := bitum.DecodeUserComments([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	pubkeys := make(map[string]bool, len(uc.PublicKeys))
+	for _, v := range uc.PublicKeys {
+		pubkeys[v] = true
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	comments := make([]bitum.Comment, 0)
+	for _, cs := range c.comments {
+		for _, v := range cs {
+			if !pubkeys[v.PublicKey] {
+				continue
+			}
+			if v.Censored && !uc.IncludeCensored {
+				continue
+			}
+			comments = append(comments, v)
+		}
+	}
+
+	// Newest first.  Comments with the same timestamp are sorted
+	// by key in descending order the same way the cache does.
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].Timestamp != comments[j].Timestamp {
+			return comments[i].Timestamp > comments[j].Timestamp
+		}
+		return comments[i].Token+comments[i].CommentID >
+			comments[j].Token+comments[j].CommentID
+	})
+
+	if uint32(len(comments)) > uc.Offset {
+		comments = comments[uc.Offset:]
+	} else {
+		comments = comments[:0]
+	}
+	var nextOffset uint32
+	if uc.Limit > 0 && uint32(len(comments)) > uc.Limit {
+		comments = comments[:uc.Limit]
+		nextOffset = uc.Offset + uc.Limit
+	}
+
+	ucrb, err := bitum.EncodeUserCommentsReply(
+		bitum.UserCommentsReply{
+			Comments:   comments,
+			NextOffset: nextOffset,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(ucrb), nil
+}
+
+func (c *testcache) newComment(cmdPayload, replyPayload string) (string, error) {
+	nc, err := bitum.DecodeNewComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	ncr, err := bitum.DecodeNewCommentReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.comments[nc.Token] = append(c.comments[nc.Token], bitum.Comment{
+		Token:     nc.Token,
+		ParentID:  nc.ParentID,
+		Comment:   nc.Comment,
+		Signature: nc.Signature,
+		PublicKey: nc.PublicKey,
+		CommentID: ncr.CommentID,
+		Receipt:   ncr.Receipt,
+		Timestamp: ncr.Timestamp,
+	})
+
+	return replyPayload, nil
+}
+
+func (c *testcache) censorComment(cmdPayload, replyPayload string) (string, error) {
+	cc, err := bitum.DecodeCensorComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for i, v := range c.comments[cc.Token] {
+		if v.CommentID == cc.CommentID {
+			c.comments[cc.Token][i].Comment = ""
+			c.comments[cc.Token][i].Censored = true
+		}
+	}
+
+	return replyPayload, nil
+}
+
+func (c *testcache) deleteComment(cmdPayload, replyPayload string) (string, error) {
+	dc, err := bitum.DecodeDeleteComment([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for i, v := range c.comments[dc.Token] {
+		if v.CommentID == dc.CommentID {
+			c.comments[dc.Token][i].Comment = ""
+			c.comments[dc.Token][i].Deleted = true
+		}
+	}
+
+	return replyPayload, nil
+}
+
 func (c *testcache) authorizeVote(cmdPayload, replyPayload string) (string, error) {
 	av, err := bitum.DecodeAuthorizeVote([]byte(cmdPayload))
 	if err != nil {
@@ -77,6 +231,55 @@ func (c *testcache) startVote(cmdPayload, replyPayload string) (string, error) {
 	return replyPayload, nil
 }
 
+func (c *testcache) startVoteRunoff(cmdPayload, replyPayload string) (string, error) {
+	sv, err := bitum.DecodeStartVoteRunoff([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	svr, err := bitum.DecodeStartVoteRunoffReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	// Store start vote data for all runoff proposals
+	tokens := bitum.RunoffTokens(*sv)
+	for _, v := range sv.StartVotes {
+		v.Vote.RunoffTokens = tokens
+		c.startVotes[v.Vote.Token] = v
+		c.startVoteReplies[v.Vote.Token] = svr.StartVoteReply
+	}
+
+	return replyPayload, nil
+}
+
+func (c *testcache) cancelVote(cmdPayload, replyPayload string) (string, error) {
+	cv, err := bitum.DecodeCancelVote([]byte(cmdPayload))
+	if err != nil {
+		return "", err
+	}
+
+	cvr, err := bitum.DecodeCancelVoteReply([]byte(replyPayload))
+	if err != nil {
+		return "", err
+	}
+
+	cv.Receipt = cvr.Receipt
+	cv.Timestamp = cvr.Timestamp
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, v := range cvr.Tokens {
+		c.cancelVotes[v] = *cv
+	}
+
+	return replyPayload, nil
+}
+
 func (c *testcache) voteDetails(payload string) (string, error) {
 	vd, err := bitum.DecodeVoteDetails([]byte(payload))
 	if err != nil {
@@ -103,6 +306,7 @@ func (c *testcache) voteDetails(payload string) (string, error) {
 			AuthorizeVote:  c.authorizeVotes[vd.Token][r.Version],
 			StartVote:      c.startVotes[vd.Token],
 			StartVoteReply: c.startVoteReplies[vd.Token],
+			CancelVote:     c.cancelVotes[vd.Token],
 		})
 	if err != nil {
 		return "", err
@@ -115,10 +319,24 @@ func (c *testcache) bitumExec(cmd, cmdPayload, replyPayload string) (string, err
 	switch cmd {
 	case bitum.CmdGetComments:
 		return c.getComments(cmdPayload)
+	case bitum.CmdGetComment:
+		return c.getComment(cmdPayload)
+	case bitum.CmdUserComments:
+		return c.userComments(cmdPayload)
+	case bitum.CmdNewComment:
+		return c.newComment(cmdPayload, replyPayload)
+	case bitum.CmdCensorComment:
+		return c.censorComment(cmdPayload, replyPayload)
+	case bitum.CmdDeleteComment:
+		return c.deleteComment(cmdPayload, replyPayload)
 	case bitum.CmdAuthorizeVote:
 		return c.authorizeVote(cmdPayload, replyPayload)
 	case bitum.CmdStartVote:
 		return c.startVote(cmdPayload, replyPayload)
+	case bitum.CmdStartVoteRunoff:
+		return c.startVoteRunoff(cmdPayload, replyPayload)
+	case bitum.CmdCancelVote:
+		return c.cancelVote(cmdPayload, replyPayload)
 	case bitum.CmdVoteDetails:
 		return c.voteDetails(cmdPayload)
 	}
@@ -5,7 +5,9 @@
 package testcache
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 
@@ -24,6 +26,7 @@ type testcache struct {
 	authorizeVotes   map[string]map[string]bitum.AuthorizeVote // [token][version]AuthorizeVote
 	startVotes       map[string]bitum.StartVote                // [token]StartVote
 	startVoteReplies map[string]bitum.StartVoteReply           // [token]StartVoteReply
+	cancelVotes      map[string]bitum.CancelVote               // [token]CancelVote
 }
 
 // NewRecords adds a record to the cache.
@@ -146,6 +149,232 @@ func (c *testcache) InventoryStats() (*cache.InventoryStats, error) {
 	return &cache.InventoryStats{}, nil
 }
 
+// matchesMetadata returns whether the passed in record contains a metadata
+// stream that satisfies the passed in MetadataFilter.
+func matchesMetadata(r cache.Record, f cache.MetadataFilter) bool {
+	for _, v := range r.Metadata {
+		if v.ID != f.StreamID {
+			continue
+		}
+		var payload map[string]interface{}
+		err := json.Unmarshal([]byte(v.Payload), &payload)
+		if err != nil {
+			return false
+		}
+		value, ok := payload[f.Field].(string)
+		if !ok {
+			return false
+		}
+		for _, want := range f.Values {
+			if value == want {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// voteStatus returns the vote status of the passed in record at the passed in
+// block height.  The rules match the ones that are applied by the cockroachdb
+// cache when filtering records by vote status.
+//
+// This function must be called with the lock held.
+func (c *testcache) voteStatus(r cache.Record, bestBlock uint64) (cache.VoteStatusT, error) {
+	token := r.CensorshipRecord.Token
+	if _, ok := c.cancelVotes[token]; ok {
+		return cache.VoteStatusCancelled, nil
+	}
+	av, ok := c.authorizeVotes[token][r.Version]
+	if !ok || av.Action != bitum.AuthVoteActionAuthorize {
+		return cache.VoteStatusNotAuthorized, nil
+	}
+	sv, ok := c.startVotes[token]
+	if !ok {
+		return cache.VoteStatusAuthorized, nil
+	}
+
+	// A scheduled vote does not have an end height until it
+	// has been started by politeiad.
+	svr := c.startVoteReplies[token]
+	if sv.Vote.StartHeight != 0 && svr.EndHeight == "" {
+		if uint64(sv.Vote.StartHeight) > bestBlock {
+			return cache.VoteStatusScheduled, nil
+		}
+		return cache.VoteStatusStarted, nil
+	}
+
+	endHeight, err := strconv.ParseUint(svr.EndHeight, 10, 64)
+	if err != nil {
+		return cache.VoteStatusInvalid, fmt.Errorf("parse end height "+
+			"'%v': %v", svr.EndHeight, err)
+	}
+	if endHeight > bestBlock {
+		return cache.VoteStatusStarted, nil
+	}
+	return cache.VoteStatusFinished, nil
+}
+
+// query returns the latest version of all records that match the filtering
+// parameters of the passed in RecordQuery, sorted according to the query sort
+// order.  Pagination is not applied.
+//
+// This function must be called with the lock held.
+func (c *testcache) query(q cache.RecordQuery) ([]cache.Record, error) {
+	statuses := make(map[cache.RecordStatusT]bool, len(q.Statuses))
+	for _, v := range q.Statuses {
+		statuses[v] = true
+	}
+	voteStatuses := make(map[cache.VoteStatusT]bool, len(q.VoteStatuses))
+	for _, v := range q.VoteStatuses {
+		switch v {
+		case cache.VoteStatusNotAuthorized, cache.VoteStatusAuthorized,
+			cache.VoteStatusScheduled, cache.VoteStatusStarted,
+			cache.VoteStatusFinished, cache.VoteStatusCancelled:
+		default:
+			return nil, fmt.Errorf("invalid vote status %v", v)
+		}
+		voteStatuses[v] = true
+	}
+
+	records := make([]cache.Record, 0, len(c.records))
+	for token := range c.records {
+		r, err := c.record(token)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(statuses) > 0 && !statuses[r.Status]:
+			continue
+		case q.TimestampAfter != 0 && r.Timestamp <= q.TimestampAfter:
+			continue
+		case q.TimestampBefore != 0 && r.Timestamp >= q.TimestampBefore:
+			continue
+		}
+		match := true
+		for _, f := range q.Metadata {
+			if !matchesMetadata(*r, f) {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if len(voteStatuses) > 0 {
+			vs, err := c.voteStatus(*r, q.BestBlock)
+			if err != nil {
+				return nil, err
+			}
+			if !voteStatuses[vs] {
+				continue
+			}
+		}
+		records = append(records, *r)
+	}
+
+	// Newest first with ties broken by ascending token. The
+	// oldest sort order is the reverse.
+	newest := q.Sort != cache.SortOrderOldest
+	sort.Slice(records, func(i, j int) bool {
+		ri, rj := records[i], records[j]
+		if ri.Timestamp != rj.Timestamp {
+			if newest {
+				return ri.Timestamp > rj.Timestamp
+			}
+			return ri.Timestamp < rj.Timestamp
+		}
+		if newest {
+			return ri.CensorshipRecord.Token < rj.CensorshipRecord.Token
+		}
+		return ri.CensorshipRecord.Token > rj.CensorshipRecord.Token
+	})
+
+	return records, nil
+}
+
+// Query returns a page of the latest version of the records that match the
+// passed in RecordQuery.
+func (c *testcache) Query(q cache.RecordQuery) ([]cache.Record, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if q.After != "" && q.Before != "" {
+		return nil, fmt.Errorf("after and before cursors cannot " +
+			"be used together")
+	}
+
+	all, err := c.query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the page boundaries
+	start, end := 0, len(all)
+	cursor := q.After
+	if cursor == "" {
+		cursor = q.Before
+	}
+	if cursor != "" {
+		idx := -1
+		for i, r := range all {
+			if r.CensorshipRecord.Token == cursor {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, cache.ErrRecordNotFound
+		}
+		if q.After != "" {
+			start = idx + 1
+		} else {
+			end = idx
+		}
+	}
+	if q.Limit > 0 && end-start > q.Limit {
+		if q.Before != "" {
+			start = end - q.Limit
+		} else {
+			end = start + q.Limit
+		}
+	}
+
+	return all[start:end], nil
+}
+
+// QueryStats returns a summary of the number of records grouped by record
+// status for all records that match the passed in RecordQuery.
+func (c *testcache) QueryStats(q cache.RecordQuery) (*cache.InventoryStats, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	all, err := c.query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var is cache.InventoryStats
+	for _, r := range all {
+		switch r.Status {
+		case cache.RecordStatusNotReviewed:
+			is.NotReviewed++
+		case cache.RecordStatusCensored:
+			is.Censored++
+		case cache.RecordStatusPublic:
+			is.Public++
+		case cache.RecordStatusUnreviewedChanges:
+			is.UnreviewedChanges++
+		case cache.RecordStatusArchived:
+			is.Archived++
+		default:
+			is.Invalid++
+		}
+	}
+
+	return &is, nil
+}
+
 // Setup is a stub to satisfy the cache interface.
 func (c *testcache) Setup() error {
 	return nil
@@ -200,5 +429,6 @@ func New() *testcache {
 		authorizeVotes:   make(map[string]map[string]bitum.AuthorizeVote),
 		startVotes:       make(map[string]bitum.StartVote),
 		startVoteReplies: make(map[string]bitum.StartVoteReply),
+		cancelVotes:      make(map[string]bitum.CancelVote),
 	}
 }
@@ -0,0 +1,15 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package testcache
+
+import (
+	"testing"
+
+	"github.com/bitum-project/politeia/politeiad/cache/cachetest"
+)
+
+func TestTestcache(t *testing.T) {
+	cachetest.Run(t, New())
+}
@@ -24,6 +24,7 @@ var (
 	jsonInFlag    = flag.String("jsonin", "", "JSON record file")
 	jsonOutFlag   = flag.Bool("jsonout", false, "return output as JSON")
 	verboseFlag   = flag.Bool("v", false, "verbose output")
+	votesFlag     = flag.String("votes", "", "JSON vote results bundle file")
 )
 
 type record struct {
@@ -54,6 +55,11 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "  -jsonin <filename> - A path to a JSON file which "+
 		"represents the record. If this option is set, the other input "+
 		"options (-k, -t, -s) should not be provided.\n")
+	fmt.Fprintf(os.Stderr, "  -votes <filename>  - A path to a JSON file which "+
+		"contains a vote results bundle. The bundle signature, the "+
+		"start vote signature, every cast vote and the tally are "+
+		"verified against the politeiad public key, which must be "+
+		"provided using -k.\n")
 	fmt.Fprintf(os.Stderr, "  -jsonout           - JSON output\n")
 	fmt.Fprintf(os.Stderr, "\n")
 }
@@ -93,6 +99,14 @@ func verifyRecord(key [ed25519.PublicKeySize]byte, merkle, token string, signatu
 
 func _main() error {
 	flag.Parse()
+	if *votesFlag != "" {
+		if *publicKeyFlag == "" {
+			usage()
+			return fmt.Errorf("must provide the politeiad public key " +
+				"(-k) to verify a vote results bundle")
+		}
+		return verifyVotes(*votesFlag)
+	}
 	if (*publicKeyFlag == "" || *tokenFlag == "" || *signatureFlag == "") &&
 		*jsonInFlag == "" {
 		usage()
@@ -0,0 +1,274 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/agl/ed25519"
+	"github.com/bitum-project/bitumd/bitumec/secp256k1"
+	"github.com/bitum-project/bitumd/bitumutil"
+	"github.com/bitum-project/bitumd/chaincfg/chainhash"
+	"github.com/bitum-project/bitumd/wire"
+	"github.com/bitum-project/politeia/bitumplugin"
+)
+
+// votesOutput is the JSON output of the vote results bundle verification.
+type votesOutput struct {
+	Success    bool                           `json:"success"`
+	Token      string                         `json:"token"`
+	TotalVotes uint64                         `json:"totalvotes"`
+	Results    []bitumplugin.VoteOptionResult `json:"results"`
+	Errors     []string                       `json:"errors,omitempty"`
+}
+
+// loadVoteResultsBundle reads a vote results bundle from the provided file.
+// Both the politeiawww VoteResultsBundleReply and the bare bundle are
+// accepted.
+func loadVoteResultsBundle(filename string) (*bitumplugin.VoteResultsBundle, error) {
+	payload, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Bundle json.RawMessage `json:"bundle"`
+	}
+	err = json.Unmarshal(payload, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.Bundle) != 0 {
+		payload = reply.Bundle
+	}
+
+	return bitumplugin.DecodeVoteResultsBundle(payload)
+}
+
+// verifyEd25519 verifies a hex encoded ed25519 signature of msg.
+func verifyEd25519(key [ed25519.PublicKeySize]byte, msg []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	var s [ed25519.SignatureSize]byte
+	copy(s[:], sig)
+	return ed25519.Verify(&key, msg, &s)
+}
+
+// verifyTicketSignature verifies that the hex encoded compact signature of
+// message was created by the key of the provided pay-to-pubkey-hash address.
+// The public key hashes are compared so that the verification does not depend
+// on the network parameters.
+func verifyTicketSignature(address, message, signature string) error {
+	addr, err := bitumutil.DecodeAddress(address)
+	if err != nil {
+		return fmt.Errorf("could not decode address: %v", err)
+	}
+	pkh, ok := addr.(*bitumutil.AddressPubKeyHash)
+	if !ok {
+		return fmt.Errorf("address is not a pay-to-pubkey-hash "+
+			"address: %v", address)
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %v", err)
+	}
+
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Bitum Signed Message:\n")
+	wire.WriteVarString(&buf, 0, message)
+	pk, wasCompressed, err := secp256k1.RecoverCompact(sig,
+		chainhash.HashB(buf.Bytes()))
+	if err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+
+	var serializedPK []byte
+	if wasCompressed {
+		serializedPK = pk.SerializeCompressed()
+	} else {
+		serializedPK = pk.SerializeUncompressed()
+	}
+	if !bytes.Equal(bitumutil.Hash160(serializedPK), pkh.Hash160()[:]) {
+		return fmt.Errorf("signature does not match address")
+	}
+
+	return nil
+}
+
+// verifyVoteResultsBundle verifies the politeiad signature of the bundle, the
+// admin signature of the start vote, every cast vote and the tally.  The
+// politeiad signatures are verified using the provided server key and never
+// using the key that the bundle carries, since anyone can sign a bundle with
+// their own key.  It returns a list of all verification failures.
+func verifyVoteResultsBundle(b *bitumplugin.VoteResultsBundle, serverKey string) ([]string, error) {
+	var failures []string
+
+	if b.Version != bitumplugin.VersionVoteResultsBundle {
+		return nil, fmt.Errorf("unsupported bundle version: %v",
+			b.Version)
+	}
+
+	// The signer can only be authenticated against a politeiad
+	// key that was obtained out of band.
+	if serverKey == "" {
+		return []string{"signer not authenticated: the politeiad " +
+			"public key was not provided"}, nil
+	}
+	key, err := hex.DecodeString(serverKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid server public key")
+	}
+	var publicKey [ed25519.PublicKeySize]byte
+	copy(publicKey[:], key)
+	if serverKey != b.PublicKey {
+		failures = append(failures, "bundle public key does not "+
+			"match the provided server key")
+	}
+
+	// Verify the bundle signature
+	digest, err := bitumplugin.VoteResultsBundleDigest(*b)
+	if err != nil {
+		return nil, err
+	}
+	if !verifyEd25519(publicKey, digest, b.Signature) {
+		failures = append(failures, "invalid bundle signature")
+	}
+
+	if b.StartVote.Vote.Token != b.Token {
+		failures = append(failures, "start vote token does not "+
+			"match bundle token")
+	}
+
+	// Verify the admin signature of the start vote.  politeiawww
+	// requires the admin to sign the proposal token.
+	adminKey, err := hex.DecodeString(b.StartVote.PublicKey)
+	if err != nil || len(adminKey) != ed25519.PublicKeySize {
+		failures = append(failures, "invalid start vote public key")
+	} else {
+		var k [ed25519.PublicKeySize]byte
+		copy(k[:], adminKey)
+		if !verifyEd25519(k, []byte(b.StartVote.Vote.Token),
+			b.StartVote.Signature) {
+			failures = append(failures, "invalid start vote "+
+				"signature")
+		}
+	}
+
+	// Verify every cast vote
+	eligible := make(map[string]struct{},
+		len(b.StartVoteReply.EligibleTickets))
+	for _, v := range b.StartVoteReply.EligibleTickets {
+		eligible[v] = struct{}{}
+	}
+	voted := make(map[string]struct{}, len(b.CastVotes))
+	votes := make([]bitumplugin.CastVote, 0, len(b.CastVotes))
+	for _, v := range b.CastVotes {
+		cv := v.CastVote
+		if cv.Token != b.Token {
+			failures = append(failures, fmt.Sprintf("ticket %v: "+
+				"invalid token", cv.Ticket))
+			continue
+		}
+		if _, ok := eligible[cv.Ticket]; !ok {
+			failures = append(failures, fmt.Sprintf("ticket %v: "+
+				"not eligible", cv.Ticket))
+			continue
+		}
+		if _, ok := voted[cv.Ticket]; ok {
+			failures = append(failures, fmt.Sprintf("ticket %v: "+
+				"duplicate vote", cv.Ticket))
+			continue
+		}
+		voted[cv.Ticket] = struct{}{}
+
+		if !verifyEd25519(publicKey, []byte(cv.Signature), v.Receipt) {
+			failures = append(failures, fmt.Sprintf("ticket %v: "+
+				"invalid receipt", cv.Ticket))
+		}
+		err := verifyTicketSignature(v.Address,
+			cv.Token+cv.Ticket+cv.VoteBit, cv.Signature)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("ticket %v: "+
+				"%v", cv.Ticket, err))
+		}
+
+		votes = append(votes, cv)
+	}
+
+	// Recompute the tally
+	results, total, err := bitumplugin.TallyVotes(b.StartVote, votes)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("could not tally "+
+			"votes: %v", err))
+	} else {
+		if total != b.TotalVotes {
+			failures = append(failures, fmt.Sprintf("total votes "+
+				"mismatch: got %v, bundle %v", total,
+				b.TotalVotes))
+		}
+		if !reflect.DeepEqual(results, b.Results) {
+			failures = append(failures, "vote tally does not "+
+				"match bundle results")
+		}
+	}
+
+	return failures, nil
+}
+
+// verifyVotes verifies the vote results bundle in the provided file.
+func verifyVotes(filename string) error {
+	b, err := loadVoteResultsBundle(filename)
+	if err != nil {
+		return err
+	}
+
+	failures, err := verifyVoteResultsBundle(b, *publicKeyFlag)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutFlag {
+		bytes, err := json.Marshal(votesOutput{
+			Success:    len(failures) == 0,
+			Token:      b.Token,
+			TotalVotes: b.TotalVotes,
+			Results:    b.Results,
+			Errors:     failures,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if len(failures) != 0 {
+		if *verboseFlag {
+			for _, v := range failures {
+				fmt.Println(v)
+			}
+		}
+		return fmt.Errorf("Vote results failed verification: %v "+
+			"errors", len(failures))
+	}
+
+	fmt.Printf("Vote results successfully verified\n")
+	fmt.Printf("  Token      : %v\n", b.Token)
+	fmt.Printf("  Total votes: %v\n", b.TotalVotes)
+	for _, v := range b.Results {
+		fmt.Printf("  %-11v: %v\n", v.ID, v.Votes)
+	}
+
+	return nil
+}
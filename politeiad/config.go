@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/bitum-project/bitumtime/api/v1"
+	"github.com/bitum-project/politeia/politeiad/backend/gitbe"
 	"github.com/bitum-project/politeia/politeiad/sharedconfig"
 	"github.com/bitum-project/politeia/util"
 	"github.com/bitum-project/politeia/util/version"
@@ -85,6 +86,9 @@ type config struct {
 	BuildCache    bool   `long:"buildcache" description:"Build the cache from scratch"`
 	Identity      string `long:"identity" description:"File containing the politeiad identity file"`
 	GitTrace      bool   `long:"gittrace" description:"Enable git tracing in logs"`
+	ChainProvider string `long:"chainprovider" description:"Chain data provider used by the bitum plugin {bitumdata, fixture}"`
+	BitumdataHost string `long:"bitumdatahost" description:"bitumdata URL used by the bitumdata chain provider"`
+	ChainFixture  string `long:"chainfixture" description:"File containing the chain fixture used by the fixture chain provider"`
 }
 
 // serviceOptions defines the configuration options for the daemon as a service
@@ -287,6 +291,8 @@ func loadConfig() (*config, []string, error) {
 		HTTPSKey:   defaultHTTPSKeyFile,
 		HTTPSCert:  defaultHTTPSCertFile,
 		Version:    version.String(),
+
+		ChainProvider: gitbe.ChainProviderBitumdata,
 	}
 
 	// Service options which are only added on Windows.
@@ -576,6 +582,38 @@ func loadConfig() (*config, []string, error) {
 		cfg.BitumtimeCert = path
 	}
 
+	// Validate the chain provider settings
+	switch cfg.ChainProvider {
+	case gitbe.ChainProviderBitumdata:
+	case gitbe.ChainProviderBitumd:
+		// bitumd can only report the ticket pool of the best
+		// block, which means that it can not start votes.
+		err := fmt.Errorf("%s: the bitumd chain provider is not "+
+			"supported since it can not snapshot the ticket pool "+
+			"that votes require", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	case gitbe.ChainProviderFixture:
+		if activeNetParams == &mainNetParams {
+			err := fmt.Errorf("%s: the fixture chain provider "+
+				"cannot be used on mainnet", funcName)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		if cfg.ChainFixture == "" {
+			err := fmt.Errorf("%s: chainfixture must be set when "+
+				"using the fixture chain provider", funcName)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		cfg.ChainFixture = cleanAndExpandPath(cfg.ChainFixture)
+	default:
+		err := fmt.Errorf("%s: invalid chainprovider %v", funcName,
+			cfg.ChainProvider)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
 	if cfg.Identity == "" {
 		cfg.Identity = defaultIdentityFile
 	}
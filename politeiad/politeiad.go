@@ -40,6 +40,10 @@ const (
 	permissionAuth
 )
 
+// scheduledVotesInterval is the interval at which politeiad checks whether
+// any scheduled votes need to be started.
+const scheduledVotesInterval = time.Minute
+
 // politeia application context.
 type politeia struct {
 	backend  backend.Backend
@@ -992,6 +996,45 @@ func (p *politeia) pluginCommand(w http.ResponseWriter, r *http.Request) {
 	util.RespondWithJSON(w, http.StatusOK, reply)
 }
 
+// startScheduledVotes periodically tells the bitum plugin to start the
+// scheduled votes whose start height has been reached.  The plugin reply is
+// sent to the cache the same way that plugin command replies are.  This
+// function must be run as a go routine.
+func (p *politeia) startScheduledVotes() {
+	log.Infof("Scheduled votes checker launched")
+
+	ticker := time.NewTicker(scheduledVotesInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		_, payload, err := p.backend.Plugin(
+			bitumplugin.CmdStartScheduledVotes, "")
+		if err != nil {
+			log.Errorf("startScheduledVotes: backend plugin: %v", err)
+			continue
+		}
+		ssvr, err := bitumplugin.DecodeStartScheduledVotesReply(
+			[]byte(payload))
+		if err != nil {
+			log.Errorf("startScheduledVotes: decode: %v", err)
+			continue
+		}
+		if len(ssvr.StartVotes) == 0 {
+			continue
+		}
+
+		_, err = p.cache.PluginExec(cache.PluginCommand{
+			ID:           bitumplugin.ID,
+			Command:      bitumplugin.CmdStartScheduledVotes,
+			ReplyPayload: payload,
+		})
+		if err != nil {
+			log.Criticalf("Cache plugin exec failed: command:%v"+
+				"replyPayload:%v error:%v",
+				bitumplugin.CmdStartScheduledVotes, payload, err)
+		}
+	}
+}
+
 func logging(f http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Trace incoming request
@@ -1113,10 +1156,26 @@ func _main() error {
 		}
 	}
 
-	// Setup backend.
+	// Setup the chain data provider. A nil provider defaults to
+	// the network's bitumdata instance.
 	gitbe.UseLogger(gitbeLog)
+	var chain gitbe.ChainProvider
+	switch loadedCfg.ChainProvider {
+	case gitbe.ChainProviderBitumdata:
+		if loadedCfg.BitumdataHost != "" {
+			chain = gitbe.NewBitumdataChain(loadedCfg.BitumdataHost)
+		}
+	case gitbe.ChainProviderFixture:
+		chain, err = gitbe.LoadFixtureChain(loadedCfg.ChainFixture)
+		if err != nil {
+			return fmt.Errorf("fixture chain provider: %v", err)
+		}
+	}
+	log.Infof("Chain provider: %v", loadedCfg.ChainProvider)
+
+	// Setup backend.
 	b, err := gitbe.New(activeNetParams.Params, loadedCfg.DataDir,
-		loadedCfg.BitumtimeHost, "", p.identity, loadedCfg.GitTrace)
+		loadedCfg.BitumtimeHost, "", p.identity, loadedCfg.GitTrace, chain)
 	if err != nil {
 		return err
 	}
@@ -1285,6 +1344,12 @@ func _main() error {
 		}()
 	}
 
+	// Start the scheduled votes of the bitum plugin once their
+	// start height has been reached.
+	if _, ok := p.plugins[bitumplugin.ID]; ok {
+		go p.startScheduledVotes()
+	}
+
 	// Tell user we are ready to go.
 	log.Infof("Start of day")
 
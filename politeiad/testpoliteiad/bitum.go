@@ -92,13 +92,183 @@ func (p *TestPoliteiad) startVote(payload string) (string, error) {
 	return string(svrb), nil
 }
 
+func (p *TestPoliteiad) startVoteRunoff(payload string) (string, error) {
+	sv, err := bitum.DecodeStartVoteRunoff([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+	if len(sv.StartVotes) == 0 {
+		return "", fmt.Errorf("no start votes")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	// Prepare reply. All runoff proposals share the same vote
+	// parameters so the first start vote is used.
+	endHeight := bestBlock + sv.StartVotes[0].Vote.Duration
+	svr := bitum.StartVoteReply{
+		Version:          bitum.VersionStartVoteReply,
+		StartBlockHeight: strconv.FormatUint(uint64(bestBlock), 10),
+		EndHeight:        strconv.FormatUint(uint64(endHeight), 10),
+		EligibleTickets:  []string{},
+	}
+	svrb, err := bitum.EncodeStartVoteRunoffReply(
+		bitum.StartVoteRunoffReply{
+			StartVoteReply: svr,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	// Store start votes and replies
+	tokens := bitum.RunoffTokens(*sv)
+	for _, v := range sv.StartVotes {
+		v.Vote.RunoffTokens = tokens
+		p.startVotes[v.Vote.Token] = v
+		p.startVoteReplies[v.Vote.Token] = svr
+	}
+
+	return string(svrb), nil
+}
+
+func (p *TestPoliteiad) cancelVote(payload string) (string, error) {
+	cv, err := bitum.DecodeCancelVote([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	// Sign cancel vote
+	s := p.identity.SignMessage([]byte(cv.Signature))
+	cv.Receipt = hex.EncodeToString(s[:])
+	cv.Timestamp = time.Now().Unix()
+	cv.Version = bitum.VersionCancelVote
+
+	p.Lock()
+	defer p.Unlock()
+
+	sv, ok := p.startVotes[cv.Token]
+	if !ok {
+		return "", fmt.Errorf("vote not started")
+	}
+	tokens := []string{cv.Token}
+	if sv.Vote.Type == bitum.VoteTypeRunoff {
+		tokens = sv.Vote.RunoffTokens
+	}
+
+	// Store cancel vote
+	for _, v := range tokens {
+		p.cancelVotes[v] = *cv
+	}
+
+	// Prepare reply
+	cvrb, err := bitum.EncodeCancelVoteReply(
+		bitum.CancelVoteReply{
+			Tokens:    tokens,
+			Receipt:   cv.Receipt,
+			Timestamp: cv.Timestamp,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(cvrb), nil
+}
+
+func (p *TestPoliteiad) newComment(payload string) (string, error) {
+	nc, err := bitum.DecodeNewComment([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	_, err = p.record(nc.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Comment IDs start at 1
+	p.commentIDs[nc.Token]++
+	s := p.identity.SignMessage([]byte(nc.Signature))
+
+	// Prepare reply
+	ncrb, err := bitum.EncodeNewCommentReply(
+		bitum.NewCommentReply{
+			CommentID: strconv.FormatUint(p.commentIDs[nc.Token], 10),
+			Receipt:   hex.EncodeToString(s[:]),
+			Timestamp: time.Now().Unix(),
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(ncrb), nil
+}
+
+func (p *TestPoliteiad) censorComment(payload string) (string, error) {
+	cc, err := bitum.DecodeCensorComment([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	// Sign censor comment
+	s := p.identity.SignMessage([]byte(cc.Signature))
+
+	// Prepare reply
+	ccrb, err := bitum.EncodeCensorCommentReply(
+		bitum.CensorCommentReply{
+			Receipt: hex.EncodeToString(s[:]),
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(ccrb), nil
+}
+
+func (p *TestPoliteiad) deleteComment(payload string) (string, error) {
+	dc, err := bitum.DecodeDeleteComment([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	// Sign delete comment
+	s := p.identity.SignMessage([]byte(dc.Signature))
+
+	// Prepare reply
+	dcrb, err := bitum.EncodeDeleteCommentReply(
+		bitum.DeleteCommentReply{
+			Receipt:   hex.EncodeToString(s[:]),
+			Timestamp: time.Now().Unix(),
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return string(dcrb), nil
+}
+
 // bitumExec executes the passed in plugin command.
 func (p *TestPoliteiad) bitumExec(pc v1.PluginCommand) (string, error) {
 	switch pc.Command {
 	case bitum.CmdStartVote:
 		return p.startVote(pc.Payload)
+	case bitum.CmdStartVoteRunoff:
+		return p.startVoteRunoff(pc.Payload)
 	case bitum.CmdAuthorizeVote:
 		return p.authorizeVote(pc.Payload)
+	case bitum.CmdCancelVote:
+		return p.cancelVote(pc.Payload)
+	case bitum.CmdNewComment:
+		return p.newComment(pc.Payload)
+	case bitum.CmdCensorComment:
+		return p.censorComment(pc.Payload)
+	case bitum.CmdDeleteComment:
+		return p.deleteComment(pc.Payload)
+	case bitum.CmdBestBlock:
+		return strconv.FormatUint(uint64(bestBlock), 10), nil
 	}
 	return "", fmt.Errorf("invalid plugin command")
 }
@@ -51,6 +51,8 @@ type TestPoliteiad struct {
 	authorizeVotes   map[string]map[string]bitum.AuthorizeVote // [token][version]AuthorizeVote
 	startVotes       map[string]bitum.StartVote                // [token]StartVote
 	startVoteReplies map[string]bitum.StartVoteReply           // [token]StartVoteReply
+	cancelVotes      map[string]bitum.CancelVote               // [token]CancelVote
+	commentIDs       map[string]uint64                         // [token]Last comment ID
 }
 
 func respondWithUserError(w http.ResponseWriter,
@@ -331,6 +333,59 @@ func (p *TestPoliteiad) handleSetVettedStatus(w http.ResponseWriter, r *http.Req
 		})
 }
 
+func (p *TestPoliteiad) handlePluginCommand(w http.ResponseWriter, r *http.Request) {
+	// Decode request
+	var pc v1.PluginCommand
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pc); err != nil {
+		respondWithUserError(w, v1.ErrorStatusInvalidRequestPayload, nil)
+		return
+	}
+
+	// Verify challenge
+	challenge, err := hex.DecodeString(pc.Challenge)
+	if err != nil || len(challenge) != v1.ChallengeSize {
+		respondWithUserError(w, v1.ErrorStatusInvalidChallenge, nil)
+		return
+	}
+	response := p.identity.SignMessage(challenge)
+
+	// Execute plugin command
+	var payload string
+	switch pc.ID {
+	case bitum.ID:
+		payload, err = p.bitumExec(pc)
+	default:
+		err = fmt.Errorf("invalid plugin")
+	}
+	if err != nil {
+		util.RespondWithJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Update cache.  Not every plugin command modifies plugin data.
+	_, err = p.cache.PluginExec(
+		cache.PluginCommand{
+			ID:             pc.ID,
+			Command:        pc.Command,
+			CommandPayload: pc.Payload,
+			ReplyPayload:   payload,
+		})
+	if err != nil && err != cache.ErrInvalidPluginCmd {
+		log.Printf("cache plugin exec: %v", err)
+	}
+
+	// Send response
+	util.RespondWithJSON(w, http.StatusOK,
+		v1.PluginCommandReply{
+			Response:  hex.EncodeToString(response[:]),
+			ID:        pc.ID,
+			Command:   pc.Command,
+			CommandID: pc.CommandID,
+			Payload:   payload,
+		})
+}
+
 // Plugin is a pass through function for plugin commands. The plugin command
 // is executed in politeiad and is then passed to the cache. This function
 // is intended to be used as a way to setup test data.
@@ -403,6 +458,8 @@ func New(t *testing.T, c cache.Cache) *TestPoliteiad {
 		authorizeVotes:   make(map[string]map[string]bitum.AuthorizeVote),
 		startVotes:       make(map[string]bitum.StartVote),
 		startVoteReplies: make(map[string]bitum.StartVoteReply),
+		cancelVotes:      make(map[string]bitum.CancelVote),
+		commentIDs:       make(map[string]uint64),
 	}
 
 	// Setup routes
@@ -410,6 +467,7 @@ func New(t *testing.T, c cache.Cache) *TestPoliteiad {
 	router.HandleFunc(v1.NewRecordRoute, p.handleNewRecord)
 	router.HandleFunc(v1.SetUnvettedStatusRoute, p.handleSetUnvettedStatus)
 	router.HandleFunc(v1.SetVettedStatusRoute, p.handleSetVettedStatus)
+	router.HandleFunc(v1.PluginCommandRoute, p.handlePluginCommand)
 
 	// Setup the test server
 	p.server = httptest.NewServer(router)
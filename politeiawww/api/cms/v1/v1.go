@@ -21,6 +21,7 @@ const (
 	RouteAdminInvoices       = "/admin/invoices"
 	RouteGeneratePayouts     = "/admin/generatepayouts"
 	RoutePayInvoices         = "/admin/payinvoices"
+	RouteSyncInvoices        = "/admin/syncinvoices"
 	RouteInvoiceComments     = "/invoices/{token:[A-z0-9]{64}}/comments"
 	RouteInvoiceExchangeRate = "/invoices/exchangerate"
 
@@ -328,3 +329,17 @@ type PayInvoices struct{}
 
 // PayInvoicesReply will be empty if no errors have occurred.
 type PayInvoicesReply struct{}
+
+// SyncInvoices compares the invoices in the cms database against the
+// politeiad inventory and applies any changes that are missing from the cms
+// database.  Only invoices whose version or status changed since the last
+// sync are fetched from politeiad and updated.
+type SyncInvoices struct{}
+
+// SyncInvoicesReply is used to reply to the SyncInvoices command.
+type SyncInvoicesReply struct {
+	Added     []string `json:"added"`     // Invoices that were missing from the cms database
+	Updated   []string `json:"updated"`   // Invoices whose version or status had diverged
+	Unchanged int      `json:"unchanged"` // Number of invoices that were already in sync
+	Missing   []string `json:"missing"`   // Invoices in the cms database that politeiad no longer has
+}
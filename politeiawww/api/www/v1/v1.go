@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -8,6 +9,7 @@ type ErrorStatusT int
 type PropStateT int
 type PropStatusT int
 type PropVoteStatusT int
+type VoteT int
 type UserManageActionT int
 type EmailNotificationT int
 
@@ -35,11 +37,14 @@ const (
 	RouteUserProposals            = "/user/proposals"
 	RouteUserProposalCredits      = "/user/proposals/credits"
 	RouteUserCommentsLikes        = "/user/proposals/{token:[A-z0-9]{64}}/commentslikes"
+	RouteUserComments             = "/user/{userid:[0-9a-zA-Z-]{36}}/comments"
+	RouteUserUnread               = "/user/unread"
 	RouteVerifyUserPayment        = "/user/verifypayment"
 	RouteUserPaymentsRescan       = "/user/payments/rescan"
 	RouteManageUser               = "/user/manage"
 	RouteEditUser                 = "/user/edit"
 	RouteUsers                    = "/users"
+	RouteCacheStats               = "/cachestats"
 	RouteTokenInventory           = "/proposals/tokeninventory"
 	RouteAllVetted                = "/proposals/vetted"
 	RouteAllUnvetted              = "/proposals/unvetted"
@@ -47,6 +52,8 @@ const (
 	RouteEditProposal             = "/proposals/edit"
 	RouteAuthorizeVote            = "/proposals/authorizevote"
 	RouteStartVote                = "/proposals/startvote"
+	RouteStartVoteRunoff          = "/proposals/startvoterunoff"
+	RouteCancelVote               = "/proposals/cancelvote"
 	RouteActiveVote               = "/proposals/activevote" // XXX rename to ActiveVotes
 	RouteCastVotes                = "/proposals/castvotes"
 	RouteAllVoteStatus            = "/proposals/votestatus"
@@ -56,11 +63,26 @@ const (
 	RouteProposalDetails          = "/proposals/{token:[A-z0-9]{64}}"
 	RouteSetProposalStatus        = "/proposals/{token:[A-z0-9]{64}}/status"
 	RouteCommentsGet              = "/proposals/{token:[A-z0-9]{64}}/comments"
+	RouteCommentRevisions         = "/proposals/{token:[A-z0-9]{64}}/comments/{commentid:[0-9]+}/revisions"
+	RouteCommentProof             = "/proposals/{token:[A-z0-9]{64}}/comments/{commentid:[0-9]+}/proof"
 	RouteVoteResults              = "/proposals/{token:[A-z0-9]{64}}/votes"
+	RouteVoteResultsBundle        = "/proposals/{token:[A-z0-9]{64}}/votes/bundle"
+	RouteVoteReceipt              = "/proposals/{token:[A-z0-9]{64}}/votes/{ticket:[A-z0-9]{64}}"
+	RouteVoteProof                = "/proposals/{token:[A-z0-9]{64}}/votes/{ticket:[A-z0-9]{64}}/proof"
+	RouteVoteTimeSeries           = "/proposals/{token:[A-z0-9]{64}}/votes/timeseries"
 	RouteVoteStatus               = "/proposals/{token:[A-z0-9]{64}}/votestatus"
 	RouteNewComment               = "/comments/new"
 	RouteLikeComment              = "/comments/like"
 	RouteCensorComment            = "/comments/censor"
+	RouteEditComment              = "/comments/edit"
+	RouteDeleteComment            = "/comments/delete"
+	RouteFlagComment              = "/comments/flag"
+	RouteFlaggedComments          = "/comments/flagged"
+	RouteDismissCommentFlags      = "/comments/flagged/dismiss"
+	RouteAppealCensorship         = "/comments/appeal"
+	RouteCensorshipAppeals        = "/comments/appeals"
+	RouteResolveAppeal            = "/comments/appeals/resolve"
+	RouteMarkCommentsRead         = "/comments/read"
 	RouteUnauthenticatedWebSocket = "/ws"
 	RouteAuthenticatedWebSocket   = "/aws"
 
@@ -107,6 +129,11 @@ const (
 	// accepted for comments
 	PolicyMaxCommentLength = 8000
 
+	// PolicyMaxCommentMentions is the maximum number of users that are
+	// notified of being mentioned in a single comment.  Any mentions
+	// beyond this are ignored.
+	PolicyMaxCommentMentions = 10
+
 	// ProposalListPageSize is the maximum number of proposals returned
 	// for the routes that return lists of proposals
 	ProposalListPageSize = 20
@@ -115,6 +142,29 @@ const (
 	// for the routes that return lists of users
 	UserListPageSize = 20
 
+	// CommentListPageSize is the maximum number of root comments
+	// returned per page when comments are requested in pages
+	CommentListPageSize = 20
+
+	// UserCommentsPageSize is the maximum number of comments returned
+	// per page by the user comments route
+	UserCommentsPageSize = 20
+
+	// Comment sort orders
+	CommentSortTop = "top" // Highest vote score first
+	CommentSortNew = "new" // Newest first
+	CommentSortOld = "old" // Oldest first
+
+	// Comment flag reasons
+	CommentFlagReasonSpam     = "spam"     // Spam or advertising
+	CommentFlagReasonAbuse    = "abuse"    // Abusive or harassing
+	CommentFlagReasonOffTopic = "offtopic" // Not related to the proposal
+	CommentFlagReasonOther    = "other"    // Anything else
+
+	// Censorship appeal resolutions
+	AppealActionUphold  = "uphold"  // Comment remains censored
+	AppealActionRestore = "restore" // Comment is restored
+
 	// Error status codes
 	ErrorStatusInvalid                     ErrorStatusT = 0
 	ErrorStatusInvalidEmailOrPassword      ErrorStatusT = 1
@@ -177,6 +227,22 @@ const (
 	ErrorStatusInvalidCensorshipToken      ErrorStatusT = 58
 	ErrorStatusEmailAlreadyVerified        ErrorStatusT = 59
 	ErrorStatusInvalidPassword             ErrorStatusT = 85
+	ErrorStatusCancelReasonCannotBeBlank   ErrorStatusT = 86
+	ErrorStatusVoteReceiptNotFound         ErrorStatusT = 87
+	ErrorStatusInvalidVotePolicy           ErrorStatusT = 88
+	ErrorStatusCannotEditComment           ErrorStatusT = 89
+	ErrorStatusUserNotCommentAuthor        ErrorStatusT = 90
+	ErrorStatusCannotDeleteComment         ErrorStatusT = 91
+	ErrorStatusInvalidCommentFlagReason    ErrorStatusT = 92
+	ErrorStatusCommentAlreadyFlagged       ErrorStatusT = 93
+	ErrorStatusCommentNotFlagged           ErrorStatusT = 94
+	ErrorStatusCannotAppealCensorship      ErrorStatusT = 95
+	ErrorStatusAppealNotFound              ErrorStatusT = 96
+	ErrorStatusInvalidAppealAction         ErrorStatusT = 97
+	ErrorStatusCannotResolveOwnCensorship  ErrorStatusT = 98
+	ErrorStatusRateLimited                 ErrorStatusT = 99
+	ErrorStatusJournalEntryNotAnchored     ErrorStatusT = 100
+	ErrorStatusCannotProveComment          ErrorStatusT = 101
 
 	// CMS Errors
 	ErrorStatusMalformedName                  ErrorStatusT = 60
@@ -237,6 +303,14 @@ const (
 	PropVoteStatusStarted       PropVoteStatusT = 3 // Proposal vote has been started
 	PropVoteStatusFinished      PropVoteStatusT = 4 // Proposal vote has been finished
 	PropVoteStatusDoesntExist   PropVoteStatusT = 5 // Proposal doesn't exist
+	PropVoteStatusCancelled     PropVoteStatusT = 6 // Proposal vote has been cancelled by an admin
+	PropVoteStatusScheduled     PropVoteStatusT = 7 // Proposal vote has been scheduled to start at a future block height
+
+	// Vote types
+	VoteTypeStandard       VoteT = 0 // Yes/no vote that must meet quorum and pass percentage
+	VoteTypeMultipleChoice VoteT = 1 // Single choice out of several options, plurality winner
+	VoteTypeApproval       VoteT = 2 // Tickets may approve several options, most approvals wins
+	VoteTypeRunoff         VoteT = 3 // Yes/no vote between several proposals, most approvals wins
 
 	// User manage actions
 	UserManageInvalid                         UserManageActionT = 0 // Invalid action type
@@ -248,6 +322,14 @@ const (
 	UserManageDeactivate                      UserManageActionT = 6
 	UserManageReactivate                      UserManageActionT = 7
 
+	// User manage actions that are taken on the comments of a user.
+	// They are recorded against the comment author and can not be
+	// submitted using ManageUser.
+	UserManageCensorComment       UserManageActionT = 8
+	UserManageDismissCommentFlags UserManageActionT = 9
+	UserManageUpholdCensorship    UserManageActionT = 10
+	UserManageRestoreComment      UserManageActionT = 11
+
 	// Authorize vote actions
 	// XXX these should be in bitumplugin
 	AuthVoteActionAuthorize = "authorize" // Authorize a proposal vote
@@ -263,6 +345,7 @@ const (
 	NotificationEmailAdminProposalVoteAuthorized EmailNotificationT = 1 << 6
 	NotificationEmailCommentOnMyProposal         EmailNotificationT = 1 << 7
 	NotificationEmailCommentOnMyComment          EmailNotificationT = 1 << 8
+	NotificationEmailCommentMention              EmailNotificationT = 1 << 9
 )
 
 var (
@@ -277,6 +360,15 @@ var (
 	PolicyUsernameSupportedChars = []string{
 		"a-z", "0-9", ".", ",", ":", ";", "-", "@", "+", "(", ")", "_"}
 
+	// PolicyCommentFlagReasons are the reasons that can be given when
+	// flagging a comment
+	PolicyCommentFlagReasons = []string{
+		CommentFlagReasonSpam,
+		CommentFlagReasonAbuse,
+		CommentFlagReasonOffTopic,
+		CommentFlagReasonOther,
+	}
+
 	// PoliteiaWWWAPIRoute is the prefix to the API route
 	PoliteiaWWWAPIRoute = fmt.Sprintf("/v%v", PoliteiaWWWAPIVersion)
 
@@ -372,6 +464,22 @@ var (
 		ErrorStatusInvalidInvoiceMonthYear:        "an invalid month/year was submitted on an invoice",
 		ErrorStatusInvalidExchangeRate:            "exchange rate was invalid or didn't match expected result",
 		ErrorStatusInvalidPassword:                "invalid password",
+		ErrorStatusCancelReasonCannotBeBlank:      "cancel vote reason cannot be blank",
+		ErrorStatusVoteReceiptNotFound:            "vote receipt not found",
+		ErrorStatusInvalidVotePolicy:              "invalid vote policy",
+		ErrorStatusCannotEditComment:              "cannot edit comment",
+		ErrorStatusUserNotCommentAuthor:           "user is not the comment author",
+		ErrorStatusCannotDeleteComment:            "cannot delete comment",
+		ErrorStatusInvalidCommentFlagReason:       "invalid comment flag reason",
+		ErrorStatusCommentAlreadyFlagged:          "comment already flagged by user",
+		ErrorStatusCommentNotFlagged:              "comment has not been flagged",
+		ErrorStatusCannotAppealCensorship:         "censorship cannot be appealed",
+		ErrorStatusAppealNotFound:                 "censorship appeal not found",
+		ErrorStatusInvalidAppealAction:            "invalid appeal action",
+		ErrorStatusCannotResolveOwnCensorship:     "cannot resolve appeal of own censorship",
+		ErrorStatusRateLimited:                    "rate limit exceeded",
+		ErrorStatusJournalEntryNotAnchored:        "journal entry has not been anchored yet",
+		ErrorStatusCannotProveComment:             "cannot prove censored or deleted comment",
 	}
 
 	// PropStatus converts propsal status codes to human readable text
@@ -392,6 +500,8 @@ var (
 		PropVoteStatusStarted:       "voting active",
 		PropVoteStatusFinished:      "voting finished",
 		PropVoteStatusDoesntExist:   "proposal does not exist",
+		PropVoteStatusCancelled:     "voting cancelled",
+		PropVoteStatusScheduled:     "voting scheduled",
 	}
 
 	// UserManageAction converts user edit actions to human readable text
@@ -404,6 +514,10 @@ var (
 		UserManageUnlock:                          "unlock user",
 		UserManageDeactivate:                      "deactivate user",
 		UserManageReactivate:                      "reactivate user",
+		UserManageCensorComment:                   "censor comment",
+		UserManageDismissCommentFlags:             "dismiss comment flags",
+		UserManageUpholdCensorship:                "uphold comment censorship",
+		UserManageRestoreComment:                  "restore censored comment",
 	}
 )
 
@@ -445,6 +559,7 @@ type ProposalRecord struct {
 	Signature           string      `json:"signature"`                     // Signature of merkle root
 	Files               []File      `json:"files"`                         // Files that make up the proposal
 	NumComments         uint        `json:"numcomments"`                   // Number of comments on the proposal
+	UnreadComments      uint64      `json:"unreadcomments,omitempty"`      // Number of comments the logged in user has not read
 	Version             string      `json:"version"`                       // Record version
 	StatusChangeMessage string      `json:"statuschangemessage,omitempty"` // Message associated to the status change
 	PublishedAt         int64       `json:"publishedat,omitempty"`         // The timestamp of when the proposal has been published
@@ -646,10 +761,15 @@ type UserPaymentsRescanReply struct {
 // If After is specified, the "page" returned starts after the proposal
 // whose censorship token is provided. If Before is specified, the "page"
 // returned starts before the proposal whose censorship token is provided.
+// TimestampAfter and TimestampBefore optionally restrict the list to the
+// proposals whose latest version was submitted within the given UNIX time
+// range.  Both bounds are exclusive.
 type UserProposals struct {
-	UserId string `schema:"userid"`
-	Before string `schema:"before"`
-	After  string `schema:"after"`
+	UserId          string `schema:"userid"`
+	Before          string `schema:"before"`
+	After           string `schema:"after"`
+	TimestampAfter  int64  `schema:"timestampafter"`
+	TimestampBefore int64  `schema:"timestampbefore"`
 }
 
 // UserProposalsReply replies to the UserProposals command with
@@ -799,11 +919,16 @@ type SetProposalStatusReply struct {
 // If After is specified, the "page" returned starts after the proposal whose
 // censorship token is provided. If Before is specified, the "page" returned
 // starts before the proposal whose censorship token is provided.
+// TimestampAfter and TimestampBefore optionally restrict the list to the
+// proposals whose latest version was submitted within the given UNIX time
+// range.  Both bounds are exclusive.
 //
 // Note: This call requires admin privileges.
 type GetAllUnvetted struct {
-	Before string `schema:"before"`
-	After  string `schema:"after"`
+	Before          string `schema:"before"`
+	After           string `schema:"after"`
+	TimestampAfter  int64  `schema:"timestampafter"`
+	TimestampBefore int64  `schema:"timestampbefore"`
 }
 
 // GetAllUnvettedReply is used to reply with a list of all unvetted proposals.
@@ -816,10 +941,17 @@ type GetAllUnvettedReply struct {
 // parameter, which specify a proposal's censorship token. If After is specified,
 // the "page" returned starts after the proposal whose censorship token is provided.
 // If Before is specified, the "page" returned starts before the proposal whose
-// censorship token is provided.
+// censorship token is provided. TimestampAfter and TimestampBefore optionally
+// restrict the list to the proposals whose latest version was submitted within
+// the given UNIX time range, both bounds being exclusive. VoteStatuses
+// optionally restricts the list to the proposals that have any of the given
+// vote statuses.
 type GetAllVetted struct {
-	Before string `schema:"before"`
-	After  string `schema:"after"`
+	Before          string            `schema:"before"`
+	After           string            `schema:"after"`
+	TimestampAfter  int64             `schema:"timestampafter"`
+	TimestampBefore int64             `schema:"timestampbefore"`
+	VoteStatuses    []PropVoteStatusT `schema:"votestatus"`
 }
 
 // GetAllVettedReply is used to reply with a list of vetted proposals.
@@ -834,22 +966,38 @@ type Policy struct{}
 // PolicyReply is used to reply to the policy command. It returns
 // the file upload restrictions set for Politeia.
 type PolicyReply struct {
-	MinPasswordLength          uint     `json:"minpasswordlength"`
-	MinUsernameLength          uint     `json:"minusernamelength"`
-	MaxUsernameLength          uint     `json:"maxusernamelength"`
-	UsernameSupportedChars     []string `json:"usernamesupportedchars"`
-	ProposalListPageSize       uint     `json:"proposallistpagesize"`
-	UserListPageSize           uint     `json:"userlistpagesize"`
-	MaxImages                  uint     `json:"maximages"`
-	MaxImageSize               uint     `json:"maximagesize"`
-	MaxMDs                     uint     `json:"maxmds"`
-	MaxMDSize                  uint     `json:"maxmdsize"`
-	ValidMIMETypes             []string `json:"validmimetypes"`
-	MinProposalNameLength      uint     `json:"minproposalnamelength"`
-	MaxProposalNameLength      uint     `json:"maxproposalnamelength"`
-	ProposalNameSupportedChars []string `json:"proposalnamesupportedchars"`
-	MaxCommentLength           uint     `json:"maxcommentlength"`
-	BackendPublicKey           string   `json:"backendpublickey"`
+	MinPasswordLength          uint         `json:"minpasswordlength"`
+	MinUsernameLength          uint         `json:"minusernamelength"`
+	MaxUsernameLength          uint         `json:"maxusernamelength"`
+	UsernameSupportedChars     []string     `json:"usernamesupportedchars"`
+	ProposalListPageSize       uint         `json:"proposallistpagesize"`
+	UserListPageSize           uint         `json:"userlistpagesize"`
+	CommentListPageSize        uint         `json:"commentlistpagesize"`
+	MaxImages                  uint         `json:"maximages"`
+	MaxImageSize               uint         `json:"maximagesize"`
+	MaxMDs                     uint         `json:"maxmds"`
+	MaxMDSize                  uint         `json:"maxmdsize"`
+	ValidMIMETypes             []string     `json:"validmimetypes"`
+	MinProposalNameLength      uint         `json:"minproposalnamelength"`
+	MaxProposalNameLength      uint         `json:"maxproposalnamelength"`
+	ProposalNameSupportedChars []string     `json:"proposalnamesupportedchars"`
+	MaxCommentLength           uint         `json:"maxcommentlength"`
+	MaxCommentMentions         uint         `json:"maxcommentmentions"`
+	CommentFlagReasons         []string     `json:"commentflagreasons"`
+	BackendPublicKey           string       `json:"backendpublickey"`
+	VotePolicies               []VotePolicy `json:"votepolicies"`
+}
+
+// VotePolicy is a named set of vote parameters that is defined by the server.
+// A policy can be used to start a vote instead of providing the parameters.
+// Options contains the IDs of the vote options that the policy allows.  Any
+// options are allowed when it is empty.
+type VotePolicy struct {
+	Name             string   `json:"name"`              // Policy name
+	Duration         uint32   `json:"duration"`          // Duration in blocks
+	QuorumPercentage uint32   `json:"quorumpercentage"`  // Percent of eligible votes required for quorum
+	PassPercentage   uint32   `json:"passpercentage"`    // Percent of total votes required to pass
+	Options          []string `json:"options,omitempty"` // Allowed vote option IDs
 }
 
 // VoteOption describes a single vote option.
@@ -862,11 +1010,25 @@ type VoteOption struct {
 // Vote represents the vote options for vote that is identified by its token.
 type Vote struct {
 	Token            string       `json:"token"`            // Token that identifies vote
+	Type             VoteT        `json:"type"`             // Vote type
 	Mask             uint64       `json:"mask"`             // Valid votebits
 	Duration         uint32       `json:"duration"`         // Duration in blocks
 	QuorumPercentage uint32       `json:"quorumpercentage"` // Percent of eligible votes required for quorum
 	PassPercentage   uint32       `json:"passpercentage"`   // Percent of total votes required to pass
 	Options          []VoteOption `json:"options"`          // Vote options
+
+	// RunoffTokens is set by the server and contains the tokens
+	// of all proposals that are part of the same runoff vote.
+	RunoffTokens []string `json:"runofftokens,omitempty"`
+
+	// AllowRevote allows a ticket to change its vote until the
+	// voting period ends.  Only the latest vote of a ticket is
+	// counted.
+	AllowRevote bool `json:"allowrevote,omitempty"`
+
+	// StartHeight schedules the vote to start at a future block
+	// height.  The vote starts immediately when it is not set.
+	StartHeight uint32 `json:"startheight,omitempty"`
 }
 
 // ActiveVote obtains all proposals that have active votes.
@@ -881,7 +1043,8 @@ type ProposalVoteTuple struct {
 
 // ActiveVoteReply returns all proposals that have active votes.
 type ActiveVoteReply struct {
-	Votes []ProposalVoteTuple `json:"votes"` // Active votes
+	Votes     []ProposalVoteTuple `json:"votes"`               // Active votes
+	Scheduled []ProposalVoteTuple `json:"scheduled,omitempty"` // Votes scheduled to start
 }
 
 // plugin commands
@@ -906,9 +1069,10 @@ type AuthorizeVoteReply struct {
 
 // StartVote starts the voting process for a proposal.
 type StartVote struct {
-	PublicKey string `json:"publickey"` // Key used for signature.
-	Vote      Vote   `json:"vote"`      // Vote
-	Signature string `json:"signature"` // Signature of Votehash
+	PublicKey string `json:"publickey"`        // Key used for signature.
+	Vote      Vote   `json:"vote"`             // Vote
+	Signature string `json:"signature"`        // Signature of Votehash
+	Policy    string `json:"policy,omitempty"` // Name of the vote policy to apply
 }
 
 // StartVoteReply returns the eligible ticket pool.
@@ -919,6 +1083,39 @@ type StartVoteReply struct {
 	EligibleTickets  []string `json:"eligibletickets"`  // Valid voting tickets
 }
 
+// StartVoteRunoff starts a runoff vote between several proposals.  All
+// proposals are voted on using the same ticket snapshot and must use the
+// runoff vote type with identical vote parameters.  Only the proposal with
+// the most approving votes can be approved.
+type StartVoteRunoff struct {
+	StartVotes []StartVote `json:"startvotes"` // Start vote for each proposal
+}
+
+// StartVoteRunoffReply returns the eligible ticket pool that is shared by all
+// proposals in the runoff vote.
+type StartVoteRunoffReply struct {
+	StartBlockHeight string   `json:"startblockheight"` // Block height
+	StartBlockHash   string   `json:"startblockhash"`   // Block hash
+	EndHeight        string   `json:"endheight"`        // Height of vote end
+	EligibleTickets  []string `json:"eligibletickets"`  // Valid voting tickets
+}
+
+// CancelVote cancels an active proposal vote.  The reason is mandatory.
+// Cancelling a runoff vote cancels the vote of every proposal in the runoff.
+type CancelVote struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	Reason    string `json:"reason"`    // Reason for cancelling the vote
+	Signature string `json:"signature"` // Signature of token+reason
+	PublicKey string `json:"publickey"` // Key used for signature
+}
+
+// CancelVoteReply returns the tokens of all proposals whose vote was
+// cancelled along with the server receipt.
+type CancelVoteReply struct {
+	Tokens  []string `json:"tokens"`  // Tokens of cancelled votes
+	Receipt string   `json:"receipt"` // Server signature of client signature
+}
+
 // CastVote is a signed vote.
 type CastVote struct {
 	Token     string `json:"token"`     // Proposal ID
@@ -955,6 +1152,88 @@ type VoteResultsReply struct {
 	StartVoteReply StartVoteReply `json:"startvotereply"` // Eligible tickets and other details
 }
 
+// VoteResultsBundle requests the signed results bundle of a finished proposal
+// vote.
+type VoteResultsBundle struct{}
+
+// VoteResultsBundleReply returns the signed results bundle of a finished
+// proposal vote.  The bundle is the politeiad bitum plugin VoteResultsBundle
+// and is forwarded as-is so that its politeiad signature can be verified
+// offline using politeia_verify.
+type VoteResultsBundleReply struct {
+	Bundle json.RawMessage `json:"bundle"` // Signed vote results bundle
+}
+
+// VoteReceipt requests the vote that was cast by a ticket along with the
+// receipt that was returned when the vote was cast.
+type VoteReceipt struct{}
+
+// VoteReceiptReply returns the vote that was cast by a ticket.  Receipt is
+// the politeiad signature of CastVote.Signature and can be verified using the
+// politeiad public key.
+type VoteReceiptReply struct {
+	CastVote CastVote `json:"castvote"` // Vote that was recorded
+	Receipt  string   `json:"receipt"`  // Server signature of CastVote.Signature
+}
+
+// VoteProof requests the proof that the latest vote of a ticket existed when
+// the ballot journal of the proposal was anchored in bitumtime.
+type VoteProof struct{}
+
+// CommentProof requests the proof that a comment existed when the comment
+// journal of the proposal was anchored in bitumtime.
+type CommentProof struct{}
+
+// JournalProofReply is the proof that a comment or a cast vote existed when
+// the journal head that contains it was anchored.  The journal head is the
+// merkle root of the SHA256 digests of the first Entries journal entries and
+// MerklePath leads from the digest of Entry to it; the bits of Index determine
+// on which side each sibling digest goes.  The journal head is anchored as an
+// individual digest so it can be verified with bitumtime directly.
+// ChainTimestamp and Transaction are not set until the anchor is confirmed.
+type JournalProofReply struct {
+	Journal        string   `json:"journal"`        // Journal filename
+	Entry          string   `json:"entry"`          // Journal entry
+	Index          uint64   `json:"index"`          // Position of the entry in the journal
+	Entries        uint64   `json:"entries"`        // Number of entries in the journal head
+	JournalHead    string   `json:"journalhead"`    // Merkle root of the journal head
+	MerklePath     []string `json:"merklepath"`     // Sibling digests from entry to journal head
+	Anchor         string   `json:"anchor"`         // Merkle root of the anchor
+	Timestamp      int64    `json:"timestamp"`      // Time the anchor was dropped
+	ChainTimestamp int64    `json:"chaintimestamp"` // Block timestamp of the anchor
+	Transaction    string   `json:"transaction"`    // Anchor transaction
+}
+
+// VoteTimeSeries requests the cumulative vote results of a proposal vote
+// bucketed by block height.  BucketSize is the number of blocks per bucket
+// and defaults to 1.
+type VoteTimeSeries struct {
+	BucketSize uint32 `json:"bucketsize"` // Number of blocks per bucket
+}
+
+// VoteTimeSeriesBucket contains the cumulative vote results at the end of a
+// range of block heights.  QuorumProgress is the percentage of the quorum that
+// had been reached and may exceed 100.
+type VoteTimeSeriesBucket struct {
+	StartHeight    uint32             `json:"startheight"`    // First block height of the bucket
+	EndHeight      uint32             `json:"endheight"`      // Last block height of the bucket
+	TotalVotes     uint64             `json:"totalvotes"`     // Number of tickets that had voted
+	QuorumProgress uint32             `json:"quorumprogress"` // Percent of the quorum that had been reached
+	OptionsResult  []VoteOptionResult `json:"optionsresult"`  // Cumulative votes received by each option
+}
+
+// VoteTimeSeriesReply returns the cumulative vote results of a proposal vote
+// bucketed by block height.  Buckets are ordered by block height and cover the
+// voting period up to the current best block.
+type VoteTimeSeriesReply struct {
+	Token              string                 `json:"token"`              // Censorship token
+	BucketSize         uint32                 `json:"bucketsize"`         // Number of blocks per bucket
+	NumOfEligibleVotes int                    `json:"numofeligiblevotes"` // Total number of eligible votes
+	QuorumPercentage   uint32                 `json:"quorumpercentage"`   // Percent of eligible votes required for quorum
+	QuorumVotes        uint64                 `json:"quorumvotes"`        // Number of votes required for quorum
+	Buckets            []VoteTimeSeriesBucket `json:"buckets"`            // Vote results per bucket
+}
+
 // Comment is the structure that describes the full server side content.  It
 // includes server side meta-data as well.
 type Comment struct {
@@ -972,6 +1251,17 @@ type Comment struct {
 	TotalVotes  uint64 `json:"totalvotes"`  // Total number of up/down votes
 	ResultVotes int64  `json:"resultvotes"` // Vote score
 	Censored    bool   `json:"censored"`    // Has this comment been censored
+	Deleted     bool   `json:"deleted"`     // Has this comment been deleted by its author
+
+	// Edit metadata generated by bitum plugin
+	Edits         uint32 `json:"edits,omitempty"`         // Number of times the comment was edited
+	EditTimestamp int64  `json:"edittimestamp,omitempty"` // UNIX timestamp of latest edit
+	EditSignature string `json:"editsignature,omitempty"` // Client signature of latest edit
+	EditPublicKey string `json:"editpublickey,omitempty"` // Pubkey used for EditSignature
+	EditReceipt   string `json:"editreceipt,omitempty"`   // Server signature of EditSignature
+
+	// Thread metadata
+	Replies uint64 `json:"replies"` // Number of direct replies
 
 	// Metadata generated by www
 	UserID   string `json:"userid"`   // User id
@@ -997,14 +1287,26 @@ type NewCommentReply struct {
 }
 
 // GetComments retrieve all comments for a given proposal.
+//
+// When any of Sort, Cursor or MaxDepth is set the comments are returned in
+// pages of CommentListPageSize root comments, along with the replies to those
+// root comments, and the reply contains a cursor for the next page.  MaxDepth
+// limits the depth of the returned comments, where root comments have a depth
+// of 1.  The Replies field of a comment can be used to tell whether replies
+// were left out.
 type GetComments struct {
-	Token string `json:"token"` // Censorship token
+	Token    string `json:"token" schema:"-"`                     // Censorship token
+	Sort     string `json:"sort,omitempty" schema:"sort"`         // Sort order
+	Cursor   string `json:"cursor,omitempty" schema:"cursor"`     // Page cursor
+	MaxDepth uint32 `json:"maxdepth,omitempty" schema:"maxdepth"` // Maximum comment depth
 }
 
-// GetCommentsReply returns the provided number of comments.
+// GetCommentsReply returns the provided number of comments.  NextCursor is
+// only set when there is another page of comments.
 type GetCommentsReply struct {
 	Comments   []Comment `json:"comments"`             // Comments
 	AccessTime int64     `json:"accesstime,omitempty"` // User Access Time
+	NextCursor string    `json:"nextcursor,omitempty"` // Cursor of next page
 }
 
 // LikeComment allows a user to up or down vote a comment.
@@ -1041,6 +1343,175 @@ type CensorCommentReply struct {
 	Receipt string `json:"receipt"` // Server signature of client signature
 }
 
+// EditComment allows the author of a comment to replace the comment text.
+// Every revision of the comment is retained and can be retrieved using the
+// CommentRevisions route.
+type EditComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Comment   string `json:"comment"`   // New comment text
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Comment
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// EditCommentReply returns the edited comment along with the receipt for the
+// edit.  The signature and receipt of the returned comment are those of the
+// original comment; the signatures of the edits are available through the
+// CommentRevisions route.
+type EditCommentReply struct {
+	Comment Comment `json:"comment"` // Edited comment
+	Receipt string  `json:"receipt"` // Server signature of edit signature
+}
+
+// CommentEdit is a single edit of a comment.  The receipt is the server
+// signature of the client signature.
+type CommentEdit struct {
+	Comment   string `json:"comment"`   // Comment text
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Comment
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+}
+
+// CommentRevisionsReply returns the comment as it was originally submitted
+// along with every edit that has been made to it, oldest first.
+type CommentRevisionsReply struct {
+	Comment Comment       `json:"comment"` // Original comment
+	Edits   []CommentEdit `json:"edits"`   // Edits, oldest first
+}
+
+// DeleteComment allows the author of a comment to delete it.  The comment
+// text is removed but the comment remains in place, along with its signature
+// and receipt, so that replies to it are not orphaned.
+type DeleteComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Signature string `json:"signature"` // Client signature of Token+CommentID
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// DeleteCommentReply returns a receipt if the comment was successfully
+// deleted.
+type DeleteCommentReply struct {
+	Receipt string `json:"receipt"` // Server signature of client signature
+}
+
+// FlagComment allows a user to flag a comment for admin attention.  A user
+// can only flag a comment once until its flags are dismissed.
+type FlagComment struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason category
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// FlagCommentReply returns a receipt if the comment was successfully flagged.
+type FlagCommentReply struct {
+	Receipt string `json:"receipt"` // Server signature of client signature
+}
+
+// CommentFlag is a flag that a user put on a comment.
+type CommentFlag struct {
+	UserID    string `json:"userid"`    // Unique user ID
+	Username  string `json:"username"`  // Unique username
+	Reason    string `json:"reason"`    // Reason category
+	Timestamp int64  `json:"timestamp"` // UNIX timestamp of flag
+}
+
+// FlaggedComment is a comment that has flags which have not been dismissed.
+type FlaggedComment struct {
+	Comment Comment       `json:"comment"` // Flagged comment
+	Flags   []CommentFlag `json:"flags"`   // Flags, oldest first
+}
+
+// FlaggedComments retrieves the comment moderation queue.  This call requires
+// admin privileges.
+type FlaggedComments struct{}
+
+// FlaggedCommentsReply returns all comments that have flags which have not
+// been dismissed, sorted by number of flags, most flagged first.
+type FlaggedCommentsReply struct {
+	Comments []FlaggedComment `json:"comments"`
+}
+
+// DismissCommentFlags removes all of the flags from a comment without
+// censoring it.  This call requires admin privileges.
+type DismissCommentFlags struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason the flags were dismissed
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// DismissCommentFlagsReply returns a receipt if the comment flags were
+// successfully dismissed.
+type DismissCommentFlagsReply struct {
+	Receipt string `json:"receipt"` // Server signature of client signature
+}
+
+// AppealCensorship allows the author of a censored comment to appeal the
+// censorship.  A censorship can only be appealed once.
+type AppealCensorship struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Reason    string `json:"reason"`    // Reason for the appeal
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// AppealCensorshipReply returns a receipt if the appeal was successfully
+// submitted.
+type AppealCensorshipReply struct {
+	Receipt string `json:"receipt"` // Server signature of client signature
+}
+
+// CensorshipAppeal is an appeal that has not been resolved.  The comment is
+// returned as it was prior to being censored.  CommentHash is the SHA256
+// digest of the censored comment text that was recorded by politeiad when
+// the comment was censored.
+type CensorshipAppeal struct {
+	Comment         Comment `json:"comment"`         // Comment prior to censorship
+	CommentHash     string  `json:"commenthash"`     // SHA256 digest of censored text
+	CensorUserID    string  `json:"censoruserid"`    // ID of admin that censored the comment
+	CensorUsername  string  `json:"censorusername"`  // Username of admin that censored the comment
+	CensorReason    string  `json:"censorreason"`    // Reason the comment was censored
+	CensorTimestamp int64   `json:"censortimestamp"` // UNIX timestamp of censorship
+	Reason          string  `json:"reason"`          // Reason for the appeal
+	Timestamp       int64   `json:"timestamp"`       // UNIX timestamp of appeal
+}
+
+// CensorshipAppeals retrieves all censorship appeals that have not been
+// resolved.  This call requires admin privileges.
+type CensorshipAppeals struct{}
+
+// CensorshipAppealsReply returns all censorship appeals that have not been
+// resolved, oldest first.
+type CensorshipAppealsReply struct {
+	Appeals []CensorshipAppeal `json:"appeals"`
+}
+
+// ResolveAppeal allows an admin to resolve a censorship appeal by either
+// upholding the censorship or restoring the comment.  An appeal must be
+// resolved by an admin other than the one that censored the comment.  This
+// call requires admin privileges.
+type ResolveAppeal struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	Action    string `json:"action"`    // Uphold or restore
+	Reason    string `json:"reason"`    // Reason for the resolution
+	Signature string `json:"signature"` // Client signature of Token+CommentID+Action+Reason
+	PublicKey string `json:"publickey"` // Pubkey used for signature
+}
+
+// ResolveAppealReply returns a receipt if the appeal was successfully
+// resolved.  The restored comment is returned when the comment is restored.
+type ResolveAppealReply struct {
+	Receipt string   `json:"receipt"`           // Server signature of client signature
+	Comment *Comment `json:"comment,omitempty"` // Restored comment
+}
+
 // CommentLike describes the voting action an user has given
 // to a comment (e.g: up or down vote)
 type CommentLike struct {
@@ -1059,6 +1530,51 @@ type UserCommentsLikesReply struct {
 	CommentsLikes []CommentLike `json:"commentslikes"`
 }
 
+// UserComments is used to retrieve the comments that a user has made across
+// all proposals, newest first.  Censored comments are only returned to
+// admins and only show that the comment was censored since the comment text
+// is removed on censorship.  The cursor of the first page is empty.
+type UserComments struct {
+	UserID string `json:"userid" schema:"-"`                // User ID
+	Cursor string `json:"cursor,omitempty" schema:"cursor"` // Page cursor
+}
+
+// UserCommentsReply returns a page of the user's comments.  NextCursor is only
+// set when there is another page of comments.
+type UserCommentsReply struct {
+	Comments   []Comment `json:"comments"`             // Comments
+	NextCursor string    `json:"nextcursor,omitempty"` // Cursor of next page
+}
+
+// MarkCommentsRead marks all of the comments of a proposal as read by the
+// logged in user.
+type MarkCommentsRead struct {
+	Token string `json:"token"` // Censorship token
+}
+
+// MarkCommentsReadReply returns the new access time of the proposal comments.
+type MarkCommentsReadReply struct {
+	AccessTime int64 `json:"accesstime"` // UNIX timestamp of access time
+}
+
+// UserUnread is used to retrieve the proposals that have comments which the
+// logged in user has not read since they last read the proposal comments.
+type UserUnread struct{}
+
+// UnreadProposal describes the unread comments of a proposal.
+type UnreadProposal struct {
+	Token          string `json:"token"`          // Censorship token
+	UnreadComments uint64 `json:"unreadcomments"` // Number of unread comments
+	LatestComment  int64  `json:"latestcomment"`  // Timestamp of newest unread comment
+	AccessTime     int64  `json:"accesstime"`     // Timestamp of last access
+}
+
+// UserUnreadReply returns the proposals with unread comments, the proposal
+// with the newest unread comment first.
+type UserUnreadReply struct {
+	Proposals []UnreadProposal `json:"proposals"`
+}
+
 // VoteOptionResult is a structure that describes a VotingOption along with the
 // number of votes it has received
 type VoteOptionResult struct {
@@ -1072,14 +1588,20 @@ type VoteStatus struct{}
 
 // VoteStatusReply describes the vote status for a given proposal
 type VoteStatusReply struct {
-	Token              string             `json:"token"`              // Censorship token
-	Status             PropVoteStatusT    `json:"status"`             // Vote status (finished, started, etc)
-	TotalVotes         uint64             `json:"totalvotes"`         // Proposal's total number of votes
-	OptionsResult      []VoteOptionResult `json:"optionsresult"`      // VoteOptionResult for each option
-	EndHeight          string             `json:"endheight"`          // Vote end height
-	NumOfEligibleVotes int                `json:"numofeligiblevotes"` // Total number of eligible votes
-	QuorumPercentage   uint32             `json:"quorumpercentage"`   // Percent of eligible votes required for quorum
-	PassPercentage     uint32             `json:"passpercentage"`     // Percent of total votes required to pass
+	Token              string             `json:"token"`                  // Censorship token
+	Status             PropVoteStatusT    `json:"status"`                 // Vote status (finished, started, etc)
+	Type               VoteT              `json:"type"`                   // Vote type
+	TotalVotes         uint64             `json:"totalvotes"`             // Proposal's total number of votes
+	OptionsResult      []VoteOptionResult `json:"optionsresult"`          // VoteOptionResult for each option
+	EndHeight          string             `json:"endheight"`              // Vote end height
+	NumOfEligibleVotes int                `json:"numofeligiblevotes"`     // Total number of eligible votes
+	QuorumPercentage   uint32             `json:"quorumpercentage"`       // Percent of eligible votes required for quorum
+	PassPercentage     uint32             `json:"passpercentage"`         // Percent of total votes required to pass
+	Winner             string             `json:"winner,omitempty"`       // ID of the winning option once the vote has finished
+	RunoffTokens       []string           `json:"runofftokens,omitempty"` // Tokens of all proposals in a runoff vote
+	RunoffWinner       string             `json:"runoffwinner,omitempty"` // Token of the runoff winner once the vote has finished
+	CancelReason       string             `json:"cancelreason,omitempty"` // Reason the vote was cancelled
+	StartHeight        uint32             `json:"startheight,omitempty"`  // Start height of a scheduled vote
 }
 
 // GetAllVoteStatus attempts to fetch the vote status of all public propsals
@@ -1190,13 +1712,35 @@ type TokenInventoryReply struct {
 	Approved  []string `json:"approved"`  // Tokens of all props that have been approved by a vote
 	Rejected  []string `json:"rejected"`  // Tokens of all props that have been rejected by a vote
 	Abandoned []string `json:"abandoned"` // Tokens of all props that have been abandoned
+	Cancelled []string `json:"cancelled"` // Tokens of all props whose vote has been cancelled
+	Scheduled []string `json:"scheduled"` // Tokens of all props with a scheduled vote
+}
+
+// CacheStats retrieves the hit/miss statistics of the politeiawww in-memory
+// read caches.  This is an admin only command.
+type CacheStats struct{}
+
+// ReadCacheStats contains the statistics of a single read cache.
+type ReadCacheStats struct {
+	Size    int    `json:"size"`    // Maximum number of entries
+	Entries int    `json:"entries"` // Current number of entries
+	Hits    uint64 `json:"hits"`    // Number of lookups served from memory
+	Misses  uint64 `json:"misses"`  // Number of lookups that fell through
+}
+
+// CacheStatsReply is used to reply to the CacheStats command.
+type CacheStatsReply struct {
+	Records       ReadCacheStats `json:"records"`       // Proposal records
+	Comments      ReadCacheStats `json:"comments"`      // Proposal comments
+	VoteSummaries ReadCacheStats `json:"votesummaries"` // Proposal vote summaries
 }
 
 // Websocket commands
 const (
-	WSCError     = "error"
-	WSCPing      = "ping"
-	WSCSubscribe = "subscribe"
+	WSCError          = "error"
+	WSCPing           = "ping"
+	WSCSubscribe      = "subscribe"
+	WSCCommentMention = "commentmention"
 )
 
 // WSHeader is required to be sent before any other command. The point is to
@@ -1225,3 +1769,13 @@ type WSSubscribe struct {
 type WSPing struct {
 	Timestamp int64 `json:"timestamp"` // Server side timestamp
 }
+
+// WSCommentMention is a server side push to notify a user that they were
+// mentioned in a comment.  This subscription requires authentication.
+type WSCommentMention struct {
+	Token     string `json:"token"`     // Proposal censorship token
+	CommentID string `json:"commentid"` // Comment ID
+	UserID    string `json:"userid"`    // ID of the comment author
+	Username  string `json:"username"`  // Username of the comment author
+	Timestamp int64  `json:"timestamp"` // UNIX timestamp of the comment
+}
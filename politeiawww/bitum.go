@@ -52,8 +52,17 @@ func (p *politeiawww) bitumGetComment(token, commentID string) (*bitumplugin.Com
 }
 
 // bitumGetComments sends the bitum plugin getcomments command to the cache
-// and returns all of the comments for the passed in proposal token.
+// and returns all of the comments for the passed in proposal token.  The
+// comments are served from the read cache when possible.
 func (p *politeiawww) bitumGetComments(token string) ([]bitumplugin.Comment, error) {
+	if dc, ok := p.cachedComments(token); ok {
+		return dc, nil
+	}
+
+	// The comments are only stored in the read cache if they
+	// were not invalidated while being read.
+	gen := p.readCache.comments.generation(token)
+
 	// Setup plugin command
 	gc := bitumplugin.GetComments{
 		Token: token,
@@ -81,9 +90,87 @@ func (p *politeiawww) bitumGetComments(token string) ([]bitumplugin.Comment, err
 		return nil, err
 	}
 
+	p.readCache.comments.putGeneration(token, gcr.Comments, gen)
+
 	return gcr.Comments, nil
 }
 
+// bitumGetCommentsPage returns a sorted page of comments for the provided
+// record from the cache.  Pages are not stored in the read cache.
+func (p *politeiawww) bitumGetCommentsPage(gc bitumplugin.GetComments) (*bitumplugin.GetCommentsReply, error) {
+	payload, err := bitumplugin.EncodeGetComments(gc)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdGetComments,
+		CommandPayload: string(payload),
+	}
+
+	// Get comments from the cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, fmt.Errorf("PluginExec: %v", err)
+	}
+
+	return bitumplugin.DecodeGetCommentsReply([]byte(reply.Payload))
+}
+
+// bitumUserComments sends the bitum plugin usercomments command to the cache
+// and returns the requested page of comments made by the passed in public
+// keys.
+func (p *politeiawww) bitumUserComments(uc bitumplugin.UserComments) (*bitumplugin.UserCommentsReply, error) {
+	payload, err := bitumplugin.EncodeUserComments(uc)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdUserComments,
+		CommandPayload: string(payload),
+	}
+
+	// Get user comments from the cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	return bitumplugin.DecodeUserCommentsReply([]byte(reply.Payload))
+}
+
+// bitumUnreadComments sends the bitum plugin unreadcomments command to the
+// cache and returns the number of unread comments of each of the passed in
+// proposals.  Proposals without unread comments are left out.
+func (p *politeiawww) bitumUnreadComments(uc bitumplugin.UnreadComments) (map[string]bitumplugin.UnreadComment, error) {
+	payload, err := bitumplugin.EncodeUnreadComments(uc)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdUnreadComments,
+		CommandPayload: string(payload),
+	}
+
+	// Get unread comment counts from the cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	ucr, err := bitumplugin.DecodeUnreadCommentsReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return ucr.Unread, nil
+}
+
 // bitumCommentLikes sends the bitum plugin commentlikes command to the cache
 // and returns all of the comment likes for the passed in comment.
 func (p *politeiawww) bitumCommentLikes(token, commentID string) ([]bitumplugin.LikeComment, error) {
@@ -118,6 +205,72 @@ func (p *politeiawww) bitumCommentLikes(token, commentID string) ([]bitumplugin.
 	return clr.CommentLikes, nil
 }
 
+// bitumCommentFlags sends the bitum plugin commentflags command to the cache
+// and returns the flags of the passed in comment that have not been
+// dismissed.
+func (p *politeiawww) bitumCommentFlags(token, commentID string) ([]bitumplugin.FlagComment, error) {
+	// Setup plugin command
+	cf := bitumplugin.CommentFlags{
+		Token:     token,
+		CommentID: commentID,
+	}
+
+	payload, err := bitumplugin.EncodeCommentFlags(cf)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdCommentFlags,
+		CommandPayload: string(payload),
+	}
+
+	// Get comment flags from cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	cfr, err := bitumplugin.DecodeCommentFlagsReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return cfr.Flags, nil
+}
+
+// bitumFlaggedComments sends the bitum plugin flaggedcomments command to the
+// cache and returns all comments that have flags which have not been
+// dismissed.
+func (p *politeiawww) bitumFlaggedComments() ([]bitumplugin.FlaggedComment, error) {
+	// Setup plugin command
+	payload, err := bitumplugin.EncodeFlaggedComments(
+		bitumplugin.FlaggedComments{})
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdFlaggedComments,
+		CommandPayload: string(payload),
+	}
+
+	// Get flagged comments from cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	fcr, err := bitumplugin.DecodeFlaggedCommentsReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return fcr.Comments, nil
+}
+
 // bitumPropCommentLikes sends the bitum plugin proposalcommentslikes command
 // to the cache and returns all of the comment likes for the passed in proposal
 // token.
@@ -219,6 +372,75 @@ func (p *politeiawww) bitumProposalVotes(token string) (*bitumplugin.VoteResults
 	return vrr, nil
 }
 
+// bitumVoteReceipt sends the bitum plugin votereceipt command to the cache
+// and returns the vote that the passed in ticket cast on the passed in
+// proposal.
+func (p *politeiawww) bitumVoteReceipt(token, ticket string) (*bitumplugin.VoteReceiptReply, error) {
+	// Setup plugin command
+	vr := bitumplugin.VoteReceipt{
+		Token:  token,
+		Ticket: ticket,
+	}
+
+	payload, err := bitumplugin.EncodeVoteReceipt(vr)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdVoteReceipt,
+		CommandPayload: string(payload),
+	}
+
+	// Get vote receipt from cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	vrr, err := bitumplugin.DecodeVoteReceiptReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return vrr, nil
+}
+
+// bitumVoteTimeSeries sends the bitum plugin votetimeseries command to the
+// cache and returns the vote bit counts per block height of the passed in
+// proposal.
+func (p *politeiawww) bitumVoteTimeSeries(token string) (*bitumplugin.VoteTimeSeriesReply, error) {
+	// Setup plugin command
+	vts := bitumplugin.VoteTimeSeries{
+		Token: token,
+	}
+
+	payload, err := bitumplugin.EncodeVoteTimeSeries(vts)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := cache.PluginCommand{
+		ID:             bitumplugin.ID,
+		Command:        bitumplugin.CmdVoteTimeSeries,
+		CommandPayload: string(payload),
+	}
+
+	// Get vote time series from cache
+	reply, err := p.cache.PluginExec(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	vtsr, err := bitumplugin.DecodeVoteTimeSeriesReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return vtsr, nil
+}
+
 // bitumInventory sends the bitum plugin inventory command to the cache and
 // returns the bitum plugin inventory.
 func (p *politeiawww) bitumInventory() (*bitumplugin.InventoryReply, error) {
@@ -332,9 +554,63 @@ func (p *politeiawww) bitumLoadVoteResults(bestBlock uint64) (*bitumplugin.LoadV
 	return reply, nil
 }
 
+// bitumJournalProof sends the bitum plugin journalproof command to politeiad
+// and returns the proof that a comment or a cast vote was anchored.  Journal
+// anchors are not kept in the cache.
+func (p *politeiawww) bitumJournalProof(jp bitumplugin.JournalProof) (*bitumplugin.JournalProofReply, error) {
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := bitumplugin.EncodeJournalProof(jp)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdJournalProof,
+		CommandID: bitumplugin.CmdJournalProof,
+		Payload:   string(payload),
+	}
+
+	// Send plugin command to politeiad
+	respBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response
+	var pcr pd.PluginCommandReply
+	err = json.Unmarshal(respBody, &pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, pcr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return bitumplugin.DecodeJournalProofReply([]byte(pcr.Payload))
+}
+
 // bitumVoteSummary uses the bitum plugin vote summary command to request a
-// vote summary for a specific proposal from the cache.
+// vote summary for a specific proposal from the cache.  The vote summary is
+// served from the read cache when possible.
 func (p *politeiawww) bitumVoteSummary(token string) (*bitumplugin.VoteSummaryReply, error) {
+	if vs, ok := p.cachedVoteSummary(token); ok {
+		return vs, nil
+	}
+
+	// The vote summary is only stored in the read cache if it
+	// was not invalidated while being read.
+	gen := p.readCache.voteSummaries.generation(token)
+
 	v := bitumplugin.VoteSummary{
 		Token: token,
 	}
@@ -359,5 +635,7 @@ func (p *politeiawww) bitumVoteSummary(token string) (*bitumplugin.VoteSummaryRe
 		return nil, err
 	}
 
+	p.readCache.voteSummaries.putGeneration(token, *reply, gen)
+
 	return reply, nil
 }
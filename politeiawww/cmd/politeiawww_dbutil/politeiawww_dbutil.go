@@ -28,6 +28,10 @@ import (
 	"github.com/bitum-project/bitumd/chaincfg"
 	"github.com/bitum-project/politeia/bitumplugin"
 	"github.com/bitum-project/politeia/politeiad/backend/gitbe"
+	cachedb "github.com/bitum-project/politeia/politeiad/cache/cockroachdb"
+	"github.com/bitum-project/politeia/politeiawww/cmsdatabase"
+	cmscockroachdb "github.com/bitum-project/politeia/politeiawww/cmsdatabase/cockroachdb"
+	cmssqlite "github.com/bitum-project/politeia/politeiawww/cmsdatabase/sqlite"
 	"github.com/bitum-project/politeia/politeiawww/sharedconfig"
 	"github.com/bitum-project/politeia/politeiawww/user"
 	"github.com/bitum-project/politeia/politeiawww/user/cockroachdb"
@@ -50,10 +54,21 @@ const (
 	commentsJournalFilename = "comments.journal"
 	proposalMDFilename      = "00.metadata.txt"
 
+	// CMS database options
+	cmsDBCockroach  = "cockroachdb"
+	cmsDBSqlite     = "sqlite"
+	cmsDBSqliteFile = "cms.db"
+
 	// Journal actions
 	journalActionAdd     = "add"     // Add entry
 	journalActionDel     = "del"     // Delete entry
 	journalActionAddLike = "addlike" // Add comment like
+	journalActionEdit    = "edit"    // Edit comment
+	journalActionDelete  = "delete"  // Delete comment by author
+	journalActionFlag    = "flag"    // Flag comment
+	journalActionDismiss = "dismiss" // Dismiss comment flags
+	journalActionAppeal  = "appeal"  // Appeal comment censorship
+	journalActionResolve = "resolve" // Resolve censorship appeal
 )
 
 var (
@@ -73,6 +88,7 @@ var (
 	clientCert    = flag.String("clientcert", defaultClientCert, "")
 	clientKey     = flag.String("clientkey", defaultClientKey, "")
 	encryptionKey = flag.String("encryptionkey", defaultEncryptionKey, "")
+	cmsDB         = flag.String("cmsdb", cmsDBCockroach, "")
 
 	// Commands
 	addCredits = flag.Bool("adbitumedits", false, "")
@@ -81,6 +97,7 @@ var (
 	stubUsers  = flag.Bool("stubusers", false, "")
 	migrate    = flag.Bool("migrate", false, "")
 	createKey  = flag.Bool("createkey", false, "")
+	cmsSync    = flag.Bool("cmssync", false, "")
 
 	network string // Mainnet or testnet
 	// XXX ldb should be abstracted away. dbutil commands should use
@@ -117,6 +134,9 @@ const usageMsg = `politeiawww_dbutil usage:
     -encryptionkey string
           File containing the CockroachDB encryption key
           (default osDataDir/politeiawww/sbox.key)
+    -cmsdb string
+          CMS database to sync when using -cmssync (cockroachdb or sqlite)
+          (default cockroachdb)
 
   Commands
     -adbitumedits
@@ -147,6 +167,10 @@ const usageMsg = `politeiawww_dbutil usage:
           Migrate a LevelDB user database to CockroachDB
           Required DB flag : None
           Args             : None
+    -cmssync
+          Sync the CMS database with the invoices in the politeiad cache
+          Required DB flag : -cockroachdb
+          Args             : None
 `
 
 type proposalMetadata struct {
@@ -369,6 +393,8 @@ func cmdAddCredits() error {
 	return nil
 }
 
+// replayCommentsJournal replays the comments journal at the passed in path
+// and adds the public keys of every journal entry to pubkeys.
 func replayCommentsJournal(path string, pubkeys map[string]struct{}) error {
 	b, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -410,6 +436,54 @@ func replayCommentsJournal(path string, pubkeys map[string]struct{}) error {
 			}
 			pubkeys[lc.PublicKey] = struct{}{}
 
+		case journalActionEdit:
+			var ec bitumplugin.EditComment
+			err = d.Decode(&ec)
+			if err != nil {
+				return fmt.Errorf("journal edit: %v", err)
+			}
+			pubkeys[ec.PublicKey] = struct{}{}
+
+		case journalActionDelete:
+			var dc bitumplugin.DeleteComment
+			err = d.Decode(&dc)
+			if err != nil {
+				return fmt.Errorf("journal delete: %v", err)
+			}
+			pubkeys[dc.PublicKey] = struct{}{}
+
+		case journalActionFlag:
+			var fc bitumplugin.FlagComment
+			err = d.Decode(&fc)
+			if err != nil {
+				return fmt.Errorf("journal flag: %v", err)
+			}
+			pubkeys[fc.PublicKey] = struct{}{}
+
+		case journalActionDismiss:
+			var dcf bitumplugin.DismissCommentFlags
+			err = d.Decode(&dcf)
+			if err != nil {
+				return fmt.Errorf("journal dismiss: %v", err)
+			}
+			pubkeys[dcf.PublicKey] = struct{}{}
+
+		case journalActionAppeal:
+			var ac bitumplugin.AppealCensorship
+			err = d.Decode(&ac)
+			if err != nil {
+				return fmt.Errorf("journal appeal: %v", err)
+			}
+			pubkeys[ac.PublicKey] = struct{}{}
+
+		case journalActionResolve:
+			var ra bitumplugin.ResolveAppeal
+			err = d.Decode(&ra)
+			if err != nil {
+				return fmt.Errorf("journal resolve: %v", err)
+			}
+			pubkeys[ra.PublicKey] = struct{}{}
+
 		default:
 			return fmt.Errorf("invalid action: %v",
 				action.Action)
@@ -718,6 +792,102 @@ func cmdCreateKey() error {
 	return nil
 }
 
+func cmdCMSSync() error {
+	// Open the cms database
+	var (
+		cmsdb cmsdatabase.Database
+		err   error
+	)
+	switch *cmsDB {
+	case cmsDBCockroach:
+		cmsdb, err = cmscockroachdb.New(*host, network, *rootCert,
+			*clientCert, *clientKey)
+	case cmsDBSqlite:
+		cmsdb, err = cmssqlite.New(filepath.Join(*dataDir, network,
+			cmsDBSqliteFile))
+	default:
+		err = fmt.Errorf("invalid cmsdb '%v'", *cmsDB)
+	}
+	if err != nil {
+		return fmt.Errorf("new cmsdb: %v", err)
+	}
+	defer cmsdb.Close()
+
+	err = cmsdb.Setup()
+	if err != nil {
+		return fmt.Errorf("cmsdb setup: %v", err)
+	}
+
+	// Read the invoices from the politeiad cache
+	cdb, err := cachedb.New(cachedb.UserPoliteiawww, *host, network,
+		*rootCert, *clientCert, *clientKey)
+	if err != nil {
+		return fmt.Errorf("new cache: %v", err)
+	}
+	defer cdb.Close()
+
+	records, err := cdb.Inventory()
+	if err != nil {
+		return fmt.Errorf("cache inventory: %v", err)
+	}
+
+	// Map the public keys to user IDs
+	userIDs := make(map[string]string) // [pubkey]userID
+	err = userDB.AllUsers(func(u *user.User) {
+		for _, id := range u.Identities {
+			userIDs[hex.EncodeToString(id.Key[:])] = u.ID.String()
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("all users: %v", err)
+	}
+
+	invoices := make(map[string]cmsdatabase.Invoice, len(records))
+	inventory := make([]cmsdatabase.SyncState, 0, len(records))
+	for _, r := range records {
+		inv, err := cmsdatabase.ConvertInvoiceFromCache(r)
+		if err != nil {
+			return fmt.Errorf("convert invoice %v: %v",
+				r.CensorshipRecord.Token, err)
+		}
+		invoices[inv.Token] = *inv
+		inventory = append(inventory, cmsdatabase.SyncState{
+			Token:   inv.Token,
+			Version: inv.Version,
+			Status:  inv.Status,
+		})
+	}
+
+	sr, err := cmsdatabase.Sync(cmsdb, inventory,
+		func(token string) (*cmsdatabase.Invoice, error) {
+			inv := invoices[token]
+			userID, ok := userIDs[inv.PublicKey]
+			if !ok {
+				fmt.Printf("User not found for invoice %v pubkey %v\n",
+					inv.Token, inv.PublicKey)
+			}
+			inv.UserID = userID
+			return &inv, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range sr.Added {
+		fmt.Printf("Added    : %v\n", v)
+	}
+	for _, v := range sr.Updated {
+		fmt.Printf("Updated  : %v\n", v)
+	}
+	for _, v := range sr.Missing {
+		fmt.Printf("Missing  : %v\n", v)
+	}
+	fmt.Printf("Done! %v added, %v updated, %v unchanged, %v missing\n",
+		len(sr.Added), len(sr.Updated), sr.Unchanged, len(sr.Missing))
+
+	return nil
+}
+
 func validateCockroachParams() error {
 	// Validate host
 	_, err := url.Parse(*host)
@@ -781,6 +951,12 @@ func _main() error {
 			return fmt.Errorf("missing database flag; must use " +
 				"either -leveldb or -cockroachdb")
 		}
+	case *cmsSync:
+		// These commands must be run with -cockroachdb
+		if !*cockroach {
+			return fmt.Errorf("missing database flag; must use " +
+				"-cockroachdb with this command")
+		}
 	case *dump:
 		// These commands must be run with -leveldb
 		if !*level {
@@ -845,6 +1021,8 @@ func _main() error {
 		return cmdMigrate()
 	case *createKey:
 		return cmdCreateKey()
+	case *cmsSync:
+		return cmdCMSSync()
 	default:
 		flag.Usage()
 	}
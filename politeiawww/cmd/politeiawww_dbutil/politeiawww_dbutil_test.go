@@ -0,0 +1,103 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/backend/gitbe"
+)
+
+// journalEntry returns a comments journal entry for the passed in action and
+// payload in the format that is written by politeiad.
+func journalEntry(t *testing.T, action string, payload interface{}) string {
+	t.Helper()
+
+	a, err := json.Marshal(gitbe.JournalAction{
+		Version: "1",
+		Action:  action,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(a) + string(p) + "\n"
+}
+
+func TestReplayCommentsJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "politeiawww_dbutil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Each journal action uses a different public key so that it
+	// can be verified that the key of every action was collected.
+	key := func(c string) string {
+		return strings.Repeat(c, 64)
+	}
+	entries := []string{
+		journalEntry(t, journalActionAdd,
+			bitumplugin.Comment{CommentID: "1", PublicKey: key("1")}),
+		journalEntry(t, journalActionAddLike,
+			bitumplugin.LikeComment{CommentID: "1", PublicKey: key("2")}),
+		journalEntry(t, journalActionEdit,
+			bitumplugin.EditComment{CommentID: "1", PublicKey: key("3")}),
+		journalEntry(t, journalActionFlag,
+			bitumplugin.FlagComment{CommentID: "1", PublicKey: key("4")}),
+		journalEntry(t, journalActionDismiss,
+			bitumplugin.DismissCommentFlags{CommentID: "1", PublicKey: key("5")}),
+		journalEntry(t, journalActionDel,
+			bitumplugin.CensorComment{CommentID: "1", PublicKey: key("6")}),
+		journalEntry(t, journalActionAppeal,
+			bitumplugin.AppealCensorship{CommentID: "1", PublicKey: key("7")}),
+		journalEntry(t, journalActionResolve,
+			bitumplugin.ResolveAppeal{CommentID: "1", PublicKey: key("8")}),
+		journalEntry(t, journalActionDelete,
+			bitumplugin.DeleteComment{CommentID: "1", PublicKey: key("9")}),
+	}
+
+	path := filepath.Join(dir, commentsJournalFilename)
+	err = ioutil.WriteFile(path, []byte(strings.Join(entries, "")), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubkeys := make(map[string]struct{})
+	err = replayCommentsJournal(path, pubkeys)
+	if err != nil {
+		t.Fatalf("replayCommentsJournal: %v", err)
+	}
+	if len(pubkeys) != len(entries) {
+		t.Fatalf("got %v public keys, want %v", len(pubkeys), len(entries))
+	}
+	for i := range entries {
+		k := key(string('1' + byte(i)))
+		if _, ok := pubkeys[k]; !ok {
+			t.Errorf("public key %v not found", k)
+		}
+	}
+
+	// Unknown actions are rejected
+	err = ioutil.WriteFile(path, []byte(journalEntry(t, "unknown",
+		bitumplugin.Comment{})), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = replayCommentsJournal(path, make(map[string]struct{}))
+	if err == nil {
+		t.Fatalf("replayCommentsJournal: want error for unknown action")
+	}
+}
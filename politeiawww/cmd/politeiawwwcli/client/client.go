@@ -1056,6 +1056,314 @@ func (c *Client) CensorComment(cc *v1.CensorComment) (*v1.CensorCommentReply, er
 	return &ccr, nil
 }
 
+// DeleteComment deletes the specified proposal comment.
+func (c *Client) DeleteComment(dc *v1.DeleteComment) (*v1.DeleteCommentReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteDeleteComment, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	var dcr v1.DeleteCommentReply
+	err = json.Unmarshal(responseBody, &dcr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DeleteCommentReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(dcr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dcr, nil
+}
+
+// DismissCommentFlags dismisses the flags of the specified proposal comment.
+func (c *Client) DismissCommentFlags(dcf *v1.DismissCommentFlags) (*v1.DismissCommentFlagsReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteDismissCommentFlags, dcf)
+	if err != nil {
+		return nil, err
+	}
+
+	var dcfr v1.DismissCommentFlagsReply
+	err = json.Unmarshal(responseBody, &dcfr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DismissCommentFlagsReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(dcfr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dcfr, nil
+}
+
+// FlagComment flags the specified proposal comment.
+func (c *Client) FlagComment(fc *v1.FlagComment) (*v1.FlagCommentReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteFlagComment, fc)
+	if err != nil {
+		return nil, err
+	}
+
+	var fcr v1.FlagCommentReply
+	err = json.Unmarshal(responseBody, &fcr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal FlagCommentReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(fcr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &fcr, nil
+}
+
+// MarkCommentsRead marks all of the comments of a proposal as read by the
+// logged in user.
+func (c *Client) MarkCommentsRead(mcr *v1.MarkCommentsRead) (*v1.MarkCommentsReadReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteMarkCommentsRead, mcr)
+	if err != nil {
+		return nil, err
+	}
+
+	var mcrr v1.MarkCommentsReadReply
+	err = json.Unmarshal(responseBody, &mcrr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal MarkCommentsReadReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(mcrr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &mcrr, nil
+}
+
+// UserUnread retrieves the proposals that have comments which the logged in
+// user has not read.
+func (c *Client) UserUnread() (*v1.UserUnreadReply, error) {
+	responseBody, err := c.makeRequest("GET", v1.RouteUserUnread, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var uur v1.UserUnreadReply
+	err = json.Unmarshal(responseBody, &uur)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal UserUnreadReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(uur)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &uur, nil
+}
+
+// UserComments retrieves a page of the comments that the specified user has
+// made across all proposals.
+func (c *Client) UserComments(uc *v1.UserComments) (*v1.UserCommentsReply, error) {
+	responseBody, err := c.makeRequest("GET",
+		"/user/"+uc.UserID+"/comments", uc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ucr v1.UserCommentsReply
+	err = json.Unmarshal(responseBody, &ucr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal UserCommentsReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(ucr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ucr, nil
+}
+
+// CommentProof retrieves the proof that a comment was anchored.
+func (c *Client) CommentProof(token, commentID string) (*v1.JournalProofReply, error) {
+	responseBody, err := c.makeRequest("GET",
+		"/proposals/"+token+"/comments/"+commentID+"/proof", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jpr v1.JournalProofReply
+	err = json.Unmarshal(responseBody, &jpr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JournalProofReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(jpr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &jpr, nil
+}
+
+// VoteProof retrieves the proof that the latest vote of a ticket was
+// anchored.
+func (c *Client) VoteProof(token, ticket string) (*v1.JournalProofReply, error) {
+	responseBody, err := c.makeRequest("GET",
+		"/proposals/"+token+"/votes/"+ticket+"/proof", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jpr v1.JournalProofReply
+	err = json.Unmarshal(responseBody, &jpr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JournalProofReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(jpr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &jpr, nil
+}
+
+// FlaggedComments retrieves the comment moderation queue.
+func (c *Client) FlaggedComments() (*v1.FlaggedCommentsReply, error) {
+	responseBody, err := c.makeRequest("GET", v1.RouteFlaggedComments, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fcr v1.FlaggedCommentsReply
+	err = json.Unmarshal(responseBody, &fcr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal FlaggedCommentsReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(fcr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &fcr, nil
+}
+
+// AppealCensorship appeals the censorship of the specified proposal comment.
+func (c *Client) AppealCensorship(ac *v1.AppealCensorship) (*v1.AppealCensorshipReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteAppealCensorship, ac)
+	if err != nil {
+		return nil, err
+	}
+
+	var acr v1.AppealCensorshipReply
+	err = json.Unmarshal(responseBody, &acr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal AppealCensorshipReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(acr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &acr, nil
+}
+
+// CensorshipAppeals retrieves the censorship appeals that have not been
+// resolved.
+func (c *Client) CensorshipAppeals() (*v1.CensorshipAppealsReply, error) {
+	responseBody, err := c.makeRequest("GET", v1.RouteCensorshipAppeals, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var car v1.CensorshipAppealsReply
+	err = json.Unmarshal(responseBody, &car)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal CensorshipAppealsReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(car)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &car, nil
+}
+
+// ResolveAppeal resolves the censorship appeal of the specified proposal
+// comment.
+func (c *Client) ResolveAppeal(ra *v1.ResolveAppeal) (*v1.ResolveAppealReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteResolveAppeal, ra)
+	if err != nil {
+		return nil, err
+	}
+
+	var rar v1.ResolveAppealReply
+	err = json.Unmarshal(responseBody, &rar)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal ResolveAppealReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(rar)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &rar, nil
+}
+
+// EditComment edits the specified proposal comment.
+func (c *Client) EditComment(ec *v1.EditComment) (*v1.EditCommentReply, error) {
+	responseBody, err := c.makeRequest("POST", v1.RouteEditComment, ec)
+	if err != nil {
+		return nil, err
+	}
+
+	var ecr v1.EditCommentReply
+	err = json.Unmarshal(responseBody, &ecr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal EditCommentReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(ecr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ecr, nil
+}
+
 // StartVote starts the voting period for the specified proposal.
 func (c *Client) StartVote(sv *v1.StartVote) (*v1.StartVoteReply, error) {
 	responseBody, err := c.makeRequest("POST", v1.RouteStartVote, sv)
@@ -0,0 +1,105 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// AppealCensorshipCmd appeals the censorship of a proposal comment.
+type AppealCensorshipCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+		Reason    string `positional-arg-name:"reason"`    // Reason for appeal
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the appeal censorship command.
+func (cmd *AppealCensorshipCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+	reason := cmd.Args.Reason
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup appeal censorship request
+	s := cfg.Identity.SignMessage([]byte(token + commentID + reason))
+	signature := hex.EncodeToString(s[:])
+	ac := &v1.AppealCensorship{
+		Token:     token,
+		CommentID: commentID,
+		Reason:    reason,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(ac)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	acr, err := client.AppealCensorship(ac)
+	if err != nil {
+		return err
+	}
+
+	// Validate appeal censorship receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(acr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(acr)
+}
+
+// appealCensorshipHelpMsg is the output of the help command when
+// 'appealcensorship' is specified.
+const appealCensorshipHelpMsg = `appealcensorship "token" "commentID" "reason"
+
+Appeal the censorship of a comment. Must be the comment author. A censorship
+can only be appealed once.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+3. reason      (string, required)   Reason for the appeal
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "reason":     (string)  Reason for the appeal
+  "signature":  (string)  Signature of appeal (Token+CommentID+Reason)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "receipt":  (string)  Server signature of appeal signature
+}`
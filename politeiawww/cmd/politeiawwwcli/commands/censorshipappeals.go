@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+// CensorshipAppealsCmd retrieves the censorship appeals that have not been
+// resolved.
+type CensorshipAppealsCmd struct{}
+
+// Execute executes the censorship appeals command.
+func (cmd *CensorshipAppealsCmd) Execute(args []string) error {
+	car, err := client.CensorshipAppeals()
+	if err != nil {
+		return err
+	}
+	return printJSON(car)
+}
+
+// censorshipAppealsHelpMsg is the output of the help command when
+// 'censorshipappeals' is specified.
+const censorshipAppealsHelpMsg = `censorshipappeals
+
+Get all censorship appeals that have not been resolved, oldest first.
+Requires admin privileges.
+
+Arguments:
+None
+
+Response:
+{
+  "appeals": [
+    {
+      "comment": {
+        "token":        (string)  Censorship token
+        "parentid":     (string)  Id of comment (defaults to '0' (top-level))
+        "comment":      (string)  Comment as it was prior to censorship
+        "signature":    (string)  Signature of comment (Token+ParentID+Comment)
+        "publickey":    (string)  Public key of user
+        "commentid":    (string)  Id of the comment
+        "receipt":      (string)  Server signature of the comment signature
+        "timestamp":    (int64)   Received UNIX timestamp
+        "totalvotes":   (uint64)  Total number of up/down votes
+        "resultvotes":  (int64)   Vote score
+        "censored":     (bool)    If comment has been censored
+        "userid":       (string)  User id
+        "username":     (string)  Username
+      },
+      "commenthash":      (string)  SHA256 digest of the censored comment text
+      "censoruserid":     (string)  Id of the admin that censored the comment
+      "censorusername":   (string)  Username of the admin that censored the comment
+      "censorreason":     (string)  Reason the comment was censored
+      "censortimestamp":  (int64)   UNIX timestamp of the censorship
+      "reason":           (string)  Reason for the appeal
+      "timestamp":        (int64)   UNIX timestamp of the appeal
+    }
+  ]
+}`
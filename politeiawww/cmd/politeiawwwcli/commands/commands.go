@@ -17,6 +17,7 @@ import (
 	"github.com/agl/ed25519"
 	"github.com/bitum-project/bitumd/chaincfg/chainhash"
 	"github.com/bitum-project/bitumtime/merkle"
+	"github.com/bitum-project/politeia/bitumplugin"
 	"github.com/bitum-project/politeia/politeiad/api/v1/identity"
 	"github.com/bitum-project/politeia/politeiad/api/v1/mime"
 	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
@@ -58,10 +59,18 @@ var (
 type Cmds struct {
 	AdminInvoices       AdminInvoicesCmd       `command:"admininvoices" description:"(admin) get all invoices (optional by month/year and/or status)"`
 	ActiveVotes         ActiveVotesCmd         `command:"activevotes" description:"(public) get the proposals that are being voted on"`
+	AppealCensorship    AppealCensorshipCmd    `command:"appealcensorship" description:"(user)   appeal the censorship of a proposal comment (must be comment author)"`
 	AuthorizeVote       AuthorizeVoteCmd       `command:"authorizevote" description:"(user)   authorize a proposal vote (must be proposal author)"`
 	CensorComment       CensorCommentCmd       `command:"censorcomment" description:"(admin)  censor a proposal comment"`
+	CensorshipAppeals   CensorshipAppealsCmd   `command:"censorshipappeals" description:"(admin)  get the censorship appeals that have not been resolved"`
 	ChangePassword      ChangePasswordCmd      `command:"changepassword" description:"(user)   change the password for the logged in user"`
 	ChangeUsername      ChangeUsernameCmd      `command:"changeusername" description:"(user)   change the username for the logged in user"`
+	CommentProof        CommentProofCmd        `command:"commentproof" description:"(public) get the anchor proof of a proposal comment"`
+	DeleteComment       DeleteCommentCmd       `command:"deletecomment" description:"(user)   delete a proposal comment (must be comment author)"`
+	DismissCommentFlags DismissCommentFlagsCmd `command:"dismisscommentflags" description:"(admin)  dismiss the flags of a proposal comment"`
+	EditComment         EditCommentCmd         `command:"editcomment" description:"(user)   edit a proposal comment (must be comment author)"`
+	FlagComment         FlagCommentCmd         `command:"flagcomment" description:"(user)   flag a proposal comment for admin attention"`
+	FlaggedComments     FlaggedCommentsCmd     `command:"flaggedcomments" description:"(admin)  get the flagged comments moderation queue"`
 	EditInvoice         EditInvoiceCmd         `command:"editinvoice" description:"(user)    edit a invoice"`
 	EditProposal        EditProposalCmd        `command:"editproposal" description:"(user)   edit a proposal"`
 	ManageUser          ManageUserCmd          `command:"manageuser" description:"(admin)  edit certain properties of the specified user"`
@@ -76,6 +85,7 @@ type Cmds struct {
 	LikeComment         LikeCommentCmd         `command:"likecomment" description:"(user)   upvote/downvote a comment"`
 	Login               LoginCmd               `command:"login" description:"(public) login to Politeia"`
 	Logout              LogoutCmd              `command:"logout" description:"(public) logout of Politeia"`
+	MarkCommentsRead    MarkCommentsReadCmd    `command:"markcommentsread" description:"(user)   mark the comments of a proposal as read"`
 	Me                  MeCmd                  `command:"me" description:"(user)   get user details for the logged in user"`
 	NewInvoice          NewInvoiceCmd          `command:"newinvoice" description:"(user)   create a new invoice"`
 	NewProposal         NewProposalCmd         `command:"newproposal" description:"(user)   create a new proposal"`
@@ -91,6 +101,7 @@ type Cmds struct {
 	VettedProposals     VettedProposalsCmd     `command:"vettedproposals" description:"(public) get a page of vetted proposals"`
 	RegisterUser        RegisterUserCmd        `command:"register" description:"(public) register an invited user to cms"`
 	RescanUserPayments  RescanUserPaymentsCmd  `command:"rescanuserpayments" description:"(admin)  rescan a user's payments to check for missed payments"`
+	ResolveAppeal       ResolveAppealCmd       `command:"resolveappeal" description:"(admin)  uphold a comment censorship or restore the comment"`
 	ResendVerification  ResendVerificationCmd  `command:"resendverification" description:"(public) resend the user verification email"`
 	ResetPassword       ResetPasswordCmd       `command:"resetpassword" description:"(public) reset the password for a user that is not logged in"`
 	Secret              SecretCmd              `command:"secret" description:"(user)   ping politeiawww"`
@@ -103,16 +114,19 @@ type Cmds struct {
 	TestRun             TestRunCmd             `command:"testrun" description:"         run a series of tests on the politeiawww routes (dev use only)"`
 	TokenInventory      TokenInventoryCmd      `command:"tokeninventory" description:"(public) get the censorship record tokens of all proposals"`
 	UpdateUserKey       UpdateUserKeyCmd       `command:"updateuserkey" description:"(user)   generate a new identity for the logged in user"`
+	UserComments        UserCommentsCmd        `command:"usercomments" description:"(public) get the comments made by a user"`
 	UserDetails         UserDetailsCmd         `command:"userdetails" description:"(public) get the details of a user profile"`
 	UserLikeComments    UserLikeCommentsCmd    `command:"userlikecomments" description:"(user)   get the logged in user's comment upvotes/downvotes for a proposal"`
 	UserPendingPayment  UserPendingPaymentCmd  `command:"userpendingpayment" description:"(user)   get details for a pending payment for the logged in user"`
 	UserInvoices        UserInvoicesCmd        `command:"userinvoices" description:"(user) get all invoices submitted by a specific user"`
 	UserProposals       UserProposalsCmd       `command:"userproposals" description:"(public) get all proposals submitted by a specific user"`
+	UserUnread          UserUnreadCmd          `command:"userunread" description:"(user)   get the proposals with comments the logged in user has not read"`
 	Users               UsersCmd               `command:"users" description:"(admin)  get a list of users"`
 	VerifyUserEmail     VerifyUserEmailCmd     `command:"verifyuseremail" description:"(public) verify a user's email address"`
 	VerifyUserPayment   VerifyUserPaymentCmd   `command:"verifyuserpayment" description:"(user)   check if the logged in user has paid their user registration fee"`
 	Version             VersionCmd             `command:"version" description:"(public) get server info and CSRF token"`
 	Vote                VoteCmd                `command:"vote" description:"(public) cast votes for a proposal"`
+	VoteProof           VoteProofCmd           `command:"voteproof" description:"(public) get the anchor proof of a ticket vote"`
 	VoteResults         VoteResultsCmd         `command:"voteresults" description:"(public) get vote results for a proposal"`
 	VoteStatus          VoteStatusCmd          `command:"votestatus" description:"(public) get the vote status of a proposal"`
 	VoteStatuses        VoteStatusesCmd        `command:"votestatuses" description:"(public) get the vote status for all public proposals"`
@@ -341,6 +355,23 @@ func verifyInvoice(p cms.InvoiceRecord, serverPubKey string) error {
 	return nil
 }
 
+// verifyJournalProof verifies that the merkle path of a journal proof leads
+// from the journal entry to the anchored journal head.
+func verifyJournalProof(jpr v1.JournalProofReply) error {
+	return bitumplugin.VerifyJournalProof(bitumplugin.JournalProofReply{
+		Journal:        jpr.Journal,
+		Entry:          jpr.Entry,
+		Index:          jpr.Index,
+		Entries:        jpr.Entries,
+		JournalHead:    jpr.JournalHead,
+		MerklePath:     jpr.MerklePath,
+		Anchor:         jpr.Anchor,
+		Timestamp:      jpr.Timestamp,
+		ChainTimestamp: jpr.ChainTimestamp,
+		Transaction:    jpr.Transaction,
+	})
+}
+
 // convertTicketHashes converts a slice of hexadecimal ticket hashes into
 // a slice of byte slices.
 func convertTicketHashes(h []string) ([][]byte, error) {
@@ -0,0 +1,59 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import "fmt"
+
+// CommentProofCmd retrieves the proof that a comment existed when the comment
+// journal of its proposal was anchored.
+type CommentProofCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the comment proof command.
+func (cmd *CommentProofCmd) Execute(args []string) error {
+	jpr, err := client.CommentProof(cmd.Args.Token, cmd.Args.CommentID)
+	if err != nil {
+		return err
+	}
+
+	// Verify proof
+	err = verifyJournalProof(*jpr)
+	if err != nil {
+		return fmt.Errorf("unable to verify comment proof: %v", err)
+	}
+
+	return printJSON(jpr)
+}
+
+// commentProofHelpMsg is the output of the help command when 'commentproof'
+// is specified.
+const commentProofHelpMsg = `commentproof "token" "commentID"
+
+Fetch the proof that a comment existed when the comment journal of the
+proposal was anchored and verify that the comment is part of the anchored
+journal head.  The journal head can be verified with bitumtime.  Censored and
+deleted comments cannot be proven.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+
+Result:
+{
+  "journal":         (string)    Journal filename
+  "entry":           (string)    Journal entry of the comment
+  "index":           (uint64)    Position of the entry in the journal
+  "entries":         (uint64)    Number of entries in the journal head
+  "journalhead":     (string)    Merkle root of the journal head
+  "merklepath":      ([]string)  Sibling digests from entry to journal head
+  "anchor":          (string)    Merkle root of the anchor
+  "timestamp":       (int64)     Time the anchor was dropped
+  "chaintimestamp":  (int64)     Block timestamp of the anchor (0 if unconfirmed)
+  "transaction":     (string)    Anchor transaction
+}`
@@ -0,0 +1,101 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// DeleteCommentCmd deletes a proposal comment on behalf of its author.
+type DeleteCommentCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the delete comment command.
+func (cmd *DeleteCommentCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup delete comment request
+	s := cfg.Identity.SignMessage([]byte(token + commentID))
+	signature := hex.EncodeToString(s[:])
+	dc := &v1.DeleteComment{
+		Token:     token,
+		CommentID: commentID,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(dc)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	dcr, err := client.DeleteComment(dc)
+	if err != nil {
+		return err
+	}
+
+	// Validate delete comment receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(dcr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(dcr)
+}
+
+// deleteCommentHelpMsg is the output of the help command when 'deletecomment'
+// is specified.
+const deleteCommentHelpMsg = `deletecomment "token" "commentID"
+
+Delete a comment. Only the author of a comment is allowed to delete it. The
+comment text is removed but the comment remains in place and is shown as
+deleted by its author.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "signature":  (string)  Signature of delete comment (Token+CommentID)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "receipt":  (string)  Server signature of delete comment signature
+}`
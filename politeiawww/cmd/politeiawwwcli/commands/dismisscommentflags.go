@@ -0,0 +1,105 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// DismissCommentFlagsCmd dismisses the flags of a proposal comment.
+type DismissCommentFlagsCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+		Reason    string `positional-arg-name:"reason"`    // Reason for dismissing
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the dismiss comment flags command.
+func (cmd *DismissCommentFlagsCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+	reason := cmd.Args.Reason
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup dismiss comment flags request
+	s := cfg.Identity.SignMessage([]byte(token + commentID + reason))
+	signature := hex.EncodeToString(s[:])
+	dcf := &v1.DismissCommentFlags{
+		Token:     token,
+		CommentID: commentID,
+		Reason:    reason,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(dcf)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	dcfr, err := client.DismissCommentFlags(dcf)
+	if err != nil {
+		return err
+	}
+
+	// Validate dismiss comment flags receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(dcfr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(dcfr)
+}
+
+// dismissCommentFlagsHelpMsg is the output of the help command when
+// 'dismisscommentflags' is specified.
+const dismissCommentFlagsHelpMsg = `dismisscommentflags "token" "commentID" "reason"
+
+Dismiss all of the flags of a comment without censoring it. Requires admin
+privileges.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+3. reason      (string, required)   Reason for dismissing the flags
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "reason":     (string)  Reason for dismissing the flags
+  "signature":  (string)  Signature of dismissal (Token+CommentID+Reason)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "receipt":  (string)  Server signature of dismissal signature
+}`
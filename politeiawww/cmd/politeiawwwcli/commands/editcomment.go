@@ -0,0 +1,121 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// EditCommentCmd edits a proposal comment.
+type EditCommentCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+		Comment   string `positional-arg-name:"comment"`   // New comment text
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the edit comment command.
+func (cmd *EditCommentCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+	comment := cmd.Args.Comment
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup edit comment request
+	s := cfg.Identity.SignMessage([]byte(token + commentID + comment))
+	signature := hex.EncodeToString(s[:])
+	ec := &v1.EditComment{
+		Token:     token,
+		CommentID: commentID,
+		Comment:   comment,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(ec)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	ecr, err := client.EditComment(ec)
+	if err != nil {
+		return err
+	}
+
+	// Validate edit comment receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(ecr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(ecr)
+}
+
+// editCommentHelpMsg is the output of the help command when 'editcomment' is
+// specified.
+const editCommentHelpMsg = `editcomment "token" "commentID" "comment"
+
+Edit a comment. Only the author of a comment is allowed to edit it.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+3. comment     (string, required)   New comment text
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "comment":    (string)  New comment text
+  "signature":  (string)  Signature of edit comment (Token+CommentID+Comment)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "comment": {
+    "token":          (string)  Censorship token
+    "parentid":       (string)  Id of comment (defaults to '0' (top-level))
+    "comment":        (string)  Latest comment text
+    "signature":      (string)  Signature of the original comment
+    "publickey":      (string)  Public key of the comment author
+    "commentid":      (string)  Id of the comment
+    "receipt":        (string)  Server signature of the original signature
+    "timestamp":      (int64)   Received UNIX timestamp
+    "totalvotes":     (uint64)  Total number of up/down votes
+    "resultvotes":    (int64)   Vote score
+    "censored":       (bool)    Has the comment been censored
+    "edits":          (uint32)  Number of times the comment was edited
+    "edittimestamp":  (int64)   UNIX timestamp of the latest edit
+    "userid":         (string)  User id of the comment author
+    "username":       (string)  Username of the comment author
+  },
+  "receipt":  (string)  Server signature of edit comment signature
+}`
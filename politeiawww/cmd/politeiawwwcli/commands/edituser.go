@@ -31,6 +31,7 @@ func (cmd *EditUserCmd) Execute(args []string) error {
 		"userauthorizedvote":        v1.NotificationEmailAdminProposalVoteAuthorized,
 		"commentonproposal":         v1.NotificationEmailCommentOnMyProposal,
 		"commentoncomment":          v1.NotificationEmailCommentOnMyComment,
+		"commentmention":            v1.NotificationEmailCommentMention,
 	}
 
 	var notif v1.EmailNotificationT
@@ -105,6 +106,7 @@ Valid options are:
 64.  userauthorizedvote         Notify when user authorizes vote (admin only)
 128. commentonproposal          Notify when comment is made on my proposal
 256. commentoncomment           Notify when comment is made on my comment
+512. commentmention             Notify when I am mentioned in a comment
 
 Request:
 {
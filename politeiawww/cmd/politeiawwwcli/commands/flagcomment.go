@@ -0,0 +1,106 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// FlagCommentCmd flags a proposal comment for admin attention.
+type FlagCommentCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+		Reason    string `positional-arg-name:"reason"`    // Reason category
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the flag comment command.
+func (cmd *FlagCommentCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+	reason := cmd.Args.Reason
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup flag comment request
+	s := cfg.Identity.SignMessage([]byte(token + commentID + reason))
+	signature := hex.EncodeToString(s[:])
+	fc := &v1.FlagComment{
+		Token:     token,
+		CommentID: commentID,
+		Reason:    reason,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(fc)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	fcr, err := client.FlagComment(fc)
+	if err != nil {
+		return err
+	}
+
+	// Validate flag comment receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(fcr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(fcr)
+}
+
+// flagCommentHelpMsg is the output of the help command when 'flagcomment' is
+// specified.
+const flagCommentHelpMsg = `flagcomment "token" "commentID" "reason"
+
+Flag a comment for admin attention. A comment can only be flagged once by a
+user until its flags are dismissed.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+3. reason      (string, required)   Reason category (spam, abuse, offtopic or
+                                    other)
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "reason":     (string)  Reason category
+  "signature":  (string)  Signature of flag comment (Token+CommentID+Reason)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "receipt":  (string)  Server signature of flag comment signature
+}`
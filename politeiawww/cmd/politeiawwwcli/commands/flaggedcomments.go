@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+// FlaggedCommentsCmd retrieves the comment moderation queue.
+type FlaggedCommentsCmd struct{}
+
+// Execute executes the flagged comments command.
+func (cmd *FlaggedCommentsCmd) Execute(args []string) error {
+	fcr, err := client.FlaggedComments()
+	if err != nil {
+		return err
+	}
+	return printJSON(fcr)
+}
+
+// flaggedCommentsHelpMsg is the output of the help command when
+// 'flaggedcomments' is specified.
+const flaggedCommentsHelpMsg = `flaggedcomments
+
+Get all comments that have flags which have not been dismissed, most flagged
+first. Requires admin privileges.
+
+Arguments:
+None
+
+Response:
+{
+  "comments": [
+    {
+      "comment": {
+        "token":        (string)  Censorship token
+        "parentid":     (string)  Id of comment (defaults to '0' (top-level))
+        "comment":      (string)  Comment
+        "signature":    (string)  Signature of comment (Token+ParentID+Comment)
+        "publickey":    (string)  Public key of user
+        "commentid":    (string)  Id of the comment
+        "receipt":      (string)  Server signature of the comment signature
+        "timestamp":    (int64)   Received UNIX timestamp
+        "totalvotes":   (uint64)  Total number of up/down votes
+        "resultvotes":  (int64)   Vote score
+        "censored":     (bool)    If comment has been censored
+        "userid":       (string)  User id
+        "username":     (string)  Username
+      },
+      "flags": [
+        {
+          "userid":     (string)  Id of the user that flagged the comment
+          "username":   (string)  Username of the user that flagged the comment
+          "reason":     (string)  Reason category
+          "timestamp":  (int64)   UNIX timestamp of the flag
+        }
+      ]
+    }
+  ]
+}`
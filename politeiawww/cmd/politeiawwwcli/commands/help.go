@@ -40,6 +40,10 @@ func (cmd *HelpCmd) Execute(args []string) error {
 		fmt.Printf("%s\n", sendFaucetTxHelpMsg)
 	case "userdetails":
 		fmt.Printf("%s\n", userDetailsHelpMsg)
+	case "usercomments":
+		fmt.Printf("%s\n", userCommentsHelpMsg)
+	case "userunread":
+		fmt.Printf("%s\n", userUnreadHelpMsg)
 	case "proposaldetails":
 		fmt.Printf("%s\n", proposalDetailsHelpMsg)
 	case "userproposals":
@@ -56,6 +60,28 @@ func (cmd *HelpCmd) Execute(args []string) error {
 		fmt.Printf("%s\n", proposalCommentsHelpMsg)
 	case "censorcomment":
 		fmt.Printf("%s\n", censorCommentHelpMsg)
+	case "appealcensorship":
+		fmt.Printf("%s\n", appealCensorshipHelpMsg)
+	case "censorshipappeals":
+		fmt.Printf("%s\n", censorshipAppealsHelpMsg)
+	case "resolveappeal":
+		fmt.Printf("%s\n", resolveAppealHelpMsg)
+	case "deletecomment":
+		fmt.Printf("%s\n", deleteCommentHelpMsg)
+	case "dismisscommentflags":
+		fmt.Printf("%s\n", dismissCommentFlagsHelpMsg)
+	case "editcomment":
+		fmt.Printf("%s\n", editCommentHelpMsg)
+	case "flagcomment":
+		fmt.Printf("%s\n", flagCommentHelpMsg)
+	case "flaggedcomments":
+		fmt.Printf("%s\n", flaggedCommentsHelpMsg)
+	case "commentproof":
+		fmt.Printf("%s\n", commentProofHelpMsg)
+	case "voteproof":
+		fmt.Printf("%s\n", voteProofHelpMsg)
+	case "markcommentsread":
+		fmt.Printf("%s\n", markCommentsReadHelpMsg)
 	case "likecomment":
 		fmt.Printf("%s\n", likeCommentHelpMsg)
 	case "editproposal":
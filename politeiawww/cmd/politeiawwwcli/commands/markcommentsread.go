@@ -0,0 +1,41 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+
+// MarkCommentsReadCmd marks all of the comments of a proposal as read by the
+// logged in user.
+type MarkCommentsReadCmd struct {
+	Args struct {
+		Token string `positional-arg-name:"token"` // Censorship token
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the mark comments read command.
+func (cmd *MarkCommentsReadCmd) Execute(args []string) error {
+	mcrr, err := client.MarkCommentsRead(
+		&v1.MarkCommentsRead{
+			Token: cmd.Args.Token,
+		})
+	if err != nil {
+		return err
+	}
+	return printJSON(mcrr)
+}
+
+// markCommentsReadHelpMsg is the output of the help command when
+// 'markcommentsread' is specified.
+const markCommentsReadHelpMsg = `markcommentsread "token"
+
+Mark all of the comments of a proposal as read by the logged in user.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+
+Result:
+{
+  "accesstime":  (int64)  UNIX timestamp of the new access time
+}`
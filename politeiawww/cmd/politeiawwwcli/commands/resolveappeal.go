@@ -0,0 +1,119 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/util"
+)
+
+// ResolveAppealCmd resolves a censorship appeal.
+type ResolveAppealCmd struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`     // Censorship token
+		CommentID string `positional-arg-name:"commentID"` // Comment ID
+		Action    string `positional-arg-name:"action"`    // Uphold or restore
+		Reason    string `positional-arg-name:"reason"`    // Reason for resolution
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the resolve appeal command.
+func (cmd *ResolveAppealCmd) Execute(args []string) error {
+	token := cmd.Args.Token
+	commentID := cmd.Args.CommentID
+	action := cmd.Args.Action
+	reason := cmd.Args.Reason
+
+	// Validate action
+	if action != v1.AppealActionUphold &&
+		action != v1.AppealActionRestore {
+		return fmt.Errorf("invalid action; must be '%v' or '%v'",
+			v1.AppealActionUphold, v1.AppealActionRestore)
+	}
+
+	// Check for user identity
+	if cfg.Identity == nil {
+		return errUserIdentityNotFound
+	}
+
+	// Get server public key
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+
+	// Setup resolve appeal request
+	s := cfg.Identity.SignMessage([]byte(token + commentID + action + reason))
+	signature := hex.EncodeToString(s[:])
+	ra := &v1.ResolveAppeal{
+		Token:     token,
+		CommentID: commentID,
+		Action:    action,
+		Reason:    reason,
+		Signature: signature,
+		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+	}
+
+	// Print request details
+	err = printJSON(ra)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	rar, err := client.ResolveAppeal(ra)
+	if err != nil {
+		return err
+	}
+
+	// Validate resolve appeal receipt
+	serverID, err := util.IdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptB, err := util.ConvertSignature(rar.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(signature), receiptB) {
+		return fmt.Errorf("could not verify receipt signature")
+	}
+
+	// Print response details
+	return printJSON(rar)
+}
+
+// resolveAppealHelpMsg is the output of the help command when
+// 'resolveappeal' is specified.
+const resolveAppealHelpMsg = `resolveappeal "token" "commentID" "action" "reason"
+
+Resolve a censorship appeal by either upholding the censorship or restoring
+the comment. Requires admin privileges. The appeal must be resolved by an
+admin other than the one that censored the comment.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. commentID   (string, required)   Id of the comment
+3. action      (string, required)   Resolution (uphold or restore)
+4. reason      (string, required)   Reason for the resolution
+
+Request:
+{
+  "token":      (string)  Censorship token
+  "commentid":  (string)  Id of comment
+  "action":     (string)  Resolution (uphold or restore)
+  "reason":     (string)  Reason for the resolution
+  "signature":  (string)  Signature of resolution (Token+CommentID+Action+Reason)
+  "publickey":  (string)  Public key used for signature
+}
+
+Response:
+{
+  "receipt":  (string)  Server signature of resolution signature
+  "comment":  (object)  Restored comment, only set when restored
+}`
@@ -20,6 +20,7 @@ type StartVoteCmd struct {
 		QuorumPercentage string `positional-arg-name:"quorumpercentage"`      // Quorum percentage
 		PassPercentage   string `positional-arg-name:"passpercentage"`        // Pass percentage
 	} `positional-args:"true"`
+	Policy string `long:"policy" optional:"true"` // Vote policy name
 }
 
 // Execute executes the start vote command.
@@ -29,29 +30,40 @@ func (cmd *StartVoteCmd) Execute(args []string) error {
 		return errUserIdentityNotFound
 	}
 
-	// Set vote parameter defaults
-	if cmd.Args.Duration == "" {
-		cmd.Args.Duration = "2016"
-	}
-	if cmd.Args.QuorumPercentage == "" {
-		cmd.Args.QuorumPercentage = "10"
-	}
-	if cmd.Args.PassPercentage == "" {
-		cmd.Args.PassPercentage = "75"
+	// Set vote parameter defaults. The vote parameters are
+	// filled in by the server when a vote policy is used.
+	if cmd.Policy == "" {
+		if cmd.Args.Duration == "" {
+			cmd.Args.Duration = "2016"
+		}
+		if cmd.Args.QuorumPercentage == "" {
+			cmd.Args.QuorumPercentage = "10"
+		}
+		if cmd.Args.PassPercentage == "" {
+			cmd.Args.PassPercentage = "75"
+		}
 	}
 
 	// Convert vote parameters
-	duration, err := strconv.ParseUint(cmd.Args.Duration, 10, 32)
-	if err != nil {
-		return fmt.Errorf("parsing Duration: %v", err)
+	var duration, quorum, pass uint64
+	var err error
+	if cmd.Args.Duration != "" {
+		duration, err = strconv.ParseUint(cmd.Args.Duration, 10, 32)
+		if err != nil {
+			return fmt.Errorf("parsing Duration: %v", err)
+		}
 	}
-	quorum, err := strconv.ParseUint(cmd.Args.QuorumPercentage, 10, 32)
-	if err != nil {
-		return fmt.Errorf("parsing QuorumPercentage: %v", err)
+	if cmd.Args.QuorumPercentage != "" {
+		quorum, err = strconv.ParseUint(cmd.Args.QuorumPercentage, 10, 32)
+		if err != nil {
+			return fmt.Errorf("parsing QuorumPercentage: %v", err)
+		}
 	}
-	pass, err := strconv.ParseUint(cmd.Args.PassPercentage, 10, 32)
-	if err != nil {
-		return fmt.Errorf("parsing PassPercentage: %v", err)
+	if cmd.Args.PassPercentage != "" {
+		pass, err = strconv.ParseUint(cmd.Args.PassPercentage, 10, 32)
+		if err != nil {
+			return fmt.Errorf("parsing PassPercentage: %v", err)
+		}
 	}
 
 	// Setup start vote request
@@ -59,6 +71,7 @@ func (cmd *StartVoteCmd) Execute(args []string) error {
 	sv := &v1.StartVote{
 		Signature: hex.EncodeToString(sig[:]),
 		PublicKey: hex.EncodeToString(cfg.Identity.Public.Key[:]),
+		Policy:    cmd.Policy,
 		Vote: v1.Vote{
 			Token:            cmd.Args.Token,
 			Mask:             0x03, // bit 0 no, bit 1 yes
@@ -105,7 +118,8 @@ func (cmd *StartVoteCmd) Execute(args []string) error {
 var startVoteHelpMsg = `startvote "token" "duration" "quorumpercentage" "passpercentage"
 
 Start voting period for a proposal. Requires admin privileges.  The optional
-arguments must either all be used or none be used.
+arguments must either all be used or none be used.  The vote parameters are
+set by the server when a vote policy is used.
 
 Arguments:
 1. token              (string, required)  Proposal censorship token
@@ -113,6 +127,9 @@ Arguments:
 3. quorumpercentage   (string, optional)  Percent of votes required for quorum
 4. passpercentage     (string, optional)  Percent of votes required to pass
 
+Flags:
+  --policy            (string, optional)  Name of the vote policy to use
+
 Result:
 
 {
@@ -105,7 +105,7 @@ func (cmd *SubscribeCmd) Execute(args []string) error {
 
 // subscribeHelpMsg is the output of the help command when 'subscribe' is
 // specified.
-const subscribeHelpMsg = `subscribe [auth] <ping...>
+const subscribeHelpMsg = `subscribe [auth] <ping|commentmention...>
 
 Connect and subcribe to www websocket. If auth is provided the connection will
 be made to the authenticated websocket (must be logged in).
@@ -115,6 +115,7 @@ Flags:
 
 Supported commands:
 	- ping (does not require authentication)
+	- commentmention (requires authentication)
 
 Request:
 {
@@ -0,0 +1,63 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+
+// UserCommentsCmd retrieves the comments that a user has made across all
+// proposals.
+type UserCommentsCmd struct {
+	Args struct {
+		UserID string `positional-arg-name:"userID"` // User ID
+	} `positional-args:"true" required:"true"`
+	Cursor string `long:"cursor" optional:"true"` // Page cursor
+}
+
+// Execute executes the user comments command.
+func (cmd *UserCommentsCmd) Execute(args []string) error {
+	ucr, err := client.UserComments(
+		&v1.UserComments{
+			UserID: cmd.Args.UserID,
+			Cursor: cmd.Cursor,
+		})
+	if err != nil {
+		return err
+	}
+	return printJSON(ucr)
+}
+
+// userCommentsHelpMsg is the output of the help command when 'usercomments'
+// is specified.
+const userCommentsHelpMsg = `usercomments "userID" 
+
+Fetch the comments that a user has made across all proposals, newest first.
+Censored comments are only returned to admins.
+
+Arguments:
+1. userID      (string, required)   User id
+
+Flags:
+  --cursor     (string, optional)   Cursor of the page to fetch
+
+Result:
+{
+  "comments": [
+    {
+      "token":         (string)  Censorship token
+      "parentid":      (string)  Id of comment (defaults to '0' (top-level))
+      "comment":       (string)  Comment
+      "signature":     (string)  Signature of token+parentID+comment
+      "publickey":     (string)  Public key of user
+      "commentid":     (string)  Id of the comment
+      "receipt":       (string)  Server signature of the comment signature
+      "timestamp":     (int64)   Received UNIX timestamp
+      "resultvotes":   (int64)   Vote score
+      "censored":      (bool)    If comment has been censored
+      "userid":        (string)  User id
+      "username":      (string)  Username
+    }
+  ],
+  "nextcursor":        (string)  Cursor of the next page
+}`
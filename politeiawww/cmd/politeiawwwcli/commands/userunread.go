@@ -0,0 +1,40 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+// UserUnreadCmd retrieves the proposals that have comments which the logged
+// in user has not read.
+type UserUnreadCmd struct{}
+
+// Execute executes the user unread command.
+func (cmd *UserUnreadCmd) Execute(args []string) error {
+	uur, err := client.UserUnread()
+	if err != nil {
+		return err
+	}
+	return printJSON(uur)
+}
+
+// userUnreadHelpMsg is the output of the help command when 'userunread' is
+// specified.
+const userUnreadHelpMsg = `userunread
+
+Get the proposals that have new comments since the logged in user last read
+their comments, the proposal with the newest comment first.
+
+Arguments:
+None
+
+Result:
+{
+  "proposals": [
+    {
+      "token":           (string)  Censorship token
+      "unreadcomments":  (uint64)  Number of unread comments
+      "latestcomment":   (int64)   UNIX timestamp of the newest unread comment
+      "accesstime":      (int64)   UNIX timestamp of the last access
+    }
+  ]
+}`
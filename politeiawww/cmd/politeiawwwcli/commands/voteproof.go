@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package commands
+
+import "fmt"
+
+// VoteProofCmd retrieves the proof that the latest vote of a ticket existed
+// when the ballot journal of the proposal was anchored.
+type VoteProofCmd struct {
+	Args struct {
+		Token  string `positional-arg-name:"token"`  // Censorship token
+		Ticket string `positional-arg-name:"ticket"` // Ticket hash
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the vote proof command.
+func (cmd *VoteProofCmd) Execute(args []string) error {
+	jpr, err := client.VoteProof(cmd.Args.Token, cmd.Args.Ticket)
+	if err != nil {
+		return err
+	}
+
+	// Verify proof
+	err = verifyJournalProof(*jpr)
+	if err != nil {
+		return fmt.Errorf("unable to verify vote proof: %v", err)
+	}
+
+	return printJSON(jpr)
+}
+
+// voteProofHelpMsg is the output of the help command when 'voteproof' is
+// specified.
+const voteProofHelpMsg = `voteproof "token" "ticket"
+
+Fetch the proof that the latest vote of a ticket existed when the ballot
+journal of the proposal was anchored and verify that the vote is part of the
+anchored journal head.  The journal head can be verified with bitumtime.
+
+Arguments:
+1. token       (string, required)   Proposal censorship token
+2. ticket      (string, required)   Ticket hash
+
+Result:
+{
+  "journal":         (string)    Journal filename
+  "entry":           (string)    Journal entry of the vote
+  "index":           (uint64)    Position of the entry in the journal
+  "entries":         (uint64)    Number of entries in the journal head
+  "journalhead":     (string)    Merkle root of the journal head
+  "merklepath":      ([]string)  Sibling digests from entry to journal head
+  "anchor":          (string)    Merkle root of the anchor
+  "timestamp":       (int64)     Time the anchor was dropped
+  "chaintimestamp":  (int64)     Block timestamp of the anchor (0 if unconfirmed)
+  "transaction":     (string)    Anchor transaction
+}`
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"sync"
 
 	"github.com/bitum-project/politeia/bitumplugin"
@@ -25,6 +26,7 @@ const (
 	tableNameLineItem      = "line_items"
 	tableNameInvoiceChange = "invoice_changes"
 	tableNameExchangeRate  = "exchange_rates"
+	tableNameSyncState     = "invoice_sync_states"
 
 	userPoliteiawww = "politeiawww" // cmsdb user (read/write access)
 )
@@ -46,7 +48,9 @@ func (c *cockroachdb) NewInvoice(dbInvoice *database.Invoice) error {
 	return c.recordsdb.Create(invoice).Error
 }
 
-// Update existing invoice.
+// Update existing invoice.  The line items of the invoice are replaced by the
+// ones in the passed in invoice and any passed in status changes that have not
+// already been recorded are added to the invoice status change history.
 //
 // UpdateInvoice satisfies the database interface.
 func (c *cockroachdb) UpdateInvoice(dbInvoice *database.Invoice) error {
@@ -54,7 +58,61 @@ func (c *cockroachdb) UpdateInvoice(dbInvoice *database.Invoice) error {
 
 	log.Debugf("UpdateInvoice: %v", invoice.Token)
 
-	return c.recordsdb.Save(invoice).Error
+	// Status changes are saved separately so that changes that have
+	// already been recorded are not duplicated.
+	changes := invoice.Changes
+	invoice.Changes = nil
+
+	// Remove the existing line items first so that line items that
+	// were removed from the invoice don't linger.
+	tx := c.recordsdb.Begin()
+	err := tx.Where("invoice_token = ?", invoice.Token).
+		Delete(LineItem{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete line items: %v", err)
+	}
+	err = tx.Save(invoice).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Add the status changes that are not already recorded
+	var existing []InvoiceChange
+	err = tx.Where("invoice_token = ?", invoice.Token).
+		Find(&existing).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("lookup invoice changes: %v", err)
+	}
+	recorded := make(map[string]struct{}, len(existing))
+	for _, v := range existing {
+		recorded[invoiceChangeKey(v)] = struct{}{}
+	}
+	for _, v := range changes {
+		if _, ok := recorded[invoiceChangeKey(v)]; ok {
+			continue
+		}
+		v.InvoiceToken = invoice.Token
+		err = tx.Create(&v).Error
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("create invoice change: %v", err)
+		}
+		recorded[invoiceChangeKey(v)] = struct{}{}
+	}
+
+	return tx.Commit().Error
+}
+
+// invoiceChangeKey returns a key that uniquely identifies an invoice status
+// change.
+func invoiceChangeKey(c InvoiceChange) string {
+	return strconv.FormatInt(c.Timestamp.Unix(), 10) + ":" +
+		strconv.FormatUint(uint64(c.NewStatus), 10) + ":" + c.AdminPublicKey
 }
 
 // Return all invoices by userid
@@ -102,7 +160,10 @@ func (c *cockroachdb) InvoiceByToken(token string) (*database.Invoice, error) {
 	invoice := Invoice{
 		Token: token,
 	}
-	err := c.recordsdb.Find(&invoice).Error
+	err := c.recordsdb.
+		Preload("LineItems").
+		Find(&invoice).
+		Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			err = database.ErrInvoiceNotFound
@@ -264,6 +325,35 @@ func (c *cockroachdb) InvoicesAll() ([]database.Invoice, error) {
 	return dbInvoices, nil
 }
 
+// SyncStates returns the last synced state of all invoices mapped by token.
+//
+// SyncStates satisfies the database interface.
+func (c *cockroachdb) SyncStates() (map[string]database.SyncState, error) {
+	log.Tracef("SyncStates")
+
+	var states []SyncState
+	err := c.recordsdb.Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dbStates := make(map[string]database.SyncState, len(states))
+	for _, v := range states {
+		dbStates[v.Token] = decodeSyncState(v)
+	}
+	return dbStates, nil
+}
+
+// Create or update the last synced state of an invoice.
+//
+// SetSyncState satisfies the database interface.
+func (c *cockroachdb) SetSyncState(dbState *database.SyncState) error {
+	state := encodeSyncState(dbState)
+
+	log.Debugf("SetSyncState: %v", state.Token)
+	return c.recordsdb.Save(&state).Error
+}
+
 // Create new exchange rate.
 //
 // NewExchangeRate satisfies the database interface.
@@ -327,6 +417,12 @@ func createCmsTables(tx *gorm.DB) error {
 			return err
 		}
 	}
+	if !tx.HasTable(tableNameSyncState) {
+		err := tx.CreateTable(&SyncState{}).Error
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -358,7 +454,8 @@ func (c *cockroachdb) Build(payload string) error {
 	}
 
 	// Drop all bitum plugin tables
-	err = c.recordsdb.DropTableIfExists(tableNameInvoice, tableNameLineItem).Error
+	err = c.recordsdb.DropTableIfExists(tableNameInvoice, tableNameLineItem,
+		tableNameInvoiceChange, tableNameSyncState).Error
 	if err != nil {
 		return fmt.Errorf("drop invoice tables failed: %v", err)
 	}
@@ -399,11 +496,19 @@ func buildQueryString(user, rootCert, cert, key string) string {
 // specified database that was made using the politeiawww user and the passed
 // in certificates.
 func New(host, net, rootCert, cert, key string) (*cockroachdb, error) {
-	log.Tracef("New: %v %v %v %v %v", host, net, rootCert, cert, key)
+	return newCockroachdb(userPoliteiawww, host, net, rootCert, cert, key)
+}
+
+// newCockroachdb returns a new cockroachdb context that contains a connection
+// to the specified database that was made using the passed in user and
+// certificates.
+func newCockroachdb(user, host, net, rootCert, cert, key string) (*cockroachdb, error) {
+	log.Tracef("New: %v %v %v %v %v %v", user, host, net, rootCert, cert,
+		key)
 
 	// Connect to database
 	dbName := cacheID + "_" + net
-	h := "postgresql://" + userPoliteiawww + "@" + host + "/" + dbName
+	h := "postgresql://" + user + "@" + host + "/" + dbName
 	u, err := url.Parse(h)
 	if err != nil {
 		return nil, fmt.Errorf("parse url '%v': %v", h, err)
@@ -0,0 +1,60 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cockroachdb
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/bitum-project/politeia/politeiawww/cmsdatabase/dbtest"
+	"github.com/bitum-project/politeia/util"
+)
+
+// TestCockroachdb runs the shared cmsdatabase test suite against a running
+// CockroachDB instance.  The test is skipped unless the CMSDB_HOST,
+// CMSDB_ROOTCERT, CMSDB_CERT and CMSDB_KEY environment variables are set.
+// The client certificate must belong to the root user since the suite is run
+// against a throwaway database that is created and dropped by the test.
+func TestCockroachdb(t *testing.T) {
+	host := os.Getenv("CMSDB_HOST")
+	rootCert := os.Getenv("CMSDB_ROOTCERT")
+	cert := os.Getenv("CMSDB_CERT")
+	key := os.Getenv("CMSDB_KEY")
+	if host == "" || rootCert == "" || cert == "" || key == "" {
+		t.Skip("cockroachdb connection not configured")
+	}
+
+	b, err := util.Random(8)
+	if err != nil {
+		t.Fatalf("Random: %v", err)
+	}
+	net := "test" + hex.EncodeToString(b)
+
+	db, err := newCockroachdb("root", host, net, rootCert, cert, key)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	dbName := cacheID + "_" + net
+	err = db.recordsdb.Exec("CREATE DATABASE " + dbName).Error
+	if err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+	defer func() {
+		err := db.recordsdb.Exec("DROP DATABASE " + dbName + " CASCADE").Error
+		if err != nil {
+			t.Errorf("drop database: %v", err)
+		}
+	}()
+
+	err = db.Setup()
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	dbtest.Run(t, db)
+}
@@ -173,3 +173,19 @@ func decodeExchangeRate(exchangeRate ExchangeRate) *database.ExchangeRate {
 	dbExchangeRate.ExchangeRate = exchangeRate.ExchangeRate
 	return dbExchangeRate
 }
+
+func encodeSyncState(dbState *database.SyncState) SyncState {
+	state := SyncState{}
+	state.Token = dbState.Token
+	state.Version = dbState.Version
+	state.Status = uint(dbState.Status)
+	return state
+}
+
+func decodeSyncState(state SyncState) database.SyncState {
+	dbState := database.SyncState{}
+	dbState.Token = state.Token
+	dbState.Version = state.Version
+	dbState.Status = cms.InvoiceStatusT(state.Status)
+	return dbState
+}
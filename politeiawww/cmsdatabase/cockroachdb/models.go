@@ -73,6 +73,18 @@ func (InvoiceChange) TableName() string {
 	return tableNameInvoiceChange
 }
 
+// SyncState is the database model for the database.SyncState type
+type SyncState struct {
+	Token   string `gorm:"primary_key"` // Censorship token of the invoice
+	Version string `gorm:"not null"`    // Invoice version as of the last sync
+	Status  uint   `gorm:"not null"`    // Invoice status as of the last sync
+}
+
+// TableName returns the table name of the invoice sync states table.
+func (SyncState) TableName() string {
+	return tableNameSyncState
+}
+
 // ExchangeRate contains cached calculated rates for a given month/year
 type ExchangeRate struct {
 	Month        uint `gorm:"not null"`
@@ -0,0 +1,138 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmsdatabase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bitum-project/politeia/politeiad/cache"
+	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+)
+
+const (
+	// invoiceFilename is the name of the record file that contains the
+	// invoice input.
+	invoiceFilename = "invoice.json"
+
+	// Invoice metadata stream IDs
+	mdStreamInvoiceGeneral       = 3 // General invoice metadata
+	mdStreamInvoiceStatusChanges = 4 // Invoice status changes
+)
+
+// invoiceMetadata is the general invoice metadata that politeiawww stores in
+// politeiad.
+type invoiceMetadata struct {
+	Version   uint64 `json:"version"`   // Version of the struct
+	Timestamp int64  `json:"timestamp"` // Last update of invoice
+	PublicKey string `json:"publickey"` // Key used for signature
+	Signature string `json:"signature"` // Signature of merkle root
+}
+
+// invoiceStatusChange is an invoice status change that politeiawww stores in
+// politeiad.
+type invoiceStatusChange struct {
+	Version        uint               `json:"version"`        // Version of the struct
+	AdminPublicKey string             `json:"adminpublickey"` // Identity of the administrator
+	NewStatus      cms.InvoiceStatusT `json:"newstatus"`      // Status
+	Reason         string             `json:"reason"`         // Reason
+	Timestamp      int64              `json:"timestamp"`      // Timestamp of the change
+}
+
+// ConvertInvoiceFromCache decodes the passed in politeiad record into an
+// Invoice, including its line items and its full status change history.  The
+// status of the invoice is the status of the most recent status change.  The
+// user ID is not part of the record and is left blank.
+func ConvertInvoiceFromCache(r cache.Record) (*Invoice, error) {
+	inv := Invoice{
+		Token:           r.CensorshipRecord.Token,
+		ServerSignature: r.CensorshipRecord.Signature,
+		Version:         r.Version,
+		Files:           make([]www.File, 0, len(r.Files)),
+	}
+
+	for _, f := range r.Files {
+		inv.Files = append(inv.Files, www.File{
+			Name:    f.Name,
+			MIME:    f.MIME,
+			Digest:  f.Digest,
+			Payload: f.Payload,
+		})
+		if f.Name != invoiceFilename {
+			continue
+		}
+
+		b, err := base64.StdEncoding.DecodeString(f.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode invoice file: %v", err)
+		}
+		var ii cms.InvoiceInput
+		err = json.Unmarshal(b, &ii)
+		if err != nil {
+			return nil, fmt.Errorf("decode invoice input: %v", err)
+		}
+
+		inv.Month = ii.Month
+		inv.Year = ii.Year
+		inv.ExchangeRate = ii.ExchangeRate
+		inv.ContractorName = ii.ContractorName
+		inv.ContractorLocation = ii.ContractorLocation
+		inv.ContractorContact = ii.ContractorContact
+		inv.ContractorRate = ii.ContractorRate
+		inv.PaymentAddress = ii.PaymentAddress
+		for _, v := range ii.LineItems {
+			inv.LineItems = append(inv.LineItems, LineItem{
+				InvoiceToken: inv.Token,
+				Type:         v.Type,
+				Domain:       v.Domain,
+				Subdomain:    v.Subdomain,
+				Description:  v.Description,
+				ProposalURL:  v.ProposalToken,
+				Labor:        v.Labor,
+				Expenses:     v.Expenses,
+			})
+		}
+	}
+
+	for _, m := range r.Metadata {
+		switch m.ID {
+		case mdStreamInvoiceGeneral:
+			var md invoiceMetadata
+			err := json.Unmarshal([]byte(m.Payload), &md)
+			if err != nil {
+				return nil, fmt.Errorf("decode general metadata: %v", err)
+			}
+			inv.Timestamp = md.Timestamp
+			inv.PublicKey = md.PublicKey
+			inv.UserSignature = md.Signature
+
+		case mdStreamInvoiceStatusChanges:
+			d := json.NewDecoder(strings.NewReader(m.Payload))
+			for {
+				var sc invoiceStatusChange
+				err := d.Decode(&sc)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return nil, fmt.Errorf("decode status changes: %v", err)
+				}
+				inv.Changes = append(inv.Changes, InvoiceChange{
+					AdminPublicKey: sc.AdminPublicKey,
+					NewStatus:      sc.NewStatus,
+					Reason:         sc.Reason,
+					Timestamp:      sc.Timestamp,
+				})
+				inv.Status = sc.NewStatus
+				inv.StatusChangeReason = sc.Reason
+			}
+		}
+	}
+
+	return &inv, nil
+}
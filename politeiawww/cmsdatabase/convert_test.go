@@ -0,0 +1,132 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmsdatabase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/bitum-project/politeia/politeiad/cache"
+	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
+)
+
+func TestConvertInvoiceFromCache(t *testing.T) {
+	ii := cms.InvoiceInput{
+		Month:          3,
+		Year:           2019,
+		ContractorName: "contractor",
+		ContractorRate: 4000,
+		PaymentAddress: "address",
+		LineItems: []cms.LineItemsInput{
+			{
+				Type:          cms.LineItemTypeLabor,
+				Domain:        "development",
+				Description:   "work",
+				ProposalToken: "proposal",
+				Labor:         60,
+			},
+		},
+	}
+	b, err := json.Marshal(ii)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := json.Marshal(invoiceMetadata{
+		Timestamp: 100,
+		PublicKey: "publickey",
+		Signature: "signature",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Status changes are stored as a stream of JSON objects
+	changes := []invoiceStatusChange{
+		{NewStatus: cms.InvoiceStatusUpdated, Timestamp: 200},
+		{
+			AdminPublicKey: "admin",
+			NewStatus:      cms.InvoiceStatusRejected,
+			Reason:         "rejected",
+			Timestamp:      300,
+		},
+	}
+	var sc string
+	for _, v := range changes {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc += string(b)
+	}
+
+	r := cache.Record{
+		Version: "2",
+		CensorshipRecord: cache.CensorshipRecord{
+			Token:     "token",
+			Signature: "serversignature",
+		},
+		Metadata: []cache.MetadataStream{
+			{ID: mdStreamInvoiceGeneral, Payload: string(md)},
+			{ID: mdStreamInvoiceStatusChanges, Payload: sc},
+		},
+		Files: []cache.File{
+			{
+				Name:    invoiceFilename,
+				Payload: base64.StdEncoding.EncodeToString(b),
+			},
+		},
+	}
+
+	inv, err := ConvertInvoiceFromCache(r)
+	if err != nil {
+		t.Fatalf("ConvertInvoiceFromCache: %v", err)
+	}
+	if inv.Token != "token" || inv.Version != "2" ||
+		inv.ServerSignature != "serversignature" ||
+		inv.Month != 3 || inv.Year != 2019 ||
+		inv.ContractorName != "contractor" ||
+		inv.ContractorRate != 4000 || inv.PaymentAddress != "address" ||
+		inv.Timestamp != 100 || inv.PublicKey != "publickey" ||
+		inv.UserSignature != "signature" || len(inv.Files) != 1 {
+		t.Fatalf("unexpected invoice %+v", inv)
+	}
+
+	wantLineItems := []LineItem{
+		{
+			InvoiceToken: "token",
+			Type:         cms.LineItemTypeLabor,
+			Domain:       "development",
+			Description:  "work",
+			ProposalURL:  "proposal",
+			Labor:        60,
+		},
+	}
+	if !reflect.DeepEqual(inv.LineItems, wantLineItems) {
+		t.Fatalf("got line items %+v, want %+v", inv.LineItems,
+			wantLineItems)
+	}
+
+	// The status is taken from the most recent status change
+	if inv.Status != cms.InvoiceStatusRejected ||
+		inv.StatusChangeReason != "rejected" || len(inv.Changes) != 2 {
+		t.Fatalf("got status %v reason %q and %v changes, want %v "+
+			"rejected and 2", inv.Status, inv.StatusChangeReason,
+			len(inv.Changes), cms.InvoiceStatusRejected)
+	}
+	if inv.Changes[1].AdminPublicKey != "admin" ||
+		inv.Changes[1].Timestamp != 300 {
+		t.Fatalf("unexpected status change %+v", inv.Changes[1])
+	}
+
+	// Malformed metadata is rejected
+	r.Metadata[1].Payload = "{"
+	_, err = ConvertInvoiceFromCache(r)
+	if err == nil {
+		t.Fatalf("ConvertInvoiceFromCache: want error for malformed " +
+			"status changes")
+	}
+}
@@ -29,7 +29,7 @@ type Database interface {
 	// Invoice functions
 	NewInvoice(*Invoice) error // Create new invoice
 
-	UpdateInvoice(*Invoice) error // Update existing invoice
+	UpdateInvoice(*Invoice) error // Update existing invoice; replaces line items and adds new status changes
 	InvoicesByUserID(string) ([]Invoice, error)
 	InvoiceByToken(string) (*Invoice, error) // Return invoice given its token
 
@@ -38,6 +38,10 @@ type Database interface {
 	InvoicesByStatus(int) ([]Invoice, error)                          // Returns all invoices by status
 	InvoicesAll() ([]Invoice, error)                                  // Returns all invoices
 
+	// Sync functions
+	SyncStates() (map[string]SyncState, error) // Returns the last synced state of all invoices
+	SetSyncState(*SyncState) error             // Create or update the last synced state of an invoice
+
 	// ExchangeRate functions
 	NewExchangeRate(*ExchangeRate) error // Create new exchange rate
 
@@ -102,6 +106,14 @@ type InvoiceChange struct {
 	Timestamp      int64
 }
 
+// SyncState contains the version and status that an invoice had in politeiad
+// the last time that it was synced into the cmsdatabase.
+type SyncState struct {
+	Token   string
+	Version string
+	Status  cms.InvoiceStatusT
+}
+
 // ExchangeRate contains cached calculated rates for a given month/year
 type ExchangeRate struct {
 	Month        uint
@@ -0,0 +1,444 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package dbtest contains a test suite that is shared by all cmsdatabase
+// implementations.  Each implementation runs the suite against a freshly
+// setup database to ensure that all drivers behave the same.
+package dbtest
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
+	database "github.com/bitum-project/politeia/politeiawww/cmsdatabase"
+	"github.com/bitum-project/politeia/util"
+)
+
+// randomHex returns a random hex encoded string of n bytes.
+func randomHex(t *testing.T, n int) string {
+	t.Helper()
+
+	b, err := util.Random(n)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// newInvoice returns a new invoice with random identifiers.  Random
+// identifiers are used so that the suite can be run against a database that
+// already contains data.
+func newInvoice(t *testing.T, userID string, month, year uint, status cms.InvoiceStatusT) database.Invoice {
+	t.Helper()
+
+	token := randomHex(t, 32)
+	return database.Invoice{
+		Token:              token,
+		UserID:             userID,
+		Month:              month,
+		Year:               year,
+		ExchangeRate:       1651,
+		Timestamp:          time.Now().Unix(),
+		Status:             status,
+		PublicKey:          randomHex(t, 32),
+		UserSignature:      randomHex(t, 64),
+		ServerSignature:    randomHex(t, 64),
+		Version:            "1",
+		ContractorName:     "Contractor",
+		ContractorLocation: "Earth",
+		ContractorContact:  "contractor@example.org",
+		ContractorRate:     4000,
+		PaymentAddress:     "TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd",
+		LineItems: []database.LineItem{
+			{
+				InvoiceToken: token,
+				Type:         cms.LineItemTypeLabor,
+				Domain:       "Development",
+				Subdomain:    "politeia",
+				Description:  "Work",
+				Labor:        60,
+			},
+			{
+				InvoiceToken: token,
+				Type:         cms.LineItemTypeExpense,
+				Domain:       "Development",
+				Subdomain:    "politeia",
+				Description:  "Hosting",
+				Expenses:     2500,
+			},
+		},
+	}
+}
+
+// containsToken returns whether the passed in invoices contain an invoice
+// with the given token.
+func containsToken(invoices []database.Invoice, token string) bool {
+	for _, v := range invoices {
+		if v.Token == token {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the cmsdatabase test suite against the passed in database.  The
+// database must already be setup.  The suite finishes by rebuilding the
+// database, which drops all invoice data.
+func Run(t *testing.T, db database.Database) {
+	t.Run("invoices", func(t *testing.T) { testInvoices(t, db) })
+	t.Run("exchangerates", func(t *testing.T) { testExchangeRates(t, db) })
+	t.Run("sync", func(t *testing.T) { testSync(t, db) })
+	t.Run("build", func(t *testing.T) { testBuild(t, db) })
+}
+
+func testInvoices(t *testing.T, db database.Database) {
+	userID := randomHex(t, 16)
+	otherUserID := randomHex(t, 16)
+
+	// Use a month/year combination that is unlikely to exist in an
+	// existing database.
+	year := uint(1000 + time.Now().UnixNano()%1000)
+	inv1 := newInvoice(t, userID, 1, year, cms.InvoiceStatusNew)
+	inv2 := newInvoice(t, userID, 2, year, cms.InvoiceStatusNew)
+	inv3 := newInvoice(t, otherUserID, 1, year, cms.InvoiceStatusApproved)
+	for _, v := range []database.Invoice{inv1, inv2, inv3} {
+		v := v
+		err := db.NewInvoice(&v)
+		if err != nil {
+			t.Fatalf("NewInvoice: %v", err)
+		}
+	}
+
+	// Lookup by token
+	inv, err := db.InvoiceByToken(inv1.Token)
+	if err != nil {
+		t.Fatalf("InvoiceByToken: %v", err)
+	}
+	if inv.UserID != inv1.UserID || inv.Month != inv1.Month ||
+		inv.Year != inv1.Year || inv.Status != inv1.Status ||
+		inv.Timestamp != inv1.Timestamp ||
+		inv.ContractorRate != inv1.ContractorRate ||
+		inv.PaymentAddress != inv1.PaymentAddress {
+		t.Fatalf("InvoiceByToken: got %+v, want %+v", inv, inv1)
+	}
+	if len(inv.LineItems) != len(inv1.LineItems) {
+		t.Fatalf("InvoiceByToken: got %v line items, want %v",
+			len(inv.LineItems), len(inv1.LineItems))
+	}
+
+	_, err = db.InvoiceByToken(randomHex(t, 32))
+	if err != database.ErrInvoiceNotFound {
+		t.Fatalf("InvoiceByToken: got error %v, want %v", err,
+			database.ErrInvoiceNotFound)
+	}
+
+	// Lookup by user
+	invs, err := db.InvoicesByUserID(userID)
+	if err != nil {
+		t.Fatalf("InvoicesByUserID: %v", err)
+	}
+	if len(invs) != 2 || !containsToken(invs, inv1.Token) ||
+		!containsToken(invs, inv2.Token) {
+		t.Fatalf("InvoicesByUserID: got %v invoices, want 2", len(invs))
+	}
+	for _, v := range invs {
+		if len(v.LineItems) != 2 {
+			t.Fatalf("InvoicesByUserID: got %v line items, want 2",
+				len(v.LineItems))
+		}
+	}
+
+	// Lookup by month and year
+	invs, err = db.InvoicesByMonthYear(1, uint16(year))
+	if err != nil {
+		t.Fatalf("InvoicesByMonthYear: %v", err)
+	}
+	if len(invs) != 2 || !containsToken(invs, inv1.Token) ||
+		!containsToken(invs, inv3.Token) {
+		t.Fatalf("InvoicesByMonthYear: got %v invoices, want 2", len(invs))
+	}
+
+	// Lookup by month, year and status
+	invs, err = db.InvoicesByMonthYearStatus(1, uint16(year),
+		int(cms.InvoiceStatusApproved))
+	if err != nil {
+		t.Fatalf("InvoicesByMonthYearStatus: %v", err)
+	}
+	if len(invs) != 1 || invs[0].Token != inv3.Token {
+		t.Fatalf("InvoicesByMonthYearStatus: got %v invoices, want 1",
+			len(invs))
+	}
+
+	// Update status and remove a line item. The update is applied
+	// twice to verify that status changes are not duplicated.
+	inv1.Status = cms.InvoiceStatusApproved
+	inv1.LineItems = inv1.LineItems[:1]
+	inv1.Changes = []database.InvoiceChange{
+		{
+			AdminPublicKey: randomHex(t, 32),
+			NewStatus:      cms.InvoiceStatusApproved,
+			Timestamp:      time.Now().Unix(),
+		},
+	}
+	for i := 0; i < 2; i++ {
+		err = db.UpdateInvoice(&inv1)
+		if err != nil {
+			t.Fatalf("UpdateInvoice: %v", err)
+		}
+	}
+
+	invs, err = db.InvoicesByStatus(int(cms.InvoiceStatusApproved))
+	if err != nil {
+		t.Fatalf("InvoicesByStatus: %v", err)
+	}
+	if !containsToken(invs, inv1.Token) || !containsToken(invs, inv3.Token) {
+		t.Fatalf("InvoicesByStatus: updated invoice not found")
+	}
+	for _, v := range invs {
+		if v.Token != inv1.Token {
+			continue
+		}
+		if len(v.Changes) != 1 {
+			t.Fatalf("InvoicesByStatus: got %v status changes, want 1",
+				len(v.Changes))
+		}
+		if len(v.LineItems) != 1 {
+			t.Fatalf("InvoicesByStatus: got %v line items, want 1",
+				len(v.LineItems))
+		}
+	}
+
+	invs, err = db.InvoicesAll()
+	if err != nil {
+		t.Fatalf("InvoicesAll: %v", err)
+	}
+	for _, v := range []database.Invoice{inv1, inv2, inv3} {
+		if !containsToken(invs, v.Token) {
+			t.Fatalf("InvoicesAll: invoice %v not found", v.Token)
+		}
+	}
+}
+
+func testExchangeRates(t *testing.T, db database.Database) {
+	month := 7
+	year := int(1000 + time.Now().UnixNano()%1000)
+
+	_, err := db.ExchangeRate(month, year)
+	if err != database.ErrExchangeRateNotFound {
+		t.Fatalf("ExchangeRate: got error %v, want %v", err,
+			database.ErrExchangeRateNotFound)
+	}
+
+	err = db.NewExchangeRate(&database.ExchangeRate{
+		Month:        uint(month),
+		Year:         uint(year),
+		ExchangeRate: 1651,
+	})
+	if err != nil {
+		t.Fatalf("NewExchangeRate: %v", err)
+	}
+
+	er, err := db.ExchangeRate(month, year)
+	if err != nil {
+		t.Fatalf("ExchangeRate: %v", err)
+	}
+	if er.ExchangeRate != 1651 {
+		t.Fatalf("ExchangeRate: got %v, want 1651", er.ExchangeRate)
+	}
+}
+
+// contains returns whether the passed in string is in the passed in slice.
+func contains(s []string, v string) bool {
+	for _, w := range s {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// syncState returns the sync state of the passed in invoice.
+func syncState(inv database.Invoice) database.SyncState {
+	return database.SyncState{
+		Token:   inv.Token,
+		Version: inv.Version,
+		Status:  inv.Status,
+	}
+}
+
+func testSync(t *testing.T, db database.Database) {
+	userID := randomHex(t, 16)
+	year := uint(1000 + time.Now().UnixNano()%1000)
+
+	// The database has a stale copy of inv1, an up to date copy of
+	// inv3 and is missing inv2. It also has a copy of inv4, which
+	// was never synced and is not in the inventory.
+	inv1 := newInvoice(t, userID, 3, year, cms.InvoiceStatusNew)
+	inv2 := newInvoice(t, userID, 3, year, cms.InvoiceStatusNew)
+	inv3 := newInvoice(t, userID, 3, year, cms.InvoiceStatusNew)
+	inv4 := newInvoice(t, userID, 3, year, cms.InvoiceStatusNew)
+	for _, v := range []database.Invoice{inv1, inv3, inv4} {
+		v := v
+		err := db.NewInvoice(&v)
+		if err != nil {
+			t.Fatalf("NewInvoice: %v", err)
+		}
+	}
+
+	inv1.Version = "2"
+	inv1.Status = cms.InvoiceStatusUpdated
+	inv1.UserID = ""
+	records := map[string]database.Invoice{
+		inv1.Token: inv1,
+		inv2.Token: inv2,
+		inv3.Token: inv3,
+	}
+	fetched := make(map[string]int) // [token]fetchCount
+	fetch := func(token string) (*database.Invoice, error) {
+		fetched[token]++
+		inv := records[token]
+		return &inv, nil
+	}
+	inventory := []database.SyncState{
+		syncState(inv1),
+		syncState(inv2),
+		syncState(inv3),
+	}
+
+	sr, err := database.Sync(db, inventory, fetch)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(sr.Added) != 1 || sr.Added[0] != inv2.Token {
+		t.Fatalf("Sync: got added %v, want %v", sr.Added, inv2.Token)
+	}
+	if len(sr.Updated) != 1 || sr.Updated[0] != inv1.Token {
+		t.Fatalf("Sync: got updated %v, want %v", sr.Updated, inv1.Token)
+	}
+	if sr.Unchanged != 1 {
+		t.Fatalf("Sync: got %v unchanged, want 1", sr.Unchanged)
+	}
+	if fetched[inv1.Token] != 1 || fetched[inv2.Token] != 1 ||
+		fetched[inv3.Token] != 0 {
+		t.Fatalf("Sync: got fetches %v, want inv1 and inv2 once", fetched)
+	}
+	if !contains(sr.Missing, inv4.Token) {
+		t.Fatalf("Sync: got missing %v, want %v", sr.Missing, inv4.Token)
+	}
+
+	inv, err := db.InvoiceByToken(inv1.Token)
+	if err != nil {
+		t.Fatalf("InvoiceByToken: %v", err)
+	}
+	if inv.Version != "2" || inv.Status != cms.InvoiceStatusUpdated {
+		t.Fatalf("Sync: got version %v status %v, want version 2 "+
+			"status %v", inv.Version, inv.Status,
+			cms.InvoiceStatusUpdated)
+	}
+	if inv.UserID != userID {
+		t.Fatalf("Sync: got user id %v, want %v", inv.UserID, userID)
+	}
+
+	states, err := db.SyncStates()
+	if err != nil {
+		t.Fatalf("SyncStates: %v", err)
+	}
+	for _, v := range inventory {
+		if states[v.Token] != v {
+			t.Fatalf("SyncStates: got %+v, want %+v", states[v.Token], v)
+		}
+	}
+
+	// A second sync must not fetch anything and should report the
+	// invoice that has disappeared from the inventory.
+	fetched = make(map[string]int)
+	sr, err = database.Sync(db, inventory[:2], fetch)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(sr.Added) != 0 || len(sr.Updated) != 0 || len(fetched) != 0 {
+		t.Fatalf("Sync: got added %v updated %v fetched %v, want none",
+			sr.Added, sr.Updated, fetched)
+	}
+	if !contains(sr.Missing, inv3.Token) {
+		t.Fatalf("Sync: got missing %v, want %v", sr.Missing, inv3.Token)
+	}
+}
+
+func testBuild(t *testing.T, db database.Database) {
+	userID := randomHex(t, 16)
+	year := uint(1000 + time.Now().UnixNano()%1000)
+
+	inv := newInvoice(t, userID, 4, year, cms.InvoiceStatusApproved)
+	inv.Changes = []database.InvoiceChange{
+		{
+			AdminPublicKey: randomHex(t, 32),
+			NewStatus:      cms.InvoiceStatusApproved,
+			Timestamp:      time.Now().Unix(),
+		},
+	}
+	err := db.NewInvoice(&inv)
+	if err != nil {
+		t.Fatalf("NewInvoice: %v", err)
+	}
+	state := syncState(inv)
+	err = db.SetSyncState(&state)
+	if err != nil {
+		t.Fatalf("SetSyncState: %v", err)
+	}
+	er := database.ExchangeRate{
+		Month:        4,
+		Year:         year,
+		ExchangeRate: 1651,
+	}
+	err = db.NewExchangeRate(&er)
+	if err != nil {
+		t.Fatalf("NewExchangeRate: %v", err)
+	}
+
+	err = db.Build("{}")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// All invoice data is dropped
+	invs, err := db.InvoicesAll()
+	if err != nil {
+		t.Fatalf("InvoicesAll: %v", err)
+	}
+	if len(invs) != 0 {
+		t.Fatalf("InvoicesAll: got %v invoices, want 0", len(invs))
+	}
+	states, err := db.SyncStates()
+	if err != nil {
+		t.Fatalf("SyncStates: %v", err)
+	}
+	if len(states) != 0 {
+		t.Fatalf("SyncStates: got %v states, want 0", len(states))
+	}
+
+	// The status changes must be dropped along with the invoices so
+	// that they don't reappear when the invoice is added again.
+	inv.Changes = nil
+	err = db.NewInvoice(&inv)
+	if err != nil {
+		t.Fatalf("NewInvoice: %v", err)
+	}
+	invs, err = db.InvoicesAll()
+	if err != nil {
+		t.Fatalf("InvoicesAll: %v", err)
+	}
+	if len(invs) != 1 || len(invs[0].Changes) != 0 {
+		t.Fatalf("InvoicesAll: got %+v, want 1 invoice without "+
+			"status changes", invs)
+	}
+
+	// Exchange rates are not invoice data and are kept
+	_, err = db.ExchangeRate(int(er.Month), int(er.Year))
+	if err != nil {
+		t.Fatalf("ExchangeRate: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"strconv"
+	"time"
+
+	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
+	database "github.com/bitum-project/politeia/politeiawww/cmsdatabase"
+)
+
+// encodeInvoice encodes a generic database.Invoice instance into a sqlite
+// Invoice.
+func encodeInvoice(dbInvoice *database.Invoice) *Invoice {
+	invoice := Invoice{
+		Token:              dbInvoice.Token,
+		UserID:             dbInvoice.UserID,
+		Month:              dbInvoice.Month,
+		Year:               dbInvoice.Year,
+		ExchangeRate:       dbInvoice.ExchangeRate,
+		Timestamp:          time.Unix(dbInvoice.Timestamp, 0),
+		Status:             uint(dbInvoice.Status),
+		StatusChangeReason: dbInvoice.StatusChangeReason,
+		PublicKey:          dbInvoice.PublicKey,
+		UserSignature:      dbInvoice.UserSignature,
+		ServerSignature:    dbInvoice.ServerSignature,
+		Version:            dbInvoice.Version,
+		ContractorName:     dbInvoice.ContractorName,
+		ContractorLocation: dbInvoice.ContractorLocation,
+		ContractorRate:     dbInvoice.ContractorRate,
+		ContractorContact:  dbInvoice.ContractorContact,
+		PaymentAddress:     dbInvoice.PaymentAddress,
+	}
+
+	for i, dbLineItem := range dbInvoice.LineItems {
+		lineItem := encodeLineItem(&dbLineItem)
+		lineItem.LineItemKey = dbLineItem.InvoiceToken + strconv.Itoa(i)
+		invoice.LineItems = append(invoice.LineItems, lineItem)
+	}
+
+	for _, dbInvoiceChange := range dbInvoice.Changes {
+		invoice.Changes = append(invoice.Changes,
+			encodeInvoiceChange(&dbInvoiceChange))
+	}
+
+	return &invoice
+}
+
+// decodeInvoice decodes a sqlite Invoice instance into a generic
+// database.Invoice.
+func decodeInvoice(invoice *Invoice) *database.Invoice {
+	dbInvoice := database.Invoice{
+		Token:              invoice.Token,
+		UserID:             invoice.UserID,
+		Username:           invoice.Username,
+		Month:              invoice.Month,
+		Year:               invoice.Year,
+		ExchangeRate:       invoice.ExchangeRate,
+		Timestamp:          invoice.Timestamp.Unix(),
+		Status:             cms.InvoiceStatusT(invoice.Status),
+		StatusChangeReason: invoice.StatusChangeReason,
+		PublicKey:          invoice.PublicKey,
+		UserSignature:      invoice.UserSignature,
+		ServerSignature:    invoice.ServerSignature,
+		Version:            invoice.Version,
+		ContractorName:     invoice.ContractorName,
+		ContractorLocation: invoice.ContractorLocation,
+		ContractorContact:  invoice.ContractorContact,
+		ContractorRate:     invoice.ContractorRate,
+		PaymentAddress:     invoice.PaymentAddress,
+	}
+
+	for _, lineItem := range invoice.LineItems {
+		dbInvoice.LineItems = append(dbInvoice.LineItems,
+			decodeLineItem(&lineItem))
+	}
+
+	for _, invoiceChange := range invoice.Changes {
+		dbInvoice.Changes = append(dbInvoice.Changes,
+			decodeInvoiceChange(&invoiceChange))
+	}
+
+	return &dbInvoice
+}
+
+// encodeLineItem encodes a database.LineItem into a sqlite line item.
+func encodeLineItem(dbLineItem *database.LineItem) LineItem {
+	return LineItem{
+		InvoiceToken: dbLineItem.InvoiceToken,
+		Type:         uint(dbLineItem.Type),
+		Domain:       dbLineItem.Domain,
+		Subdomain:    dbLineItem.Subdomain,
+		Description:  dbLineItem.Description,
+		ProposalURL:  dbLineItem.ProposalURL,
+		Labor:        dbLineItem.Labor,
+		Expenses:     dbLineItem.Expenses,
+	}
+}
+
+// decodeLineItem decodes a sqlite line item into a generic database.LineItem.
+func decodeLineItem(lineItem *LineItem) database.LineItem {
+	return database.LineItem{
+		InvoiceToken: lineItem.InvoiceToken,
+		Type:         cms.LineItemTypeT(lineItem.Type),
+		Domain:       lineItem.Domain,
+		Subdomain:    lineItem.Subdomain,
+		Description:  lineItem.Description,
+		ProposalURL:  lineItem.ProposalURL,
+		Labor:        lineItem.Labor,
+		Expenses:     lineItem.Expenses,
+	}
+}
+
+func encodeInvoiceChange(dbInvoiceChange *database.InvoiceChange) InvoiceChange {
+	return InvoiceChange{
+		AdminPublicKey: dbInvoiceChange.AdminPublicKey,
+		NewStatus:      uint(dbInvoiceChange.NewStatus),
+		Reason:         dbInvoiceChange.Reason,
+		Timestamp:      time.Unix(dbInvoiceChange.Timestamp, 0),
+	}
+}
+
+func decodeInvoiceChange(invoiceChange *InvoiceChange) database.InvoiceChange {
+	return database.InvoiceChange{
+		AdminPublicKey: invoiceChange.AdminPublicKey,
+		NewStatus:      cms.InvoiceStatusT(invoiceChange.NewStatus),
+		Reason:         invoiceChange.Reason,
+		Timestamp:      invoiceChange.Timestamp.Unix(),
+	}
+}
+
+func encodeExchangeRate(dbExchangeRate *database.ExchangeRate) ExchangeRate {
+	return ExchangeRate{
+		Month:        dbExchangeRate.Month,
+		Year:         dbExchangeRate.Year,
+		ExchangeRate: dbExchangeRate.ExchangeRate,
+	}
+}
+
+func decodeExchangeRate(exchangeRate ExchangeRate) *database.ExchangeRate {
+	return &database.ExchangeRate{
+		Month:        exchangeRate.Month,
+		Year:         exchangeRate.Year,
+		ExchangeRate: exchangeRate.ExchangeRate,
+	}
+}
+
+func encodeSyncState(dbState *database.SyncState) SyncState {
+	return SyncState{
+		Token:   dbState.Token,
+		Version: dbState.Version,
+		Status:  uint(dbState.Status),
+	}
+}
+
+func decodeSyncState(state SyncState) database.SyncState {
+	return database.SyncState{
+		Token:   state.Token,
+		Version: state.Version,
+		Status:  cms.InvoiceStatusT(state.Status),
+	}
+}
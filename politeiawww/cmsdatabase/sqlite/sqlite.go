@@ -0,0 +1,379 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	database "github.com/bitum-project/politeia/politeiawww/cmsdatabase"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+const (
+	// Database table names
+	tableNameInvoice       = "invoices"
+	tableNameLineItem      = "line_items"
+	tableNameInvoiceChange = "invoice_changes"
+	tableNameExchangeRate  = "exchange_rates"
+	tableNameSyncState     = "invoice_sync_states"
+)
+
+// sqlite implements the cmsdatabase interface using an embedded SQLite
+// database.  It is intended for running cmswww locally without needing a
+// CockroachDB cluster.
+type sqlite struct {
+	sync.RWMutex
+	shutdown  bool     // Backend is shutdown
+	recordsdb *gorm.DB // Database context
+}
+
+// NewInvoice creates a new invoice.
+//
+// NewInvoice satisfies the database interface.
+func (s *sqlite) NewInvoice(dbInvoice *database.Invoice) error {
+	invoice := encodeInvoice(dbInvoice)
+
+	log.Debugf("NewInvoice: %v", invoice.Token)
+
+	return s.recordsdb.Create(invoice).Error
+}
+
+// UpdateInvoice updates an existing invoice.  The line items of the invoice
+// are replaced by the ones in the passed in invoice and any passed in status
+// changes that have not already been recorded are added to the invoice status
+// change history.
+//
+// UpdateInvoice satisfies the database interface.
+func (s *sqlite) UpdateInvoice(dbInvoice *database.Invoice) error {
+	invoice := encodeInvoice(dbInvoice)
+
+	log.Debugf("UpdateInvoice: %v", invoice.Token)
+
+	// Status changes are saved separately so that changes that have
+	// already been recorded are not duplicated.
+	changes := invoice.Changes
+	invoice.Changes = nil
+
+	// Remove the existing line items first so that line items that
+	// were removed from the invoice don't linger.
+	tx := s.recordsdb.Begin()
+	err := tx.Where("invoice_token = ?", invoice.Token).
+		Delete(LineItem{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete line items: %v", err)
+	}
+	err = tx.Save(invoice).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Add the status changes that are not already recorded
+	var existing []InvoiceChange
+	err = tx.Where("invoice_token = ?", invoice.Token).
+		Find(&existing).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("lookup invoice changes: %v", err)
+	}
+	recorded := make(map[string]struct{}, len(existing))
+	for _, v := range existing {
+		recorded[invoiceChangeKey(v)] = struct{}{}
+	}
+	for _, v := range changes {
+		if _, ok := recorded[invoiceChangeKey(v)]; ok {
+			continue
+		}
+		v.InvoiceToken = invoice.Token
+		err = tx.Create(&v).Error
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("create invoice change: %v", err)
+		}
+		recorded[invoiceChangeKey(v)] = struct{}{}
+	}
+
+	return tx.Commit().Error
+}
+
+// invoiceChangeKey returns a key that uniquely identifies an invoice status
+// change.
+func invoiceChangeKey(c InvoiceChange) string {
+	return strconv.FormatInt(c.Timestamp.Unix(), 10) + ":" +
+		strconv.FormatUint(uint64(c.NewStatus), 10) + ":" + c.AdminPublicKey
+}
+
+// invoices returns all invoices that match the passed in query along with
+// their line items and status changes.
+func (s *sqlite) invoices(query *gorm.DB) ([]database.Invoice, error) {
+	invoices := make([]Invoice, 0, 1024) // PNOOMA
+	err := query.
+		Preload("LineItems").
+		Preload("Changes").
+		Find(&invoices).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	dbInvoices := make([]database.Invoice, 0, len(invoices))
+	for _, v := range invoices {
+		dbInvoices = append(dbInvoices, *decodeInvoice(&v))
+	}
+
+	return dbInvoices, nil
+}
+
+// InvoicesByUserID returns all invoices by userid.
+//
+// InvoicesByUserID satisfies the database interface.
+func (s *sqlite) InvoicesByUserID(userid string) ([]database.Invoice, error) {
+	log.Tracef("InvoicesByUserID")
+
+	return s.invoices(s.recordsdb.Where("user_id = ?", userid))
+}
+
+// InvoiceByToken returns an invoice by its token.
+//
+// InvoiceByToken satisfies the database interface.
+func (s *sqlite) InvoiceByToken(token string) (*database.Invoice, error) {
+	log.Debugf("InvoiceByToken: %v", token)
+
+	invoice := Invoice{
+		Token: token,
+	}
+	err := s.recordsdb.
+		Preload("LineItems").
+		Find(&invoice).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = database.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+
+	return decodeInvoice(&invoice), nil
+}
+
+// InvoicesByMonthYearStatus returns all invoices by month, year and status.
+//
+// InvoicesByMonthYearStatus satisfies the database interface.
+func (s *sqlite) InvoicesByMonthYearStatus(month, year uint16, status int) ([]database.Invoice, error) {
+	log.Tracef("InvoicesByMonthYearStatus")
+
+	return s.invoices(s.recordsdb.
+		Where("month = ? AND year = ? AND status = ?", month, year, status))
+}
+
+// InvoicesByMonthYear returns all invoices by month and year.
+//
+// InvoicesByMonthYear satisfies the database interface.
+func (s *sqlite) InvoicesByMonthYear(month, year uint16) ([]database.Invoice, error) {
+	log.Tracef("InvoicesByMonthYear")
+
+	return s.invoices(s.recordsdb.Where("month = ? AND year = ?", month, year))
+}
+
+// InvoicesByStatus returns all invoices by status.
+//
+// InvoicesByStatus satisfies the database interface.
+func (s *sqlite) InvoicesByStatus(status int) ([]database.Invoice, error) {
+	log.Tracef("InvoicesByStatus")
+
+	return s.invoices(s.recordsdb.Where("status = ?", status))
+}
+
+// InvoicesAll returns all invoices.
+//
+// InvoicesAll satisfies the database interface.
+func (s *sqlite) InvoicesAll() ([]database.Invoice, error) {
+	log.Tracef("InvoicesAll")
+
+	return s.invoices(s.recordsdb)
+}
+
+// SyncStates returns the last synced state of all invoices mapped by token.
+//
+// SyncStates satisfies the database interface.
+func (s *sqlite) SyncStates() (map[string]database.SyncState, error) {
+	log.Tracef("SyncStates")
+
+	var states []SyncState
+	err := s.recordsdb.Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dbStates := make(map[string]database.SyncState, len(states))
+	for _, v := range states {
+		dbStates[v.Token] = decodeSyncState(v)
+	}
+
+	return dbStates, nil
+}
+
+// SetSyncState creates or updates the last synced state of an invoice.
+//
+// SetSyncState satisfies the database interface.
+func (s *sqlite) SetSyncState(dbState *database.SyncState) error {
+	state := encodeSyncState(dbState)
+
+	log.Debugf("SetSyncState: %v", state.Token)
+
+	return s.recordsdb.Save(&state).Error
+}
+
+// NewExchangeRate creates a new exchange rate.
+//
+// NewExchangeRate satisfies the database interface.
+func (s *sqlite) NewExchangeRate(dbExchangeRate *database.ExchangeRate) error {
+	exchRate := encodeExchangeRate(dbExchangeRate)
+
+	log.Debugf("NewExchangeRate: %v %v", exchRate.Month, exchRate.Year)
+
+	return s.recordsdb.Create(&exchRate).Error
+}
+
+// ExchangeRate returns the exchange rate for the given month and year.
+//
+// ExchangeRate satisfies the database interface.
+func (s *sqlite) ExchangeRate(month, year int) (*database.ExchangeRate, error) {
+	log.Tracef("ExchangeRate")
+
+	exchangeRate := ExchangeRate{}
+	err := s.recordsdb.
+		Where("month = ? AND year = ?", month, year).
+		Find(&exchangeRate).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = database.ErrExchangeRateNotFound
+		}
+		return nil, err
+	}
+
+	return decodeExchangeRate(exchangeRate), nil
+}
+
+// Close satisfies the database interface.
+func (s *sqlite) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.shutdown = true
+	return s.recordsdb.Close()
+}
+
+// This function must be called within a transaction.
+func createCmsTables(tx *gorm.DB) error {
+	log.Tracef("createCmsTables")
+
+	if !tx.HasTable(tableNameInvoice) {
+		err := tx.CreateTable(&Invoice{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableNameLineItem) {
+		err := tx.CreateTable(&LineItem{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableNameInvoiceChange) {
+		err := tx.CreateTable(&InvoiceChange{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableNameExchangeRate) {
+		err := tx.CreateTable(&ExchangeRate{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableNameSyncState) {
+		err := tx.CreateTable(&SyncState{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build drops all existing invoice tables from the database then recreates
+// them.
+//
+// Build satisfies the database interface.
+func (s *sqlite) Build(payload string) error {
+	log.Tracef("Build")
+
+	// Decode the payload
+	_, err := bitumplugin.DecodeInventoryReply([]byte(payload))
+	if err != nil {
+		return fmt.Errorf("DecodeInventoryReply: %v", err)
+	}
+
+	// Drop all invoice tables
+	err = s.recordsdb.DropTableIfExists(tableNameInvoice,
+		tableNameLineItem, tableNameInvoiceChange, tableNameSyncState).Error
+	if err != nil {
+		return fmt.Errorf("drop invoice tables failed: %v", err)
+	}
+
+	return s.Setup()
+}
+
+// Setup creates the database tables if they do not already exist.
+//
+// Setup satisfies the database interface.
+func (s *sqlite) Setup() error {
+	tx := s.recordsdb.Begin()
+	err := createCmsTables(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// New returns a new sqlite context that uses the database file at the given
+// path.  The file is created if it does not exist.
+func New(dbFile string) (*sqlite, error) {
+	log.Tracef("New: %v", dbFile)
+
+	db, err := gorm.Open("sqlite3", dbFile)
+	if err != nil {
+		return nil, fmt.Errorf("open database '%v': %v", dbFile, err)
+	}
+
+	// SQLite only allows a single writer at a time. Limiting the
+	// connection pool to a single connection serializes access and
+	// prevents database is locked errors.
+	db.DB().SetMaxOpenConns(1)
+
+	s := &sqlite{
+		recordsdb: db,
+	}
+
+	// Disable gorm logging. This prevents duplicate errors from
+	// being printed since we handle errors manually.
+	s.recordsdb.LogMode(false)
+
+	// Disable automatic table name pluralization. We set table
+	// names manually.
+	s.recordsdb.SingularTable(true)
+
+	return s, nil
+}
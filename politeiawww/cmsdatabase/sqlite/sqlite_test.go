@@ -0,0 +1,35 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitum-project/politeia/politeiawww/cmsdatabase/dbtest"
+)
+
+func TestSqlite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmsdb.sqlite.test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(filepath.Join(dir, "cms.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	err = db.Setup()
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	dbtest.Run(t, db)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmsdatabase
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SyncResult describes the changes that were made to a database by Sync.
+// All token lists are sorted.
+type SyncResult struct {
+	Added     []string // Tokens of invoices that were missing from the database
+	Updated   []string // Tokens of invoices whose version or status had diverged
+	Unchanged int      // Number of invoices that were already in sync
+	Missing   []string // Tokens of stored or previously synced invoices that are not in the inventory
+}
+
+// FetchFunc returns the full invoice, including its line items and status
+// changes, for the passed in token.
+type FetchFunc func(token string) (*Invoice, error)
+
+// Sync brings the database in line with the passed in inventory, which must
+// contain the version and status of the most recent version of every invoice
+// that politeiad knows about.  The inventory is diffed against all of the
+// invoices in the database so that fetch is only called for the invoices that
+// are missing from the database or whose database copy has diverged.  The
+// sync state of every invoice in the inventory is recorded.  Invoices that are
+// in the database, or that were synced before, but are no longer in the
+// inventory are reported in the result but are not removed.
+//
+// Fetched invoices that do not have a user ID set inherit the user ID that is
+// stored in the database.
+func Sync(db Database, inventory []SyncState, fetch FetchFunc) (*SyncResult, error) {
+	synced, err := db.SyncStates()
+	if err != nil {
+		return nil, fmt.Errorf("SyncStates: %v", err)
+	}
+	invoices, err := db.InvoicesAll()
+	if err != nil {
+		return nil, fmt.Errorf("InvoicesAll: %v", err)
+	}
+	stored := make(map[string]Invoice, len(invoices)) // [token]Invoice
+	for _, v := range invoices {
+		stored[v.Token] = v
+	}
+
+	var sr SyncResult
+	for _, v := range inventory {
+		state := v
+		prev, wasSynced := synced[state.Token]
+		delete(synced, state.Token)
+		inv, ok := stored[state.Token]
+		delete(stored, state.Token)
+
+		switch {
+		case !ok:
+			// The invoice is missing from the database
+			f, err := fetch(state.Token)
+			if err != nil {
+				return nil, fmt.Errorf("fetch %v: %v", state.Token, err)
+			}
+			err = db.NewInvoice(f)
+			if err != nil {
+				return nil, fmt.Errorf("NewInvoice %v: %v", f.Token, err)
+			}
+			state.Version = f.Version
+			state.Status = f.Status
+			sr.Added = append(sr.Added, f.Token)
+
+		case inv.Version != state.Version || inv.Status != state.Status:
+			// The database copy has diverged
+			f, err := fetch(state.Token)
+			if err != nil {
+				return nil, fmt.Errorf("fetch %v: %v", state.Token, err)
+			}
+			if f.UserID == "" {
+				f.UserID = inv.UserID
+			}
+			err = db.UpdateInvoice(f)
+			if err != nil {
+				return nil, fmt.Errorf("UpdateInvoice %v: %v",
+					f.Token, err)
+			}
+			state.Version = f.Version
+			state.Status = f.Status
+			sr.Updated = append(sr.Updated, f.Token)
+
+		default:
+			sr.Unchanged++
+			if wasSynced && prev == state {
+				continue
+			}
+		}
+
+		err = db.SetSyncState(&state)
+		if err != nil {
+			return nil, fmt.Errorf("SetSyncState %v: %v", state.Token, err)
+		}
+	}
+
+	// Whatever is left over was not found in the inventory
+	for token := range stored {
+		sr.Missing = append(sr.Missing, token)
+		delete(synced, token)
+	}
+	for token := range synced {
+		sr.Missing = append(sr.Missing, token)
+	}
+
+	sort.Strings(sr.Added)
+	sort.Strings(sr.Updated)
+	sort.Strings(sr.Missing)
+
+	return &sr, nil
+}
@@ -396,6 +396,21 @@ func (p *politeiawww) handlePayInvoices(w http.ResponseWriter, r *http.Request)
 	util.RespondWithJSON(w, http.StatusOK, reply)
 }
 
+// handleSyncInvoices handles the request to sync the cms database with the
+// politeiad inventory.
+func (p *politeiawww) handleSyncInvoices(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleSyncInvoices")
+
+	reply, err := p.processSyncInvoices()
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleSyncInvoices: processSyncInvoices %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
 func (p *politeiawww) setCMSWWWRoutes() {
 	// Templates
 	//p.addTemplate(templateNewProposalSubmittedName,
@@ -453,4 +468,6 @@ func (p *politeiawww) setCMSWWWRoutes() {
 		p.handleGeneratePayouts, permissionAdmin)
 	p.addRoute(http.MethodGet, cms.RoutePayInvoices,
 		p.handlePayInvoices, permissionAdmin)
+	p.addRoute(http.MethodPost, cms.RouteSyncInvoices,
+		p.handleSyncInvoices, permissionAdmin)
 }
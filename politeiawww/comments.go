@@ -9,8 +9,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bitum-project/politeia/bitumplugin"
 	pd "github.com/bitum-project/politeia/politeiad/api/v1"
@@ -21,31 +23,6 @@ import (
 	"github.com/bitum-project/politeia/util"
 )
 
-// initCommentScores populates the comment scores cache.
-func (p *politeiawww) initCommentScores() error {
-	log.Tracef("initCommentScores")
-
-	// Fetch bitum plugin inventory from cache
-	ir, err := p.bitumInventory()
-	if err != nil {
-		return fmt.Errorf("bitumInventory: %v", err)
-	}
-
-	// XXX this could be done much more efficiently since we
-	// already have all of the like comments in the inventory
-	// response, but re-using the updateCommentScore function is
-	// simpler. This only gets run on startup so I'm not that
-	// worried about performance for right now.
-	for _, v := range ir.Comments {
-		_, err := p.updateCommentScore(v.Token, v.CommentID)
-		if err != nil {
-			return fmt.Errorf("updateCommentScore: %v", err)
-		}
-	}
-
-	return nil
-}
-
 // getComment retreives the specified comment from the cache then fills in
 // politeiawww specific data for the comment.
 func (p *politeiawww) getComment(token, commentID string) (*www.Comment, error) {
@@ -59,14 +36,6 @@ func (p *politeiawww) getComment(token, commentID string) (*www.Comment, error)
 	p.RLock()
 	defer p.RUnlock()
 
-	// Lookup comment vote score
-	score, ok := p.commentScores[token+commentID]
-	if !ok {
-		log.Errorf("getComment: comment score lookup failed for "+
-			"token:%v commentID:%v", token, commentID)
-	}
-	c.ResultVotes = score
-
 	// Lookup author info
 	userID, ok := p.userPubkeys[c.PublicKey]
 	if !ok {
@@ -79,81 +48,6 @@ func (p *politeiawww) getComment(token, commentID string) (*www.Comment, error)
 	return &c, nil
 }
 
-// updateCommentScore calculates the comment score for the specified comment
-// then updates the in-memory comment score cache.
-func (p *politeiawww) updateCommentScore(token, commentID string) (int64, error) {
-	log.Tracef("updateCommentScore: %v %v", token, commentID)
-
-	// Fetch all comment likes for the specified comment
-	likes, err := p.bitumCommentLikes(token, commentID)
-	if err != nil {
-		return 0, fmt.Errorf("bitumLikeComments: %v", err)
-	}
-
-	// Sanity check. Like comments should already be sorted in
-	// chronological order.
-	sort.SliceStable(likes, func(i, j int) bool {
-		return likes[i].Timestamp < likes[j].Timestamp
-	})
-
-	p.Lock()
-	defer p.Unlock()
-
-	// Compute the comment score. We have to keep track of each
-	// user's most recent like action because the net effect of an
-	// upvote/downvote on the comment score is dependent on the
-	// user's previous action. Example: a user upvoting a comment
-	// twice results in a net score of 0 because the second upvote
-	// is actually the user taking away their original upvote.
-	var score int64
-	userActions := make(map[string]int64) // [userID]action
-	for _, v := range likes {
-		action, err := strconv.ParseInt(v.Action, 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("parse action '%v' failed on "+
-				"commentID %v: %v", v.Action, v.CommentID, err)
-		}
-
-		// Lookup the userID of the comment author
-		userID, ok := p.userPubkeys[v.PublicKey]
-		if !ok {
-			return 0, fmt.Errorf("userID lookup failed for pubkey %v",
-				v.PublicKey)
-		}
-
-		// Lookup the previous like comment action that the author
-		// made on this comment
-		prevAction := userActions[userID]
-
-		switch {
-		case prevAction == 0:
-			// No previous action so we add the new action to the
-			// vote score
-			score += action
-			userActions[userID] = action
-
-		case prevAction == action:
-			// New action is the same as the previous action so we
-			// remove the previous action from the vote score
-			score -= prevAction
-			userActions[userID] = 0
-
-		case prevAction != action:
-			// New action is different than the previous action so
-			// we remove the previous action from the vote score
-			// and then add the new action to the vote score
-			score -= prevAction
-			score += action
-			userActions[userID] = action
-		}
-	}
-
-	// Set final comment likes score
-	p.commentScores[token+commentID] = score
-
-	return score, nil
-}
-
 func validateComment(c www.NewComment) error {
 	// max length
 	if len(c.Comment) > www.PolicyMaxCommentLength {
@@ -171,6 +65,85 @@ func validateComment(c www.NewComment) error {
 	return err
 }
 
+// commentMentionRegexp matches a username that is prefixed with an @.  The @
+// must not be preceded by a word character so that email addresses are not
+// treated as mentions.
+var commentMentionRegexp = regexp.MustCompile(`(?:^|[^\w@])@([\w.,:;\-@+()]+)`)
+
+// parseCommentMentions returns the normalized usernames that are mentioned in
+// a comment in the order that they first appear.  Trailing punctuation is not
+// considered part of a mention.  At most www.PolicyMaxCommentMentions
+// usernames are returned.
+func parseCommentMentions(comment string) []string {
+	matches := commentMentionRegexp.FindAllStringSubmatch(comment, -1)
+	usernames := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		username := formatUsername(strings.TrimRight(m[1], ".,:;)"))
+		if len(username) < www.PolicyMinUsernameLength ||
+			len(username) > www.PolicyMaxUsernameLength {
+			continue
+		}
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+		usernames = append(usernames, username)
+		if len(usernames) == www.PolicyMaxCommentMentions {
+			break
+		}
+	}
+	return usernames
+}
+
+// commentMentionedUsers returns the users that are mentioned in a comment and
+// should be notified of the mention.  The comment author is never notified.
+// Neither are the proposal author, for a top-level comment, or the parent
+// comment author, for a reply, since they are already notified of the
+// comment itself.
+func (p *politeiawww) commentMentionedUsers(c www.Comment, pr *www.ProposalRecord, author *user.User) []*user.User {
+	usernames := parseCommentMentions(c.Comment)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	// Users that are not notified of the mention
+	skip := map[string]struct{}{
+		author.ID.String(): {},
+	}
+	if c.ParentID == "0" {
+		skip[pr.UserId] = struct{}{}
+	} else {
+		parent, err := p.bitumGetComment(c.Token, c.ParentID)
+		if err != nil {
+			log.Errorf("commentMentionedUsers: bitumGetComment %v %v: %v",
+				c.Token, c.ParentID, err)
+		} else {
+			p.RLock()
+			skip[p.userPubkeys[parent.PublicKey]] = struct{}{}
+			p.RUnlock()
+		}
+	}
+
+	users := make([]*user.User, 0, len(usernames))
+	for _, username := range usernames {
+		u, err := p.db.UserGetByUsername(username)
+		if err != nil {
+			if err != user.ErrUserNotFound {
+				log.Errorf("commentMentionedUsers: UserGetByUsername "+
+					"%v: %v", username, err)
+			}
+			continue
+		}
+		if _, ok := skip[u.ID.String()]; ok || u.Deactivated {
+			continue
+		}
+		users = append(users, u)
+	}
+
+	return users
+}
+
 // processNewComment sends a new comment bitum plugin command to politeaid
 // then fetches the new comment from the cache and returns it.
 func (p *politeiawww) processNewComment(nc www.NewComment, u *user.User) (*www.NewCommentReply, error) {
@@ -220,13 +193,14 @@ func (p *politeiawww) processNewComment(nc www.NewComment, u *user.User) (*www.N
 	}
 	_, avr := convertAuthVoteFromBitum(vdr.AuthorizeVote)
 	svr := convertStartVoteReplyFromBitum(vdr.StartVoteReply)
+	_, cvr := convertCancelVoteFromBitum(vdr.CancelVote)
 
 	bb, err := p.getBestBlock()
 	if err != nil {
 		return nil, fmt.Errorf("getBestBlock: %v", err)
 	}
 
-	if getVoteStatus(avr, svr, bb) == www.PropVoteStatusFinished {
+	if getVoteStatus(avr, svr, cvr, bb) == www.PropVoteStatusFinished {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
 		}
@@ -259,6 +233,9 @@ func (p *politeiawww) processNewComment(nc www.NewComment, u *user.User) (*www.N
 		return nil, err
 	}
 
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(nc.Token)
+
 	// Handle response
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
@@ -277,11 +254,6 @@ func (p *politeiawww) processNewComment(nc www.NewComment, u *user.User) (*www.N
 		return nil, err
 	}
 
-	// Add comment to commentScores in-memory cache
-	p.Lock()
-	p.commentScores[nc.Token+ncr.CommentID] = 0
-	p.Unlock()
-
 	// Get comment from cache
 	c, err := p.getComment(nc.Token, ncr.CommentID)
 	if err != nil {
@@ -293,6 +265,14 @@ func (p *politeiawww) processNewComment(nc www.NewComment, u *user.User) (*www.N
 		Comment: c,
 	})
 
+	// Fire off comment mention events
+	for _, v := range p.commentMentionedUsers(*c, pr, u) {
+		p.fireEvent(EventTypeCommentMention, EventDataCommentMention{
+			Comment: c,
+			User:    v,
+		})
+	}
+
 	return &www.NewCommentReply{
 		Comment: *c,
 	}, nil
@@ -370,6 +350,9 @@ func (p *politeiawww) processNewCommentInvoice(nc www.NewComment, u *user.User)
 		return nil, err
 	}
 
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(nc.Token)
+
 	// Handle response
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
@@ -388,11 +371,6 @@ func (p *politeiawww) processNewCommentInvoice(nc www.NewComment, u *user.User)
 		return nil, err
 	}
 
-	// Add comment to commentScores in-memory cache
-	p.Lock()
-	p.commentScores[nc.Token+ncr.CommentID] = 0
-	p.Unlock()
-
 	// Get comment from cache
 	c, err := p.getComment(nc.Token, ncr.CommentID)
 	if err != nil {
@@ -459,7 +437,8 @@ func (p *politeiawww) processLikeComment(lc www.LikeComment, u *user.User) (*www
 		return nil, fmt.Errorf("getBestBlock: %v", err)
 	}
 
-	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply, bb)
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
 	if s == www.PropVoteStatusFinished {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
@@ -496,6 +475,7 @@ func (p *politeiawww) processLikeComment(lc www.LikeComment, u *user.User) (*www
 	}
 
 	dlc := convertLikeCommentToBitum(lc)
+	dlc.UserID = u.ID.String()
 	payload, err := bitumplugin.EncodeLikeComment(dlc)
 	if err != nil {
 		return nil, err
@@ -516,6 +496,9 @@ func (p *politeiawww) processLikeComment(lc www.LikeComment, u *user.User) (*www
 		return nil, err
 	}
 
+	// Remove the stale comment scores from the read cache
+	p.readCache.invalidateComments(lc.Token)
+
 	// Handle response
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
@@ -534,16 +517,14 @@ func (p *politeiawww) processLikeComment(lc www.LikeComment, u *user.User) (*www
 		return nil, err
 	}
 
-	// Update comment score in the in-memory cache
-	result, err := p.updateCommentScore(lc.Token, lc.CommentID)
+	// The comment score is tallied by the cache
+	c, err := p.bitumGetComment(lc.Token, lc.CommentID)
 	if err != nil {
-		log.Criticalf("processLikeComment: update comment score "+
-			"failed token:%v commentID:%v error:%v", lc.Token,
-			lc.CommentID, err)
+		return nil, fmt.Errorf("bitumGetComment: %v", err)
 	}
 
 	return &www.LikeCommentReply{
-		Result:  result,
+		Result:  c.ResultVotes,
 		Receipt: lcr.Receipt,
 		Error:   lcr.Error,
 	}, nil
@@ -591,7 +572,8 @@ func (p *politeiawww) processCensorComment(cc www.CensorComment, u *user.User) (
 		return nil, fmt.Errorf("getBestBlock: %v", err)
 	}
 
-	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply, bb)
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
 	if s == www.PropVoteStatusFinished {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
@@ -625,6 +607,9 @@ func (p *politeiawww) processCensorComment(cc www.CensorComment, u *user.User) (
 		return nil, err
 	}
 
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(cc.Token)
+
 	// Handle response
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
@@ -642,7 +627,1129 @@ func (p *politeiawww) processCensorComment(cc www.CensorComment, u *user.User) (
 		return nil, err
 	}
 
+	// Fire off user manage event
+	p.fireCommentModerationEvent(u, *c, www.UserManageCensorComment,
+		cc.Reason)
+
 	return &www.CensorCommentReply{
 		Receipt: ccr.Receipt,
 	}, nil
 }
+
+// processEditComment sends an edit comment bitum plugin command to politeiad
+// then fetches the edited comment from the cache and returns it.  Only the
+// author of a comment is allowed to edit it.
+func (p *politeiawww) processEditComment(ec www.EditComment, u *user.User) (*www.EditCommentReply, error) {
+	log.Tracef("processEditComment: %v %v %v", ec.Token, ec.CommentID, u.ID)
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, ec.PublicKey, ec.Signature,
+		ec.Token, ec.CommentID, ec.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate comment
+	if len(ec.Comment) > www.PolicyMaxCommentLength {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCommentLengthExceededPolicy,
+		}
+	}
+
+	// Ensure proposal exists and is public
+	pr, err := p.getProp(ec.Token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
+		return nil, err
+	}
+	if pr.Status != www.PropStatusPublic {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCannotCommentOnProp,
+		}
+	}
+
+	// Ensure comment exists and has not been censored or deleted
+	c, err := p.bitumGetComment(ec.Token, ec.CommentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+	if c.Censored || c.Deleted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCannotEditComment,
+		}
+	}
+
+	// Ensure user is the comment author
+	p.RLock()
+	authorID := p.userPubkeys[c.PublicKey]
+	p.RUnlock()
+	if authorID != u.ID.String() {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserNotCommentAuthor,
+		}
+	}
+
+	// Ensure proposal voting has not ended
+	vdr, err := p.bitumVoteDetails(ec.Token)
+	if err != nil {
+		return nil, fmt.Errorf("bitumVoteDetails: %v", err)
+	}
+	vd := convertVoteDetailsReplyFromBitum(*vdr)
+
+	bb, err := p.getBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("getBestBlock: %v", err)
+	}
+
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
+	if s == www.PropVoteStatusFinished {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongVoteStatus,
+		}
+	}
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := convertEditCommentToBitum(ec)
+	payload, err := bitumplugin.EncodeEditComment(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdEditComment,
+		CommandID: bitumplugin.CmdEditComment,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(ec.Token)
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	ecr, err := bitumplugin.DecodeEditCommentReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get edited comment from cache
+	wc, err := p.getComment(ec.Token, ec.CommentID)
+	if err != nil {
+		return nil, fmt.Errorf("getComment: %v", err)
+	}
+
+	return &www.EditCommentReply{
+		Comment: *wc,
+		Receipt: ecr.Receipt,
+	}, nil
+}
+
+// processDeleteComment sends a delete comment bitum plugin command to
+// politeiad then returns the delete comment receipt.  Only the author of a
+// comment is allowed to delete it.
+func (p *politeiawww) processDeleteComment(dc www.DeleteComment, u *user.User) (*www.DeleteCommentReply, error) {
+	log.Tracef("processDeleteComment: %v %v %v", dc.Token, dc.CommentID, u.ID)
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, dc.PublicKey, dc.Signature,
+		dc.Token, dc.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure comment exists and has not already been censored or
+	// deleted
+	c, err := p.bitumGetComment(dc.Token, dc.CommentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+	if c.Censored || c.Deleted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCannotDeleteComment,
+		}
+	}
+
+	// Ensure user is the comment author
+	p.RLock()
+	authorID := p.userPubkeys[c.PublicKey]
+	p.RUnlock()
+	if authorID != u.ID.String() {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserNotCommentAuthor,
+		}
+	}
+
+	// Ensure proposal voting has not ended
+	vdr, err := p.bitumVoteDetails(dc.Token)
+	if err != nil {
+		return nil, fmt.Errorf("bitumVoteDetails: %v", err)
+	}
+	vd := convertVoteDetailsReplyFromBitum(*vdr)
+
+	bb, err := p.getBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("getBestBlock: %v", err)
+	}
+
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
+	if s == www.PropVoteStatusFinished {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongVoteStatus,
+		}
+	}
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ddc := convertDeleteCommentToBitum(dc)
+	payload, err := bitumplugin.EncodeDeleteComment(ddc)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdDeleteComment,
+		CommandID: bitumplugin.CmdDeleteComment,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(dc.Token)
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	dcr, err := bitumplugin.DecodeDeleteCommentReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.DeleteCommentReply{
+		Receipt: dcr.Receipt,
+	}, nil
+}
+
+// processCommentRevisions returns the original version of a comment along
+// with every edit that has been made to it.  The revision history is not kept
+// in the cache so it is requested from politeiad.
+func (p *politeiawww) processCommentRevisions(token, commentID string) (*www.CommentRevisionsReply, error) {
+	log.Tracef("processCommentRevisions: %v %v", token, commentID)
+
+	// Ensure comment exists
+	_, err := p.bitumGetComment(token, commentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	// Request the revisions from politeiad
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := bitumplugin.EncodeCommentRevisions(
+		bitumplugin.CommentRevisions{
+			Token:     token,
+			CommentID: commentID,
+		})
+	if err != nil {
+		return nil, err
+	}
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdCommentRevisions,
+		CommandID: bitumplugin.CmdCommentRevisions,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	crr, err := bitumplugin.DecodeCommentRevisionsReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the author info of the original comment
+	c := convertCommentFromBitum(crr.Comment)
+	p.RLock()
+	c.UserID = p.userPubkeys[c.PublicKey]
+	p.RUnlock()
+	c.Username = p.getUsernameById(c.UserID)
+
+	edits := make([]www.CommentEdit, 0, len(crr.Edits))
+	for _, v := range crr.Edits {
+		edits = append(edits, convertCommentEditFromBitum(v))
+	}
+
+	return &www.CommentRevisionsReply{
+		Comment: c,
+		Edits:   edits,
+	}, nil
+}
+
+// processCommentProof returns the proof that a comment existed when the
+// comment journal of its proposal was anchored.  The proof contains the comment
+// as it was submitted so censored and deleted comments cannot be proven.
+func (p *politeiawww) processCommentProof(token, commentID string) (*www.JournalProofReply, error) {
+	log.Tracef("processCommentProof: %v %v", token, commentID)
+
+	// Ensure comment exists
+	c, err := p.bitumGetComment(token, commentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+	if c.Censored || c.Deleted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCannotProveComment,
+		}
+	}
+
+	return p.journalProof(bitumplugin.JournalProof{
+		Token:     token,
+		CommentID: commentID,
+	})
+}
+
+// journalProof requests the proof of a journal entry from politeiad.  A user
+// error is returned when the entry has not been anchored yet.
+func (p *politeiawww) journalProof(jp bitumplugin.JournalProof) (*www.JournalProofReply, error) {
+	jpr, err := p.bitumJournalProof(jp)
+	if err != nil {
+		return nil, err
+	}
+	if jpr.Anchor == "" {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusJournalEntryNotAnchored,
+		}
+	}
+
+	reply := convertJournalProofReplyFromBitum(*jpr)
+	return &reply, nil
+}
+
+// fireCommentModerationEvent fires a user manage event for an admin action
+// that was taken on a comment.  The event is recorded against the comment
+// author so that it ends up in the admin log along with the other admin
+// actions on the user.
+func (p *politeiawww) fireCommentModerationEvent(adminUser *user.User, c bitumplugin.Comment, action www.UserManageActionT, reason string) {
+	p.RLock()
+	userID := p.userPubkeys[c.PublicKey]
+	p.RUnlock()
+
+	author, err := p.getUserByIDStr(userID)
+	if err != nil {
+		log.Errorf("fireCommentModerationEvent: author lookup failed "+
+			"token:%v commentID:%v: %v", c.Token, c.CommentID, err)
+		return
+	}
+
+	p.fireEvent(EventTypeUserManage, EventDataUserManage{
+		AdminUser: adminUser,
+		User:      author,
+		ManageUser: &www.ManageUser{
+			UserID: userID,
+			Action: action,
+			Reason: fmt.Sprintf("%v:%v %v", c.Token, c.CommentID, reason),
+		},
+	})
+}
+
+// validateCommentFlagReason ensures that the provided reason is one of the
+// comment flag reasons that are allowed by the policy.
+func validateCommentFlagReason(reason string) error {
+	for _, v := range www.PolicyCommentFlagReasons {
+		if reason == v {
+			return nil
+		}
+	}
+	return www.UserError{
+		ErrorCode: www.ErrorStatusInvalidCommentFlagReason,
+	}
+}
+
+// processFlagComment sends a flag comment bitum plugin command to politeiad
+// then returns the flag comment receipt.  A user can only flag a comment once
+// until its flags are dismissed.
+func (p *politeiawww) processFlagComment(fc www.FlagComment, u *user.User) (*www.FlagCommentReply, error) {
+	log.Tracef("processFlagComment: %v %v %v", fc.Token, fc.CommentID, u.ID)
+
+	// Pay up sucker!
+	if !p.HasUserPaid(u) {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserNotPaid,
+		}
+	}
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, fc.PublicKey, fc.Signature,
+		fc.Token, fc.CommentID, fc.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate reason
+	err = validateCommentFlagReason(fc.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure comment exists and has not been censored or deleted
+	c, err := p.bitumGetComment(fc.Token, fc.CommentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+	if c.Censored || c.Deleted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCommentNotFound,
+		}
+	}
+
+	// Ensure user has not already flagged the comment
+	flags, err := p.bitumCommentFlags(fc.Token, fc.CommentID)
+	if err != nil {
+		return nil, fmt.Errorf("bitumCommentFlags: %v", err)
+	}
+	p.RLock()
+	for _, v := range flags {
+		if p.userPubkeys[v.PublicKey] == u.ID.String() {
+			p.RUnlock()
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusCommentAlreadyFlagged,
+			}
+		}
+	}
+	p.RUnlock()
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	dfc := convertFlagCommentToBitum(fc)
+	payload, err := bitumplugin.EncodeFlagComment(dfc)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdFlagComment,
+		CommandID: bitumplugin.CmdFlagComment,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	fcr, err := bitumplugin.DecodeFlagCommentReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.FlagCommentReply{
+		Receipt: fcr.Receipt,
+	}, nil
+}
+
+// processFlaggedComments returns the comment moderation queue, which contains
+// all comments that have flags which have not been dismissed.  The most
+// flagged comments are returned first.
+func (p *politeiawww) processFlaggedComments() (*www.FlaggedCommentsReply, error) {
+	log.Tracef("processFlaggedComments")
+
+	dfc, err := p.bitumFlaggedComments()
+	if err != nil {
+		return nil, fmt.Errorf("bitumFlaggedComments: %v", err)
+	}
+
+	dc := make([]bitumplugin.Comment, 0, len(dfc))
+	for _, v := range dfc {
+		dc = append(dc, v.Comment)
+	}
+	comments := p.fillComments(dc)
+
+	p.RLock()
+	defer p.RUnlock()
+
+	fc := make([]www.FlaggedComment, 0, len(dfc))
+	for i, v := range dfc {
+		flags := make([]www.CommentFlag, 0, len(v.Flags))
+		for _, f := range v.Flags {
+			userID := p.userPubkeys[f.PublicKey]
+			flags = append(flags, www.CommentFlag{
+				UserID:    userID,
+				Username:  p.getUsernameById(userID),
+				Reason:    f.Reason,
+				Timestamp: f.Timestamp,
+			})
+		}
+		fc = append(fc, www.FlaggedComment{
+			Comment: comments[i],
+			Flags:   flags,
+		})
+	}
+
+	return &www.FlaggedCommentsReply{
+		Comments: fc,
+	}, nil
+}
+
+// processDismissCommentFlags sends a dismiss comment flags bitum plugin
+// command to politeiad then returns the receipt.  The comment itself is left
+// untouched.
+func (p *politeiawww) processDismissCommentFlags(dcf www.DismissCommentFlags, u *user.User) (*www.DismissCommentFlagsReply, error) {
+	log.Tracef("processDismissCommentFlags: %v %v", dcf.Token, dcf.CommentID)
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, dcf.PublicKey, dcf.Signature,
+		dcf.Token, dcf.CommentID, dcf.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure reason is present
+	if strings.TrimSpace(dcf.Reason) == "" {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"reason cannot be blank"},
+		}
+	}
+
+	// Ensure comment exists and has flags
+	c, err := p.bitumGetComment(dcf.Token, dcf.CommentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+	flags, err := p.bitumCommentFlags(dcf.Token, dcf.CommentID)
+	if err != nil {
+		return nil, fmt.Errorf("bitumCommentFlags: %v", err)
+	}
+	if len(flags) == 0 {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCommentNotFlagged,
+		}
+	}
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ddcf := convertDismissCommentFlagsToBitum(dcf)
+	payload, err := bitumplugin.EncodeDismissCommentFlags(ddcf)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdDismissCommentFlags,
+		CommandID: bitumplugin.CmdDismissCommentFlags,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	dcfr, err := bitumplugin.DecodeDismissCommentFlagsReply(
+		[]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fire off user manage event
+	p.fireCommentModerationEvent(u, *c, www.UserManageDismissCommentFlags,
+		dcf.Reason)
+
+	return &www.DismissCommentFlagsReply{
+		Receipt: dcfr.Receipt,
+	}, nil
+}
+
+// censorshipAppeals requests the censorship appeals that match the provided
+// filter from politeiad.  The appeals are not stored in the cache since the
+// cache does not retain the text of censored comments.
+func (p *politeiawww) censorshipAppeals(ca bitumplugin.CensorshipAppeals) ([]bitumplugin.CensorshipAppeal, error) {
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := bitumplugin.EncodeCensorshipAppeals(ca)
+	if err != nil {
+		return nil, err
+	}
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdCensorshipAppeals,
+		CommandID: bitumplugin.CmdCensorshipAppeals,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := bitumplugin.DecodeCensorshipAppealsReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return car.Appeals, nil
+}
+
+// verifyCensoredComment verifies that the comment of a censorship appeal is
+// the comment that was censored.  The comment text must match the hash that
+// was recorded when the comment was censored and must be signed by the
+// comment author, either by the original comment signature or, if the
+// comment was edited, by the signature of the latest edit.
+func verifyCensoredComment(ca bitumplugin.CensorshipAppeal) error {
+	c := ca.Comment
+	if bitumplugin.CommentHash(c.Comment) != ca.Censor.CommentHash {
+		return fmt.Errorf("comment hash mismatch")
+	}
+
+	pubkey, signature := c.PublicKey, c.Signature
+	elements := []string{c.Token, c.ParentID, c.Comment}
+	if len(ca.Edits) > 0 {
+		e := ca.Edits[len(ca.Edits)-1]
+		if e.Comment != c.Comment {
+			return fmt.Errorf("comment does not match latest edit")
+		}
+		pubkey, signature = e.PublicKey, e.Signature
+		elements = []string{e.Token, e.CommentID, e.Comment}
+	}
+
+	pk, err := hex.DecodeString(pubkey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %v", err)
+	}
+	err = checkSignature(pk, signature, elements...)
+	if err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+// processAppealCensorship sends an appeal censorship bitum plugin command to
+// politeiad then returns the receipt.  Only the author of a censored comment
+// can appeal its censorship and a censorship can only be appealed once.
+func (p *politeiawww) processAppealCensorship(ac www.AppealCensorship, u *user.User) (*www.AppealCensorshipReply, error) {
+	log.Tracef("processAppealCensorship: %v %v %v", ac.Token, ac.CommentID,
+		u.ID)
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, ac.PublicKey, ac.Signature,
+		ac.Token, ac.CommentID, ac.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure reason is present
+	if strings.TrimSpace(ac.Reason) == "" {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"reason cannot be blank"},
+		}
+	}
+
+	// Ensure comment exists
+	c, err := p.bitumGetComment(ac.Token, ac.CommentID)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	// Ensure user is the comment author
+	p.RLock()
+	authorID := p.userPubkeys[c.PublicKey]
+	p.RUnlock()
+	if authorID != u.ID.String() {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserNotCommentAuthor,
+		}
+	}
+
+	// Ensure comment is censored and has not already been appealed
+	if !c.Censored {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusCannotAppealCensorship,
+			ErrorContext: []string{"comment is not censored"},
+		}
+	}
+	appeals, err := p.censorshipAppeals(bitumplugin.CensorshipAppeals{
+		Token:     ac.Token,
+		CommentID: ac.CommentID,
+		Resolved:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("censorshipAppeals: %v", err)
+	}
+	if len(appeals) > 0 {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusCannotAppealCensorship,
+			ErrorContext: []string{"censorship has already been appealed"},
+		}
+	}
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	dac := convertAppealCensorshipToBitum(ac)
+	payload, err := bitumplugin.EncodeAppealCensorship(dac)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdAppealCensorship,
+		CommandID: bitumplugin.CmdAppealCensorship,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	acr, err := bitumplugin.DecodeAppealCensorshipReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.AppealCensorshipReply{
+		Receipt: acr.Receipt,
+	}, nil
+}
+
+// processCensorshipAppeals returns all censorship appeals that have not been
+// resolved along with the comments as they were prior to being censored.
+func (p *politeiawww) processCensorshipAppeals() (*www.CensorshipAppealsReply, error) {
+	log.Tracef("processCensorshipAppeals")
+
+	dca, err := p.censorshipAppeals(bitumplugin.CensorshipAppeals{})
+	if err != nil {
+		return nil, fmt.Errorf("censorshipAppeals: %v", err)
+	}
+
+	dc := make([]bitumplugin.Comment, 0, len(dca))
+	for _, v := range dca {
+		dc = append(dc, v.Comment)
+	}
+	comments := p.fillComments(dc)
+
+	p.RLock()
+	defer p.RUnlock()
+
+	appeals := make([]www.CensorshipAppeal, 0, len(dca))
+	for i, v := range dca {
+		censorID := p.userPubkeys[v.Censor.PublicKey]
+		appeals = append(appeals, www.CensorshipAppeal{
+			Comment:         comments[i],
+			CommentHash:     v.Censor.CommentHash,
+			CensorUserID:    censorID,
+			CensorUsername:  p.getUsernameById(censorID),
+			CensorReason:    v.Censor.Reason,
+			CensorTimestamp: v.Censor.Timestamp,
+			Reason:          v.Appeal.Reason,
+			Timestamp:       v.Appeal.Timestamp,
+		})
+	}
+
+	return &www.CensorshipAppealsReply{
+		Appeals: appeals,
+	}, nil
+}
+
+// processResolveAppeal sends a resolve appeal bitum plugin command to
+// politeiad then returns the receipt.  An appeal must be resolved by an admin
+// other than the one that censored the comment.  A comment is only restored
+// after it has been verified against the hash recorded at censorship and the
+// signature of its author.
+func (p *politeiawww) processResolveAppeal(ra www.ResolveAppeal, u *user.User) (*www.ResolveAppealReply, error) {
+	log.Tracef("processResolveAppeal: %v %v %v", ra.Token, ra.CommentID,
+		ra.Action)
+
+	// Verify authenticity
+	err := checkPublicKeyAndSignature(u, ra.PublicKey, ra.Signature,
+		ra.Token, ra.CommentID, ra.Action, ra.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate action and reason
+	var action www.UserManageActionT
+	switch ra.Action {
+	case www.AppealActionUphold:
+		action = www.UserManageUpholdCensorship
+	case www.AppealActionRestore:
+		action = www.UserManageRestoreComment
+	default:
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidAppealAction,
+		}
+	}
+	if strings.TrimSpace(ra.Reason) == "" {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"reason cannot be blank"},
+		}
+	}
+
+	// Ensure the comment has a pending appeal
+	appeals, err := p.censorshipAppeals(bitumplugin.CensorshipAppeals{
+		Token:     ra.Token,
+		CommentID: ra.CommentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("censorshipAppeals: %v", err)
+	}
+	if len(appeals) == 0 {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusAppealNotFound,
+		}
+	}
+	appeal := appeals[0]
+
+	// Ensure the admin did not censor the comment
+	p.RLock()
+	censorID := p.userPubkeys[appeal.Censor.PublicKey]
+	p.RUnlock()
+	if censorID == u.ID.String() {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCannotResolveOwnCensorship,
+		}
+	}
+
+	// Ensure the comment can be restored as it was submitted
+	if ra.Action == www.AppealActionRestore {
+		err = verifyCensoredComment(appeal)
+		if err != nil {
+			return nil, fmt.Errorf("verifyCensoredComment %v:%v: %v",
+				ra.Token, ra.CommentID, err)
+		}
+	}
+
+	// Setup plugin command
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	dra := convertResolveAppealToBitum(ra)
+	payload, err := bitumplugin.EncodeResolveAppeal(dra)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdResolveAppeal,
+		CommandID: bitumplugin.CmdResolveAppeal,
+		Payload:   string(payload),
+	}
+
+	// Send plugin request
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the stale comments from the read cache
+	p.readCache.invalidateComments(ra.Token)
+
+	// Handle response
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	rar, err := bitumplugin.DecodeResolveAppealReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fire off user manage event
+	p.fireCommentModerationEvent(u, appeal.Comment, action, ra.Reason)
+
+	rr := www.ResolveAppealReply{
+		Receipt: rar.Receipt,
+	}
+	if rar.Comment != nil {
+		c := p.fillComments([]bitumplugin.Comment{*rar.Comment})
+		rr.Comment = &c[0]
+	}
+
+	return &rr, nil
+}
+
+// setUnreadComments fills in the number of comments of each of the passed in
+// proposals that the passed in user has not read.  A comment is unread when
+// it was made by another user after the user last read the proposal
+// comments.  Nothing is filled in when there is no user.
+func (p *politeiawww) setUnreadComments(props []www.ProposalRecord, u *user.User) error {
+	if u == nil || len(props) == 0 {
+		return nil
+	}
+
+	accessTimes := make(map[string]int64, len(props)) // [token]accessTime
+	for _, v := range props {
+		token := v.CensorshipRecord.Token
+		accessTimes[token] = u.ProposalCommentsAccessTimes[token]
+	}
+	unread, err := p.bitumUnreadComments(bitumplugin.UnreadComments{
+		AccessTimes: accessTimes,
+		PublicKeys:  userPublicKeys(u),
+	})
+	if err != nil {
+		return fmt.Errorf("bitumUnreadComments: %v", err)
+	}
+
+	for i, v := range props {
+		props[i].UnreadComments = unread[v.CensorshipRecord.Token].Count
+	}
+
+	return nil
+}
+
+// processMarkCommentsRead marks all of the comments of the passed in proposal
+// as read by updating the user's access time of the proposal comments.
+func (p *politeiawww) processMarkCommentsRead(mcr www.MarkCommentsRead, u *user.User) (*www.MarkCommentsReadReply, error) {
+	log.Tracef("processMarkCommentsRead: %v %v", mcr.Token, u.ID)
+
+	// Ensure proposal exists
+	_, err := p.getProp(mcr.Token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	if u.ProposalCommentsAccessTimes == nil {
+		u.ProposalCommentsAccessTimes = make(map[string]int64)
+	}
+	accessTime := time.Now().Unix()
+	u.ProposalCommentsAccessTimes[mcr.Token] = accessTime
+	err = p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.MarkCommentsReadReply{
+		AccessTime: accessTime,
+	}, nil
+}
+
+// processUserUnread returns the proposals that have comments which the passed
+// in user has not read since they last read the proposal comments.  Only
+// proposals whose comments the user has read before are considered.
+func (p *politeiawww) processUserUnread(u *user.User) (*www.UserUnreadReply, error) {
+	log.Tracef("processUserUnread: %v", u.ID)
+
+	unread, err := p.bitumUnreadComments(bitumplugin.UnreadComments{
+		AccessTimes: u.ProposalCommentsAccessTimes,
+		PublicKeys:  userPublicKeys(u),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bitumUnreadComments: %v", err)
+	}
+
+	up := make([]www.UnreadProposal, 0, len(unread))
+	for token, v := range unread {
+		up = append(up, www.UnreadProposal{
+			Token:          token,
+			UnreadComments: v.Count,
+			LatestComment:  v.Latest,
+			AccessTime:     u.ProposalCommentsAccessTimes[token],
+		})
+	}
+	sort.Slice(up, func(i, j int) bool {
+		if up[i].LatestComment != up[j].LatestComment {
+			return up[i].LatestComment > up[j].LatestComment
+		}
+		return up[i].Token < up[j].Token
+	})
+
+	return &www.UserUnreadReply{
+		Proposals: up,
+	}, nil
+}
@@ -0,0 +1,415 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	pd "github.com/bitum-project/politeia/politeiad/api/v1"
+	"github.com/bitum-project/politeia/politeiad/api/v1/identity"
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/politeiawww/user"
+	"github.com/bitum-project/politeia/util"
+	"github.com/go-test/deep"
+)
+
+func TestValidateCommentFlagReason(t *testing.T) {
+	invalidReason := www.UserError{
+		ErrorCode: www.ErrorStatusInvalidCommentFlagReason,
+	}
+
+	var tests = []struct {
+		name   string
+		reason string
+		want   error
+	}{
+		{"spam", www.CommentFlagReasonSpam, nil},
+		{"abuse", www.CommentFlagReasonAbuse, nil},
+		{"off topic", www.CommentFlagReasonOffTopic, nil},
+		{"other", www.CommentFlagReasonOther, nil},
+		{"empty", "", invalidReason},
+		{"wrong case", "Spam", invalidReason},
+		{"unknown", "boring", invalidReason},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := validateCommentFlagReason(v.reason)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestVerifyCensoredComment(t *testing.T) {
+	id, err := identity.New()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	pubkey := hex.EncodeToString(id.Public.Key[:])
+	sign := func(msg string) string {
+		s := id.SignMessage([]byte(msg))
+		return hex.EncodeToString(s[:])
+	}
+
+	token := "abf0fd1fc1b8c1c9535685373dce6c54948b7eb018e17e3a8cea26a3c9b85684"
+	c := bitumplugin.Comment{
+		Token:     token,
+		ParentID:  "0",
+		Comment:   "original",
+		CommentID: "1",
+		PublicKey: pubkey,
+		Signature: sign(token + "0" + "original"),
+	}
+	e := bitumplugin.EditComment{
+		Token:     token,
+		CommentID: "1",
+		Comment:   "edited",
+		PublicKey: pubkey,
+		Signature: sign(token + "1" + "edited"),
+	}
+	edited := c
+	edited.Comment = e.Comment
+
+	// Setup tests
+	var tests = []struct {
+		name    string
+		appeal  bitumplugin.CensorshipAppeal
+		wantErr bool
+	}{
+		{"unedited comment",
+			bitumplugin.CensorshipAppeal{
+				Comment: c,
+				Censor: bitumplugin.CensorComment{
+					CommentHash: bitumplugin.CommentHash(c.Comment),
+				},
+			}, false},
+		{"edited comment",
+			bitumplugin.CensorshipAppeal{
+				Comment: edited,
+				Edits:   []bitumplugin.EditComment{e},
+				Censor: bitumplugin.CensorComment{
+					CommentHash: bitumplugin.CommentHash(e.Comment),
+				},
+			}, false},
+		{"hash mismatch",
+			bitumplugin.CensorshipAppeal{
+				Comment: c,
+				Censor: bitumplugin.CensorComment{
+					CommentHash: bitumplugin.CommentHash("tampered"),
+				},
+			}, true},
+		{"edited comment without edits",
+			bitumplugin.CensorshipAppeal{
+				Comment: edited,
+				Censor: bitumplugin.CensorComment{
+					CommentHash: bitumplugin.CommentHash(e.Comment),
+				},
+			}, true},
+		{"comment does not match edit",
+			bitumplugin.CensorshipAppeal{
+				Comment: c,
+				Edits:   []bitumplugin.EditComment{e},
+				Censor: bitumplugin.CensorComment{
+					CommentHash: bitumplugin.CommentHash(c.Comment),
+				},
+			}, true},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := verifyCensoredComment(v.appeal)
+			if (err != nil) != v.wantErr {
+				t.Errorf("got error %v, want error %v", err, v.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCommentMentions(t *testing.T) {
+	// Mentions beyond the policy limit are ignored
+	var many []string
+	var manyWant []string
+	for i := 0; i < www.PolicyMaxCommentMentions+5; i++ {
+		username := fmt.Sprintf("user%v", i)
+		many = append(many, "@"+username)
+		if i < www.PolicyMaxCommentMentions {
+			manyWant = append(manyWant, username)
+		}
+	}
+
+	var tests = []struct {
+		name    string
+		comment string
+		want    []string
+	}{
+		{"no mentions", "no mentions here", []string{}},
+		{"single mention", "@alice what do you think?",
+			[]string{"alice"}},
+		{"multiple mentions", "cc @alice and @bob",
+			[]string{"alice", "bob"}},
+		{"duplicate mentions", "@alice @bob @Alice",
+			[]string{"alice", "bob"}},
+		{"trailing punctuation", "thanks @alice, @bob.",
+			[]string{"alice", "bob"}},
+		{"parentheses", "(see @alice)", []string{"alice"}},
+		{"markdown", "**@alice**", []string{"alice"}},
+		{"email address", "mail me at bob@example.com", []string{}},
+		{"too short", "@al", []string{}},
+		{"too long", "@" + strings.Repeat("a",
+			www.PolicyMaxUsernameLength+1), []string{}},
+		{"mention limit", strings.Join(many, " "), manyWant},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := parseCommentMentions(v.comment)
+			diff := deep.Equal(got, v.want)
+			if diff != nil {
+				t.Errorf("got/want diff:\n%v", diff)
+			}
+		})
+	}
+}
+
+func newCommentCmd(t *testing.T, token, comment string, id *identity.FullIdentity) pd.PluginCommand {
+	sig := id.SignMessage([]byte(token + comment))
+	payload, err := bitumplugin.EncodeNewComment(bitumplugin.NewComment{
+		Token:     token,
+		Comment:   comment,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: hex.EncodeToString(id.Public.Key[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdNewComment,
+		CommandID: bitumplugin.CmdNewComment,
+		Payload:   string(payload),
+	}
+}
+
+func newCensorCommentCmd(t *testing.T, token, commentID string, id *identity.FullIdentity) pd.PluginCommand {
+	reason := "spam"
+	sig := id.SignMessage([]byte(token + commentID + reason))
+	payload, err := bitumplugin.EncodeCensorComment(bitumplugin.CensorComment{
+		Token:     token,
+		CommentID: commentID,
+		Reason:    reason,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: hex.EncodeToString(id.Public.Key[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdCensorComment,
+		CommandID: bitumplugin.CmdCensorComment,
+		Payload:   string(payload),
+	}
+}
+
+func newDeleteComment(token, commentID string, id *identity.FullIdentity) www.DeleteComment {
+	sig := id.SignMessage([]byte(token + commentID))
+	return www.DeleteComment{
+		Token:     token,
+		CommentID: commentID,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: hex.EncodeToString(id.Public.Key[:]),
+	}
+}
+
+func newEditComment(token, commentID, comment string, id *identity.FullIdentity) www.EditComment {
+	sig := id.SignMessage([]byte(token + commentID + comment))
+	return www.EditComment{
+		Token:     token,
+		CommentID: commentID,
+		Comment:   comment,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: hex.EncodeToString(id.Public.Key[:]),
+	}
+}
+
+func TestProcessDeleteComment(t *testing.T) {
+	// Setup test environment
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create test data. Comment 1 is deleted by the tests and
+	// comment 2 is censored by an admin.
+	admin, adminID := newUser(t, p, true, true)
+	author, authorID := newUser(t, p, true, false)
+	other, otherID := newUser(t, p, true, false)
+
+	prop := newProposalRecord(t, admin, adminID, www.PropStatusPublic)
+	token := prop.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, prop))
+	d.Plugin(t, newCommentCmd(t, token, "first", authorID))
+	d.Plugin(t, newCommentCmd(t, token, "second", authorID))
+	d.Plugin(t, newCensorCommentCmd(t, token, "2", adminID))
+
+	invalidSig := newDeleteComment(token, "1", authorID)
+	invalidSig.CommentID = "2"
+
+	// Setup tests.  The tests are run in order since a comment can
+	// only be deleted once.
+	var tests = []struct {
+		name string
+		user *user.User
+		dc   www.DeleteComment
+		want error
+	}{
+		{"invalid signature", author, invalidSig,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidSignature,
+			}},
+
+		{"comment not found", author,
+			newDeleteComment(token, "99", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}},
+
+		{"not comment author", other,
+			newDeleteComment(token, "1", otherID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusUserNotCommentAuthor,
+			}},
+
+		{"comment censored", author,
+			newDeleteComment(token, "2", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCannotDeleteComment,
+			}},
+
+		{"success", author,
+			newDeleteComment(token, "1", authorID), nil},
+
+		{"comment already deleted", author,
+			newDeleteComment(token, "1", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCannotDeleteComment,
+			}},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			reply, err := p.processDeleteComment(v.dc, v.user)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err != nil {
+				// Test case passes
+				return
+			}
+
+			if reply.Receipt == "" {
+				t.Fatalf("unexpected reply %+v", reply)
+			}
+		})
+	}
+}
+
+func TestProcessEditComment(t *testing.T) {
+	// Setup test environment
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create test data. Comment 1 is deleted by its author and
+	// comment 2 is censored by an admin.
+	admin, adminID := newUser(t, p, true, true)
+	author, authorID := newUser(t, p, true, false)
+	other, otherID := newUser(t, p, true, false)
+
+	prop := newProposalRecord(t, admin, adminID, www.PropStatusPublic)
+	token := prop.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, prop))
+	d.Plugin(t, newCommentCmd(t, token, "first", authorID))
+	d.Plugin(t, newCommentCmd(t, token, "second", authorID))
+	d.Plugin(t, newCommentCmd(t, token, "third", authorID))
+	d.Plugin(t, newCensorCommentCmd(t, token, "2", adminID))
+
+	_, err := p.processDeleteComment(newDeleteComment(token, "1",
+		authorID), author)
+	if err != nil {
+		t.Fatalf("processDeleteComment: %v", err)
+	}
+
+	var tests = []struct {
+		name string
+		user *user.User
+		ec   www.EditComment
+		want error
+	}{
+		{"comment not found", author,
+			newEditComment(token, "99", "edit", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCommentNotFound,
+			}},
+
+		{"comment deleted", author,
+			newEditComment(token, "1", "edit", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCannotEditComment,
+			}},
+
+		{"comment censored", author,
+			newEditComment(token, "2", "edit", authorID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCannotEditComment,
+			}},
+
+		{"not comment author", other,
+			newEditComment(token, "3", "edit", otherID),
+			www.UserError{
+				ErrorCode: www.ErrorStatusUserNotCommentAuthor,
+			}},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := p.processEditComment(v.ec, v.user)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/bitum-project/politeia/util/version"
 
 	"github.com/bitum-project/politeia/politeiad/api/v1"
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
 	"github.com/bitum-project/politeia/politeiawww/sharedconfig"
 	"github.com/bitum-project/politeia/util"
 	flags "github.com/jessevdk/go-flags"
@@ -74,6 +75,16 @@ const (
 	userDBCockroach = "cockroachdb"
 
 	defaultUserDB = userDBLevel
+
+	// CMS database options
+	cmsDBCockroach = "cockroachdb"
+	cmsDBSqlite    = "sqlite"
+
+	defaultCMSDB = cmsDBCockroach
+
+	// defaultCMSDBFilename is the filename of the sqlite cms database.
+	// It is created in the data directory.
+	defaultCMSDBFilename = "cms.db"
 )
 
 var (
@@ -123,6 +134,7 @@ type config struct {
 	DBCert                   string `long:"dbcert" description:"File containing the politeiawww client certificate for the database"`
 	DBKey                    string `long:"dbkey" description:"File containing the politeiawww client certificate key for the database"`
 	UserDB                   string `long:"userdb" description:"Database choice for the user database"`
+	CMSDB                    string `long:"cmsdb" description:"Database choice for the cms database when running in cmswww mode {cockroachdb, sqlite}"`
 	EncryptionKey            string `long:"encryptionkey" description:"File containing encryption key used for encrypting user data at rest"`
 	OldEncryptionKey         string `long:"oldencryptionkey" description:"File containing old encryption key (only set when rotating keys)"`
 	FetchIdentity            bool   `long:"fetchidentity" description:"Whether or not politeiawww fetches the identity from politeiad."`
@@ -137,7 +149,19 @@ type config struct {
 	Mode                     string `long:"mode" description:"Mode www runs as. Supported values: piwww, cmswww"`
 	SMTPSkipVerify           bool   `long:"smtpskipverify" description:"Skip SMTP TLS cert verification. Will only skip if SMTPCert is empty"`
 	SMTPCert                 string `long:"smtpcert" description:"File containing the smtp certificate file"`
+	ReadCacheSize            int    `long:"readcachesize" description:"Maximum number of proposals, comment lists and vote summaries to keep in the in-memory read cache; 0 disables the read cache"`
 	SystemCerts              *x509.CertPool
+	VotePolicies             []string `long:"votepolicy" description:"Add a named vote policy in the format name:duration:quorumpercentage:passpercentage[:optionid,optionid,...]"`
+	votePolicies             map[string]www.VotePolicy
+	CommentRate              float64 `long:"commentrate" description:"Number of comments per minute that a user may submit; 0 disables per user comment rate limiting"`
+	CommentBurst             int     `long:"commentburst" description:"Number of comments that a user may submit in a burst"`
+	CommentIPRate            float64 `long:"commentiprate" description:"Number of comments per minute that may be submitted from an IP address; 0 disables per IP address comment rate limiting"`
+	CommentIPBurst           int     `long:"commentipburst" description:"Number of comments that may be submitted from an IP address in a burst"`
+	LikeRate                 float64 `long:"likerate" description:"Number of comment likes per minute that a user may submit; 0 disables per user comment like rate limiting"`
+	LikeBurst                int     `long:"likeburst" description:"Number of comment likes that a user may submit in a burst"`
+	LikeIPRate               float64 `long:"likeiprate" description:"Number of comment likes per minute that may be submitted from an IP address; 0 disables per IP address comment like rate limiting"`
+	LikeIPBurst              int     `long:"likeipburst" description:"Number of comment likes that may be submitted from an IP address in a burst"`
+	TrustProxy               bool    `long:"trustproxy" description:"Use the client address that a reverse proxy appends to the X-Forwarded-For header for rate limiting; only enable when politeiawww is only reachable through a trusted proxy"`
 }
 
 // serviceOptions defines the configuration options for the rpc as a service
@@ -308,6 +332,69 @@ func normalizeAddresses(addrs []string, defaultPort string) []string {
 	return removeDuplicateAddresses(addrs)
 }
 
+// parseVotePolicies parses the passed in vote policy strings and returns the
+// vote policies mapped by name.  A vote policy string has the format
+// name:duration:quorumpercentage:passpercentage[:optionid,optionid,...].
+func parseVotePolicies(policies []string, durationMin, durationMax uint32) (map[string]www.VotePolicy, error) {
+	vp := make(map[string]www.VotePolicy, len(policies))
+	for _, v := range policies {
+		fields := strings.Split(v, ":")
+		if len(fields) != 4 && len(fields) != 5 {
+			return nil, fmt.Errorf("invalid vote policy '%v'", v)
+		}
+
+		name := fields[0]
+		if name == "" {
+			return nil, fmt.Errorf("invalid vote policy '%v': "+
+				"name is empty", v)
+		}
+		if _, ok := vp[name]; ok {
+			return nil, fmt.Errorf("duplicate vote policy '%v'", name)
+		}
+
+		var params [3]uint32
+		for k, f := range fields[1:4] {
+			p, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vote policy '%v': %v",
+					v, err)
+			}
+			params[k] = uint32(p)
+		}
+		duration, quorum, pass := params[0], params[1], params[2]
+		if duration < durationMin || duration > durationMax {
+			return nil, fmt.Errorf("invalid vote policy '%v': "+
+				"duration must be between %v and %v", v, durationMin,
+				durationMax)
+		}
+		if quorum > 100 || pass > 100 {
+			return nil, fmt.Errorf("invalid vote policy '%v': "+
+				"percentages must not exceed 100", v)
+		}
+
+		var options []string
+		if len(fields) == 5 {
+			options = strings.Split(fields[4], ",")
+			for _, o := range options {
+				if o == "" {
+					return nil, fmt.Errorf("invalid vote policy "+
+						"'%v': empty option id", v)
+				}
+			}
+		}
+
+		vp[name] = www.VotePolicy{
+			Name:             name,
+			Duration:         duration,
+			QuorumPercentage: quorum,
+			PassPercentage:   pass,
+			Options:          options,
+		}
+	}
+
+	return vp, nil
+}
+
 // filesExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -391,6 +478,16 @@ func loadConfig() (*config, []string, error) {
 		MailAddress:              defaultMailAddress,
 		Mode:                     defaultWWWMode,
 		UserDB:                   defaultUserDB,
+		CMSDB:                    defaultCMSDB,
+		ReadCacheSize:            defaultReadCacheSize,
+		CommentRate:              defaultCommentRate,
+		CommentBurst:             defaultCommentBurst,
+		CommentIPRate:            defaultCommentIPRate,
+		CommentIPBurst:           defaultCommentIPBurst,
+		LikeRate:                 defaultLikeRate,
+		LikeBurst:                defaultLikeBurst,
+		LikeIPRate:               defaultLikeIPRate,
+		LikeIPBurst:              defaultLikeIPBurst,
 	}
 
 	// Service options which are only added on Windows.
@@ -635,6 +732,34 @@ func loadConfig() (*config, []string, error) {
 			"be either leveldb or cockroachdb", cfg.UserDB)
 	}
 
+	// Validate cms database selection.
+	switch cfg.CMSDB {
+	case cmsDBCockroach, cmsDBSqlite:
+		// Valid selection; continue
+	default:
+		return nil, nil, fmt.Errorf("invalid cmsdb '%v'; must "+
+			"be either cockroachdb or sqlite", cfg.CMSDB)
+	}
+
+	// Parse vote policies.
+	cfg.votePolicies, err = parseVotePolicies(cfg.VotePolicies,
+		cfg.VoteDurationMin, cfg.VoteDurationMax)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Validate rate limits.
+	if cfg.CommentRate < 0 || cfg.CommentIPRate < 0 ||
+		cfg.LikeRate < 0 || cfg.LikeIPRate < 0 {
+		return nil, nil, fmt.Errorf("commentrate, commentiprate, " +
+			"likerate and likeiprate must not be negative")
+	}
+	if cfg.CommentBurst < 1 || cfg.CommentIPBurst < 1 ||
+		cfg.LikeBurst < 1 || cfg.LikeIPBurst < 1 {
+		return nil, nil, fmt.Errorf("commentburst, commentipburst, " +
+			"likeburst and likeipburst must be at least 1")
+	}
+
 	// Validate encryption keys.
 	cfg.EncryptionKey = cleanAndExpandPath(cfg.EncryptionKey)
 	cfg.OldEncryptionKey = cleanAndExpandPath(cfg.OldEncryptionKey)
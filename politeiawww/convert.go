@@ -74,11 +74,14 @@ func convertVoteOptionsFromWWW(vo []www.VoteOption) []bitumplugin.VoteOption {
 func convertVoteFromWWW(v www.Vote) bitumplugin.Vote {
 	return bitumplugin.Vote{
 		Token:            v.Token,
+		Type:             bitumplugin.VoteT(v.Type),
 		Mask:             v.Mask,
 		Duration:         v.Duration,
 		QuorumPercentage: v.QuorumPercentage,
 		PassPercentage:   v.PassPercentage,
 		Options:          convertVoteOptionsFromWWW(v.Options),
+		AllowRevote:      v.AllowRevote,
+		StartHeight:      v.StartHeight,
 	}
 }
 
@@ -220,6 +223,43 @@ func convertPropStatusFromCache(s cache.RecordStatusT) www.PropStatusT {
 	return www.PropStatusInvalid
 }
 
+// convertPropStatesToCache returns the cache record statuses that make up the
+// proposal states that are set to true in the passed in state map.
+func convertPropStatesToCache(states map[www.PropStateT]bool) []cache.RecordStatusT {
+	statuses := make([]cache.RecordStatusT, 0, 8)
+	if states[www.PropStateUnvetted] {
+		statuses = append(statuses, cache.RecordStatusNotReviewed,
+			cache.RecordStatusUnreviewedChanges,
+			cache.RecordStatusCensored)
+	}
+	if states[www.PropStateVetted] {
+		statuses = append(statuses, cache.RecordStatusPublic,
+			cache.RecordStatusArchived)
+	}
+	return statuses
+}
+
+// convertPropVoteStatusToCache converts a proposal vote status into a cache
+// vote status.  VoteStatusInvalid is returned for vote statuses that cannot
+// be used to filter proposals.
+func convertPropVoteStatusToCache(s www.PropVoteStatusT) cache.VoteStatusT {
+	switch s {
+	case www.PropVoteStatusNotAuthorized:
+		return cache.VoteStatusNotAuthorized
+	case www.PropVoteStatusAuthorized:
+		return cache.VoteStatusAuthorized
+	case www.PropVoteStatusScheduled:
+		return cache.VoteStatusScheduled
+	case www.PropVoteStatusStarted:
+		return cache.VoteStatusStarted
+	case www.PropVoteStatusFinished:
+		return cache.VoteStatusFinished
+	case www.PropVoteStatusCancelled:
+		return cache.VoteStatusCancelled
+	}
+	return cache.VoteStatusInvalid
+}
+
 func convertPropFromCache(r cache.Record) www.ProposalRecord {
 	// Decode markdown stream payloads
 	var bpm *BackendProposalMetadata
@@ -350,21 +390,98 @@ func convertCensorCommentToBitum(cc www.CensorComment) bitumplugin.CensorComment
 }
 
 func convertCommentFromBitum(c bitumplugin.Comment) www.Comment {
-	// ResultVotes, UserID, and Username are filled in as zero
-	// values since a cache comment does not contain this data.
+	// UserID and Username are filled in as zero values since a
+	// cache comment does not contain this data.
 	return www.Comment{
-		Token:       c.Token,
-		ParentID:    c.ParentID,
-		Comment:     c.Comment,
-		Signature:   c.Signature,
-		PublicKey:   c.PublicKey,
-		CommentID:   c.CommentID,
-		Receipt:     c.Receipt,
-		Timestamp:   c.Timestamp,
-		ResultVotes: 0,
-		UserID:      "",
-		Username:    "",
-		Censored:    c.Censored,
+		Token:         c.Token,
+		ParentID:      c.ParentID,
+		Comment:       c.Comment,
+		Signature:     c.Signature,
+		PublicKey:     c.PublicKey,
+		CommentID:     c.CommentID,
+		Receipt:       c.Receipt,
+		Timestamp:     c.Timestamp,
+		ResultVotes:   c.ResultVotes,
+		UserID:        "",
+		Username:      "",
+		Censored:      c.Censored,
+		Deleted:       c.Deleted,
+		Edits:         c.Edits,
+		EditTimestamp: c.EditTimestamp,
+		EditSignature: c.EditSignature,
+		EditPublicKey: c.EditPublicKey,
+		EditReceipt:   c.EditReceipt,
+		Replies:       c.Replies,
+	}
+}
+
+func convertEditCommentToBitum(ec www.EditComment) bitumplugin.EditComment {
+	return bitumplugin.EditComment{
+		Token:     ec.Token,
+		CommentID: ec.CommentID,
+		Comment:   ec.Comment,
+		Signature: ec.Signature,
+		PublicKey: ec.PublicKey,
+	}
+}
+
+func convertDeleteCommentToBitum(dc www.DeleteComment) bitumplugin.DeleteComment {
+	return bitumplugin.DeleteComment{
+		Token:     dc.Token,
+		CommentID: dc.CommentID,
+		Signature: dc.Signature,
+		PublicKey: dc.PublicKey,
+	}
+}
+
+func convertFlagCommentToBitum(fc www.FlagComment) bitumplugin.FlagComment {
+	return bitumplugin.FlagComment{
+		Token:     fc.Token,
+		CommentID: fc.CommentID,
+		Reason:    fc.Reason,
+		Signature: fc.Signature,
+		PublicKey: fc.PublicKey,
+	}
+}
+
+func convertDismissCommentFlagsToBitum(dcf www.DismissCommentFlags) bitumplugin.DismissCommentFlags {
+	return bitumplugin.DismissCommentFlags{
+		Token:     dcf.Token,
+		CommentID: dcf.CommentID,
+		Reason:    dcf.Reason,
+		Signature: dcf.Signature,
+		PublicKey: dcf.PublicKey,
+	}
+}
+
+func convertAppealCensorshipToBitum(ac www.AppealCensorship) bitumplugin.AppealCensorship {
+	return bitumplugin.AppealCensorship{
+		Token:     ac.Token,
+		CommentID: ac.CommentID,
+		Reason:    ac.Reason,
+		Signature: ac.Signature,
+		PublicKey: ac.PublicKey,
+	}
+}
+
+func convertResolveAppealToBitum(ra www.ResolveAppeal) bitumplugin.ResolveAppeal {
+	return bitumplugin.ResolveAppeal{
+		Token:     ra.Token,
+		CommentID: ra.CommentID,
+		Action:    ra.Action,
+		Reason:    ra.Reason,
+		Signature: ra.Signature,
+		PublicKey: ra.PublicKey,
+	}
+}
+
+func convertCommentEditFromBitum(ec bitumplugin.EditComment) www.CommentEdit {
+	return www.CommentEdit{
+		Comment:   ec.Comment,
+		Signature: ec.Signature,
+		PublicKey: ec.PublicKey,
+		Receipt:   ec.Receipt,
+		Timestamp: ec.Timestamp,
 	}
 }
 
@@ -412,11 +529,15 @@ func convertStartVoteFromBitum(sv bitumplugin.StartVote) www.StartVote {
 		PublicKey: sv.PublicKey,
 		Vote: www.Vote{
 			Token:            sv.Vote.Token,
+			Type:             www.VoteT(sv.Vote.Type),
 			Mask:             sv.Vote.Mask,
 			Duration:         sv.Vote.Duration,
 			QuorumPercentage: sv.Vote.QuorumPercentage,
 			PassPercentage:   sv.Vote.PassPercentage,
 			Options:          opts,
+			RunoffTokens:     sv.Vote.RunoffTokens,
+			AllowRevote:      sv.Vote.AllowRevote,
+			StartHeight:      sv.Vote.StartHeight,
 		},
 		Signature: sv.Signature,
 	}
@@ -431,13 +552,32 @@ func convertStartVoteReplyFromBitum(svr bitumplugin.StartVoteReply) www.StartVot
 	}
 }
 
+func convertCancelVoteFromBitum(dcv bitumplugin.CancelVote) (www.CancelVote, www.CancelVoteReply) {
+	cv := www.CancelVote{
+		Token:     dcv.Token,
+		Reason:    dcv.Reason,
+		Signature: dcv.Signature,
+		PublicKey: dcv.PublicKey,
+	}
+
+	cvr := www.CancelVoteReply{
+		Receipt: dcv.Receipt,
+	}
+
+	return cv, cvr
+}
+
 func convertVoteDetailsReplyFromBitum(vdr bitumplugin.VoteDetailsReply) VoteDetails {
 	av, avr := convertAuthVoteFromBitum(vdr.AuthorizeVote)
+	cv, cvr := convertCancelVoteFromBitum(vdr.CancelVote)
 	return VoteDetails{
 		AuthorizeVote:      av,
 		AuthorizeVoteReply: avr,
 		StartVote:          convertStartVoteFromBitum(vdr.StartVote),
 		StartVoteReply:     convertStartVoteReplyFromBitum(vdr.StartVoteReply),
+		CancelVote:         cv,
+		CancelVoteReply:    cvr,
+		ScheduledVote:      convertStartVoteFromBitum(vdr.ScheduledVote),
 	}
 }
 
@@ -450,6 +590,21 @@ func convertCastVoteFromBitum(cv bitumplugin.CastVote) www.CastVote {
 	}
 }
 
+func convertJournalProofReplyFromBitum(jpr bitumplugin.JournalProofReply) www.JournalProofReply {
+	return www.JournalProofReply{
+		Journal:        jpr.Journal,
+		Entry:          jpr.Entry,
+		Index:          jpr.Index,
+		Entries:        jpr.Entries,
+		JournalHead:    jpr.JournalHead,
+		MerklePath:     jpr.MerklePath,
+		Anchor:         jpr.Anchor,
+		Timestamp:      jpr.Timestamp,
+		ChainTimestamp: jpr.ChainTimestamp,
+		Transaction:    jpr.Transaction,
+	}
+}
+
 func convertCastVotesFromBitum(cv []bitumplugin.CastVote) []www.CastVote {
 	cvr := make([]www.CastVote, 0, len(cv))
 	for _, v := range cv {
@@ -504,6 +659,8 @@ func convertTokenInventoryReplyFromBitum(r bitumplugin.TokenInventoryReply) www.
 		Approved:  r.Approved,
 		Rejected:  r.Rejected,
 		Abandoned: r.Abandoned,
+		Cancelled: r.Cancelled,
+		Scheduled: r.Scheduled,
 	}
 }
 
@@ -601,6 +758,39 @@ func convertLineItemsToDatabase(token string, l []cms.LineItemsInput) []cmsdatab
 	return dl
 }
 
+// convertRecordToCache converts a politeiad record into a cache record.  The
+// politeiad and cache record statuses share the same values.
+func convertRecordToCache(r pd.Record) cache.Record {
+	md := make([]cache.MetadataStream, 0, len(r.Metadata))
+	for _, v := range r.Metadata {
+		md = append(md, cache.MetadataStream{
+			ID:      v.ID,
+			Payload: v.Payload,
+		})
+	}
+	files := make([]cache.File, 0, len(r.Files))
+	for _, v := range r.Files {
+		files = append(files, cache.File{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return cache.Record{
+		Version:   r.Version,
+		Status:    cache.RecordStatusT(r.Status),
+		Timestamp: r.Timestamp,
+		CensorshipRecord: cache.CensorshipRecord{
+			Token:     r.CensorshipRecord.Token,
+			Merkle:    r.CensorshipRecord.Merkle,
+			Signature: r.CensorshipRecord.Signature,
+		},
+		Metadata: md,
+		Files:    files,
+	}
+}
+
 func convertRecordToDatabaseInvoice(p pd.Record) (*cmsdatabase.Invoice, error) {
 	dbInvoice := cmsdatabase.Invoice{
 		Files:           convertRecordFilesToWWW(p.Files),
@@ -184,6 +184,40 @@ func (p *politeiawww) emailAuthorForCensoredProposal(proposal *www.ProposalRecor
 	return p.sendEmailTo(subject, body, authorUser.Email)
 }
 
+// emailAuthorForCancelledVote sends an email notification to the proposal
+// author when an admin cancels the proposal vote.
+func (p *politeiawww) emailAuthorForCancelledVote(proposal *www.ProposalRecord, authorUser *user.User, reason string) error {
+	if p.smtp.disabled {
+		return nil
+	}
+
+	l, err := url.Parse(p.cfg.WebServerAddress + "/proposals/" +
+		proposal.CensorshipRecord.Token)
+	if err != nil {
+		return err
+	}
+
+	if authorUser.EmailNotifications&
+		uint64(www.NotificationEmailMyProposalStatusChange) == 0 {
+		return nil
+	}
+
+	tplData := proposalStatusChangeTemplateData{
+		Link:               l.String(),
+		Name:               proposal.Name,
+		StatusChangeReason: reason,
+	}
+
+	subject := "Voting On Your Proposal Has Been Cancelled"
+	body, err := createBody(templateProposalVoteCancelledForAuthor,
+		&tplData)
+	if err != nil {
+		return err
+	}
+
+	return p.sendEmailTo(subject, body, authorUser.Email)
+}
+
 // emailUsersForVettedProposal sends an email notification for a new proposal
 // becoming vetted.
 func (p *politeiawww) emailUsersForVettedProposal(proposal *www.ProposalRecord, authorUser *user.User, adminUser *user.User) error {
@@ -479,6 +513,39 @@ func (p *politeiawww) emailAuthorForCommentOnComment(proposal *www.ProposalRecor
 	return p.sendEmailTo(subject, body, authorUser.Email)
 }
 
+// emailUserForCommentMention sends an email notification to a user that was
+// mentioned in a comment.
+func (p *politeiawww) emailUserForCommentMention(proposal *www.ProposalRecord, mentionedUser *user.User, commentID, username string) error {
+	if p.smtp.disabled {
+		return nil
+	}
+
+	l, err := url.Parse(fmt.Sprintf("%v/proposals/%v/comments/%v",
+		p.cfg.WebServerAddress, proposal.CensorshipRecord.Token, commentID))
+	if err != nil {
+		return err
+	}
+
+	if mentionedUser.EmailNotifications&
+		uint64(www.NotificationEmailCommentMention) == 0 {
+		return nil
+	}
+
+	tplData := commentMentionTemplateData{
+		Commenter:    username,
+		ProposalName: proposal.Name,
+		CommentLink:  l.String(),
+	}
+
+	subject := "You Were Mentioned In A Comment"
+	body, err := createBody(templateCommentMention, &tplData)
+	if err != nil {
+		return err
+	}
+
+	return p.sendEmailTo(subject, body, mentionedUser.Email)
+}
+
 // emailUpdateUserKeyVerificationLink emails the link with the verification
 // token used for setting a new key pair if the email server is set up.
 func (p *politeiawww) emailUpdateUserKeyVerificationLink(email, publicKey, token string) error {
@@ -29,8 +29,11 @@ const (
 	EventTypeProposalVoteStarted
 	EventTypeProposalVoteAuthorized
 	EventTypeProposalVoteFinished
+	EventTypeProposalVoteCancelled
+	EventTypeProposalVoteScheduled
 	EventTypeComment
 	EventTypeUserManage
+	EventTypeCommentMention
 )
 
 type EventDataProposalSubmitted struct {
@@ -59,10 +62,21 @@ type EventDataProposalVoteAuthorized struct {
 	User          *user.User
 }
 
+type EventDataProposalVoteCancelled struct {
+	AdminUser  *user.User
+	CancelVote *www.CancelVote
+	Tokens     []string
+}
+
 type EventDataComment struct {
 	Comment *www.Comment
 }
 
+type EventDataCommentMention struct {
+	Comment *www.Comment
+	User    *user.User // Mentioned user
+}
+
 type EventDataUserManage struct {
 	AdminUser  *user.User
 	User       *user.User
@@ -138,7 +152,10 @@ func (p *politeiawww) initEventManager() {
 
 	p._setupProposalStatusChangeLogging()
 	p._setupProposalVoteStartedLogging()
+	p._setupProposalVoteCancelledLogging()
+	p._setupProposalVoteScheduledLogging()
 	p._setupUserManageLogging()
+	p._setupCommentMentionWebsocketNotification()
 
 	if p.smtp.disabled {
 		return
@@ -149,7 +166,9 @@ func (p *politeiawww) initEventManager() {
 	p._setupProposalEditedEmailNotification()
 	p._setupProposalVoteStartedEmailNotification()
 	p._setupProposalVoteAuthorizedEmailNotification()
+	p._setupProposalVoteCancelledEmailNotification()
 	p._setupCommentReplyEmailNotifications()
+	p._setupCommentMentionEmailNotification()
 }
 
 func (p *politeiawww) _setupProposalSubmittedEmailNotification() {
@@ -328,6 +347,82 @@ func (p *politeiawww) _setupProposalVoteStartedLogging() {
 	p.eventManager._register(EventTypeProposalVoteStarted, ch)
 }
 
+func (p *politeiawww) _setupProposalVoteScheduledLogging() {
+	ch := make(chan interface{})
+	go func() {
+		for data := range ch {
+			pvs, ok := data.(EventDataProposalVoteStarted)
+			if !ok {
+				log.Errorf("invalid event data")
+				continue
+			}
+
+			// Log the action in the admin log.
+			err := p.logAdminProposalAction(pvs.AdminUser,
+				pvs.StartVote.Vote.Token, "schedule vote",
+				fmt.Sprintf("start height %v",
+					pvs.StartVote.Vote.StartHeight))
+			if err != nil {
+				log.Errorf("could not log action to file: %v", err)
+			}
+		}
+	}()
+	p.eventManager._register(EventTypeProposalVoteScheduled, ch)
+}
+
+func (p *politeiawww) _setupProposalVoteCancelledEmailNotification() {
+	ch := make(chan interface{})
+	go func() {
+		for data := range ch {
+			pvc, ok := data.(EventDataProposalVoteCancelled)
+			if !ok {
+				log.Errorf("invalid event data")
+				continue
+			}
+
+			for _, token := range pvc.Tokens {
+				proposal, author, err := p.getProposalAndAuthor(
+					token)
+				if err != nil {
+					log.Error(err)
+					continue
+				}
+
+				err = p.emailAuthorForCancelledVote(proposal, author,
+					pvc.CancelVote.Reason)
+				if err != nil {
+					log.Errorf("email author for cancelled vote %v: %v",
+						token, err)
+				}
+			}
+		}
+	}()
+	p.eventManager._register(EventTypeProposalVoteCancelled, ch)
+}
+
+func (p *politeiawww) _setupProposalVoteCancelledLogging() {
+	ch := make(chan interface{})
+	go func() {
+		for data := range ch {
+			pvc, ok := data.(EventDataProposalVoteCancelled)
+			if !ok {
+				log.Errorf("invalid event data")
+				continue
+			}
+
+			// Log the action in the admin log.
+			for _, token := range pvc.Tokens {
+				err := p.logAdminProposalAction(pvc.AdminUser,
+					token, "cancel vote", pvc.CancelVote.Reason)
+				if err != nil {
+					log.Errorf("could not log action to file: %v", err)
+				}
+			}
+		}
+	}()
+	p.eventManager._register(EventTypeProposalVoteCancelled, ch)
+}
+
 func (p *politeiawww) _setupProposalVoteAuthorizedEmailNotification() {
 	ch := make(chan interface{})
 	go func() {
@@ -422,6 +517,57 @@ func (p *politeiawww) _setupCommentReplyEmailNotifications() {
 	p.eventManager._register(EventTypeComment, ch)
 }
 
+func (p *politeiawww) _setupCommentMentionEmailNotification() {
+	ch := make(chan interface{})
+	go func() {
+		for data := range ch {
+			cm, ok := data.(EventDataCommentMention)
+			if !ok {
+				log.Errorf("invalid event data")
+				continue
+			}
+
+			proposal, err := p.getProp(cm.Comment.Token)
+			if err != nil {
+				log.Errorf("EventManager: getProp failed for token %v: %v",
+					cm.Comment.Token, err)
+				continue
+			}
+
+			err = p.emailUserForCommentMention(proposal, cm.User,
+				cm.Comment.CommentID, cm.Comment.Username)
+			if err != nil {
+				log.Errorf("email user %v for mention in comment %v: %v",
+					cm.User.ID, cm.Comment.CommentID, err)
+			}
+		}
+	}()
+	p.eventManager._register(EventTypeCommentMention, ch)
+}
+
+func (p *politeiawww) _setupCommentMentionWebsocketNotification() {
+	ch := make(chan interface{})
+	go func() {
+		for data := range ch {
+			cm, ok := data.(EventDataCommentMention)
+			if !ok {
+				log.Errorf("invalid event data")
+				continue
+			}
+
+			p.websocketCommentMention(cm.User.ID.String(),
+				www.WSCommentMention{
+					Token:     cm.Comment.Token,
+					CommentID: cm.Comment.CommentID,
+					UserID:    cm.Comment.UserID,
+					Username:  cm.Comment.Username,
+					Timestamp: cm.Comment.Timestamp,
+				})
+		}
+	}()
+	p.eventManager._register(EventTypeCommentMention, ch)
+}
+
 func (p *politeiawww) _setupUserManageLogging() {
 	ch := make(chan interface{})
 	go func() {
@@ -1455,6 +1455,168 @@ func (p *politeiawww) processPayInvoices(u *user.User) (*cms.PayInvoicesReply, e
 	return reply, err
 }
 
+// invoiceSyncStates fetches the record inventory from politeiad without the
+// record files and returns the version and status of every invoice.  The
+// tokens of the invoices that are not vetted are returned separately so that
+// they can be fetched from the correct politeiad route.
+func (p *politeiawww) invoiceSyncStates() ([]database.SyncState, map[string]struct{}, error) {
+	log.Tracef("invoiceSyncStates")
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost, pd.InventoryRoute,
+		pd.Inventory{
+			Challenge:    hex.EncodeToString(challenge),
+			IncludeFiles: false,
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ir pd.InventoryReply
+	err = json.Unmarshal(responseBody, &ir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not unmarshal "+
+			"InventoryReply: %v", err)
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, ir.Response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unvetted := make(map[string]struct{}, len(ir.Branches))
+	for _, r := range ir.Branches {
+		unvetted[r.CensorshipRecord.Token] = struct{}{}
+	}
+
+	records := append(ir.Vetted, ir.Branches...)
+	states := make([]database.SyncState, 0, len(records))
+	for _, r := range records {
+		// The version and status only depend on the record
+		// metadata so the files are not needed.
+		inv, err := convertRecordToDatabaseInvoice(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("convertRecordToDatabaseInvoice "+
+				"%v: %v", r.CensorshipRecord.Token, err)
+		}
+		states = append(states, database.SyncState{
+			Token:   inv.Token,
+			Version: inv.Version,
+			Status:  inv.Status,
+		})
+	}
+
+	return states, unvetted, nil
+}
+
+// fetchInvoice fetches the full invoice record from politeiad and converts it
+// into a cmsdatabase invoice.  The user ID of the invoice is looked up using
+// the invoice public key and the status change history is filled in from the
+// invoice status changes metadata stream.
+func (p *politeiawww) fetchInvoice(token string, unvetted bool) (*database.Invoice, error) {
+	log.Tracef("fetchInvoice: %v %v", token, unvetted)
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		route string
+		req   interface{}
+	)
+	if unvetted {
+		route = pd.GetUnvettedRoute
+		req = pd.GetUnvetted{
+			Challenge: hex.EncodeToString(challenge),
+			Token:     token,
+		}
+	} else {
+		route = pd.GetVettedRoute
+		req = pd.GetVetted{
+			Challenge: hex.EncodeToString(challenge),
+			Token:     token,
+		}
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost, route, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetVettedReply and GetUnvettedReply share the same layout
+	var reply pd.GetVettedReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal reply: %v", err)
+	}
+
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	r := reply.Record
+	inv, err := database.ConvertInvoiceFromCache(convertRecordToCache(r))
+	if err != nil {
+		return nil, fmt.Errorf("ConvertInvoiceFromCache %v: %v",
+			r.CensorshipRecord.Token, err)
+	}
+
+	userID, ok := p.getUserIDByPubKey(inv.PublicKey)
+	if !ok {
+		log.Errorf("fetchInvoice: userID not found for pubkey:%v "+
+			"token:%v", inv.PublicKey, inv.Token)
+	}
+	inv.UserID = userID
+
+	return inv, nil
+}
+
+// processSyncInvoices brings the cms database in line with the politeiad
+// inventory.  Only the invoice metadata is requested from politeiad and the
+// full record is only fetched for invoices whose version or status differs
+// from the cms database copy.  Invoices that are missing from the cms database are
+// added and invoices whose version or status have diverged from politeiad are
+// updated.  Invoices in the cms database that are no longer in politeiad are
+// reported but left untouched.
+func (p *politeiawww) processSyncInvoices() (*cms.SyncInvoicesReply, error) {
+	log.Tracef("processSyncInvoices")
+
+	states, unvetted, err := p.invoiceSyncStates()
+	if err != nil {
+		return nil, fmt.Errorf("invoiceSyncStates: %v", err)
+	}
+
+	sr, err := database.Sync(p.cmsDB, states,
+		func(token string) (*database.Invoice, error) {
+			_, ok := unvetted[token]
+			return p.fetchInvoice(token, ok)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Sync: %v", err)
+	}
+
+	for _, v := range sr.Missing {
+		log.Errorf("processSyncInvoices: invoice %v is in the cms "+
+			"database but not in politeiad", v)
+	}
+	log.Infof("Invoice sync: %v added, %v updated, %v unchanged, "+
+		"%v missing from politeiad", len(sr.Added), len(sr.Updated),
+		sr.Unchanged, len(sr.Missing))
+
+	return &cms.SyncInvoicesReply{
+		Added:     sr.Added,
+		Updated:   sr.Updated,
+		Unchanged: sr.Unchanged,
+		Missing:   sr.Missing,
+	}, nil
+}
+
 // backendInvoiceMetadata represents the general metadata for an invoice and is
 // stored in the metadata stream mdStreamInvoiceGeneral in politeiad.
 type backendInvoiceMetadata struct {
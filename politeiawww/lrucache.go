@@ -0,0 +1,264 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/cache"
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+)
+
+const (
+	// defaultReadCacheSize is the default maximum number of entries
+	// that each of the politeiawww read caches will hold.
+	defaultReadCacheSize = 1000
+)
+
+// lruEntry is a single key/value pair stored in a lruCache.
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+// lruCache is a size bounded, concurrency safe, least recently used cache.
+// A lruCache with a size of zero caches nothing and reports every lookup as a
+// miss.
+//
+// Every key has a generation that is bumped each time the key is removed.
+// Read-through callers capture the generation before reading the underlying
+// data and use putGeneration so that data that was read before an
+// invalidation can not be stored after it.  The generations are never pruned
+// so they grow with the number of distinct keys that have been removed, which
+// is bounded by the number of records.
+type lruCache struct {
+	sync.Mutex
+	size        int
+	entries     map[string]*list.Element // [key]element
+	order       *list.List               // Front is most recently used
+	generations map[string]uint64        // [key]generation
+
+	// Hits and misses must be accessed atomically.
+	hits   uint64
+	misses uint64
+}
+
+// get returns the value for the given key and marks it as the most recently
+// used entry.
+func (c *lruCache) get(key string) (interface{}, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	atomic.AddUint64(&c.hits, 1)
+
+	return e.Value.(*lruEntry).value, true
+}
+
+// generation returns the current generation of the given key.
+func (c *lruCache) generation(key string) uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.generations[key]
+}
+
+// put adds the given key/value pair to the cache, evicting the least recently
+// used entry if the cache is full.
+func (c *lruCache) put(key string, value interface{}) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.putLocked(key, value)
+}
+
+// putGeneration adds the given key/value pair to the cache only if the
+// generation of the key is still the passed in generation, i.e. if the key
+// has not been removed since the generation was captured.  It returns whether
+// the value was stored.
+func (c *lruCache) putGeneration(key string, value interface{}, generation uint64) bool {
+	if c.size <= 0 {
+		return false
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.generations[key] != generation {
+		return false
+	}
+	c.putLocked(key, value)
+
+	return true
+}
+
+// putLocked adds the given key/value pair to the cache, evicting the least
+// recently used entry if the cache is full.
+//
+// This function must be called with the lock held.
+func (c *lruCache) putLocked(key string, value interface{}) {
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{
+		key:   key,
+		value: value,
+	})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// remove removes the given key from the cache and bumps its generation.  The
+// generation is bumped even when the key is not cached since a read of the
+// key may be in flight.
+func (c *lruCache) remove(key string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.generations[key]++
+
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.order.Remove(e)
+	delete(c.entries, key)
+}
+
+// stats returns the hit/miss statistics for the cache.
+func (c *lruCache) stats() www.ReadCacheStats {
+	c.Lock()
+	entries := c.order.Len()
+	c.Unlock()
+
+	return www.ReadCacheStats{
+		Size:    c.size,
+		Entries: entries,
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+	}
+}
+
+// newLRUCache returns a new lruCache that holds at most size entries.
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:        size,
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		generations: make(map[string]uint64),
+	}
+}
+
+// readCache is an in-memory read-through cache that sits in front of the
+// politeiad cache for the reads that get hit the hardest: proposal records,
+// proposal comments and vote summaries.  Only the raw cache data is stored.
+// Data that politeiawww fills in on top of it, such as usernames and comment
+// scores, is always looked up fresh.
+//
+// Entries are invalidated whenever politeiawww sends a request to politeiad
+// that modifies the underlying data.  Changes made to politeiad by anything
+// other than this politeiawww instance will not be picked up until the entry
+// is evicted, so the cache should be disabled (size 0) when running multiple
+// politeiawww instances against the same politeiad.
+type readCache struct {
+	records       *lruCache // [token]cache.Record
+	comments      *lruCache // [token][]bitumplugin.Comment
+	voteSummaries *lruCache // [token]bitumplugin.VoteSummaryReply
+}
+
+// record returns the most recent version of the given record, fetching it
+// from the politeiad cache if it is not already cached.
+func (rc *readCache) record(c cache.Cache, token string) (*cache.Record, error) {
+	if v, ok := rc.records.get(token); ok {
+		r := v.(cache.Record)
+		return &r, nil
+	}
+
+	gen := rc.records.generation(token)
+	r, err := c.Record(token)
+	if err != nil {
+		return nil, err
+	}
+	rc.records.putGeneration(token, *r, gen)
+
+	return r, nil
+}
+
+// invalidateRecord removes the given record from the cache.  The vote summary
+// is removed as well since it is derived from the record's vote metadata.
+func (rc *readCache) invalidateRecord(token string) {
+	rc.records.remove(token)
+	rc.voteSummaries.remove(token)
+}
+
+// invalidateComments removes the comments of the given record from the cache.
+func (rc *readCache) invalidateComments(token string) {
+	rc.comments.remove(token)
+}
+
+// invalidateVoteSummary removes the vote summary of the given record from the
+// cache.
+func (rc *readCache) invalidateVoteSummary(token string) {
+	rc.voteSummaries.remove(token)
+}
+
+// stats returns the hit/miss statistics for all of the read caches.
+func (rc *readCache) stats() www.CacheStatsReply {
+	return www.CacheStatsReply{
+		Records:       rc.records.stats(),
+		Comments:      rc.comments.stats(),
+		VoteSummaries: rc.voteSummaries.stats(),
+	}
+}
+
+// newReadCache returns a new readCache where each of the underlying caches
+// holds at most size entries.
+func newReadCache(size int) *readCache {
+	return &readCache{
+		records:       newLRUCache(size),
+		comments:      newLRUCache(size),
+		voteSummaries: newLRUCache(size),
+	}
+}
+
+// cachedComments returns the comments for the given token if they are
+// present in the read cache.
+func (p *politeiawww) cachedComments(token string) ([]bitumplugin.Comment, bool) {
+	v, ok := p.readCache.comments.get(token)
+	if !ok {
+		return nil, false
+	}
+	return v.([]bitumplugin.Comment), true
+}
+
+// cachedVoteSummary returns the vote summary for the given token if it is
+// present in the read cache.
+func (p *politeiawww) cachedVoteSummary(token string) (*bitumplugin.VoteSummaryReply, bool) {
+	v, ok := p.readCache.voteSummaries.get(token)
+	if !ok {
+		return nil, false
+	}
+	vs := v.(bitumplugin.VoteSummaryReply)
+	return &vs, true
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/bitum-project/politeia/bitumplugin"
+	"github.com/bitum-project/politeia/politeiad/cache"
+	"github.com/bitum-project/politeia/politeiad/cache/testcache"
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+)
+
+// lruKeys returns the keys of the passed in cache ordered from most recently
+// used to least recently used.
+func lruKeys(c *lruCache) []string {
+	c.Lock()
+	defer c.Unlock()
+
+	keys := make([]string, 0, c.order.Len())
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*lruEntry).key)
+	}
+	return keys
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := newLRUCache(3)
+	c.put("a", 1)
+	c.put("b", 2)
+	c.put("c", 3)
+
+	// A lookup makes the entry the most recently used one
+	_, ok := c.get("a")
+	if !ok {
+		t.Fatalf("entry a not found")
+	}
+	if got, want := lruKeys(c), []string{"a", "c", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+
+	// Overwriting an entry updates the value and makes it the most
+	// recently used one
+	c.put("b", 20)
+	if got, want := lruKeys(c), []string{"b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+	v, _ := c.get("b")
+	if v.(int) != 20 {
+		t.Fatalf("got value %v, want 20", v)
+	}
+
+	// The least recently used entry is evicted first
+	c.put("d", 4)
+	_, ok = c.get("c")
+	if ok {
+		t.Fatalf("entry c was not evicted")
+	}
+	if got, want := lruKeys(c), []string{"d", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+
+	// Removed entries are no longer returned
+	c.remove("b")
+	c.remove("unknown")
+	if got, want := lruKeys(c), []string{"d", "a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+}
+
+func TestLRUCacheSize(t *testing.T) {
+	size := 10
+	c := newLRUCache(size)
+	for i := 0; i < size*3; i++ {
+		c.put(strconv.Itoa(i), i)
+		if len(c.entries) != c.order.Len() {
+			t.Fatalf("got %v entries and %v list elements",
+				len(c.entries), c.order.Len())
+		}
+		if c.order.Len() > size {
+			t.Fatalf("got %v entries, want at most %v",
+				c.order.Len(), size)
+		}
+	}
+
+	// Only the most recent entries remain
+	for i := 0; i < size*3; i++ {
+		_, ok := c.get(strconv.Itoa(i))
+		want := i >= size*2
+		if ok != want {
+			t.Fatalf("entry %v: got cached %v, want %v", i, ok, want)
+		}
+	}
+
+	// A cache with a size of zero caches nothing
+	c = newLRUCache(0)
+	c.put("a", 1)
+	_, ok := c.get("a")
+	if ok || c.order.Len() != 0 {
+		t.Fatalf("zero size cache stored an entry")
+	}
+}
+
+func TestLRUCacheStats(t *testing.T) {
+	c := newLRUCache(2)
+	c.get("a")
+	c.put("a", 1)
+	c.get("a")
+	c.get("a")
+	c.get("b")
+
+	want := www.ReadCacheStats{
+		Size:    2,
+		Entries: 1,
+		Hits:    2,
+		Misses:  2,
+	}
+	if got := c.stats(); got != want {
+		t.Fatalf("got stats %+v, want %+v", got, want)
+	}
+}
+
+func TestLRUCacheGeneration(t *testing.T) {
+	c := newLRUCache(2)
+
+	// A put with the current generation is stored
+	gen := c.generation("a")
+	if !c.putGeneration("a", 1, gen) {
+		t.Fatalf("put with current generation was rejected")
+	}
+
+	// A removal bumps the generation so a put with a generation
+	// that was captured before the removal is rejected. This is
+	// the case even when the key was not cached at the time of
+	// the removal.
+	gen = c.generation("a")
+	c.remove("a")
+	if c.putGeneration("a", 2, gen) {
+		t.Fatalf("put with stale generation was stored")
+	}
+	gen = c.generation("b")
+	c.remove("b")
+	if c.putGeneration("b", 2, gen) {
+		t.Fatalf("put with stale generation was stored")
+	}
+	if got := lruKeys(c); len(got) != 0 {
+		t.Fatalf("got entries %v, want none", got)
+	}
+
+	// Keys have independent generations
+	gen = c.generation("b")
+	c.remove("a")
+	if !c.putGeneration("b", 2, gen) {
+		t.Fatalf("put was rejected by the removal of another key")
+	}
+}
+
+// invalidatingCache is a cache that invalidates the read cache entry of a
+// record while the record is being read, simulating a write that lands
+// between a read cache miss and the read cache put.
+type invalidatingCache struct {
+	cache.Cache
+	rc *readCache
+}
+
+func (c invalidatingCache) Record(token string) (*cache.Record, error) {
+	r, err := c.Cache.Record(token)
+	c.rc.invalidateRecord(token)
+	return r, err
+}
+
+func TestReadCacheRecordInvalidatedDuringRead(t *testing.T) {
+	rc := newReadCache(10)
+	tc := testcache.New()
+	err := tc.NewRecord(cache.Record{
+		Version: "1",
+		CensorshipRecord: cache.CensorshipRecord{
+			Token: "token",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRecord: %v", err)
+	}
+
+	// The record that was read before the invalidation is returned
+	// but it must not be cached.
+	_, err = rc.record(invalidatingCache{tc, rc}, "token")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if rc.stats().Records.Entries != 0 {
+		t.Fatalf("record read before an invalidation was cached")
+	}
+
+	// Reads that are not interrupted by an invalidation are cached
+	_, err = rc.record(tc, "token")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if rc.stats().Records.Entries != 1 {
+		t.Fatalf("record was not cached")
+	}
+}
+
+func TestReadCacheRecord(t *testing.T) {
+	rc := newReadCache(10)
+	tc := testcache.New()
+	r := cache.Record{
+		Version: "1",
+		Status:  cache.RecordStatusPublic,
+		CensorshipRecord: cache.CensorshipRecord{
+			Token: "token",
+		},
+	}
+	err := tc.NewRecord(r)
+	if err != nil {
+		t.Fatalf("NewRecord: %v", err)
+	}
+
+	// The first read is a miss that populates the cache and the
+	// second read is a hit.
+	for i := 0; i < 2; i++ {
+		got, err := rc.record(tc, "token")
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		if got.CensorshipRecord.Token != "token" {
+			t.Fatalf("got token %v, want token", got.CensorshipRecord.Token)
+		}
+	}
+	s := rc.stats().Records
+	if s.Hits != 1 || s.Misses != 1 || s.Entries != 1 {
+		t.Fatalf("unexpected record stats %+v", s)
+	}
+
+	// Records that are not found are not cached
+	_, err = rc.record(tc, "unknown")
+	if err != cache.ErrRecordNotFound {
+		t.Fatalf("got error %v, want %v", err, cache.ErrRecordNotFound)
+	}
+	if rc.stats().Records.Entries != 1 {
+		t.Fatalf("record that was not found was cached")
+	}
+}
+
+func TestReadCacheInvalidateRecord(t *testing.T) {
+	rc := newReadCache(10)
+	rc.records.put("token", cache.Record{})
+	rc.comments.put("token", []bitumplugin.Comment{})
+	rc.voteSummaries.put("token", bitumplugin.VoteSummaryReply{})
+
+	// Invalidating a record drops its vote summary as well since
+	// the summary is derived from the record, but the comments are
+	// kept.
+	rc.invalidateRecord("token")
+	if _, ok := rc.records.get("token"); ok {
+		t.Fatalf("record was not invalidated")
+	}
+	if _, ok := rc.voteSummaries.get("token"); ok {
+		t.Fatalf("vote summary was not invalidated")
+	}
+	if _, ok := rc.comments.get("token"); !ok {
+		t.Fatalf("comments were invalidated")
+	}
+
+	rc.invalidateComments("token")
+	if _, ok := rc.comments.get("token"); ok {
+		t.Fatalf("comments were not invalidated")
+	}
+}
@@ -45,12 +45,16 @@ var (
 		template.New("proposal_vetted_for_author_template").Parse(templateProposalVettedForAuthorRaw))
 	templateProposalCensoredForAuthor = template.Must(
 		template.New("proposal_censored_for_author_template").Parse(templateProposalCensoredForAuthorRaw))
+	templateProposalVoteCancelledForAuthor = template.Must(
+		template.New("proposal_vote_cancelled_for_author_template").Parse(templateProposalVoteCancelledForAuthorRaw))
 	templateProposalVoteStartedForAuthor = template.Must(
 		template.New("proposal_vote_started_for_author_template").Parse(templateProposalVoteStartedForAuthorRaw))
 	templateCommentReplyOnProposal = template.Must(
 		template.New("comment_reply_on_proposal").Parse(templateCommentReplyOnProposalRaw))
 	templateCommentReplyOnComment = template.Must(
 		template.New("comment_reply_on_comment").Parse(templateCommentReplyOnCommentRaw))
+	templateCommentMention = template.Must(
+		template.New("comment_mention").Parse(templateCommentMentionRaw))
 )
 
 // wsContext is the websocket context. If uuid == "" then it is an
@@ -63,6 +67,7 @@ type wsContext struct {
 	subscriptions map[string]struct{}
 	errorC        chan www.WSError
 	pingC         chan struct{}
+	mentionC      chan www.WSCommentMention
 	done          chan struct{} // SHUT...DOWN...EVERYTHING...
 }
 
@@ -90,8 +95,9 @@ type politeiawww struct {
 	wsMtx sync.RWMutex
 
 	// Cache
-	cache   cache.Cache
-	plugins []Plugin
+	cache     cache.Cache
+	readCache *readCache // In-memory read-through cache
+	plugins   []Plugin
 
 	// Politeiad client
 	client *http.Client
@@ -99,6 +105,10 @@ type politeiawww struct {
 	// SMTP client
 	smtp *smtp
 
+	// Comment and comment like rate limiters
+	commentLimiter *rateLimiter
+	likeLimiter    *rateLimiter
+
 	templates map[string]*template.Template
 	tmplMtx   sync.RWMutex
 
@@ -116,7 +126,6 @@ type politeiawww struct {
 	// XXX userPubkeys can be removed now that the userdb is queryable
 	userPubkeys     map[string]string               // [pubkey][userid]
 	userPaywallPool map[uuid.UUID]paywallPoolMember // [userid][paywallPoolMember]
-	commentScores   map[string]int64                // [token+commentID]resultVotes
 
 	// voteStatuses is a lazy loaded cache of the votes statuses of
 	// proposals whose voting period has ended.
@@ -228,7 +237,13 @@ func (p *politeiawww) handleAllVetted(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vr, err := p.processAllVetted(v)
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		// This is a public route so a logged in user is not required
+		log.Debugf("handleAllVetted: could not get session user: %v", err)
+	}
+
+	vr, err := p.processAllVetted(v, user)
 	if err != nil {
 		RespondWithError(w, r, 0,
 			"handleAllVetted: processAllVetted %v", err)
@@ -288,6 +303,7 @@ func (p *politeiawww) handlePolicy(w http.ResponseWriter, r *http.Request) {
 		UsernameSupportedChars:     www.PolicyUsernameSupportedChars,
 		ProposalListPageSize:       www.ProposalListPageSize,
 		UserListPageSize:           www.UserListPageSize,
+		CommentListPageSize:        www.CommentListPageSize,
 		MaxImages:                  www.PolicyMaxImages,
 		MaxImageSize:               www.PolicyMaxImageSize,
 		MaxMDs:                     www.PolicyMaxMDs,
@@ -296,7 +312,10 @@ func (p *politeiawww) handlePolicy(w http.ResponseWriter, r *http.Request) {
 		MinProposalNameLength:      www.PolicyMinProposalNameLength,
 		MaxProposalNameLength:      www.PolicyMaxProposalNameLength,
 		ProposalNameSupportedChars: www.PolicyProposalNameSupportedChars,
+		CommentFlagReasons:         www.PolicyCommentFlagReasons,
 		MaxCommentLength:           www.PolicyMaxCommentLength,
+		MaxCommentMentions:         www.PolicyMaxCommentMentions,
+		VotePolicies:               p.votePolicies(),
 	}
 
 	util.RespondWithJSON(w, http.StatusOK, reply)
@@ -307,7 +326,17 @@ func (p *politeiawww) handleCommentsGet(w http.ResponseWriter, r *http.Request)
 	log.Tracef("handleCommentsGet")
 
 	pathParams := mux.Vars(r)
-	token := pathParams["token"]
+
+	var gc www.GetComments
+	err := util.ParseGetParams(r, &gc)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleCommentsGet: ParseGetParams",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+	gc.Token = pathParams["token"]
 
 	user, err := p.getSessionUser(w, r)
 	if err != nil {
@@ -317,7 +346,7 @@ func (p *politeiawww) handleCommentsGet(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
-	gcr, err := p.processCommentsGet(token, user)
+	gcr, err := p.processCommentsGet(gc, user)
 	if err != nil {
 		RespondWithError(w, r, 0,
 			"handleCommentsGet: processCommentsGet %v", err)
@@ -326,6 +355,42 @@ func (p *politeiawww) handleCommentsGet(w http.ResponseWriter, r *http.Request)
 	util.RespondWithJSON(w, http.StatusOK, gcr)
 }
 
+// handleCommentRevisions returns the revision history of a comment.
+func (p *politeiawww) handleCommentRevisions(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCommentRevisions")
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+	commentID := pathParams["commentid"]
+
+	crr, err := p.processCommentRevisions(token, commentID)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleCommentRevisions: processCommentRevisions %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, crr)
+}
+
+// handleCommentProof returns the proof that a comment was anchored.
+func (p *politeiawww) handleCommentProof(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCommentProof")
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+	commentID := pathParams["commentid"]
+
+	cpr, err := p.processCommentProof(token, commentID)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleCommentProof: processCommentProof %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, cpr)
+}
+
 // handleUserProposals returns the proposals for the given user.
 func (p *politeiawww) handleUserProposals(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleUserProposals")
@@ -359,7 +424,8 @@ func (p *politeiawww) handleUserProposals(w http.ResponseWriter, r *http.Request
 	upr, err := p.processUserProposals(
 		&up,
 		user != nil && user.ID == userId,
-		user != nil && user.Admin)
+		user != nil && user.Admin,
+		user)
 	if err != nil {
 		RespondWithError(w, r, 0,
 			"handleUserProposals: processUserProposals %v", err)
@@ -424,6 +490,91 @@ func (p *politeiawww) handleVoteResults(w http.ResponseWriter, r *http.Request)
 	util.RespondWithJSON(w, http.StatusOK, vrr)
 }
 
+// handleVoteResultsBundle returns the signed results bundle of a finished
+// proposal vote.
+func (p *politeiawww) handleVoteResultsBundle(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleVoteResultsBundle")
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+
+	vrbr, err := p.processVoteResultsBundle(token)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVoteResultsBundle: processVoteResultsBundle %v",
+			err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, vrbr)
+}
+
+// handleVoteReceipt returns the vote that was cast by a ticket along with
+// its receipt.
+func (p *politeiawww) handleVoteReceipt(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleVoteReceipt")
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+	ticket := pathParams["ticket"]
+
+	vrr, err := p.processVoteReceipt(token, ticket)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVoteReceipt: processVoteReceipt %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, vrr)
+}
+
+// handleVoteProof returns the proof that the latest vote of a ticket was
+// anchored.
+func (p *politeiawww) handleVoteProof(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleVoteProof")
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+	ticket := pathParams["ticket"]
+
+	vpr, err := p.processVoteProof(token, ticket)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVoteProof: processVoteProof %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, vpr)
+}
+
+// handleVoteTimeSeries returns the cumulative vote results of a proposal vote
+// bucketed by block height.
+func (p *politeiawww) handleVoteTimeSeries(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleVoteTimeSeries")
+
+	var vts www.VoteTimeSeries
+	err := util.ParseGetParams(r, &vts)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleVoteTimeSeries: ParseGetParams",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	pathParams := mux.Vars(r)
+	token := pathParams["token"]
+
+	vtsr, err := p.processVoteTimeSeries(token, vts)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVoteTimeSeries: processVoteTimeSeries %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, vtsr)
+}
+
 // handleGetAllVoteStatus returns the voting status of all public proposals.
 func (p *politeiawww) handleGetAllVoteStatus(w http.ResponseWriter, r *http.Request) {
 	gasvr, err := p.processGetAllVoteStatus()
@@ -458,6 +609,14 @@ func (p *politeiawww) handleProposalsStats(w http.ResponseWriter, r *http.Reques
 	util.RespondWithJSON(w, http.StatusOK, psr)
 }
 
+// handleCacheStats returns the hit/miss statistics of the in-memory read
+// caches.
+func (p *politeiawww) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCacheStats")
+
+	util.RespondWithJSON(w, http.StatusOK, p.readCache.stats())
+}
+
 // handleTokenInventory returns the tokens of all proposals in the inventory.
 func (p *politeiawww) handleTokenInventory(w http.ResponseWriter, r *http.Request) {
 	reply, err := p.processTokenInventory()
@@ -543,6 +702,13 @@ func (p *politeiawww) handleNewComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = p.checkRateLimit(w, r, p.commentLimiter, user)
+	if err != nil {
+		RespondWithError(w, r, http.StatusTooManyRequests,
+			"handleNewComment: checkRateLimit %v", err)
+		return
+	}
+
 	cr, err := p.processNewComment(sc, user)
 	if err != nil {
 		RespondWithError(w, r, 0,
@@ -574,6 +740,13 @@ func (p *politeiawww) handleLikeComment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	err = p.checkRateLimit(w, r, p.likeLimiter, user)
+	if err != nil {
+		RespondWithError(w, r, http.StatusTooManyRequests,
+			"handleLikeComment: checkRateLimit %v", err)
+		return
+	}
+
 	cr, err := p.processLikeComment(lc, user)
 	if err != nil {
 		RespondWithError(w, r, 0,
@@ -685,6 +858,27 @@ func (p *politeiawww) websocketPing(id string) {
 	}
 }
 
+// websocketCommentMention notifies the websockets of a user that are
+// subscribed to comment mentions that the user was mentioned in a comment.
+func (p *politeiawww) websocketCommentMention(id string, m www.WSCommentMention) {
+	log.Tracef("websocketCommentMention %v", id)
+	defer log.Tracef("websocketCommentMention exit %v", id)
+
+	p.wsMtx.RLock()
+	defer p.wsMtx.RUnlock()
+
+	for _, v := range p.ws[id] {
+		if _, ok := v.subscriptions[www.WSCCommentMention]; !ok {
+			continue
+		}
+
+		select {
+		case v.mentionC <- m:
+		default:
+		}
+	}
+}
+
 // handleWebsocketRead reads a websocket command off the socket and tries to
 // handle it. Currently it only supports subscribing to websocket events.
 func (p *politeiawww) handleWebsocketRead(wc *wsContext) {
@@ -755,7 +949,7 @@ func (p *politeiawww) handleWebsocketRead(wc *wsContext) {
 }
 
 // handleWebsocketWrite attempts to notify a subscribed websocket. Currently
-// only ping is supported.
+// ping and comment mentions are supported.
 func (p *politeiawww) handleWebsocketWrite(wc *wsContext) {
 	defer wc.wg.Done()
 	log.Tracef("handleWebsocketWrite %v", wc)
@@ -787,6 +981,15 @@ func (p *politeiawww) handleWebsocketWrite(wc *wsContext) {
 			cmd = www.WSCPing
 			id = ""
 			payload = www.WSPing{Timestamp: time.Now().Unix()}
+		case m, ok := <-wc.mentionC:
+			if !ok {
+				log.Tracef("handleWebsocketWrite mention not ok"+
+					" %v", wc)
+				return
+			}
+			cmd = www.WSCCommentMention
+			id = ""
+			payload = m
 		}
 
 		err := util.WSWrite(wc.conn, cmd, id, payload)
@@ -807,6 +1010,7 @@ func (p *politeiawww) handleWebsocket(w http.ResponseWriter, r *http.Request, id
 		uuid:          id,
 		subscriptions: make(map[string]struct{}),
 		pingC:         make(chan struct{}),
+		mentionC:      make(chan www.WSCommentMention),
 		errorC:        make(chan www.WSError),
 		done:          make(chan struct{}),
 	}
@@ -922,7 +1126,14 @@ func (p *politeiawww) handleAllUnvetted(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	ur, err := p.processAllUnvetted(u)
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleAllUnvetted: getSessionUser %v", err)
+		return
+	}
+
+	ur, err := p.processAllUnvetted(u, user)
 	if err != nil {
 		RespondWithError(w, r, 0,
 			"handleAllUnvetted: processAllUnvetted %v", err)
@@ -1005,6 +1216,84 @@ func (p *politeiawww) handleStartVote(w http.ResponseWriter, r *http.Request) {
 	util.RespondWithJSON(w, http.StatusOK, svr)
 }
 
+// handleStartVoteRunoff handles starting a runoff vote between several
+// proposals.
+func (p *politeiawww) handleStartVoteRunoff(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleStartVoteRunoff")
+
+	var sv www.StartVoteRunoff
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&sv); err != nil {
+		RespondWithError(w, r, 0, "handleStartVoteRunoff: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleStartVoteRunoff: getSessionUser %v", err)
+		return
+	}
+
+	// Sanity
+	if !user.Admin {
+		RespondWithError(w, r, 0,
+			"handleStartVoteRunoff: admin %v", user.Admin)
+		return
+	}
+
+	svr, err := p.processStartVoteRunoff(sv, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleStartVoteRunoff: processStartVoteRunoff %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, svr)
+}
+
+// handleCancelVote handles the cancellation of an active proposal vote by an
+// admin.
+func (p *politeiawww) handleCancelVote(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCancelVote")
+
+	var cv www.CancelVote
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cv); err != nil {
+		RespondWithError(w, r, 0, "handleCancelVote: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleCancelVote: getSessionUser %v", err)
+		return
+	}
+
+	// Sanity
+	if !user.Admin {
+		RespondWithError(w, r, 0,
+			"handleCancelVote: admin %v", user.Admin)
+		return
+	}
+
+	cvr, err := p.processCancelVote(cv, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleCancelVote: processCancelVote %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, cvr)
+}
+
 // handleCensorComment handles the censoring of a comment by an admin.
 func (p *politeiawww) handleCensorComment(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleCensorComment")
@@ -1036,6 +1325,256 @@ func (p *politeiawww) handleCensorComment(w http.ResponseWriter, r *http.Request
 	util.RespondWithJSON(w, http.StatusOK, cr)
 }
 
+// handleEditComment handles the editing of a comment by its author.
+func (p *politeiawww) handleEditComment(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleEditComment")
+
+	var ec www.EditComment
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ec); err != nil {
+		RespondWithError(w, r, 0, "handleEditComment: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleEditComment: getSessionUser %v", err)
+		return
+	}
+
+	ecr, err := p.processEditComment(ec, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleEditComment: processEditComment %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, ecr)
+}
+
+// handleDeleteComment handles the deletion of a comment by its author.
+func (p *politeiawww) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleDeleteComment")
+
+	var dc www.DeleteComment
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&dc); err != nil {
+		RespondWithError(w, r, 0, "handleDeleteComment: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleDeleteComment: getSessionUser %v", err)
+		return
+	}
+
+	dcr, err := p.processDeleteComment(dc, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleDeleteComment: processDeleteComment %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, dcr)
+}
+
+// handleFlagComment handles the flagging of a comment by a user.
+func (p *politeiawww) handleFlagComment(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleFlagComment")
+
+	var fc www.FlagComment
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&fc); err != nil {
+		RespondWithError(w, r, 0, "handleFlagComment: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleFlagComment: getSessionUser %v", err)
+		return
+	}
+
+	fcr, err := p.processFlagComment(fc, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleFlagComment: processFlagComment %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, fcr)
+}
+
+// handleFlaggedComments returns the comment moderation queue.
+func (p *politeiawww) handleFlaggedComments(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleFlaggedComments")
+
+	fcr, err := p.processFlaggedComments()
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleFlaggedComments: processFlaggedComments %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, fcr)
+}
+
+// handleDismissCommentFlags handles the dismissal of the flags of a comment
+// by an admin.
+func (p *politeiawww) handleDismissCommentFlags(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleDismissCommentFlags")
+
+	var dcf www.DismissCommentFlags
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&dcf); err != nil {
+		RespondWithError(w, r, 0, "handleDismissCommentFlags: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleDismissCommentFlags: getSessionUser %v", err)
+		return
+	}
+
+	dcfr, err := p.processDismissCommentFlags(dcf, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleDismissCommentFlags: processDismissCommentFlags %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, dcfr)
+}
+
+// handleAppealCensorship handles the appeal of a comment censorship by the
+// comment author.
+func (p *politeiawww) handleAppealCensorship(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleAppealCensorship")
+
+	var ac www.AppealCensorship
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ac); err != nil {
+		RespondWithError(w, r, 0, "handleAppealCensorship: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleAppealCensorship: getSessionUser %v", err)
+		return
+	}
+
+	acr, err := p.processAppealCensorship(ac, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleAppealCensorship: processAppealCensorship %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, acr)
+}
+
+// handleCensorshipAppeals returns the censorship appeals that have not been
+// resolved.
+func (p *politeiawww) handleCensorshipAppeals(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCensorshipAppeals")
+
+	car, err := p.processCensorshipAppeals()
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleCensorshipAppeals: processCensorshipAppeals %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, car)
+}
+
+// handleResolveAppeal handles the resolution of a censorship appeal by an
+// admin.
+func (p *politeiawww) handleResolveAppeal(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleResolveAppeal")
+
+	var ra www.ResolveAppeal
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ra); err != nil {
+		RespondWithError(w, r, 0, "handleResolveAppeal: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleResolveAppeal: getSessionUser %v", err)
+		return
+	}
+
+	rar, err := p.processResolveAppeal(ra, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleResolveAppeal: processResolveAppeal %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, rar)
+}
+
+// handleMarkCommentsRead marks all of the comments of a proposal as read by the
+// logged in user.
+func (p *politeiawww) handleMarkCommentsRead(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleMarkCommentsRead")
+
+	var mcr www.MarkCommentsRead
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&mcr); err != nil {
+		RespondWithError(w, r, 0, "handleMarkCommentsRead: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleMarkCommentsRead: getSessionUser %v", err)
+		return
+	}
+
+	mcrr, err := p.processMarkCommentsRead(mcr, user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleMarkCommentsRead: processMarkCommentsRead %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, mcrr)
+}
+
 // setPoliteiaWWWRoutes sets up the politeia routes.
 func (p *politeiawww) setPoliteiaWWWRoutes() {
 	// Templates
@@ -1063,6 +1602,10 @@ func (p *politeiawww) setPoliteiaWWWRoutes() {
 		permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteCommentsGet, p.handleCommentsGet,
 		permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteCommentRevisions,
+		p.handleCommentRevisions, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteCommentProof,
+		p.handleCommentProof, permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteUserProposals, p.handleUserProposals,
 		permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteActiveVote, p.handleActiveVote,
@@ -1071,6 +1614,14 @@ func (p *politeiawww) setPoliteiaWWWRoutes() {
 		permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteVoteResults,
 		p.handleVoteResults, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteVoteResultsBundle,
+		p.handleVoteResultsBundle, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteVoteReceipt,
+		p.handleVoteReceipt, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteVoteProof,
+		p.handleVoteProof, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteVoteTimeSeries,
+		p.handleVoteTimeSeries, permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteAllVoteStatus,
 		p.handleGetAllVoteStatus, permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteVoteStatus,
@@ -1089,6 +1640,16 @@ func (p *politeiawww) setPoliteiaWWWRoutes() {
 		p.handleNewComment, permissionLogin) // XXX comments need to become a setting
 	p.addRoute(http.MethodPost, www.RouteLikeComment,
 		p.handleLikeComment, permissionLogin) // XXX comments need to become a setting
+	p.addRoute(http.MethodPost, www.RouteEditComment,
+		p.handleEditComment, permissionLogin) // XXX comments need to become a setting
+	p.addRoute(http.MethodPost, www.RouteDeleteComment,
+		p.handleDeleteComment, permissionLogin) // XXX comments need to become a setting
+	p.addRoute(http.MethodPost, www.RouteFlagComment,
+		p.handleFlagComment, permissionLogin) // XXX comments need to become a setting
+	p.addRoute(http.MethodPost, www.RouteAppealCensorship,
+		p.handleAppealCensorship, permissionLogin) // XXX comments need to become a setting
+	p.addRoute(http.MethodPost, www.RouteMarkCommentsRead,
+		p.handleMarkCommentsRead, permissionLogin) // XXX comments need to become a setting
 	p.addRoute(http.MethodPost, www.RouteEditProposal,
 		p.handleEditProposal, permissionLogin)
 	p.addRoute(http.MethodPost, www.RouteAuthorizeVote,
@@ -1110,6 +1671,20 @@ func (p *politeiawww) setPoliteiaWWWRoutes() {
 		p.handleSetProposalStatus, permissionAdmin)
 	p.addRoute(http.MethodPost, www.RouteStartVote,
 		p.handleStartVote, permissionAdmin)
+	p.addRoute(http.MethodPost, www.RouteStartVoteRunoff,
+		p.handleStartVoteRunoff, permissionAdmin)
+	p.addRoute(http.MethodPost, www.RouteCancelVote,
+		p.handleCancelVote, permissionAdmin)
 	p.addRoute(http.MethodPost, www.RouteCensorComment,
 		p.handleCensorComment, permissionAdmin)
+	p.addRoute(http.MethodGet, www.RouteFlaggedComments,
+		p.handleFlaggedComments, permissionAdmin)
+	p.addRoute(http.MethodPost, www.RouteDismissCommentFlags,
+		p.handleDismissCommentFlags, permissionAdmin)
+	p.addRoute(http.MethodGet, www.RouteCensorshipAppeals,
+		p.handleCensorshipAppeals, permissionAdmin)
+	p.addRoute(http.MethodPost, www.RouteResolveAppeal,
+		p.handleResolveAppeal, permissionAdmin)
+	p.addRoute(http.MethodGet, www.RouteCacheStats,
+		p.handleCacheStats, permissionAdmin)
 }
@@ -39,6 +39,10 @@ const (
 
 	VersionMDStreamChanges         = 1
 	BackendProposalMetadataVersion = 1
+
+	// scheduledVotesInterval is the interval at which politeiawww
+	// checks whether politeiad has started any scheduled votes.
+	scheduledVotesInterval = time.Minute
 )
 
 type MDStreamChanges struct {
@@ -68,13 +72,14 @@ type proposalsSummary struct {
 	Abandoned         int
 }
 
-// proposalsFilter is used to pass filtering parameters into the filterProps
+// proposalsFilter is used to pass filtering parameters into the getUserProps
 // function.
 type proposalsFilter struct {
-	After    string
-	Before   string
-	UserID   string
-	StateMap map[www.PropStateT]bool
+	After           string
+	Before          string
+	TimestampAfter  int64
+	TimestampBefore int64
+	StateMap        map[www.PropStateT]bool
 }
 
 type VoteDetails struct {
@@ -82,6 +87,9 @@ type VoteDetails struct {
 	AuthorizeVoteReply www.AuthorizeVoteReply // Authorize vote reply
 	StartVote          www.StartVote          // Start vote
 	StartVoteReply     www.StartVoteReply     // Start vote reply
+	CancelVote         www.CancelVote         // Cancel vote
+	CancelVoteReply    www.CancelVoteReply    // Cancel vote reply
+	ScheduledVote      www.StartVote          // Vote scheduled to start
 }
 
 // encodeBackendProposalMetadata encodes BackendProposalMetadata into a JSON
@@ -139,7 +147,53 @@ func decodeMDStreamChanges(payload []byte) ([]MDStreamChanges, error) {
 	return msc, nil
 }
 
-// validateVoteBit ensures that bit is a valid vote bit.
+// validateVoteOptions ensures that the vote type is valid and that the vote
+// has the options that the vote type requires.
+func validateVoteOptions(vote www.Vote) error {
+	switch vote.Type {
+	case www.VoteTypeStandard:
+		return nil
+	case www.VoteTypeMultipleChoice, www.VoteTypeApproval:
+		if len(vote.Options) < 2 {
+			return www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+	case www.VoteTypeRunoff:
+		// Runoff votes are tallied using the approve option
+		for _, v := range vote.Options {
+			if v.Id == bitumplugin.VoteOptionIDApprove {
+				return nil
+			}
+		}
+		return www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+		}
+	default:
+		return www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+		}
+	}
+
+	// Approval votes can select several options at once so the
+	// option bits are not allowed to overlap.
+	if vote.Type == www.VoteTypeApproval {
+		var seen uint64
+		for _, v := range vote.Options {
+			if seen&v.Bits != 0 {
+				return www.UserError{
+					ErrorCode: www.ErrorStatusInvalidPropVoteBits,
+				}
+			}
+			seen |= v.Bits
+		}
+	}
+
+	return nil
+}
+
+// validateVoteBit ensures that bit is a valid vote bit.  Approval votes may
+// set the bits of several options.
 func validateVoteBit(vote www.Vote, bit uint64) error {
 	if len(vote.Options) == 0 {
 		return fmt.Errorf("vote corrupt")
@@ -152,6 +206,17 @@ func validateVoteBit(vote www.Vote, bit uint64) error {
 			vote.Mask, bit)
 	}
 
+	if vote.Type == www.VoteTypeApproval {
+		var options uint64
+		for _, v := range vote.Options {
+			options |= v.Bits
+		}
+		if options&bit == bit {
+			return nil
+		}
+		return fmt.Errorf("bit not found 0x%x", bit)
+	}
+
 	for _, v := range vote.Options {
 		if v.Bits == bit {
 			return nil
@@ -323,7 +388,11 @@ func voteIsAuthorized(avr www.AuthorizeVoteReply) bool {
 }
 
 // getVoteStatus returns the status for the provided vote.
-func getVoteStatus(avr www.AuthorizeVoteReply, svr www.StartVoteReply, bestBlock uint64) www.PropVoteStatusT {
+func getVoteStatus(avr www.AuthorizeVoteReply, svr www.StartVoteReply, cvr www.CancelVoteReply, bestBlock uint64) www.PropVoteStatusT {
+	if cvr.Receipt != "" {
+		// Vote has been cancelled by an admin
+		return www.PropVoteStatusCancelled
+	}
 	if svr.StartBlockHeight == "" {
 		// Vote has not started. Check if it's been authorized yet.
 		if voteIsAuthorized(avr) {
@@ -350,8 +419,17 @@ func getVoteStatus(avr www.AuthorizeVoteReply, svr www.StartVoteReply, bestBlock
 
 func voteStatusFromVoteSummary(r bitumplugin.VoteSummaryReply, bestBlock uint64) www.PropVoteStatusT {
 	switch {
+	case r.Cancelled:
+		return www.PropVoteStatusCancelled
 	case !r.Authorized:
 		return www.PropVoteStatusNotAuthorized
+	case r.Scheduled && bestBlock < uint64(r.StartHeight):
+		return www.PropVoteStatusScheduled
+	case r.Scheduled:
+		// politeiad starts a scheduled vote on its own once the
+		// start height has been reached so the summary may not
+		// reflect that the vote is open yet.
+		return www.PropVoteStatusStarted
 	case r.EndHeight == "":
 		return www.PropVoteStatusAuthorized
 	default:
@@ -396,7 +474,7 @@ func convertWWWPropCreditFromDatabasePropCredit(credit user.ProposalCredit) www.
 func (p *politeiawww) getProp(token string) (*www.ProposalRecord, error) {
 	log.Tracef("getProp: %v", token)
 
-	r, err := p.cache.Record(token)
+	r, err := p.readCache.record(p.cache, token)
 	if err != nil {
 		return nil, err
 	}
@@ -459,17 +537,9 @@ func (p *politeiawww) getPropVersion(token, version string) (*www.ProposalRecord
 	return &pr, nil
 }
 
-// getAllProps gets the latest version of all proposals from the cache then
-// fills any missing fields before returning the proposals.
-func (p *politeiawww) getAllProps() ([]www.ProposalRecord, error) {
-	log.Tracef("getAllProps")
-
-	// Get proposals from cache
-	records, err := p.cache.Inventory()
-	if err != nil {
-		return nil, err
-	}
-
+// fillProps converts the passed in cache records to proposals then fills in
+// any missing fields before returning the proposals.
+func (p *politeiawww) fillProps(records []cache.Record) []www.ProposalRecord {
 	// Fill in the number of comments for each proposal
 	props := make([]www.ProposalRecord, 0, len(records))
 	for _, v := range records {
@@ -477,7 +547,7 @@ func (p *politeiawww) getAllProps() ([]www.ProposalRecord, error) {
 
 		dc, err := p.bitumGetComments(pr.CensorshipRecord.Token)
 		if err != nil {
-			log.Errorf("getAllProps: bitumGetComments failed "+
+			log.Errorf("fillProps: bitumGetComments failed "+
 				"for token %v", pr.CensorshipRecord.Token)
 		}
 		pr.NumComments = uint(len(dc))
@@ -494,7 +564,7 @@ func (p *politeiawww) getAllProps() ([]www.ProposalRecord, error) {
 	for i, pr := range props {
 		userID, ok := p.userPubkeys[pr.PublicKey]
 		if !ok {
-			log.Errorf("getAllProps: userID lookup failed for "+
+			log.Errorf("fillProps: userID lookup failed for "+
 				"token:%v pubkey:%v", pr.CensorshipRecord.Token,
 				pr.PublicKey)
 		}
@@ -510,146 +580,83 @@ func (p *politeiawww) getAllProps() ([]www.ProposalRecord, error) {
 		props[i] = pr
 	}
 
-	return props, nil
+	return props
 }
 
-// filterProps filters the given proposals according to the filtering
-// parameters specified by the passed in proposalsFilter.  filterProps will
-// only return a single page of proposals regardless of how many proposals are
-// passed in.
-func filterProps(filter proposalsFilter, all []www.ProposalRecord) []www.ProposalRecord {
-	log.Tracef("filterProps")
-
-	sort.Slice(all, func(i, j int) bool {
-		// Sort by older timestamp first, if timestamps are different
-		// from each other
-		if all[i].Timestamp != all[j].Timestamp {
-			return all[i].Timestamp < all[j].Timestamp
-		}
-
-		// Otherwise sort by token
-		return all[i].CensorshipRecord.Token >
-			all[j].CensorshipRecord.Token
-	})
-
-	// pageStarted stores whether or not it's okay to start adding
-	// proposals to the array. If the after or before parameter is
-	// supplied, we must find the beginning (or end) of the page first.
-	pageStarted := (filter.After == "" && filter.Before == "")
-	beforeIdx := -1
-	proposals := make([]www.ProposalRecord, 0, len(all))
-
-	// Iterate in reverse order because they're sorted by oldest
-	// timestamp first.
-	for i := len(all) - 1; i >= 0; i-- {
-		proposal := all[i]
-
-		// Filter by user if it's provided.
-		if (filter.UserID != "") && (filter.UserID != proposal.UserId) {
-			continue
-		}
-
-		// Filter by the state.
-		if val, ok := filter.StateMap[proposal.State]; !ok || !val {
-			continue
-		}
-
-		if pageStarted {
-			proposals = append(proposals, proposal)
-			if len(proposals) >= www.ProposalListPageSize {
-				break
-			}
-		} else if filter.After != "" {
-			// The beginning of the page has been found, so
-			// the next public proposal is added.
-			pageStarted = proposal.CensorshipRecord.Token == filter.After
-		} else if filter.Before != "" {
-			// The end of the page has been found, so we'll
-			// have to iterate in the other direction to
-			// add the proposals; save the current index.
-			if proposal.CensorshipRecord.Token == filter.Before {
-				beforeIdx = i
-				break
-			}
-		}
-	}
-
-	// If beforeIdx is set, the caller is asking for vetted proposals
-	// whose last result is before the provided proposal.
-	if beforeIdx >= 0 {
-		for _, proposal := range all[beforeIdx+1:] {
-			// Filter by user if it's provided.
-			if (filter.UserID != "") && (filter.UserID != proposal.UserId) {
-				continue
-			}
-
-			// Filter by the state.
-			if val, ok := filter.StateMap[proposal.State]; !ok || !val {
-				continue
-			}
+// getProps gets a page of proposals that match the passed in query from the
+// cache then fills in any missing fields before returning the proposals.  The
+// filtering, sorting and pagination are performed by the cache.
+func (p *politeiawww) getProps(q cache.RecordQuery) ([]www.ProposalRecord, error) {
+	log.Tracef("getProps")
 
-			// The iteration direction is oldest -> newest,
-			// so proposals are prepended to the array so
-			// the result will be newest -> oldest.
-			proposals = append([]www.ProposalRecord{proposal},
-				proposals...)
-			if len(proposals) >= www.ProposalListPageSize {
-				break
-			}
-		}
+	records, err := p.cache.Query(q)
+	if err != nil {
+		return nil, err
 	}
 
-	return proposals
+	return p.fillProps(records), nil
 }
 
-// getUserProps gets the latest version of all proposals from the cache and
-// then filters the proposals according to the specified proposalsFilter, which
-// is required to contain a userID.  In addition to a page of filtered user
-// proposals, this function also returns summary statistics for all of the
-// proposals that the user has submitted grouped by proposal status.
-func (p *politeiawww) getUserProps(filter proposalsFilter) ([]www.ProposalRecord, *proposalsSummary, error) {
-	log.Tracef("getUserProps: %v", filter.UserID)
-
-	if filter.UserID == "" {
-		return nil, nil, fmt.Errorf("filter missing userID")
-	}
-
-	// Get the latest version of all proposals from the cache
-	all, err := p.getAllProps()
+// getUserProps queries the cache for a page of proposals that were submitted
+// by the passed in user and that match the specified proposalsFilter.  In
+// addition to a page of filtered user proposals, this function also returns
+// summary statistics for all of the proposals that the user has submitted
+// grouped by proposal status.
+func (p *politeiawww) getUserProps(u *user.User, filter proposalsFilter) ([]www.ProposalRecord, *proposalsSummary, error) {
+	log.Tracef("getUserProps: %v", u.ID)
+
+	// A user's proposals may have been submitted using any of
+	// the user's identities.
+	pubkeys := make([]string, 0, len(u.Identities))
+	for i := range u.Identities {
+		pubkeys = append(pubkeys, u.Identities[i].String())
+	}
+	if len(pubkeys) == 0 {
+		return []www.ProposalRecord{}, &proposalsSummary{}, nil
+	}
+
+	// The author public key is stored in the general metadata
+	// stream of the proposal.
+	author := cache.MetadataFilter{
+		StreamID: mdStreamGeneral,
+		Field:    "publickey",
+		Values:   pubkeys,
+	}
+
+	// Get a page of user proposals from the cache
+	props, err := p.getProps(cache.RecordQuery{
+		Statuses:        convertPropStatesToCache(filter.StateMap),
+		Metadata:        []cache.MetadataFilter{author},
+		After:           filter.After,
+		Before:          filter.Before,
+		TimestampAfter:  filter.TimestampAfter,
+		TimestampBefore: filter.TimestampBefore,
+		Limit:           www.ProposalListPageSize,
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("getAllProps: %v", err)
+		return nil, nil, fmt.Errorf("getProps: %v", err)
 	}
 
 	// Find proposal summary statistics for the user. This
 	// includes statistics on ALL of the proposals that the user
 	// has submitted. Not just the single page of proposals that
 	// is going to be returned.
-	var ps proposalsSummary
-	for _, v := range all {
-		if v.UserId != filter.UserID {
-			continue
-		}
-		switch v.Status {
-		case www.PropStatusNotReviewed:
-			ps.NotReviewed++
-		case www.PropStatusCensored:
-			ps.Censored++
-		case www.PropStatusPublic:
-			ps.Public++
-		case www.PropStatusUnreviewedChanges:
-			ps.UnreviewedChanges++
-		case www.PropStatusAbandoned:
-			ps.Abandoned++
-		default:
-			ps.Invalid++
-		}
+	is, err := p.cache.QueryStats(cache.RecordQuery{
+		Metadata: []cache.MetadataFilter{author},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("QueryStats: %v", err)
+	}
+	ps := proposalsSummary{
+		Invalid:           is.Invalid,
+		NotReviewed:       is.NotReviewed,
+		Censored:          is.Censored,
+		Public:            is.Public,
+		UnreviewedChanges: is.UnreviewedChanges,
+		Abandoned:         is.Archived,
 	}
 
-	// Filter proposals according to the proposalsFilter. Only
-	// a single page of proposals will be returned.
-	filtered := filterProps(filter, all)
-
-	return filtered, &ps, nil
+	return props, &ps, nil
 }
 
 func (p *politeiawww) getPropComments(token string) ([]www.Comment, error) {
@@ -660,6 +667,12 @@ func (p *politeiawww) getPropComments(token string) ([]www.Comment, error) {
 		return nil, fmt.Errorf("bitumGetComments: %v", err)
 	}
 
+	return p.fillComments(dc), nil
+}
+
+// fillComments converts the provided bitum plugin comments into www comments
+// and fills in the politeiawww specific data.
+func (p *politeiawww) fillComments(dc []bitumplugin.Comment) []www.Comment {
 	p.RLock()
 	defer p.RUnlock()
 
@@ -685,19 +698,48 @@ func (p *politeiawww) getPropComments(token string) ([]www.Comment, error) {
 		c.UserID = userID
 		c.Username = u
 
-		// Fill in result votes
-		score, ok := p.commentScores[c.Token+c.CommentID]
-		if !ok {
-			log.Errorf("getPropComments: comment score lookup failed"+
-				"pubkey:%v token:%v comment:%v", c.PublicKey, c.Token,
-				c.CommentID)
+		comments = append(comments, c)
+	}
+
+	return comments
+}
+
+// commentsQuery converts a www GetComments request into a paginated bitum
+// plugin GetComments command.  The cursor of a page is the number of root
+// comments that precede it.
+func commentsQuery(gc www.GetComments) (*bitumplugin.GetComments, error) {
+	// Pages need a stable order so default to sorting by score
+	order := gc.Sort
+	switch order {
+	case "":
+		order = www.CommentSortTop
+	case www.CommentSortTop, www.CommentSortNew, www.CommentSortOld:
+	default:
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"invalid sort"},
 		}
-		c.ResultVotes = score
+	}
 
-		comments = append(comments, c)
+	var offset uint64
+	if gc.Cursor != "" {
+		var err error
+		offset, err = strconv.ParseUint(gc.Cursor, 10, 32)
+		if err != nil {
+			return nil, www.UserError{
+				ErrorCode:    www.ErrorStatusInvalidInput,
+				ErrorContext: []string{"invalid cursor"},
+			}
+		}
 	}
 
-	return comments, nil
+	return &bitumplugin.GetComments{
+		Token:    gc.Token,
+		Sort:     order,
+		Offset:   uint32(offset),
+		Limit:    www.CommentListPageSize,
+		MaxDepth: gc.MaxDepth,
+	}, nil
 }
 
 // processNewProposal tries to submit a new proposal to politeiad.
@@ -1043,6 +1085,10 @@ func (p *politeiawww) processSetProposalStatus(sps www.SetProposalStatus, u *use
 			pr.CensorshipRecord.Token, pr.State))
 	}
 
+	// The proposal has been updated in politeiad so remove the stale
+	// copy from the read cache.
+	p.readCache.invalidateRecord(pr.CensorshipRecord.Token)
+
 	// Verify the challenge
 	err = util.VerifyChallenge(p.cfg.Identity, challenge,
 		challengeResponse)
@@ -1111,7 +1157,8 @@ func (p *politeiawww) processEditProposal(ep www.EditProposal, u *user.User) (*w
 		return nil, err
 	}
 
-	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply, bb)
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
 	if s != www.PropVoteStatusNotAuthorized {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
@@ -1199,6 +1246,9 @@ func (p *politeiawww) processEditProposal(ep www.EditProposal, u *user.User) (*w
 		return nil, err
 	}
 
+	// Remove the stale proposal from the read cache
+	p.readCache.invalidateRecord(ep.Token)
+
 	// Handle response
 	var pdReply pd.UpdateRecordReply
 	err = json.Unmarshal(responseBody, &pdReply)
@@ -1230,25 +1280,53 @@ func (p *politeiawww) processEditProposal(ep www.EditProposal, u *user.User) (*w
 }
 
 // processAllVetted returns an array of vetted proposals. The maximum number
-// of proposals returned is dictated by www.ProposalListPageSize.
-func (p *politeiawww) processAllVetted(v www.GetAllVetted) (*www.GetAllVettedReply, error) {
+// of proposals returned is dictated by www.ProposalListPageSize. The unread
+// comment counts are filled in when a session user is provided.
+func (p *politeiawww) processAllVetted(v www.GetAllVetted, sessionUser *user.User) (*www.GetAllVettedReply, error) {
 	log.Tracef("processAllVetted")
 
-	// Fetch all proposals from the cache
-	all, err := p.getAllProps()
-	if err != nil {
-		return nil, fmt.Errorf("getAllProps: %v", err)
+	// Validate the vote status filter. The vote status of a
+	// proposal depends on the best block so it is only looked
+	// up when required.
+	var (
+		voteStatuses []cache.VoteStatusT
+		bestBlock    uint64
+	)
+	if len(v.VoteStatuses) > 0 {
+		voteStatuses = make([]cache.VoteStatusT, 0, len(v.VoteStatuses))
+		for _, vs := range v.VoteStatuses {
+			s := convertPropVoteStatusToCache(vs)
+			if s == cache.VoteStatusInvalid {
+				return nil, www.UserError{
+					ErrorCode: www.ErrorStatusInvalidPropVoteStatus,
+				}
+			}
+			voteStatuses = append(voteStatuses, s)
+		}
+
+		bb, err := p.getBestBlock()
+		if err != nil {
+			return nil, fmt.Errorf("getBestBlock: %v", err)
+		}
+		bestBlock = bb
 	}
 
-	// Filter for vetted proposals
-	filter := proposalsFilter{
-		After:  v.After,
-		Before: v.Before,
-		StateMap: map[www.PropStateT]bool{
+	// Fetch a page of vetted proposals from the cache
+	props, err := p.getProps(cache.RecordQuery{
+		Statuses: convertPropStatesToCache(map[www.PropStateT]bool{
 			www.PropStateVetted: true,
-		},
+		}),
+		After:           v.After,
+		Before:          v.Before,
+		TimestampAfter:  v.TimestampAfter,
+		TimestampBefore: v.TimestampBefore,
+		VoteStatuses:    voteStatuses,
+		BestBlock:       bestBlock,
+		Limit:           www.ProposalListPageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getProps: %v", err)
 	}
-	props := filterProps(filter, all)
 
 	// Remove files from proposals
 	for i, p := range props {
@@ -1256,32 +1334,37 @@ func (p *politeiawww) processAllVetted(v www.GetAllVetted) (*www.GetAllVettedRep
 		props[i] = p
 	}
 
+	err = p.setUnreadComments(props, sessionUser)
+	if err != nil {
+		return nil, err
+	}
+
 	return &www.GetAllVettedReply{
 		Proposals: props,
 	}, nil
 }
 
-// processAllUnvetted returns an array of all unvetted proposals in reverse
-// order, because they're sorted by oldest timestamp first.
-func (p *politeiawww) processAllUnvetted(u www.GetAllUnvetted) (*www.GetAllUnvettedReply, error) {
+// processAllUnvetted returns an array of unvetted proposals sorted by newest
+// timestamp first. The maximum number of proposals returned is dictated by
+// www.ProposalListPageSize. The unread comment counts are filled in when a
+// session user is provided.
+func (p *politeiawww) processAllUnvetted(u www.GetAllUnvetted, sessionUser *user.User) (*www.GetAllUnvettedReply, error) {
 	log.Tracef("processAllUnvetted")
 
-	// Fetch all proposals from the cache
-	all, err := p.getAllProps()
-	if err != nil {
-		return nil, fmt.Errorf("processAllUnvetted getAllProps: %v",
-			err)
-	}
-
-	// Filter for unvetted proposals
-	filter := proposalsFilter{
-		After:  u.After,
-		Before: u.Before,
-		StateMap: map[www.PropStateT]bool{
+	// Fetch a page of unvetted proposals from the cache
+	props, err := p.getProps(cache.RecordQuery{
+		Statuses: convertPropStatesToCache(map[www.PropStateT]bool{
 			www.PropStateUnvetted: true,
-		},
+		}),
+		After:           u.After,
+		Before:          u.Before,
+		TimestampAfter:  u.TimestampAfter,
+		TimestampBefore: u.TimestampBefore,
+		Limit:           www.ProposalListPageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getProps: %v", err)
 	}
-	props := filterProps(filter, all)
 
 	// Remove files from proposals
 	for i, p := range props {
@@ -1289,6 +1372,11 @@ func (p *politeiawww) processAllUnvetted(u www.GetAllUnvetted) (*www.GetAllUnvet
 		props[i] = p
 	}
 
+	err = p.setUnreadComments(props, sessionUser)
+	if err != nil {
+		return nil, err
+	}
+
 	return &www.GetAllUnvettedReply{
 		Proposals: props,
 	}, nil
@@ -1316,16 +1404,40 @@ func (p *politeiawww) processProposalsStats() (*www.ProposalsStatsReply, error)
 	}, nil
 }
 
-// processCommentsGet returns all comments for a given proposal. If the user is
-// logged in the user's last access time for the given comments will also be
+// processCommentsGet returns the comments for a given proposal. If the user
+// is logged in the user's last access time for the given comments will also
+// be returned.  All comments are returned unless a sort order, cursor or
+// maximum depth is requested, in which case a single page of comments is
 // returned.
-func (p *politeiawww) processCommentsGet(token string, u *user.User) (*www.GetCommentsReply, error) {
-	log.Tracef("ProcessCommentGet: %v", token)
+func (p *politeiawww) processCommentsGet(gc www.GetComments, u *user.User) (*www.GetCommentsReply, error) {
+	log.Tracef("ProcessCommentGet: %v", gc.Token)
+
+	token := gc.Token
 
 	// Fetch proposal comments from cache
-	c, err := p.getPropComments(token)
-	if err != nil {
-		return nil, err
+	var (
+		c          []www.Comment
+		nextCursor string
+	)
+	if gc.Sort == "" && gc.Cursor == "" && gc.MaxDepth == 0 {
+		var err error
+		c, err = p.getPropComments(token)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		q, err := commentsQuery(gc)
+		if err != nil {
+			return nil, err
+		}
+		gcr, err := p.bitumGetCommentsPage(*q)
+		if err != nil {
+			return nil, fmt.Errorf("bitumGetCommentsPage: %v", err)
+		}
+		c = p.fillComments(gcr.Comments)
+		if gcr.NextOffset != 0 {
+			nextCursor = strconv.FormatUint(uint64(gcr.NextOffset), 10)
+		}
 	}
 
 	// Get the last time the user accessed these comments. This is
@@ -1337,7 +1449,7 @@ func (p *politeiawww) processCommentsGet(token string, u *user.User) (*www.GetCo
 		}
 		accessTime = u.ProposalCommentsAccessTimes[token]
 		u.ProposalCommentsAccessTimes[token] = time.Now().Unix()
-		err = p.db.UserUpdate(*u)
+		err := p.db.UserUpdate(*u)
 		if err != nil {
 			return nil, err
 		}
@@ -1346,6 +1458,7 @@ func (p *politeiawww) processCommentsGet(token string, u *user.User) (*www.GetCo
 	return &www.GetCommentsReply{
 		Comments:   c,
 		AccessTime: accessTime,
+		NextCursor: nextCursor,
 	}, nil
 }
 
@@ -1397,33 +1510,69 @@ func (p *politeiawww) voteStatusReply(token string, bestBlock uint64) (*www.Vote
 		return nil, err
 	}
 
-	results := convertVoteOptionResultsFromBitum(r.Results)
-	var total uint64
-	for _, v := range results {
-		total += v.VotesReceived
-	}
-
 	vsr = www.VoteStatusReply{
 		Token:              token,
 		Status:             voteStatusFromVoteSummary(*r, bestBlock),
-		TotalVotes:         total,
-		OptionsResult:      results,
+		Type:               www.VoteT(r.Type),
+		TotalVotes:         r.TotalVotes,
+		OptionsResult:      convertVoteOptionResultsFromBitum(r.Results),
 		EndHeight:          r.EndHeight,
 		NumOfEligibleVotes: r.EligibleTicketCount,
 		QuorumPercentage:   r.QuorumPercentage,
 		PassPercentage:     r.PassPercentage,
+		CancelReason:       r.CancelReason,
+		StartHeight:        r.StartHeight,
 	}
-
-	// If the voting period has ended the vote status
-	// is not going to change so add it to the memory
-	// cache.
 	if vsr.Status == www.PropVoteStatusFinished {
+		vsr.Winner = bitumplugin.VoteWinner(r.Type, r.QuorumPercentage,
+			r.PassPercentage, r.EligibleTicketCount, r.TotalVotes,
+			r.Results)
+	}
+
+	// The winner of a runoff vote can only be determined using
+	// the results of all proposals in the runoff.
+	if r.Type == bitumplugin.VoteTypeRunoff {
+		vsr.RunoffTokens = r.RunoffTokens
+		if vsr.Status == www.PropVoteStatusFinished {
+			winner, err := p.runoffWinner(*r)
+			if err != nil {
+				return nil, err
+			}
+			vsr.RunoffWinner = winner
+			if winner == token {
+				vsr.Winner = bitumplugin.VoteOptionIDApprove
+			}
+		}
+	}
+
+	// If the voting period has ended or the vote has been
+	// cancelled the vote status is not going to change so
+	// add it to the memory cache.
+	if vsr.Status == www.PropVoteStatusFinished ||
+		vsr.Status == www.PropVoteStatusCancelled {
 		p.setVoteStatusReply(vsr)
 	}
 
 	return &vsr, nil
 }
 
+// runoffWinner returns the token of the proposal that won the runoff vote
+// that the passed in vote summary is part of.
+func (p *politeiawww) runoffWinner(vs bitumplugin.VoteSummaryReply) (string, error) {
+	results := make(map[string][]bitumplugin.VoteOptionResult,
+		len(vs.RunoffTokens))
+	for _, token := range vs.RunoffTokens {
+		r, err := p.bitumVoteSummary(token)
+		if err != nil {
+			return "", err
+		}
+		results[token] = r.Results
+	}
+
+	return bitumplugin.RunoffWinner(vs.QuorumPercentage, vs.PassPercentage,
+		vs.EligibleTicketCount, results), nil
+}
+
 // processVoteStatus returns the vote status for a given proposal
 func (p *politeiawww) processVoteStatus(token string) (*www.VoteStatusReply, error) {
 	log.Tracef("ProcessProposalVotingStatus: %v", token)
@@ -1471,20 +1620,17 @@ func (p *politeiawww) processGetAllVoteStatus() (*www.GetAllVoteStatusReply, err
 		return nil, fmt.Errorf("bestBlock: %v", err)
 	}
 
-	// Get all proposals from cache
-	all, err := p.getAllProps()
+	// Get all public proposals from cache
+	all, err := p.getProps(cache.RecordQuery{
+		Statuses: []cache.RecordStatusT{cache.RecordStatusPublic},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("getAllProps: %v", err)
+		return nil, fmt.Errorf("getProps: %v", err)
 	}
 
 	// Compile votes statuses
 	vrr := make([]www.VoteStatusReply, 0, len(all))
 	for _, v := range all {
-		// We only need public proposals
-		if v.Status != www.PropStatusPublic {
-			continue
-		}
-
 		// Get vote status for proposal
 		vs, err := p.voteStatusReply(v.CensorshipRecord.Token, bestBlock)
 		if err != nil {
@@ -1509,14 +1655,23 @@ func (p *politeiawww) processActiveVote() (*www.ActiveVoteReply, error) {
 		return nil, err
 	}
 
-	// Get all proposals from cache
-	all, err := p.getAllProps()
+	// Get the public proposals whose vote is either scheduled or
+	// currently being voted on from the cache
+	all, err := p.getProps(cache.RecordQuery{
+		Statuses: []cache.RecordStatusT{cache.RecordStatusPublic},
+		VoteStatuses: []cache.VoteStatusT{
+			cache.VoteStatusScheduled,
+			cache.VoteStatusStarted,
+		},
+		BestBlock: bestBlock,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("getAllProps: %v", err)
+		return nil, fmt.Errorf("getProps: %v", err)
 	}
 
 	// Compile proposal vote tuples
 	pvt := make([]www.ProposalVoteTuple, 0, len(all))
+	scheduled := make([]www.ProposalVoteTuple, 0)
 	for _, v := range all {
 		// Get vote details from cache
 		vdr, err := p.bitumVoteDetails(v.CensorshipRecord.Token)
@@ -1527,9 +1682,13 @@ func (p *politeiawww) processActiveVote() (*www.ActiveVoteReply, error) {
 		}
 		vd := convertVoteDetailsReplyFromBitum(*vdr)
 
-		// We only want proposals that are currently being voted on
-		s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply, bestBlock)
-		if s != www.PropVoteStatusStarted {
+		// Scheduled votes are returned separately until they
+		// have been started by politeiad.
+		if vd.ScheduledVote.Vote.Token != "" {
+			scheduled = append(scheduled, www.ProposalVoteTuple{
+				Proposal:  v,
+				StartVote: vd.ScheduledVote,
+			})
 			continue
 		}
 
@@ -1541,7 +1700,8 @@ func (p *politeiawww) processActiveVote() (*www.ActiveVoteReply, error) {
 	}
 
 	return &www.ActiveVoteReply{
-		Votes: pvt,
+		Votes:     pvt,
+		Scheduled: scheduled,
 	}, nil
 }
 
@@ -1586,84 +1746,384 @@ func (p *politeiawww) processVoteResults(token string) (*www.VoteResultsReply, e
 	}, nil
 }
 
-// processCastVotes handles the www.Ballot call
-func (p *politeiawww) processCastVotes(ballot *www.Ballot) (*www.BallotReply, error) {
-	log.Tracef("processCastVotes")
+// processVoteReceipt returns the vote that the passed in ticket cast on the
+// passed in proposal along with the receipt that politeiad returned for it.
+func (p *politeiawww) processVoteReceipt(token, ticket string) (*www.VoteReceiptReply, error) {
+	log.Tracef("processVoteReceipt: %v %v", token, ticket)
 
-	challenge, err := util.Random(pd.ChallengeSize)
+	// Ensure proposal is vetted
+	pr, err := p.getProp(token)
 	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
 		return nil, err
 	}
+	if pr.State != www.PropStateVetted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongStatus,
+		}
+	}
 
-	payload, err := bitumplugin.EncodeBallot(convertBallotFromWWW(*ballot))
+	// Lookup the vote in the cache
+	vrr, err := p.bitumVoteReceipt(token, ticket)
 	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusVoteReceiptNotFound,
+			}
+		}
 		return nil, err
 	}
-	pc := pd.PluginCommand{
-		Challenge: hex.EncodeToString(challenge),
-		ID:        bitumplugin.ID,
-		Command:   bitumplugin.CmdBallot,
-		CommandID: bitumplugin.CmdBallot,
-		Payload:   string(payload),
-	}
 
-	responseBody, err := p.makeRequest(http.MethodPost,
-		pd.PluginCommandRoute, pc)
+	return &www.VoteReceiptReply{
+		CastVote: convertCastVoteFromBitum(vrr.CastVote),
+		Receipt:  vrr.Receipt,
+	}, nil
+}
+
+// processVoteProof returns the proof that the latest vote of a ticket existed
+// when the ballot journal of the proposal was anchored.
+func (p *politeiawww) processVoteProof(token, ticket string) (*www.JournalProofReply, error) {
+	log.Tracef("processVoteProof: %v %v", token, ticket)
+
+	// Ensure the ticket voted
+	_, err := p.processVoteReceipt(token, ticket)
 	if err != nil {
 		return nil, err
 	}
 
-	var reply pd.PluginCommandReply
-	err = json.Unmarshal(responseBody, &reply)
-	if err != nil {
-		return nil, fmt.Errorf("Could not unmarshal "+
-			"PluginCommandReply: %v", err)
-	}
+	return p.journalProof(bitumplugin.JournalProof{
+		Token:  token,
+		Ticket: ticket,
+	})
+}
 
-	// Verify the challenge.
-	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+// quorumVotes returns the number of votes that are required to meet the
+// quorum of a vote.
+func quorumVotes(quorumPercentage uint32, eligibleTickets int) uint64 {
+	return uint64(float64(quorumPercentage) / 100 * float64(eligibleTickets))
+}
+
+// voteTimeSeries buckets the passed in vote bit counts by block height and
+// returns the cumulative vote results at the end of each bucket.  The buckets
+// cover the voting period up to the best block.  Votes that were cast before
+// block heights were recorded are counted in the first bucket.
+func voteTimeSeries(sv bitumplugin.StartVote, svr bitumplugin.StartVoteReply, counts []bitumplugin.VoteBitCount, bucketSize uint32, bestBlock uint64) ([]www.VoteTimeSeriesBucket, error) {
+	start, err := strconv.ParseUint(svr.StartBlockHeight, 10, 32)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid start height '%v': %v",
+			svr.StartBlockHeight, err)
 	}
-
-	// Decode plugin reply
-	br, err := bitumplugin.DecodeBallotReply([]byte(reply.Payload))
+	end, err := strconv.ParseUint(svr.EndHeight, 10, 32)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid end height '%v': %v",
+			svr.EndHeight, err)
+	}
+	if bucketSize == 0 {
+		bucketSize = 1
+	}
+	last := end
+	if bestBlock < last {
+		last = bestBlock
+	}
+	if last < start {
+		last = start
 	}
-	brr := convertBallotReplyFromBitumPlugin(*br)
-	return &brr, nil
-}
 
-// processProposalPaywallDetails returns a proposal paywall that enables the
-// the user to purchase proposal credits. The user can only have one paywall
-// active at a time.  If no paywall currently exists, a new one is created and
-// the user is added to the paywall pool.
-func (p *politeiawww) processProposalPaywallDetails(u *user.User) (*www.ProposalPaywallDetailsReply, error) {
-	log.Tracef("processProposalPaywallDetails")
+	quorum := quorumVotes(sv.Vote.QuorumPercentage,
+		len(svr.EligibleTickets))
+	results := make([]uint64, len(sv.Vote.Options))
+	var total uint64
 
-	// Ensure paywall is enabled
-	if !p.paywallIsEnabled() {
-		return &www.ProposalPaywallDetailsReply{}, nil
-	}
+	size := uint64(bucketSize)
+	buckets := make([]www.VoteTimeSeriesBucket, 0, (last-start)/size+1)
+	i := 0
+	for height := start; height <= last; height += size {
+		bucketEnd := height + size - 1
+		if bucketEnd > last {
+			bucketEnd = last
+		}
+
+		// Add the votes that were cast in this bucket. The
+		// final bucket also picks up any votes that were cast
+		// after the best block that we know of.
+		for ; i < len(counts); i++ {
+			c := counts[i]
+			if uint64(c.BlockHeight) > bucketEnd && bucketEnd != last {
+				break
+			}
+			bits, err := strconv.ParseUint(c.VoteBit, 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vote bit '%v': %v",
+					c.VoteBit, err)
+			}
+			total += c.Votes
+			for k, o := range sv.Vote.Options {
+				if bitumplugin.VoteOptionSelected(sv.Vote.Type, bits,
+					o.Bits) {
+					results[k] += c.Votes
+				}
+			}
+		}
 
-	// Proposal paywalls cannot be generated until the user has paid their
-	// user registration fee.
-	if !p.HasUserPaid(u) {
-		return nil, www.UserError{
-			ErrorCode: www.ErrorStatusUserNotPaid,
+		progress := uint64(100)
+		if quorum > 0 {
+			progress = total * 100 / quorum
 		}
+		or := make([]www.VoteOptionResult, 0, len(sv.Vote.Options))
+		for k, o := range sv.Vote.Options {
+			or = append(or, www.VoteOptionResult{
+				Option: www.VoteOption{
+					Id:          o.Id,
+					Description: o.Description,
+					Bits:        o.Bits,
+				},
+				VotesReceived: results[k],
+			})
+		}
+		buckets = append(buckets, www.VoteTimeSeriesBucket{
+			StartHeight:    uint32(height),
+			EndHeight:      uint32(bucketEnd),
+			TotalVotes:     total,
+			QuorumProgress: uint32(progress),
+			OptionsResult:  or,
+		})
 	}
 
-	var pp *user.ProposalPaywall
-	if p.userHasValidProposalPaywall(u) {
-		// Don't create a new paywall if a valid one already exists.
-		pp = p.mostRecentProposalPaywall(u)
-	} else {
-		// Create a new paywall.
-		var err error
-		pp, err = p.generateProposalPaywall(u)
-		if err != nil {
+	return buckets, nil
+}
+
+// processVoteTimeSeries returns the cumulative vote results of a proposal
+// vote bucketed by block height.
+func (p *politeiawww) processVoteTimeSeries(token string, vts www.VoteTimeSeries) (*www.VoteTimeSeriesReply, error) {
+	log.Tracef("processVoteTimeSeries: %v", token)
+
+	// Ensure proposal is vetted
+	pr, err := p.getProp(token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
+		return nil, err
+	}
+	if pr.State != www.PropStateVetted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongStatus,
+		}
+	}
+
+	// Get the vote bit counts from the cache. A start vote
+	// will not exist if the vote has not been started.
+	r, err := p.bitumVoteTimeSeries(token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusWrongVoteStatus,
+			}
+		}
+		return nil, err
+	}
+
+	bb, err := p.getBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("bestBlock: %v", err)
+	}
+
+	if vts.BucketSize == 0 {
+		vts.BucketSize = 1
+	}
+	buckets, err := voteTimeSeries(r.StartVote, r.StartVoteReply, r.Counts,
+		vts.BucketSize, bb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.VoteTimeSeriesReply{
+		Token:              token,
+		BucketSize:         vts.BucketSize,
+		NumOfEligibleVotes: len(r.StartVoteReply.EligibleTickets),
+		QuorumPercentage:   r.StartVote.Vote.QuorumPercentage,
+		QuorumVotes: quorumVotes(r.StartVote.Vote.QuorumPercentage,
+			len(r.StartVoteReply.EligibleTickets)),
+		Buckets: buckets,
+	}, nil
+}
+
+// processVoteResultsBundle returns the signed results bundle of a finished
+// proposal vote.  The bundle is created and signed by politeiad.
+func (p *politeiawww) processVoteResultsBundle(token string) (*www.VoteResultsBundleReply, error) {
+	log.Tracef("processVoteResultsBundle: %v", token)
+
+	// Ensure proposal is vetted
+	pr, err := p.getProp(token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
+		return nil, err
+	}
+	if pr.State != www.PropStateVetted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongStatus,
+		}
+	}
+
+	// Ensure the vote has finished
+	bb, err := p.getBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("bestBlock: %v", err)
+	}
+	vsr, err := p.voteStatusReply(token, bb)
+	if err != nil {
+		return nil, fmt.Errorf("voteStatusReply: %v", err)
+	}
+	if vsr.Status != www.PropVoteStatusFinished {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongVoteStatus,
+		}
+	}
+
+	// Request the bundle from politeiad
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := bitumplugin.EncodeGetVoteResultsBundle(
+		bitumplugin.GetVoteResultsBundle{
+			Token: token,
+		})
+	if err != nil {
+		return nil, err
+	}
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdVoteResultsBundle,
+		CommandID: bitumplugin.CmdVoteResultsBundle + " " + token,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal "+
+			"PluginCommandReply: %v", err)
+	}
+
+	// Verify the challenge.
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanity check the bundle before forwarding it as-is
+	_, err = bitumplugin.DecodeVoteResultsBundle([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &www.VoteResultsBundleReply{
+		Bundle: json.RawMessage(reply.Payload),
+	}, nil
+}
+
+// processCastVotes handles the www.Ballot call
+func (p *politeiawww) processCastVotes(ballot *www.Ballot) (*www.BallotReply, error) {
+	log.Tracef("processCastVotes")
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := bitumplugin.EncodeBallot(convertBallotFromWWW(*ballot))
+	if err != nil {
+		return nil, err
+	}
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdBallot,
+		CommandID: bitumplugin.CmdBallot,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The vote results of every proposal that was voted on have
+	// changed so remove the stale vote summaries from the read cache.
+	for _, v := range ballot.Votes {
+		p.readCache.invalidateVoteSummary(v.Token)
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal "+
+			"PluginCommandReply: %v", err)
+	}
+
+	// Verify the challenge.
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode plugin reply
+	br, err := bitumplugin.DecodeBallotReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+	brr := convertBallotReplyFromBitumPlugin(*br)
+	return &brr, nil
+}
+
+// processProposalPaywallDetails returns a proposal paywall that enables the
+// the user to purchase proposal credits. The user can only have one paywall
+// active at a time.  If no paywall currently exists, a new one is created and
+// the user is added to the paywall pool.
+func (p *politeiawww) processProposalPaywallDetails(u *user.User) (*www.ProposalPaywallDetailsReply, error) {
+	log.Tracef("processProposalPaywallDetails")
+
+	// Ensure paywall is enabled
+	if !p.paywallIsEnabled() {
+		return &www.ProposalPaywallDetailsReply{}, nil
+	}
+
+	// Proposal paywalls cannot be generated until the user has paid their
+	// user registration fee.
+	if !p.HasUserPaid(u) {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserNotPaid,
+		}
+	}
+
+	var pp *user.ProposalPaywall
+	if p.userHasValidProposalPaywall(u) {
+		// Don't create a new paywall if a valid one already exists.
+		pp = p.mostRecentProposalPaywall(u)
+	} else {
+		// Create a new paywall.
+		var err error
+		pp, err = p.generateProposalPaywall(u)
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -1747,6 +2207,11 @@ func (p *politeiawww) processAuthorizeVote(av www.AuthorizeVote, u *user.User) (
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
 		}
+	case vd.ScheduledVote.Vote.Token != "":
+		// Vote has already been scheduled
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongVoteStatus,
+		}
 	case av.Action != www.AuthVoteActionAuthorize &&
 		av.Action != www.AuthVoteActionRevoke:
 		// Invalid authorize vote action
@@ -1812,6 +2277,9 @@ func (p *politeiawww) processAuthorizeVote(av www.AuthorizeVote, u *user.User) (
 		return nil, err
 	}
 
+	// Remove the stale proposal from the read cache
+	p.readCache.invalidateRecord(av.Token)
+
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
 	if err != nil {
@@ -1845,22 +2313,96 @@ func (p *politeiawww) processAuthorizeVote(av www.AuthorizeVote, u *user.User) (
 	}, nil
 }
 
-// processStartVote handles the www.StartVote call.
-func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.StartVoteReply, error) {
-	log.Tracef("processStartVote %v", sv.Vote.Token)
+// votePolicies returns the vote policies that are defined in the config
+// sorted by name.
+func (p *politeiawww) votePolicies() []www.VotePolicy {
+	vp := make([]www.VotePolicy, 0, len(p.cfg.votePolicies))
+	for _, v := range p.cfg.votePolicies {
+		vp = append(vp, v)
+	}
+	sort.Slice(vp, func(i, j int) bool {
+		return vp[i].Name < vp[j].Name
+	})
+	return vp
+}
 
+// applyVotePolicy sets the vote parameters of the passed in start vote to the
+// parameters of the vote policy that it names.  Parameters that were provided
+// by the client must match the policy.  The vote options must be allowed by
+// the policy.
+func (p *politeiawww) applyVotePolicy(sv *www.StartVote) error {
+	if sv.Policy == "" {
+		return nil
+	}
+	vp, ok := p.cfg.votePolicies[sv.Policy]
+	if !ok {
+		return www.UserError{
+			ErrorCode: www.ErrorStatusInvalidVotePolicy,
+		}
+	}
+
+	// Fill in the vote parameters
+	params := []struct {
+		value  *uint32
+		policy uint32
+	}{
+		{&sv.Vote.Duration, vp.Duration},
+		{&sv.Vote.QuorumPercentage, vp.QuorumPercentage},
+		{&sv.Vote.PassPercentage, vp.PassPercentage},
+	}
+	for _, v := range params {
+		if *v.value != 0 && *v.value != v.policy {
+			return www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+		*v.value = v.policy
+	}
+
+	// Verify the vote options
+	if len(vp.Options) == 0 {
+		return nil
+	}
+	allowed := make(map[string]struct{}, len(vp.Options))
+	for _, v := range vp.Options {
+		allowed[v] = struct{}{}
+	}
+	if len(sv.Vote.Options) != len(allowed) {
+		return www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+		}
+	}
+	for _, v := range sv.Vote.Options {
+		if _, ok := allowed[v.Id]; !ok {
+			return www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+		delete(allowed, v.Id)
+	}
+
+	return nil
+}
+
+// validateStartVote ensures that the passed in start vote was signed by the
+// user and that the vote type, bits and parameters are valid.
+func (p *politeiawww) validateStartVote(sv www.StartVote, u *user.User) error {
 	// Verify user
 	err := checkPublicKeyAndSignature(u, sv.PublicKey, sv.Signature,
 		sv.Vote.Token)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Validate vote bits
+	// Validate vote type and bits
+	err = validateVoteOptions(sv.Vote)
+	if err != nil {
+		return err
+	}
 	for _, v := range sv.Vote.Options {
 		err = validateVoteBit(sv.Vote, v.Bits)
 		if err != nil {
-			return nil, www.UserError{
+			return www.UserError{
 				ErrorCode: www.ErrorStatusInvalidPropVoteBits,
 			}
 		}
@@ -1870,54 +2412,104 @@ func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.Sta
 	if sv.Vote.Duration < p.cfg.VoteDurationMin ||
 		sv.Vote.Duration > p.cfg.VoteDurationMax ||
 		sv.Vote.QuorumPercentage > 100 || sv.Vote.PassPercentage > 100 {
-		return nil, www.UserError{
+		return www.UserError{
 			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
 		}
 	}
 
-	// Create vote bits as plugin payload
-	dsv := convertStartVoteFromWWW(sv)
-	payload, err := bitumplugin.EncodeStartVote(dsv)
-	if err != nil {
-		return nil, err
-	}
+	return nil
+}
 
+// voteCanStart ensures that the proposal exists, is public, that its vote has
+// been authorized and that its vote has not already started or been
+// scheduled.
+func (p *politeiawww) voteCanStart(token string) error {
 	// Get proposal from the cache
-	pr, err := p.getProp(sv.Vote.Token)
+	pr, err := p.getProp(token)
 	if err != nil {
 		if err == cache.ErrRecordNotFound {
 			err = www.UserError{
 				ErrorCode: www.ErrorStatusProposalNotFound,
 			}
 		}
-		return nil, err
+		return err
 	}
 
 	// Get vote details from cache
-	vdr, err := p.bitumVoteDetails(sv.Vote.Token)
+	vdr, err := p.bitumVoteDetails(token)
 	if err != nil {
-		return nil, fmt.Errorf("bitumVoteDetails: %v", err)
+		return fmt.Errorf("bitumVoteDetails: %v", err)
 	}
 	vd := convertVoteDetailsReplyFromBitum(*vdr)
 
 	// Ensure record is public, vote has been authorized,
-	// and vote has not already started.
+	// and vote has not already started or been scheduled.
 	if pr.Status != www.PropStatusPublic {
-		return nil, www.UserError{
+		return www.UserError{
 			ErrorCode: www.ErrorStatusWrongStatus,
 		}
 	}
 	if !voteIsAuthorized(vd.AuthorizeVoteReply) {
-		return nil, www.UserError{
+		return www.UserError{
 			ErrorCode: www.ErrorStatusVoteNotAuthorized,
 		}
 	}
-	if vd.StartVoteReply.StartBlockHeight != "" {
-		return nil, www.UserError{
+	if vd.StartVoteReply.StartBlockHeight != "" ||
+		vd.ScheduledVote.Vote.Token != "" {
+		return www.UserError{
 			ErrorCode: www.ErrorStatusWrongVoteStatus,
 		}
 	}
 
+	return nil
+}
+
+// processStartVote handles the www.StartVote call.
+func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.StartVoteReply, error) {
+	log.Tracef("processStartVote %v", sv.Vote.Token)
+
+	// Runoff votes must be started using the runoff route
+	// since they span several proposals.
+	if sv.Vote.Type == www.VoteTypeRunoff {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+		}
+	}
+
+	err := p.applyVotePolicy(&sv)
+	if err != nil {
+		return nil, err
+	}
+	err = p.validateStartVote(sv, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// A scheduled vote must start at a future block height
+	if sv.Vote.StartHeight != 0 {
+		bestBlock, err := p.getBestBlock()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(sv.Vote.StartHeight) <= bestBlock {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+	}
+
+	// Create vote bits as plugin payload
+	dsv := convertStartVoteFromWWW(sv)
+	payload, err := bitumplugin.EncodeStartVote(dsv)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.voteCanStart(sv.Vote.Token)
+	if err != nil {
+		return nil, err
+	}
+
 	// Tell bitum plugin to start voting
 	challenge, err := util.Random(pd.ChallengeSize)
 	if err != nil {
@@ -1938,6 +2530,9 @@ func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.Sta
 		return nil, err
 	}
 
+	// Remove the stale proposal from the read cache
+	p.readCache.invalidateRecord(sv.Vote.Token)
+
 	var reply pd.PluginCommandReply
 	err = json.Unmarshal(responseBody, &reply)
 	if err != nil {
@@ -1956,7 +2551,11 @@ func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.Sta
 		return nil, err
 	}
 
-	p.fireEvent(EventTypeProposalVoteStarted,
+	eventType := EventTypeProposalVoteStarted
+	if sv.Vote.StartHeight != 0 {
+		eventType = EventTypeProposalVoteScheduled
+	}
+	p.fireEvent(eventType,
 		EventDataProposalVoteStarted{
 			AdminUser: u,
 			StartVote: &sv,
@@ -1968,6 +2567,345 @@ func (p *politeiawww) processStartVote(sv www.StartVote, u *user.User) (*www.Sta
 	return &rv, nil
 }
 
+// startedScheduledVotes compares the scheduled votes of the previous check
+// with the current token inventory.  It returns the tokens that are no longer
+// scheduled and, out of those, the tokens whose vote is now active.
+func startedScheduledVotes(scheduled map[string]struct{}, inv bitumplugin.TokenInventoryReply) ([]string, []string) {
+	current := make(map[string]struct{}, len(inv.Scheduled))
+	for _, v := range inv.Scheduled {
+		current[v] = struct{}{}
+	}
+	active := make(map[string]struct{}, len(inv.Active))
+	for _, v := range inv.Active {
+		active[v] = struct{}{}
+	}
+
+	unscheduled := make([]string, 0, len(scheduled))
+	started := make([]string, 0, len(scheduled))
+	for v := range scheduled {
+		if _, ok := current[v]; ok {
+			continue
+		}
+		unscheduled = append(unscheduled, v)
+		if _, ok := active[v]; ok {
+			started = append(started, v)
+		}
+	}
+	sort.Strings(unscheduled)
+	sort.Strings(started)
+
+	return unscheduled, started
+}
+
+// fireScheduledVoteStarted fires the vote started event for a scheduled vote
+// that has been started by politeiad.  The admin that scheduled the vote is
+// reported as the admin that started it.
+func (p *politeiawww) fireScheduledVoteStarted(token string) error {
+	vdr, err := p.bitumVoteDetails(token)
+	if err != nil {
+		return fmt.Errorf("bitumVoteDetails: %v", err)
+	}
+	sv := convertStartVoteFromBitum(vdr.StartVote)
+	u, err := p.userByPubKey(sv.PublicKey)
+	if err != nil {
+		return fmt.Errorf("userByPubKey: %v", err)
+	}
+
+	p.fireEvent(EventTypeProposalVoteStarted,
+		EventDataProposalVoteStarted{
+			AdminUser: u,
+			StartVote: &sv,
+		},
+	)
+
+	return nil
+}
+
+// watchScheduledVotes periodically checks whether politeiad has started any
+// scheduled votes.  politeiad starts a scheduled vote on its own once the
+// start height has been reached, so politeiawww has to notice the change in
+// order to drop the stale record from the read cache and to fire the vote
+// started event.  This function must be run as a go routine.
+func (p *politeiawww) watchScheduledVotes() {
+	log.Infof("Scheduled votes watcher launched")
+
+	var scheduled map[string]struct{}
+	ticker := time.NewTicker(scheduledVotesInterval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		bestBlock, err := p.getBestBlock()
+		if err != nil {
+			log.Errorf("watchScheduledVotes: getBestBlock: %v", err)
+			continue
+		}
+		inv, err := p.bitumTokenInventory(bestBlock)
+		if err != nil {
+			log.Errorf("watchScheduledVotes: bitumTokenInventory: %v",
+				err)
+			continue
+		}
+
+		// The first inventory only establishes the scheduled votes
+		if scheduled != nil {
+			unscheduled, started := startedScheduledVotes(scheduled,
+				*inv)
+			for _, v := range unscheduled {
+				p.readCache.invalidateRecord(v)
+			}
+			for _, v := range started {
+				err = p.fireScheduledVoteStarted(v)
+				if err != nil {
+					log.Errorf("watchScheduledVotes: "+
+						"fireScheduledVoteStarted %v: %v", v, err)
+				}
+			}
+		}
+
+		scheduled = make(map[string]struct{}, len(inv.Scheduled))
+		for _, v := range inv.Scheduled {
+			scheduled[v] = struct{}{}
+		}
+	}
+}
+
+// processStartVoteRunoff handles the www.StartVoteRunoff call.  All proposals
+// in the runoff are started using the same ticket snapshot.
+func (p *politeiawww) processStartVoteRunoff(svr www.StartVoteRunoff, u *user.User) (*www.StartVoteRunoffReply, error) {
+	log.Tracef("processStartVoteRunoff")
+
+	// A runoff requires at least two proposals
+	if len(svr.StartVotes) < 2 {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+		}
+	}
+
+	// Apply the vote policies before the vote parameters of
+	// the proposals are compared.
+	for i := range svr.StartVotes {
+		err := p.applyVotePolicy(&svr.StartVotes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the start votes. All proposals must use the runoff
+	// vote type with identical vote parameters.
+	first := svr.StartVotes[0].Vote
+	tokens := make(map[string]struct{}, len(svr.StartVotes))
+	dsv := make([]bitumplugin.StartVote, 0, len(svr.StartVotes))
+	for _, sv := range svr.StartVotes {
+		if sv.Vote.Type != www.VoteTypeRunoff ||
+			sv.Vote.StartHeight != 0 ||
+			sv.Vote.Duration != first.Duration ||
+			sv.Vote.QuorumPercentage != first.QuorumPercentage ||
+			sv.Vote.PassPercentage != first.PassPercentage {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+		if _, ok := tokens[sv.Vote.Token]; ok {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}
+		}
+		tokens[sv.Vote.Token] = struct{}{}
+
+		err := p.validateStartVote(sv, u)
+		if err != nil {
+			return nil, err
+		}
+		err = p.voteCanStart(sv.Vote.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		dsv = append(dsv, convertStartVoteFromWWW(sv))
+	}
+
+	payload, err := bitumplugin.EncodeStartVoteRunoff(
+		bitumplugin.StartVoteRunoff{
+			StartVotes: dsv,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tell bitum plugin to start the runoff vote
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdStartVoteRunoff,
+		CommandID: bitumplugin.CmdStartVoteRunoff,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the stale proposals from the read cache
+	for token := range tokens {
+		p.readCache.invalidateRecord(token)
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal "+
+			"PluginCommandReply: %v", err)
+	}
+
+	// Verify the challenge.
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := bitumplugin.DecodeStartVoteRunoffReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range svr.StartVotes {
+		p.fireEvent(EventTypeProposalVoteStarted,
+			EventDataProposalVoteStarted{
+				AdminUser: u,
+				StartVote: &svr.StartVotes[i],
+			},
+		)
+	}
+
+	return &www.StartVoteRunoffReply{
+		StartBlockHeight: vr.StartVoteReply.StartBlockHeight,
+		StartBlockHash:   vr.StartVoteReply.StartBlockHash,
+		EndHeight:        vr.StartVoteReply.EndHeight,
+		EligibleTickets:  vr.StartVoteReply.EligibleTickets,
+	}, nil
+}
+
+// processCancelVote handles the www.CancelVote call.  Only active votes can
+// be cancelled.
+func (p *politeiawww) processCancelVote(cv www.CancelVote, u *user.User) (*www.CancelVoteReply, error) {
+	log.Tracef("processCancelVote %v", cv.Token)
+
+	// Verify user
+	err := checkPublicKeyAndSignature(u, cv.PublicKey, cv.Signature,
+		cv.Token, cv.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate reason
+	if strings.TrimSpace(cv.Reason) == "" {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusCancelReasonCannotBeBlank,
+		}
+	}
+
+	// Ensure the proposal exists and its vote is active
+	_, err = p.getProp(cv.Token)
+	if err != nil {
+		if err == cache.ErrRecordNotFound {
+			err = www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}
+		}
+		return nil, err
+	}
+	vdr, err := p.bitumVoteDetails(cv.Token)
+	if err != nil {
+		return nil, fmt.Errorf("bitumVoteDetails: %v", err)
+	}
+	vd := convertVoteDetailsReplyFromBitum(*vdr)
+	bb, err := p.getBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	s := getVoteStatus(vd.AuthorizeVoteReply, vd.StartVoteReply,
+		vd.CancelVoteReply, bb)
+	if s != www.PropVoteStatusStarted {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusWrongVoteStatus,
+		}
+	}
+
+	// Tell bitum plugin to cancel the vote
+	payload, err := bitumplugin.EncodeCancelVote(
+		bitumplugin.CancelVote{
+			Token:     cv.Token,
+			Reason:    cv.Reason,
+			Signature: cv.Signature,
+			PublicKey: cv.PublicKey,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := util.Random(pd.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := pd.PluginCommand{
+		Challenge: hex.EncodeToString(challenge),
+		ID:        bitumplugin.ID,
+		Command:   bitumplugin.CmdCancelVote,
+		CommandID: bitumplugin.CmdCancelVote + " " + cv.Token,
+		Payload:   string(payload),
+	}
+
+	responseBody, err := p.makeRequest(http.MethodPost,
+		pd.PluginCommandRoute, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply pd.PluginCommandReply
+	err = json.Unmarshal(responseBody, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal "+
+			"PluginCommandReply: %v", err)
+	}
+
+	// Verify the challenge.
+	err = util.VerifyChallenge(p.cfg.Identity, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	cvr, err := bitumplugin.DecodeCancelVoteReply([]byte(reply.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the stale proposals from the read cache. The vote
+	// of every proposal in a runoff is cancelled.
+	for _, v := range cvr.Tokens {
+		p.readCache.invalidateRecord(v)
+	}
+
+	p.fireEvent(EventTypeProposalVoteCancelled,
+		EventDataProposalVoteCancelled{
+			AdminUser:  u,
+			CancelVote: &cv,
+			Tokens:     cvr.Tokens,
+		},
+	)
+
+	return &www.CancelVoteReply{
+		Tokens:  cvr.Tokens,
+		Receipt: cvr.Receipt,
+	}, nil
+}
+
 // processTokenInventory returns the tokens of all proposals in the inventory,
 // categorized by stage of the voting process.
 func (p *politeiawww) processTokenInventory() (*www.TokenInventoryReply, error) {
@@ -14,6 +14,7 @@ import (
 	"image/color"
 	"image/png"
 	"math/rand"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -516,177 +517,6 @@ func TestValidateProposal(t *testing.T) {
 	}
 }
 
-func TestFilterProposals(t *testing.T) {
-	// Test proposal page size. Only a single page of proposals
-	// should be returned.
-	t.Run("proposal page size", func(t *testing.T) {
-		pq := proposalsFilter{
-			StateMap: map[www.PropStateT]bool{
-				www.PropStateVetted: true,
-			},
-		}
-
-		// We use simplified userIDs, timestamps, and censorship
-		// record tokens. This is ok since filterProps() does not
-		// check the validity of the data.
-		c := www.ProposalListPageSize + 5
-		propsPageTest := make([]www.ProposalRecord, 0, c)
-		for i := 1; i <= c; i++ {
-			propsPageTest = append(propsPageTest, www.ProposalRecord{
-				State:     www.PropStateVetted,
-				UserId:    strconv.Itoa(i),
-				Timestamp: int64(i),
-				CensorshipRecord: www.CensorshipRecord{
-					Token: strconv.Itoa(i),
-				},
-			})
-		}
-
-		out := filterProps(pq, propsPageTest)
-		if len(out) != www.ProposalListPageSize {
-			t.Errorf("got %v, want %v", len(out), www.ProposalListPageSize)
-		}
-	})
-
-	// Create data for test table. We use simplified userIDs,
-	// timestamps, and censorship record tokens. This is ok since
-	// filterProps() does not check the validity of the data.
-	props := make(map[int]*www.ProposalRecord, 5)
-	for i := 1; i <= 5; i++ {
-		props[i] = &www.ProposalRecord{
-			State:     www.PropStateVetted,
-			UserId:    strconv.Itoa(i),
-			Timestamp: int64(i),
-			CensorshipRecord: www.CensorshipRecord{
-				Token: strconv.Itoa(i),
-			},
-		}
-	}
-
-	// Change the State of a few proposals so that they are not
-	// all the same.
-	props[2].State = www.PropStateUnvetted
-	props[4].State = www.PropStateUnvetted
-
-	// Setup tests
-	var tests = []struct {
-		name  string
-		req   proposalsFilter
-		input []www.ProposalRecord
-		want  []www.ProposalRecord
-	}{
-		{"filter by State",
-			proposalsFilter{
-				StateMap: map[www.PropStateT]bool{
-					www.PropStateUnvetted: true,
-				},
-			},
-			[]www.ProposalRecord{
-				*props[1], *props[2], *props[3], *props[4], *props[5],
-			},
-			[]www.ProposalRecord{
-				*props[4], *props[2],
-			},
-		},
-
-		{"filter by UserID",
-			proposalsFilter{
-				UserID: "1",
-				StateMap: map[www.PropStateT]bool{
-					www.PropStateVetted: true,
-				},
-			},
-			[]www.ProposalRecord{
-				*props[1], *props[2], *props[3], *props[4], *props[5],
-			},
-			[]www.ProposalRecord{
-				*props[1],
-			},
-		},
-
-		{"filter by Before",
-			proposalsFilter{
-				Before: props[3].CensorshipRecord.Token,
-				StateMap: map[www.PropStateT]bool{
-					www.PropStateUnvetted: true,
-					www.PropStateVetted:   true,
-				},
-			},
-			[]www.ProposalRecord{
-				*props[1], *props[2], *props[3], *props[4], *props[5],
-			},
-			[]www.ProposalRecord{
-				*props[5], *props[4],
-			},
-		},
-
-		{"filter by After",
-			proposalsFilter{
-				After: props[3].CensorshipRecord.Token,
-				StateMap: map[www.PropStateT]bool{
-					www.PropStateUnvetted: true,
-					www.PropStateVetted:   true,
-				},
-			},
-			[]www.ProposalRecord{
-				*props[1], *props[2], *props[3], *props[4], *props[5],
-			},
-			[]www.ProposalRecord{
-				*props[2], *props[1],
-			},
-		},
-
-		{"unsorted proposals",
-			proposalsFilter{
-				StateMap: map[www.PropStateT]bool{
-					www.PropStateUnvetted: true,
-					www.PropStateVetted:   true,
-				},
-			},
-			[]www.ProposalRecord{
-				*props[3], *props[4], *props[1], *props[5], *props[2],
-			},
-			[]www.ProposalRecord{
-				*props[5], *props[4], *props[3], *props[2], *props[1],
-			},
-		},
-	}
-
-	// Run tests
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			out := filterProps(test.req, test.input)
-
-			// Pull out the tokens to make it easier to view the
-			// difference between want and got
-			want := make([]string, len(test.want))
-			for _, p := range test.want {
-				want = append(want, p.CensorshipRecord.Token)
-			}
-			got := make([]string, len(out))
-			for _, p := range out {
-				got = append(got, p.CensorshipRecord.Token)
-			}
-
-			// Check if want and got are the same
-			if len(want) != len(got) {
-				goto fail
-			}
-			for i, w := range want {
-				if w != got[i] {
-					goto fail
-				}
-			}
-
-			// success; want and got are the same
-			return
-
-		fail:
-			t.Errorf("got %v, want %v", got, want)
-		})
-	}
-}
-
 func TestProcessNewProposal(t *testing.T) {
 	// Setup test environment
 	p, cleanup := newTestPoliteiawww(t)
@@ -860,6 +690,103 @@ func TestVerifyStatusChange(t *testing.T) {
 	}
 }
 
+func TestValidateVoteOptions(t *testing.T) {
+	yesNo := []www.VoteOption{
+		{Id: "no", Bits: 0x01},
+		{Id: "yes", Bits: 0x02},
+	}
+	overlapping := []www.VoteOption{
+		{Id: "a", Bits: 0x01},
+		{Id: "b", Bits: 0x03},
+	}
+	single := []www.VoteOption{
+		{Id: "a", Bits: 0x01},
+	}
+
+	var tests = []struct {
+		name    string
+		voteT   www.VoteT
+		options []www.VoteOption
+		want    error
+	}{
+		{"standard", www.VoteTypeStandard, yesNo, nil},
+		{"multiple choice", www.VoteTypeMultipleChoice, overlapping, nil},
+		{"approval", www.VoteTypeApproval, yesNo, nil},
+		{"invalid type", www.VoteT(99), yesNo,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+		{"multiple choice single option", www.VoteTypeMultipleChoice,
+			single, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+		{"approval overlapping bits", www.VoteTypeApproval,
+			overlapping, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteBits,
+			}},
+		{"runoff", www.VoteTypeRunoff, yesNo, nil},
+		{"runoff without approve option", www.VoteTypeRunoff,
+			overlapping, www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := validateVoteOptions(www.Vote{
+				Type:    v.voteT,
+				Mask:    0x03,
+				Options: v.options,
+			})
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateVoteBit(t *testing.T) {
+	options := []www.VoteOption{
+		{Id: "a", Bits: 0x01},
+		{Id: "b", Bits: 0x02},
+		{Id: "c", Bits: 0x04},
+	}
+
+	var tests = []struct {
+		name      string
+		voteT     www.VoteT
+		bit       uint64
+		wantError bool
+	}{
+		{"standard single option", www.VoteTypeStandard, 0x02, false},
+		{"standard several options", www.VoteTypeStandard, 0x03, true},
+		{"multiple choice several options", www.VoteTypeMultipleChoice,
+			0x05, true},
+		{"approval single option", www.VoteTypeApproval, 0x04, false},
+		{"approval several options", www.VoteTypeApproval, 0x05, false},
+		{"approval outside mask", www.VoteTypeApproval, 0x08, true},
+		{"approval zero bit", www.VoteTypeApproval, 0x00, true},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := validateVoteBit(www.Vote{
+				Type:    v.voteT,
+				Mask:    0x07,
+				Options: options,
+			}, v.bit)
+			if v.wantError && err == nil {
+				t.Errorf("got nil error, want error")
+			}
+			if !v.wantError && err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
+
 func TestProcessSetProposalStatus(t *testing.T) {
 	// Setup test environment
 	p, cleanup := newTestPoliteiawww(t)
@@ -1107,3 +1034,518 @@ func TestProcessSetProposalStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessCancelVote(t *testing.T) {
+	// Setup test environment
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create test data
+	admin, id := newUser(t, p, true, true)
+	reason := "vote parameters were wrong"
+
+	propPublic := newProposalRecord(t, admin, id, www.PropStatusPublic)
+	tokenPublic := propPublic.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, propPublic))
+
+	propVoteStarted := newProposalRecord(t, admin, id, www.PropStatusPublic)
+	tokenVoteStarted := propVoteStarted.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, propVoteStarted))
+	d.Plugin(t, newAuthorizeVoteCmd(t, tokenVoteStarted,
+		propVoteStarted.Version, www.AuthVoteActionAuthorize, id))
+	d.Plugin(t, newStartVoteCmd(t, tokenVoteStarted, id))
+
+	newCancelVote := func(token, reason string) www.CancelVote {
+		sig := id.SignMessage([]byte(token + reason))
+		return www.CancelVote{
+			Token:     token,
+			Reason:    reason,
+			Signature: hex.EncodeToString(sig[:]),
+			PublicKey: admin.PublicKey(),
+		}
+	}
+	invalidSig := newCancelVote(tokenVoteStarted, reason)
+	invalidSig.Reason = "other reason"
+
+	// Setup tests.  The tests are run in order since a vote can only
+	// be cancelled once.
+	var tests = []struct {
+		name string
+		cv   www.CancelVote
+		want error
+	}{
+		{"invalid signature", invalidSig,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidSignature,
+			}},
+
+		{"blank reason", newCancelVote(tokenVoteStarted, " "),
+			www.UserError{
+				ErrorCode: www.ErrorStatusCancelReasonCannotBeBlank,
+			}},
+
+		{"proposal not found", newCancelVote("abc", reason),
+			www.UserError{
+				ErrorCode: www.ErrorStatusProposalNotFound,
+			}},
+
+		{"vote not started", newCancelVote(tokenPublic, reason),
+			www.UserError{
+				ErrorCode: www.ErrorStatusWrongVoteStatus,
+			}},
+
+		{"success", newCancelVote(tokenVoteStarted, reason), nil},
+
+		{"vote already cancelled", newCancelVote(tokenVoteStarted, reason),
+			www.UserError{
+				ErrorCode: www.ErrorStatusWrongVoteStatus,
+			}},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			reply, err := p.processCancelVote(v.cv, admin)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err != nil {
+				// Test case passes
+				return
+			}
+
+			if len(reply.Tokens) != 1 ||
+				reply.Tokens[0] != v.cv.Token ||
+				reply.Receipt == "" {
+				t.Fatalf("unexpected reply %+v", reply)
+			}
+		})
+	}
+}
+
+func TestProcessAllVetted(t *testing.T) {
+	// Setup test environment
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create test data. The proposal timestamps are set explicitly
+	// so that the time range filters can be tested.
+	usr, id := newUser(t, p, true, false)
+
+	propOld := newProposalRecord(t, usr, id, www.PropStatusPublic)
+	propOld.Timestamp = 100
+	d.AddRecord(t, convertPropToPD(t, propOld))
+
+	propVoteStarted := newProposalRecord(t, usr, id, www.PropStatusPublic)
+	propVoteStarted.Timestamp = 200
+	tokenVoteStarted := propVoteStarted.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, propVoteStarted))
+	d.Plugin(t, newAuthorizeVoteCmd(t, tokenVoteStarted,
+		propVoteStarted.Version, www.AuthVoteActionAuthorize, id))
+	d.Plugin(t, newStartVoteCmd(t, tokenVoteStarted, id))
+
+	propNew := newProposalRecord(t, usr, id, www.PropStatusPublic)
+	propNew.Timestamp = 300
+	d.AddRecord(t, convertPropToPD(t, propNew))
+
+	propUnvetted := newProposalRecord(t, usr, id, www.PropStatusNotReviewed)
+	propUnvetted.Timestamp = 400
+	d.AddRecord(t, convertPropToPD(t, propUnvetted))
+
+	// Setup tests
+	var tests = []struct {
+		name      string
+		v         www.GetAllVetted
+		wantProps []www.ProposalRecord
+		wantErr   error
+	}{
+		{"all", www.GetAllVetted{},
+			[]www.ProposalRecord{propNew, propVoteStarted, propOld}, nil},
+
+		{"time range",
+			www.GetAllVetted{
+				TimestampAfter:  100,
+				TimestampBefore: 400,
+			},
+			[]www.ProposalRecord{propNew, propVoteStarted}, nil},
+
+		{"vote started",
+			www.GetAllVetted{
+				VoteStatuses: []www.PropVoteStatusT{
+					www.PropVoteStatusStarted,
+				},
+			},
+			[]www.ProposalRecord{propVoteStarted}, nil},
+
+		{"vote not authorized before",
+			www.GetAllVetted{
+				TimestampBefore: 300,
+				VoteStatuses: []www.PropVoteStatusT{
+					www.PropVoteStatusNotAuthorized,
+				},
+			},
+			[]www.ProposalRecord{propOld}, nil},
+
+		{"invalid vote status",
+			www.GetAllVetted{
+				VoteStatuses: []www.PropVoteStatusT{
+					www.PropVoteStatusDoesntExist,
+				},
+			},
+			nil,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteStatus,
+			}},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			reply, err := p.processAllVetted(v.v, nil)
+			got := errToStr(err)
+			want := errToStr(v.wantErr)
+			if got != want {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err != nil {
+				// Test case passes
+				return
+			}
+
+			gotTokens := make([]string, 0, len(reply.Proposals))
+			for _, v := range reply.Proposals {
+				gotTokens = append(gotTokens, v.CensorshipRecord.Token)
+			}
+			wantTokens := make([]string, 0, len(v.wantProps))
+			for _, v := range v.wantProps {
+				wantTokens = append(wantTokens, v.CensorshipRecord.Token)
+			}
+			if !reflect.DeepEqual(gotTokens, wantTokens) {
+				t.Fatalf("got proposals %v, want %v", gotTokens,
+					wantTokens)
+			}
+		})
+	}
+}
+
+func TestProcessActiveVote(t *testing.T) {
+	// Setup test environment
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create test data
+	usr, id := newUser(t, p, true, false)
+
+	propPublic := newProposalRecord(t, usr, id, www.PropStatusPublic)
+	d.AddRecord(t, convertPropToPD(t, propPublic))
+
+	propVoteStarted := newProposalRecord(t, usr, id, www.PropStatusPublic)
+	tokenVoteStarted := propVoteStarted.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, propVoteStarted))
+	d.Plugin(t, newAuthorizeVoteCmd(t, tokenVoteStarted,
+		propVoteStarted.Version, www.AuthVoteActionAuthorize, id))
+	d.Plugin(t, newStartVoteCmd(t, tokenVoteStarted, id))
+
+	// Only the proposal that is being voted on is returned
+	reply, err := p.processActiveVote()
+	if err != nil {
+		t.Fatalf("processActiveVote: %v", err)
+	}
+	if len(reply.Votes) != 1 || len(reply.Scheduled) != 0 {
+		t.Fatalf("got %v votes and %v scheduled votes, want 1 and 0",
+			len(reply.Votes), len(reply.Scheduled))
+	}
+	if reply.Votes[0].Proposal.CensorshipRecord.Token != tokenVoteStarted {
+		t.Fatalf("got proposal %v, want %v",
+			reply.Votes[0].Proposal.CensorshipRecord.Token, tokenVoteStarted)
+	}
+}
+
+func TestVoteTimeSeries(t *testing.T) {
+	sv := bitumplugin.StartVote{
+		Vote: bitumplugin.Vote{
+			Type:             bitumplugin.VoteTypeStandard,
+			QuorumPercentage: 50,
+			Options: []bitumplugin.VoteOption{
+				{Id: "no", Bits: 0x01},
+				{Id: "yes", Bits: 0x02},
+			},
+		},
+	}
+	svr := bitumplugin.StartVoteReply{
+		StartBlockHeight: "100",
+		EndHeight:        "110",
+		EligibleTickets:  make([]string, 20),
+	}
+	counts := []bitumplugin.VoteBitCount{
+		{BlockHeight: 0, VoteBit: "2", Votes: 1},
+		{BlockHeight: 101, VoteBit: "1", Votes: 2},
+		{BlockHeight: 103, VoteBit: "2", Votes: 3},
+		{BlockHeight: 106, VoteBit: "2", Votes: 4},
+	}
+
+	var tests = []struct {
+		name       string
+		bucketSize uint32
+		bestBlock  uint64
+		wantEnds   []uint32 // Bucket end heights
+		wantTotals []uint64 // Cumulative total votes
+		wantYes    []uint64 // Cumulative yes votes
+		wantQuorum []uint32 // Quorum progress
+	}{
+		{"bucket size 4", 4, 200,
+			[]uint32{103, 107, 110},
+			[]uint64{6, 10, 10},
+			[]uint64{4, 8, 8},
+			[]uint32{60, 100, 100}},
+		{"vote in progress", 3, 104,
+			[]uint32{102, 104},
+			[]uint64{3, 10},
+			[]uint64{1, 8},
+			[]uint32{30, 100}},
+		{"single block buckets", 1, 102,
+			[]uint32{100, 101, 102},
+			[]uint64{1, 3, 10},
+			[]uint64{1, 1, 8},
+			[]uint32{10, 30, 100}},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			buckets, err := voteTimeSeries(sv, svr, counts,
+				v.bucketSize, v.bestBlock)
+			if err != nil {
+				t.Fatalf("voteTimeSeries: %v", err)
+			}
+			if len(buckets) != len(v.wantEnds) {
+				t.Fatalf("got %v buckets, want %v", len(buckets),
+					len(v.wantEnds))
+			}
+			for k, b := range buckets {
+				if b.EndHeight != v.wantEnds[k] {
+					t.Errorf("bucket %v: got end %v, want %v", k,
+						b.EndHeight, v.wantEnds[k])
+				}
+				if b.TotalVotes != v.wantTotals[k] {
+					t.Errorf("bucket %v: got total %v, want %v", k,
+						b.TotalVotes, v.wantTotals[k])
+				}
+				if b.OptionsResult[1].VotesReceived != v.wantYes[k] {
+					t.Errorf("bucket %v: got yes %v, want %v", k,
+						b.OptionsResult[1].VotesReceived, v.wantYes[k])
+				}
+				if b.QuorumProgress != v.wantQuorum[k] {
+					t.Errorf("bucket %v: got quorum %v, want %v", k,
+						b.QuorumProgress, v.wantQuorum[k])
+				}
+			}
+		})
+	}
+}
+
+func TestVoteStatusFromVoteSummary(t *testing.T) {
+	var tests = []struct {
+		name      string
+		summary   bitumplugin.VoteSummaryReply
+		bestBlock uint64
+		want      www.PropVoteStatusT
+	}{
+		{"not authorized", bitumplugin.VoteSummaryReply{}, 100,
+			www.PropVoteStatusNotAuthorized},
+		{"authorized", bitumplugin.VoteSummaryReply{Authorized: true},
+			100, www.PropVoteStatusAuthorized},
+		{"scheduled", bitumplugin.VoteSummaryReply{Authorized: true,
+			Scheduled: true, StartHeight: 101}, 100,
+			www.PropVoteStatusScheduled},
+		{"scheduled start height reached", bitumplugin.VoteSummaryReply{
+			Authorized: true, Scheduled: true, StartHeight: 101}, 101,
+			www.PropVoteStatusStarted},
+		{"started", bitumplugin.VoteSummaryReply{Authorized: true,
+			EndHeight: "200"}, 100, www.PropVoteStatusStarted},
+		{"finished", bitumplugin.VoteSummaryReply{Authorized: true,
+			EndHeight: "200"}, 200, www.PropVoteStatusFinished},
+		{"cancelled", bitumplugin.VoteSummaryReply{Authorized: true,
+			Scheduled: true, StartHeight: 101, Cancelled: true}, 100,
+			www.PropVoteStatusCancelled},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := voteStatusFromVoteSummary(v.summary, v.bestBlock)
+			if got != v.want {
+				t.Fatalf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestStartedScheduledVotes(t *testing.T) {
+	scheduled := map[string]struct{}{
+		"waiting":   {},
+		"started":   {},
+		"cancelled": {},
+	}
+	inv := bitumplugin.TokenInventoryReply{
+		Active:    []string{"other", "started"},
+		Cancelled: []string{"cancelled"},
+		Scheduled: []string{"new", "waiting"},
+	}
+
+	unscheduled, started := startedScheduledVotes(scheduled, inv)
+	if !reflect.DeepEqual(unscheduled, []string{"cancelled", "started"}) {
+		t.Fatalf("unexpected unscheduled votes %v", unscheduled)
+	}
+	if !reflect.DeepEqual(started, []string{"started"}) {
+		t.Fatalf("unexpected started votes %v", started)
+	}
+
+	// Nothing changed
+	unscheduled, started = startedScheduledVotes(map[string]struct{}{
+		"new":     {},
+		"waiting": {},
+	}, inv)
+	if len(unscheduled) != 0 || len(started) != 0 {
+		t.Fatalf("unexpected changes %v %v", unscheduled, started)
+	}
+}
+
+func TestApplyVotePolicy(t *testing.T) {
+	p := &politeiawww{
+		cfg: &config{
+			votePolicies: map[string]www.VotePolicy{
+				"standard": {
+					Name:             "standard",
+					Duration:         2016,
+					QuorumPercentage: 20,
+					PassPercentage:   60,
+					Options:          []string{"yes", "no"},
+				},
+			},
+		},
+	}
+	yesNo := []www.VoteOption{
+		{Id: "no", Bits: 0x01},
+		{Id: "yes", Bits: 0x02},
+	}
+
+	var tests = []struct {
+		name     string
+		policy   string
+		duration uint32
+		options  []www.VoteOption
+		want     error
+	}{
+		{"no policy", "", 0, yesNo, nil},
+		{"policy", "standard", 0, yesNo, nil},
+		{"matching duration", "standard", 2016, yesNo, nil},
+		{"unknown policy", "emergency", 0, yesNo,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidVotePolicy,
+			}},
+		{"conflicting duration", "standard", 4032, yesNo,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+		{"option not allowed", "standard", 0,
+			[]www.VoteOption{
+				{Id: "no", Bits: 0x01},
+				{Id: "abstain", Bits: 0x02},
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+		{"duplicate option", "standard", 0,
+			[]www.VoteOption{
+				{Id: "yes", Bits: 0x01},
+				{Id: "yes", Bits: 0x02},
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPropVoteParams,
+			}},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			sv := www.StartVote{
+				Policy: v.policy,
+				Vote: www.Vote{
+					Duration: v.duration,
+					Options:  v.options,
+				},
+			}
+			err := p.applyVotePolicy(&sv)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if err == nil && v.policy != "" &&
+				(sv.Vote.Duration != 2016 ||
+					sv.Vote.QuorumPercentage != 20 ||
+					sv.Vote.PassPercentage != 60) {
+				t.Errorf("vote parameters not set: %v", sv.Vote)
+			}
+		})
+	}
+}
+
+func TestCommentsQuery(t *testing.T) {
+	invalidInput := www.UserError{
+		ErrorCode: www.ErrorStatusInvalidInput,
+	}
+
+	var tests = []struct {
+		name       string
+		sort       string
+		cursor     string
+		wantSort   string
+		wantOffset uint32
+		want       error
+	}{
+		{"first page", "", "", www.CommentSortTop, 0, nil},
+		{"top", www.CommentSortTop, "", www.CommentSortTop, 0, nil},
+		{"new", www.CommentSortNew, "20", www.CommentSortNew, 20, nil},
+		{"old", www.CommentSortOld, "40", www.CommentSortOld, 40, nil},
+		{"invalid sort", "best", "", "", 0, invalidInput},
+		{"invalid cursor", "", "abc", "", 0, invalidInput},
+		{"negative cursor", "", "-20", "", 0, invalidInput},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			q, err := commentsQuery(www.GetComments{
+				Token:    "token",
+				Sort:     v.sort,
+				Cursor:   v.cursor,
+				MaxDepth: 2,
+			})
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if err != nil {
+				return
+			}
+			if q.Token != "token" || q.Sort != v.wantSort ||
+				q.Offset != v.wantOffset ||
+				q.Limit != www.CommentListPageSize ||
+				q.MaxDepth != 2 {
+				t.Errorf("unexpected query: %+v", q)
+			}
+		})
+	}
+}
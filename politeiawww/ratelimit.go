@@ -0,0 +1,236 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+	"github.com/bitum-project/politeia/politeiawww/user"
+)
+
+const (
+	// defaultCommentRate and defaultCommentBurst are the default number
+	// of comments per minute and the default number of comments that
+	// can be submitted in a burst by a single user.
+	defaultCommentRate  = 2
+	defaultCommentBurst = 5
+
+	// defaultCommentIPRate and defaultCommentIPBurst are the default
+	// number of comments per minute and the default number of comments
+	// that can be submitted in a burst from a single IP address.  They
+	// are higher than the user limits since several users may share an
+	// IP address.
+	defaultCommentIPRate  = 10
+	defaultCommentIPBurst = 20
+
+	// defaultLikeRate and defaultLikeBurst are the default number of
+	// comment likes per minute and the default number of comment likes
+	// that can be submitted in a burst by a single user.
+	defaultLikeRate  = 30
+	defaultLikeBurst = 30
+
+	// defaultLikeIPRate and defaultLikeIPBurst are the default number of
+	// comment likes per minute and the default number of comment likes
+	// that can be submitted in a burst from a single IP address.
+	defaultLikeIPRate  = 120
+	defaultLikeIPBurst = 120
+
+	// rateLimiterPruneInterval is the minimum amount of time between
+	// the removal of idle token buckets from a rate limiter.
+	rateLimiterPruneInterval = time.Minute
+)
+
+// rateLimit is the rate and burst of a class of keys.  A rate of zero does
+// not limit anything.
+type rateLimit struct {
+	rate  float64 // Tokens added per second
+	burst float64 // Maximum number of tokens in a bucket
+}
+
+// newRateLimit returns a rateLimit that allows perMinute requests per minute
+// with bursts of up to burst requests.
+func newRateLimit(perMinute float64, burst int) rateLimit {
+	if burst < 1 {
+		burst = 1
+	}
+	return rateLimit{
+		rate:  perMinute / 60,
+		burst: float64(burst),
+	}
+}
+
+// enabled returns whether the rate limit limits anything.
+func (l rateLimit) enabled() bool {
+	return l.rate > 0
+}
+
+// tokenBucket is the rate limiting state of a single key.
+type tokenBucket struct {
+	limit  rateLimit // Rate limit of the key
+	tokens float64   // Available tokens
+	last   time.Time // Last time the tokens were refilled
+}
+
+// rateLimiter is a concurrency safe token bucket rate limiter that limits
+// users and IP addresses separately.  Each user and each IP address has its
+// own bucket that holds up to the burst of its limit and that is refilled at
+// the rate of its limit.
+type rateLimiter struct {
+	sync.Mutex
+	user    rateLimit               // Limit of each user
+	ip      rateLimit               // Limit of each IP address
+	buckets map[string]*tokenBucket // [key]bucket
+	pruned  time.Time               // Last time idle buckets were removed
+}
+
+// newRateLimiter returns a rateLimiter that applies the passed in limits to
+// each user and to each IP address.
+func newRateLimiter(user, ip rateLimit) *rateLimiter {
+	return &rateLimiter{
+		user:    user,
+		ip:      ip,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// bucket returns the refilled bucket of the passed in key.  A full bucket is
+// created when the key does not have a bucket yet.
+//
+// This function must be called WITH the lock held.
+func (rl *rateLimiter) bucket(key string, limit rateLimit, now time.Time) *tokenBucket {
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			limit:  limit,
+			tokens: limit.burst,
+			last:   now,
+		}
+		rl.buckets[key] = b
+		return b
+	}
+
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * limit.rate
+		if b.tokens > limit.burst {
+			b.tokens = limit.burst
+		}
+		b.last = now
+	}
+
+	return b
+}
+
+// prune removes the buckets that have been refilled completely since they
+// behave the same as a new bucket.
+//
+// This function must be called WITH the lock held.
+func (rl *rateLimiter) prune(now time.Time) {
+	for k, v := range rl.buckets {
+		refill := now.Sub(v.last).Seconds() * v.limit.rate
+		if v.tokens+refill >= v.limit.burst {
+			delete(rl.buckets, k)
+		}
+	}
+	rl.pruned = now
+}
+
+// take removes a token from the buckets of the passed in user ID and IP
+// address.  Tokens are only removed when every bucket has a token available.
+// When that is not the case false is returned along with the amount of time
+// until it will be.
+func (rl *rateLimiter) take(now time.Time, userID, ip string) (bool, time.Duration) {
+	type key struct {
+		key   string
+		limit rateLimit
+	}
+	keys := make([]key, 0, 2)
+	if rl.user.enabled() {
+		keys = append(keys, key{"user:" + userID, rl.user})
+	}
+	if rl.ip.enabled() {
+		keys = append(keys, key{"ip:" + ip, rl.ip})
+	}
+	if len(keys) == 0 {
+		return true, 0
+	}
+
+	rl.Lock()
+	defer rl.Unlock()
+
+	if now.Sub(rl.pruned) >= rateLimiterPruneInterval {
+		rl.prune(now)
+	}
+
+	var wait time.Duration
+	buckets := make([]*tokenBucket, 0, len(keys))
+	for _, k := range keys {
+		b := rl.bucket(k.key, k.limit, now)
+		if b.tokens < 1 {
+			w := time.Duration((1 - b.tokens) / k.limit.rate *
+				float64(time.Second))
+			if w > wait {
+				wait = w
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	if wait > 0 {
+		return false, wait
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+
+	return true, 0
+}
+
+// requestIP returns the IP address of the client that made the request.  The
+// X-Forwarded-For header can be set by anyone, so the address appended to it
+// by the proxy is only used when politeiawww is configured to run behind a
+// trusted proxy.
+func requestIP(r *http.Request, trustProxy bool) string {
+	if xff := r.Header.Get(www.Forward); trustProxy && xff != "" {
+		addrs := strings.Split(xff, ",")
+		return strings.TrimSpace(addrs[len(addrs)-1])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// checkRateLimit takes a token from the buckets of both the passed in user
+// and the IP address of the request.  When either bucket is empty the
+// Retry-After header is set and a rate limited error, which contains the
+// number of seconds to wait before retrying, is returned.  Admins are not
+// rate limited.
+func (p *politeiawww) checkRateLimit(w http.ResponseWriter, r *http.Request, rl *rateLimiter, u *user.User) error {
+	if u.Admin {
+		return nil
+	}
+
+	ok, wait := rl.take(time.Now(), u.ID.String(),
+		requestIP(r, p.cfg.TrustProxy))
+	if ok {
+		return nil
+	}
+
+	retryAfter := strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10)
+	w.Header().Set("Retry-After", retryAfter)
+
+	return www.UserError{
+		ErrorCode:    www.ErrorStatusRateLimited,
+		ErrorContext: []string{retryAfter},
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2017-2019 The Bitum developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	// One token per second with bursts of two tokens for each user,
+	// IP addresses are not limited
+	rl := newRateLimiter(newRateLimit(60, 2), newRateLimit(0, 1))
+
+	// Burst
+	for i := 0; i < 2; i++ {
+		ok, _ := rl.take(now, "user", "ip")
+		if !ok {
+			t.Fatalf("burst take %v: got rate limited", i)
+		}
+	}
+
+	// Bucket is empty
+	ok, wait := rl.take(now, "user", "ip")
+	if ok {
+		t.Fatalf("empty bucket: got ok, want rate limited")
+	}
+	if wait != time.Second {
+		t.Errorf("empty bucket: got wait %v, want %v", wait, time.Second)
+	}
+
+	// Other users have their own bucket
+	ok, _ = rl.take(now, "other", "ip")
+	if !ok {
+		t.Errorf("other user: got rate limited")
+	}
+
+	// Partial refill
+	ok, wait = rl.take(now.Add(500*time.Millisecond), "user", "ip")
+	if ok {
+		t.Fatalf("partial refill: got ok, want rate limited")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("partial refill: got wait %v, want %v", wait,
+			500*time.Millisecond)
+	}
+
+	// Refilled
+	now = now.Add(time.Second)
+	ok, _ = rl.take(now, "user", "ip")
+	if !ok {
+		t.Fatalf("refilled: got rate limited")
+	}
+
+	// Buckets never hold more than the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		rl.take(now, "user", "ip")
+	}
+	ok, _ = rl.take(now, "user", "ip")
+	if ok {
+		t.Errorf("burst after idle: got ok, want rate limited")
+	}
+
+	// Full buckets are pruned
+	now = now.Add(time.Hour)
+	rl.take(now, "user", "ip")
+	if len(rl.buckets) != 1 {
+		t.Errorf("prune: got %v buckets, want 1", len(rl.buckets))
+	}
+
+	// A zero rate does not limit anything
+	rl = newRateLimiter(newRateLimit(0, 1), newRateLimit(0, 1))
+	for i := 0; i < 10; i++ {
+		ok, _ := rl.take(now, "user", "ip")
+		if !ok {
+			t.Fatalf("disabled take %v: got rate limited", i)
+		}
+	}
+}
+
+func TestRateLimiterUserAndIP(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	// Users may submit one request per minute, an IP address three
+	rl := newRateLimiter(newRateLimit(1, 1), newRateLimit(3, 3))
+
+	// Several users share an IP address up to the IP limit
+	for _, u := range []string{"a", "b", "c"} {
+		ok, _ := rl.take(now, u, "ip")
+		if !ok {
+			t.Fatalf("user %v: got rate limited", u)
+		}
+	}
+	ok, wait := rl.take(now, "d", "ip")
+	if ok {
+		t.Fatalf("ip limit: got ok, want rate limited")
+	}
+	if wait != 20*time.Second {
+		t.Errorf("ip limit: got wait %v, want %v", wait,
+			20*time.Second)
+	}
+
+	// A user is limited on any IP address
+	ok, wait = rl.take(now, "a", "other")
+	if ok {
+		t.Fatalf("user limit: got ok, want rate limited")
+	}
+	if wait != time.Minute {
+		t.Errorf("user limit: got wait %v, want %v", wait, time.Minute)
+	}
+
+	// No tokens are taken when any of the buckets is empty
+	ok, _ = rl.take(now, "e", "other")
+	if !ok {
+		t.Fatalf("user e: got rate limited")
+	}
+	if n := rl.buckets["ip:other"].tokens; n != 2 {
+		t.Errorf("ip tokens: got %v, want 2", n)
+	}
+}
+
+func TestRequestIP(t *testing.T) {
+	var tests = []struct {
+		name       string
+		remoteAddr string
+		forward    string
+		trustProxy bool
+		want       string
+	}{
+		{"remote addr", "192.0.2.1:1234", "", false, "192.0.2.1"},
+		{"remote addr ipv6", "[2001:db8::1]:1234", "", false,
+			"2001:db8::1"},
+		{"remote addr no port", "192.0.2.1", "", false, "192.0.2.1"},
+		{"untrusted forward", "192.0.2.1:1234", "192.0.2.2", false,
+			"192.0.2.1"},
+		{"forwarded", "127.0.0.1:1234", "192.0.2.2", true, "192.0.2.2"},
+		{"forwarded chain", "127.0.0.1:1234", "198.51.100.1, 192.0.2.2",
+			true, "192.0.2.2"},
+		{"trusted no forward", "192.0.2.1:1234", "", true, "192.0.2.1"},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			r.RemoteAddr = v.remoteAddr
+			if v.forward != "" {
+				r.Header.Set(www.Forward, v.forward)
+			}
+
+			got := requestIP(r, v.trustProxy)
+			if got != v.want {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
@@ -87,6 +87,12 @@ type commentReplyOnCommentTemplateData struct {
 	CommentLink  string
 }
 
+type commentMentionTemplateData struct {
+	Commenter    string
+	ProposalName string
+	CommentLink  string
+}
+
 const templateNewUserEmailRaw = `
 Thanks for joining Politeia, {{.Username}}!
 
@@ -193,6 +199,14 @@ Your proposal on Politeia has been censored:
 Reason: {{.StatusChangeReason}}
 `
 
+const templateProposalVoteCancelledForAuthorRaw = `
+Voting on your proposal on Politeia has been cancelled:
+
+{{.Name}}
+{{.Link}}
+Reason: {{.StatusChangeReason}}
+`
+
 const templateProposalVoteStartedForAuthorRaw = `
 Voting has just started for your proposal on Politeia!
 
@@ -214,6 +228,13 @@ Proposal: {{.ProposalName}}
 Comment: {{.CommentLink}}
 `
 
+const templateCommentMentionRaw = `
+{{.Commenter}} has mentioned you in a comment!
+
+Proposal: {{.ProposalName}}
+Comment: {{.CommentLink}}
+`
+
 const templateInviteNewUserEmailRaw = `
 You are invited to join Bitum as a contractor! To complete your registration, you will need to use the following link and register on the CMS site:
 
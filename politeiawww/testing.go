@@ -245,9 +245,18 @@ func newTestPoliteiawww(t *testing.T) (*politeiawww, func()) {
 
 	// Create politeiawww context
 	p := politeiawww{
-		cfg:             cfg,
-		db:              db,
-		cache:           testcache.New(),
+		cfg:       cfg,
+		db:        db,
+		cache:     testcache.New(),
+		readCache: newReadCache(cfg.ReadCacheSize),
+
+		commentLimiter: newRateLimiter(
+			newRateLimit(cfg.CommentRate, cfg.CommentBurst),
+			newRateLimit(cfg.CommentIPRate, cfg.CommentIPBurst)),
+		likeLimiter: newRateLimiter(
+			newRateLimit(cfg.LikeRate, cfg.LikeBurst),
+			newRateLimit(cfg.LikeIPRate, cfg.LikeIPBurst)),
+
 		params:          &chaincfg.TestNetParams,
 		router:          mux.NewRouter(),
 		store:           store,
@@ -256,7 +265,6 @@ func newTestPoliteiawww(t *testing.T) (*politeiawww, func()) {
 		userPubkeys:     make(map[string]string),
 		userEmails:      make(map[string]uuid.UUID),
 		userPaywallPool: make(map[uuid.UUID]paywallPoolMember),
-		commentScores:   make(map[string]int64),
 	}
 
 	// Setup routes
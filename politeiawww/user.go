@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/golangcrypto/bcrypt"
+	"github.com/bitum-project/politeia/bitumplugin"
 	"github.com/bitum-project/politeia/politeiad/api/v1/identity"
 	cms "github.com/bitum-project/politeia/politeiawww/api/cms/v1"
 	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
@@ -572,6 +573,61 @@ func (p *politeiawww) processUserCommentsLikes(user *user.User, token string) (*
 	}, nil
 }
 
+// userPublicKeys returns the hex encoded public keys of all of the user's
+// identities, including the deactivated ones.
+func userPublicKeys(u *user.User) []string {
+	pubkeys := make([]string, 0, len(u.Identities))
+	for _, v := range u.Identities {
+		pubkeys = append(pubkeys, hex.EncodeToString(v.Key[:]))
+	}
+	return pubkeys
+}
+
+// processUserComments returns a page of the comments that the passed in user
+// has made across all proposals, newest first.  Comments made with any of
+// the user's identities are included.  Censored comments are only returned
+// when isAdmin is set and their text is blank since it is removed from the
+// cache when a comment is censored.
+func (p *politeiawww) processUserComments(uc www.UserComments, isAdmin bool) (*www.UserCommentsReply, error) {
+	log.Tracef("processUserComments: %v", uc.UserID)
+
+	u, err := p.getUserByIDStr(uc.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset uint64
+	if uc.Cursor != "" {
+		offset, err = strconv.ParseUint(uc.Cursor, 10, 32)
+		if err != nil {
+			return nil, www.UserError{
+				ErrorCode:    www.ErrorStatusInvalidInput,
+				ErrorContext: []string{"invalid cursor"},
+			}
+		}
+	}
+
+	ucr, err := p.bitumUserComments(bitumplugin.UserComments{
+		PublicKeys:      userPublicKeys(u),
+		IncludeCensored: isAdmin,
+		Offset:          uint32(offset),
+		Limit:           www.UserCommentsPageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bitumUserComments: %v", err)
+	}
+
+	var nextCursor string
+	if ucr.NextOffset != 0 {
+		nextCursor = strconv.FormatUint(uint64(ucr.NextOffset), 10)
+	}
+
+	return &www.UserCommentsReply{
+		Comments:   p.fillComments(ucr.Comments),
+		NextCursor: nextCursor,
+	}, nil
+}
+
 // login attempts to login a a user.
 func (p *politeiawww) login(l *www.Login) loginReplyWithError {
 	// Get user from db.
@@ -1504,19 +1560,21 @@ func processUserProposalCredits(u *user.User) (*www.UserProposalCreditsReply, er
 	}, nil
 }
 
-// processUserProposals returns a page of proposals for the given user.
-func (p *politeiawww) processUserProposals(up *www.UserProposals, isCurrentUser, isAdminUser bool) (*www.UserProposalsReply, error) {
+// processUserProposals returns a page of proposals for the given user. The
+// unread comment counts are filled in when a session user is provided.
+func (p *politeiawww) processUserProposals(up *www.UserProposals, isCurrentUser, isAdminUser bool, sessionUser *user.User) (*www.UserProposalsReply, error) {
 	// Verify user exists
-	_, err := p.getUserByIDStr(up.UserId)
+	u, err := p.getUserByIDStr(up.UserId)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get a page of user proposals
-	props, ps, err := p.getUserProps(proposalsFilter{
-		After:  up.After,
-		Before: up.Before,
-		UserID: up.UserId,
+	props, ps, err := p.getUserProps(u, proposalsFilter{
+		After:           up.After,
+		Before:          up.Before,
+		TimestampAfter:  up.TimestampAfter,
+		TimestampBefore: up.TimestampBefore,
 		StateMap: map[www.PropStateT]bool{
 			www.PropStateUnvetted: isCurrentUser || isAdminUser,
 			www.PropStateVetted:   true,
@@ -1535,6 +1593,11 @@ func (p *politeiawww) processUserProposals(up *www.UserProposals, isCurrentUser,
 		numProposals += ps.NotReviewed + ps.UnreviewedChanges + ps.Censored
 	}
 
+	err = p.setUnreadComments(props, sessionUser)
+	if err != nil {
+		return nil, err
+	}
+
 	return &www.UserProposalsReply{
 		Proposals:      props,
 		NumOfProposals: numProposals,
@@ -1636,6 +1699,13 @@ func (p *politeiawww) processManageUser(mu *www.ManageUser, adminUser *user.User
 		user.Deactivated = true
 	case www.UserManageReactivate:
 		user.Deactivated = false
+	case www.UserManageCensorComment, www.UserManageDismissCommentFlags,
+		www.UserManageUpholdCensorship, www.UserManageRestoreComment:
+		// Comment moderation actions are taken using the
+		// comment routes
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidUserManageAction,
+		}
 	default:
 		return nil, fmt.Errorf("unsupported user edit action: %v",
 			www.UserManageAction[mu.Action])
@@ -6,6 +6,7 @@ package main
 
 import (
 	"encoding/hex"
+	"strconv"
 	"testing"
 	"time"
 
@@ -1173,3 +1174,189 @@ func TestProcessEditUser(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessUserProposals(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create a user with a mix of vetted and unvetted
+	// proposals and a second user with a single proposal.
+	author, id := newUser(t, p, true, false)
+	other, otherID := newUser(t, p, true, false)
+
+	propPublic := newProposalRecord(t, author, id, www.PropStatusPublic)
+	propAbandoned := newProposalRecord(t, author, id, www.PropStatusAbandoned)
+	propNotReviewed := newProposalRecord(t, author, id, www.PropStatusNotReviewed)
+	propOther := newProposalRecord(t, other, otherID, www.PropStatusPublic)
+
+	for _, v := range []www.ProposalRecord{propPublic, propAbandoned,
+		propNotReviewed, propOther} {
+		d.AddRecord(t, convertPropToPD(t, v))
+	}
+
+	// Setup test cases
+	tests := []struct {
+		name          string
+		isCurrentUser bool
+		isAdmin       bool
+		wantNum       int
+	}{
+		{"public user", false, false, 2},
+		{"current user", true, false, 3},
+		{"admin user", false, true, 3},
+	}
+
+	// Run test cases
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upr, err := p.processUserProposals(&www.UserProposals{
+				UserId: author.ID.String(),
+			}, test.isCurrentUser, test.isAdmin, nil)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+
+			if upr.NumOfProposals != test.wantNum {
+				t.Errorf("num of proposals got %v, want %v",
+					upr.NumOfProposals, test.wantNum)
+			}
+			if len(upr.Proposals) != test.wantNum {
+				t.Errorf("proposals got %v, want %v",
+					len(upr.Proposals), test.wantNum)
+			}
+			for _, v := range upr.Proposals {
+				if v.UserId != author.ID.String() {
+					t.Errorf("got proposal %v from user %v",
+						v.CensorshipRecord.Token, v.UserId)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessUserComments(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create a user with a full page of comments plus one and a
+	// second user with a single comment.  Comment 2 is censored.
+	admin, adminID := newUser(t, p, true, true)
+	author, authorID := newUser(t, p, true, false)
+	_, otherID := newUser(t, p, true, false)
+
+	prop := newProposalRecord(t, admin, adminID, www.PropStatusPublic)
+	token := prop.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, prop))
+	for i := 0; i < www.UserCommentsPageSize+1; i++ {
+		d.Plugin(t, newCommentCmd(t, token, "comment", authorID))
+	}
+	d.Plugin(t, newCommentCmd(t, token, "other", otherID))
+	d.Plugin(t, newCensorCommentCmd(t, token, "2", adminID))
+
+	// Setup test cases
+	tests := []struct {
+		name           string
+		uc             www.UserComments
+		isAdmin        bool
+		wantNum        int
+		wantCensored   bool
+		wantNextCursor string
+		wantErr        error
+	}{
+		{"invalid uuid", www.UserComments{UserID: "invalid"},
+			false, 0, false, "",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidUUID,
+			}},
+
+		{"user not found",
+			www.UserComments{
+				UserID: "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+			}, false, 0, false, "",
+			www.UserError{
+				ErrorCode: www.ErrorStatusUserNotFound,
+			}},
+
+		{"invalid cursor",
+			www.UserComments{
+				UserID: author.ID.String(),
+				Cursor: "first",
+			}, false, 0, false, "",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			}},
+
+		// The censored comment is left out for public users so
+		// the remaining comments fit in a single page.
+		{"public user",
+			www.UserComments{
+				UserID: author.ID.String(),
+			}, false, www.UserCommentsPageSize, false, "", nil},
+
+		{"admin first page",
+			www.UserComments{
+				UserID: author.ID.String(),
+			}, true, www.UserCommentsPageSize, true,
+			strconv.Itoa(www.UserCommentsPageSize), nil},
+
+		{"admin last page",
+			www.UserComments{
+				UserID: author.ID.String(),
+				Cursor: strconv.Itoa(www.UserCommentsPageSize),
+			}, true, 1, false, "", nil},
+	}
+
+	// Run test cases
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ucr, err := p.processUserComments(test.uc, test.isAdmin)
+			got := errToStr(err)
+			want := errToStr(test.wantErr)
+			if got != want {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+			if err != nil {
+				// Test case passes
+				return
+			}
+
+			if len(ucr.Comments) != test.wantNum {
+				t.Errorf("got %v comments, want %v",
+					len(ucr.Comments), test.wantNum)
+			}
+			if ucr.NextCursor != test.wantNextCursor {
+				t.Errorf("got next cursor %q, want %q",
+					ucr.NextCursor, test.wantNextCursor)
+			}
+
+			var censored bool
+			for _, v := range ucr.Comments {
+				if v.UserID != author.ID.String() {
+					t.Errorf("got comment %v from user %v",
+						v.CommentID, v.UserID)
+				}
+				if !v.Censored {
+					continue
+				}
+				censored = true
+
+				// Admins are only shown that the comment was
+				// censored.
+				if v.CommentID != "2" || v.Comment != "" {
+					t.Errorf("unexpected censored comment %v %q",
+						v.CommentID, v.Comment)
+				}
+			}
+			if censored != test.wantCensored {
+				t.Errorf("got censored comment %v, want %v",
+					censored, test.wantCensored)
+			}
+		})
+	}
+}
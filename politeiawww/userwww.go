@@ -693,6 +693,60 @@ func (p *politeiawww) handleUserCommentsLikes(w http.ResponseWriter, r *http.Req
 	util.RespondWithJSON(w, http.StatusOK, uclr)
 }
 
+// handleUserComments returns a page of the comments that a user has made
+// across all proposals.
+func (p *politeiawww) handleUserComments(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleUserComments")
+
+	var uc www.UserComments
+	err := util.ParseGetParams(r, &uc)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleUserComments: ParseGetParams",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+	uc.UserID = mux.Vars(r)["userid"]
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		// This is a public route so a logged in user is not required
+		log.Debugf("handleUserComments: could not get session user: %v", err)
+	}
+
+	ucr, err := p.processUserComments(uc, user != nil && user.Admin)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserComments: processUserComments %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, ucr)
+}
+
+// handleUserUnread returns the proposals that have comments which the logged
+// in user has not read.
+func (p *politeiawww) handleUserUnread(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleUserUnread")
+
+	user, err := p.getSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserUnread: getSessionUser %v", err)
+		return
+	}
+
+	uur, err := p.processUserUnread(user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserUnread: processUserUnread %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, uur)
+}
+
 // handleUserProposalCredits returns the spent and unspent proposal credits for
 // the logged in user.
 func (p *politeiawww) handleUserProposalCredits(w http.ResponseWriter, r *http.Request) {
@@ -759,6 +813,8 @@ func (p *politeiawww) setUserWWWRoutes() {
 		p.handleUserDetails, permissionPublic)
 	p.addRoute(http.MethodGet, www.RouteUsers,
 		p.handleUsers, permissionPublic)
+	p.addRoute(http.MethodGet, www.RouteUserComments,
+		p.handleUserComments, permissionPublic)
 
 	// Routes that require being logged in.
 	p.addRoute(http.MethodPost, www.RouteSecret, p.handleSecret,
@@ -778,6 +834,8 @@ func (p *politeiawww) setUserWWWRoutes() {
 		p.handleEditUser, permissionLogin)
 	p.addRoute(http.MethodGet, www.RouteUserCommentsLikes, // XXX comments need to become a setting
 		p.handleUserCommentsLikes, permissionLogin)
+	p.addRoute(http.MethodGet, www.RouteUserUnread, // XXX comments need to become a setting
+		p.handleUserUnread, permissionLogin)
 	p.addRoute(http.MethodGet, www.RouteUserProposalCredits,
 		p.handleUserProposalCredits, permissionLogin)
 
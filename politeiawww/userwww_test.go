@@ -771,6 +771,121 @@ func TestHandleUserDetails(t *testing.T) {
 	}
 }
 
+func TestHandleUserComments(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	d := newTestPoliteiad(t, p)
+	defer d.Close()
+
+	// Create a user with two comments, one of which is censored
+	admin, adminID := newUser(t, p, true, true)
+	author, authorID := newUser(t, p, true, false)
+
+	prop := newProposalRecord(t, admin, adminID, www.PropStatusPublic)
+	token := prop.CensorshipRecord.Token
+	d.AddRecord(t, convertPropToPD(t, prop))
+	d.Plugin(t, newCommentCmd(t, token, "first", authorID))
+	d.Plugin(t, newCommentCmd(t, token, "second", authorID))
+	d.Plugin(t, newCensorCommentCmd(t, token, "2", adminID))
+
+	// Setup tests
+	var tests = []struct {
+		name       string // Test name
+		uuid       string // UUID for route param
+		query      string // URL query string
+		sessionID  string // User ID of the session, if any
+		wantStatus int    // Wanted response status code
+		wantError  error  // Wanted response error
+		wantNum    int    // Wanted number of comments
+	}{
+		{"invalid uuid format", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			"", "", http.StatusBadRequest,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidUUID,
+			}, 0},
+
+		{"user not found", "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+			"", "", http.StatusBadRequest,
+			www.UserError{
+				ErrorCode: www.ErrorStatusUserNotFound,
+			}, 0},
+
+		{"invalid cursor", author.ID.String(), "?cursor=first",
+			"", http.StatusBadRequest,
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			}, 0},
+
+		{"public user success", author.ID.String(), "", "",
+			http.StatusOK, nil, 1},
+
+		{"logged in author success", author.ID.String(), "",
+			author.ID.String(), http.StatusOK, nil, 1},
+
+		{"admin success", author.ID.String(), "", admin.ID.String(),
+			http.StatusOK, nil, 2},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			// Setup request
+			r := httptest.NewRequest(http.MethodGet,
+				www.RouteUserComments+v.query, nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"userid": v.uuid,
+			})
+			w := httptest.NewRecorder()
+
+			// Set user session
+			if v.sessionID != "" {
+				err := p.setSessionUserID(w, r, v.sessionID)
+				if err != nil {
+					t.Fatalf("%v", err)
+				}
+			}
+
+			// Run test case
+			p.handleUserComments(w, r)
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			// Validate response
+			if res.StatusCode != v.wantStatus {
+				t.Fatalf("got status code %v, want %v",
+					res.StatusCode, v.wantStatus)
+			}
+
+			if res.StatusCode == http.StatusOK {
+				var ucr www.UserCommentsReply
+				err := json.Unmarshal(body, &ucr)
+				if err != nil {
+					t.Fatalf("unmarshal UserCommentsReply: %v", err)
+				}
+				if len(ucr.Comments) != v.wantNum {
+					t.Errorf("got %v comments, want %v",
+						len(ucr.Comments), v.wantNum)
+				}
+				return
+			}
+
+			var ue www.UserError
+			err := json.Unmarshal(body, &ue)
+			if err != nil {
+				t.Errorf("unmarshal UserError: %v", err)
+			}
+
+			got := errToStr(ue)
+			want := errToStr(v.wantError)
+			if got != want {
+				t.Errorf("got error %v, want %v",
+					got, want)
+			}
+		})
+	}
+}
+
 func TestHandleEditUser(t *testing.T) {
 	p, cleanup := newTestPoliteiawww(t)
 	defer cleanup()
@@ -28,6 +28,7 @@ import (
 	cachedb "github.com/bitum-project/politeia/politeiad/cache/cockroachdb"
 	www "github.com/bitum-project/politeia/politeiawww/api/www/v1"
 	cmsdb "github.com/bitum-project/politeia/politeiawww/cmsdatabase/cockroachdb"
+	cmssqlite "github.com/bitum-project/politeia/politeiawww/cmsdatabase/sqlite"
 	userdb "github.com/bitum-project/politeia/politeiawww/user/cockroachdb"
 	"github.com/bitum-project/politeia/politeiawww/user/localdb"
 	"github.com/bitum-project/politeia/util"
@@ -306,12 +307,20 @@ func _main() error {
 		cfg:       loadedCfg,
 		ws:        make(map[string]map[string]*wsContext),
 		templates: make(map[string]*template.Template),
+		readCache: newReadCache(loadedCfg.ReadCacheSize),
+
+		commentLimiter: newRateLimiter(
+			newRateLimit(loadedCfg.CommentRate, loadedCfg.CommentBurst),
+			newRateLimit(loadedCfg.CommentIPRate,
+				loadedCfg.CommentIPBurst)),
+		likeLimiter: newRateLimiter(
+			newRateLimit(loadedCfg.LikeRate, loadedCfg.LikeBurst),
+			newRateLimit(loadedCfg.LikeIPRate, loadedCfg.LikeIPBurst)),
 
 		// XXX reevaluate where this goes
 		userPubkeys:     make(map[string]string),
 		userEmails:      make(map[string]uuid.UUID),
 		userPaywallPool: make(map[uuid.UUID]paywallPoolMember),
-		commentScores:   make(map[string]int64),
 		voteStatuses:    make(map[string]www.VoteStatusReply),
 		params:          activeNetParams.Params,
 	}
@@ -429,12 +438,6 @@ func _main() error {
 		return err
 	}
 
-	// Setup comment scores map
-	err = p.initCommentScores()
-	if err != nil {
-		return fmt.Errorf("initCommentScore: %v", err)
-	}
-
 	// Setup events
 	p.initEventManager()
 
@@ -444,6 +447,9 @@ func _main() error {
 		if err != nil {
 			return err
 		}
+
+		// Watch for scheduled votes being started by politeiad
+		go p.watchScheduledVotes()
 	}
 
 	// Load or create new CSRF key
@@ -499,12 +505,22 @@ func _main() error {
 		p.setCMSWWWRoutes()
 		// XXX setup user routes
 		p.setCMSUserWWWRoutes()
-		cmsdb.UseLogger(cockroachdbLog)
-		net := filepath.Base(p.cfg.DataDir)
-		p.cmsDB, err = cmsdb.New(p.cfg.DBHost, net, p.cfg.DBRootCert,
-			p.cfg.DBCert, p.cfg.DBKey)
-		if err != nil {
-			return err
+		switch p.cfg.CMSDB {
+		case cmsDBCockroach:
+			cmsdb.UseLogger(cockroachdbLog)
+			net := filepath.Base(p.cfg.DataDir)
+			p.cmsDB, err = cmsdb.New(p.cfg.DBHost, net, p.cfg.DBRootCert,
+				p.cfg.DBCert, p.cfg.DBKey)
+			if err != nil {
+				return err
+			}
+		case cmsDBSqlite:
+			cmssqlite.UseLogger(sqliteLog)
+			p.cmsDB, err = cmssqlite.New(filepath.Join(p.cfg.DataDir,
+				defaultCMSDBFilename))
+			if err != nil {
+				return err
+			}
 		}
 		err = p.cmsDB.Setup()
 		if err != nil {
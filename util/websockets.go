@@ -21,6 +21,7 @@ func validCommand(cmd string) bool {
 	case v1.WSCError:
 	case v1.WSCPing:
 	case v1.WSCSubscribe:
+	case v1.WSCCommentMention:
 	default:
 		return false
 	}
@@ -30,6 +31,7 @@ func validCommand(cmd string) bool {
 func ValidSubscription(cmd string) bool {
 	switch cmd {
 	case v1.WSCPing:
+	case v1.WSCCommentMention:
 	default:
 		return false
 	}
@@ -90,6 +92,10 @@ func WSRead(c *websocket.Conn) (string, string, interface{}, error) {
 		var ping v1.WSPing
 		err = c.ReadJSON(&ping)
 		payload = ping
+	case v1.WSCCommentMention:
+		var mention v1.WSCommentMention
+		err = c.ReadJSON(&mention)
+		payload = mention
 	default:
 		return "", "", nil, ErrInvalidWSCommand
 	}